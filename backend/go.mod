@@ -4,6 +4,7 @@ go 1.23.2
 
 require (
 	github.com/JohannesKaufmann/html-to-markdown/v2 v2.2.1
+	github.com/andybalholm/cascadia v1.3.3
 	github.com/aws/aws-sdk-go-v2/credentials v1.17.47
 	github.com/aws/aws-sdk-go-v2/service/s3 v1.71.0
 	github.com/clerk/clerk-sdk-go/v2 v2.2.0
@@ -18,6 +18,8 @@ CREATE TABLE workspaces (
   billing_email VARCHAR(255) NOT NULL,
   workspace_status workspace_status NOT NULL DEFAULT 'active',
   workspace_plan workspace_plan NOT NULL DEFAULT 'trial',
+  noise_rules JSONB NOT NULL DEFAULT '[]',
+  report_mode TEXT NOT NULL DEFAULT 'digest' CHECK (report_mode IN ('digest', 'individual')),
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -52,6 +54,20 @@ CREATE TABLE workspace_users (
   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (workspace_id, user_id)
 );
+-- Notification settings of the members of a workspace, members without a row use the defaults
+CREATE TABLE notification_subscriptions (
+  workspace_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  competitor_ids UUID [] NOT NULL DEFAULT '{}',
+  categories TEXT [] NOT NULL DEFAULT '{}',
+  channels TEXT [] NOT NULL DEFAULT ARRAY ['email'],
+  frequency TEXT NOT NULL DEFAULT 'instant' CHECK (frequency IN ('instant', 'daily', 'weekly')),
+  last_notified_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (workspace_id, user_id),
+  FOREIGN KEY (workspace_id, user_id) REFERENCES workspace_users(workspace_id, user_id) ON DELETE CASCADE
+);
 -- Create competitors table
 CREATE TABLE competitors (
   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -69,7 +85,11 @@ CREATE TABLE pages (
   title TEXT,
   capture_profile JSONB,
   diff_profile TEXT [] DEFAULT ARRAY ['branding', 'customers', 'integration', 'product', 'pricing', 'partnerships', 'messaging'],
+  check_interval INTEGER NOT NULL DEFAULT 10080,
+  content_selectors JSONB NOT NULL DEFAULT '{}',
+  noise_rules JSONB NOT NULL DEFAULT '[]',
   last_checked_at TIMESTAMP WITH TIME ZONE,
+  content_fingerprint TEXT,
   status page_status NOT NULL DEFAULT 'active',
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
@@ -79,6 +99,9 @@ CREATE TABLE page_history (
   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
   page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
   diff_content JSONB,
+  raw_diff TEXT NOT NULL DEFAULT '',
+  visual_diff JSONB,
+  noise_rules TEXT [] NOT NULL DEFAULT '{}',
   prev TEXT,
   current TEXT,
   status history_status NOT NULL DEFAULT 'active',
@@ -89,6 +112,7 @@ CREATE TABLE workflow_schedules (
   workflow_type workflow_type NOT NULL,
   about TEXT,
   spec TEXT NOT NULL,
+  pipeline TEXT [],
   last_run TIMESTAMP WITH TIME ZONE,
   next_run TIMESTAMP WITH TIME ZONE,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
@@ -105,10 +129,61 @@ CREATE TABLE job_records (
   end_time TIMESTAMP WITH TIME ZONE,
   cancel_time TIMESTAMP WITH TIME ZONE,
   preemptions INTEGER NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'running' CHECK (
+    status IN ('running', 'completed', 'failed', 'aborted', 'unknown', 'paused')
+  ),
+  completed BIGINT NOT NULL DEFAULT 0,
+  failed BIGINT NOT NULL DEFAULT 0,
+  skipped BIGINT NOT NULL DEFAULT 0,
+  checkpoint JSONB,
+  scope JSONB,
+  duration_ms BIGINT,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
   deleted_at TIMESTAMP WITH TIME ZONE
 );
+-- Runs of pipelines, each stage starting once the previous one completed
+CREATE TABLE pipeline_runs (
+  id UUID PRIMARY KEY,
+  status TEXT NOT NULL DEFAULT 'running' CHECK (
+    status IN ('running', 'completed', 'failed', 'aborted')
+  ),
+  stages JSONB NOT NULL,
+  scope JSONB,
+  start_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  end_time TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- Outcome of each item processed by a job
+CREATE TABLE job_item_outcomes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  job_id UUID NOT NULL,
+  workflow_type TEXT NOT NULL CHECK (
+    workflow_type IN ('screenshot', 'report', 'dispatch')
+  ),
+  item_id UUID NOT NULL,
+  attempt INTEGER NOT NULL DEFAULT 1,
+  status TEXT NOT NULL CHECK (status IN ('succeeded', 'skipped', 'failed')),
+  error TEXT,
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- Dead-letter list of the items which failed in a job
+CREATE TABLE job_failures (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  job_id UUID NOT NULL,
+  workflow_type TEXT NOT NULL CHECK (
+    workflow_type IN ('screenshot', 'report', 'dispatch')
+  ),
+  item_id UUID NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 1,
+  last_error TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'retrying', 'resolved')),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE (job_id, item_id)
+);
 -- reports table with JSON column
 CREATE TABLE reports (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -121,6 +196,53 @@ CREATE TABLE reports (
   FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
   FOREIGN KEY (competitor_id) REFERENCES competitors(id) ON DELETE CASCADE
 );
+-- Endpoints of a workspace notified of its events
+CREATE TABLE webhooks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT [] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Log of the events delivered to the webhooks
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_id UUID NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  response_status INTEGER,
+  error TEXT,
+  next_attempt_at TIMESTAMP WITH TIME ZONE,
+  last_attempt_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Emails queued for delivery, written along with the dispatch they belong to
+CREATE TABLE email_outbox (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE,
+  report_ids UUID [] NOT NULL DEFAULT '{}',
+  recipients TEXT [] NOT NULL,
+  subject TEXT NOT NULL,
+  content TEXT NOT NULL,
+  format TEXT NOT NULL DEFAULT 'html' CHECK (format IN ('html', 'text')),
+  attachments JSONB NOT NULL DEFAULT '[]',
+  status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'sent', 'failed', 'bounced')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  provider_message_id TEXT,
+  next_attempt_at TIMESTAMP WITH TIME ZONE,
+  sent_at TIMESTAMP WITH TIME ZONE,
+  delivery_checked_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
 -- Create indexes for better query performance
 -- Indexes for workspaces
 CREATE INDEX idx_workspaces_status ON workspaces(workspace_status);
@@ -154,9 +276,25 @@ CREATE INDEX idx_job_records_workflow_type ON job_records(workflow_type)
 WHERE deleted_at IS NULL;
 CREATE INDEX idx_job_records_start_time ON job_records(start_time)
 WHERE deleted_at IS NULL;
+CREATE INDEX idx_job_records_status ON job_records(workflow_type, status)
+WHERE deleted_at IS NULL;
+CREATE INDEX idx_job_item_outcomes_job_item ON job_item_outcomes(job_id, item_id);
+CREATE INDEX idx_job_failures_job_status ON job_failures(job_id, status);
+CREATE INDEX idx_pipeline_runs_status ON pipeline_runs(status, start_time DESC);
 -- Indexes for faster querying
 CREATE INDEX idx_reports_workspace_competitor ON reports(workspace_id, competitor_id);
 CREATE INDEX idx_reports_time ON reports(time DESC);
+-- Indexes for webhooks
+CREATE INDEX idx_webhooks_workspace_id ON webhooks(workspace_id);
+CREATE INDEX idx_webhook_deliveries_webhook_created ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at)
+WHERE status = 'pending';
+-- Indexes for the email outbox
+CREATE INDEX idx_email_outbox_due ON email_outbox(next_attempt_at)
+WHERE status = 'queued';
+CREATE INDEX idx_email_outbox_sent_at ON email_outbox(sent_at)
+WHERE status = 'sent' AND delivery_checked_at IS NULL;
+CREATE INDEX idx_email_outbox_report_ids ON email_outbox USING GIN (report_ids);
 -- Functions for updating timestamps
 CREATE OR REPLACE FUNCTION update_updated_at_column() RETURNS TRIGGER AS $$ BEGIN NEW.updated_at = CURRENT_TIMESTAMP;
 RETURN NEW;
@@ -169,10 +307,18 @@ CREATE TRIGGER update_users_updated_at BEFORE
 UPDATE ON users FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 CREATE TRIGGER update_workspace_users_updated_at BEFORE
 UPDATE ON workspace_users FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_notification_subscriptions_updated_at BEFORE
+UPDATE ON notification_subscriptions FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 CREATE TRIGGER update_competitors_updated_at BEFORE
 UPDATE ON competitors FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 CREATE TRIGGER update_pages_updated_at BEFORE
 UPDATE ON pages FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_webhooks_updated_at BEFORE
+UPDATE ON webhooks FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_webhook_deliveries_updated_at BEFORE
+UPDATE ON webhook_deliveries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_email_outbox_updated_at BEFORE
+UPDATE ON email_outbox FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 -- Recreate indexes
 CREATE INDEX idx_slack_workspaces_team_id ON slack_workspaces(team_id);
 CREATE INDEX idx_slack_workspaces_workspace_id ON slack_workspaces(workspace_id);
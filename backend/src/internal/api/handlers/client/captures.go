@@ -0,0 +1,134 @@
+// ./src/internal/api/handlers/captures.go
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListPageCaptures lists the timeline of captures for a page
+func (wh *WorkspaceHandler) ListPageCaptures(c *fiber.Ctx) error {
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid competitor ID format", err.Error())
+	}
+
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid page ID format", err.Error())
+	}
+
+	ctx := c.Context()
+	captures, err := wh.workspaceService.ListCapturesForPage(ctx, competitorID, pageID)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not list page captures", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Listed page captures successfully", map[string]any{
+		"captures": captures,
+	})
+}
+
+// GetPageCaptureImage returns the screenshot of a page captured at the given time as a PNG
+func (wh *WorkspaceHandler) GetPageCaptureImage(c *fiber.Ctx) error {
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid competitor ID format", err.Error())
+	}
+
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid page ID format", err.Error())
+	}
+
+	capturedAt, err := parseCaptureTime(c.Params("capturedAt"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid capture time format", err.Error())
+	}
+
+	ctx := c.Context()
+	screenshotImage, _, err := wh.workspaceService.GetCaptureForPage(ctx, competitorID, pageID, capturedAt)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "Could not get page capture", err.Error())
+	}
+
+	if screenshotImage == nil || screenshotImage.Image == nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "Page capture has no screenshot", errors.New("screenshot not found").Error())
+	}
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, screenshotImage.Image); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not encode page capture", err.Error())
+	}
+
+	c.Type("png")
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// GetPageCaptureContent returns the content of a page captured at the given time
+func (wh *WorkspaceHandler) GetPageCaptureContent(c *fiber.Ctx) error {
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid competitor ID format", err.Error())
+	}
+
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid page ID format", err.Error())
+	}
+
+	capturedAt, err := parseCaptureTime(c.Params("capturedAt"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid capture time format", err.Error())
+	}
+
+	ctx := c.Context()
+	_, screenshotContent, err := wh.workspaceService.GetCaptureForPage(ctx, competitorID, pageID, capturedAt)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "Could not get page capture", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Fetched page capture content successfully", screenshotContent)
+}
+
+// ComparePageCaptures diffs the content of two captures of a page
+// The captures are chosen using the from and to query parameters
+func (wh *WorkspaceHandler) ComparePageCaptures(c *fiber.Ctx) error {
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid competitor ID format", err.Error())
+	}
+
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid page ID format", err.Error())
+	}
+
+	from, err := parseCaptureTime(c.Query("from"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid from capture time format", err.Error())
+	}
+
+	to, err := parseCaptureTime(c.Query("to"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid to capture time format", err.Error())
+	}
+
+	ctx := c.Context()
+	diffResult, err := wh.workspaceService.CompareCapturesForPage(ctx, competitorID, pageID, from, to)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not compare page captures", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Compared page captures successfully", map[string]any{
+		"page_id":      pageID,
+		"from":         from,
+		"to":           to,
+		"raw_diff":     diffResult.RawDiff,
+		"diff_content": diffResult.Changes,
+	})
+}
@@ -214,6 +214,41 @@ func (wh *WorkspaceHandler) ListReportsForCompetitor(c *fiber.Ctx) error {
 	})
 }
 
+// ListReportEmails lists the emails which carried a report of a competitor, along with their delivery status
+func (wh *WorkspaceHandler) ListReportEmails(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid competitor ID format", err.Error())
+	}
+
+	reportID, err := uuid.Parse(c.Params("reportID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid report ID format", err.Error())
+	}
+
+	params := api.PaginationParams{
+		Page:     max(1, c.QueryInt("_page", commons.DefaultPageNumber)),
+		PageSize: max(10, c.QueryInt("_limit", commons.DefaultPageSize)),
+	}
+	limit := params.GetLimit()
+	offset := params.GetOffset()
+
+	emails, hasMore, err := wh.workspaceService.ListReportEmails(c.Context(), workspaceID, competitorID, reportID, &limit, &offset)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not list report emails", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Retrieved report emails successfully", map[string]any{
+		"emails":   emails,
+		"has_more": hasMore,
+	})
+}
+
 func (wh *WorkspaceHandler) CreateReportForCompetitor(c *fiber.Ctx) error {
 	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
 	if err != nil {
@@ -143,6 +143,39 @@ func (wh *WorkspaceHandler) UpdatePageForCompetitor(c *fiber.Ctx) error {
 	return sendDataResponse(c, fiber.StatusOK, "Updated page in competitor successfully", page)
 }
 
+// DryRunContentSelectors extracts the content of the latest capture of a page using the given content selectors
+// When no selectors are given, the selectors of the page are used
+func (wh *WorkspaceHandler) DryRunContentSelectors(c *fiber.Ctx) error {
+	competitorID, err := uuid.Parse(c.Params("competitorID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "InvalidCompetitorID", err.Error())
+	}
+
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "InvalidPageID", err.Error())
+	}
+
+	var req api.DryRunContentSelectorsRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+		}
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	ctx := c.Context()
+	extraction, err := wh.workspaceService.DryRunContentSelectorsForPage(ctx, competitorID, pageID, req.ContentSelectors)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not extract page content", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Extracted page content successfully", extraction)
+}
+
 // RemovePageFromCompetitor removes a page from a competitor
 func (wh *WorkspaceHandler) RemovePageFromCompetitor(c *fiber.Ctx) error {
 	competitorID, err := uuid.Parse(c.Params("competitorID"))
@@ -202,3 +235,31 @@ func (wh *WorkspaceHandler) ListPageHistory(c *fiber.Ctx) error {
 		"has_more": hasMore,
 	})
 }
+
+// GetPageHistoryDiff gets the raw diff of a page history alongside the changes derived from it
+func (wh *WorkspaceHandler) GetPageHistoryDiff(c *fiber.Ctx) error {
+	pageID, err := uuid.Parse(c.Params("pageID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid page ID format", err.Error())
+	}
+
+	historyID, err := uuid.Parse(c.Params("historyID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid history ID format", err.Error())
+	}
+
+	ctx := c.Context()
+	history, err := wh.workspaceService.GetHistoryForPage(ctx, pageID, historyID)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "Could not get page history", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Fetched page history diff successfully", map[string]any{
+		"id":           history.ID,
+		"page_id":      history.PageID,
+		"created_at":   history.CreatedAt,
+		"raw_diff":     history.RawDiff,
+		"diff_content": history.DiffContent,
+		"visual_diff":  history.VisualDiff,
+	})
+}
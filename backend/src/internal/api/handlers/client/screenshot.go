@@ -2,6 +2,8 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/service/screenshot"
@@ -25,8 +27,8 @@ func NewScreenshotHandler(screenshotService screenshot.ScreenshotService, logger
 
 func (h *ScreenshotHandler) Retrieve(c *fiber.Ctx) error {
 	type req struct {
-		Options  models.ScreenshotRequestOptions `json:"options"`
-		BackDate bool                            `json:"backDate" default:"false"`
+		Options    models.ScreenshotRequestOptions `json:"options"`
+		CapturedAt time.Time                       `json:"captured_at"`
 	}
 
 	var r req
@@ -34,28 +36,18 @@ func (h *ScreenshotHandler) Retrieve(c *fiber.Ctx) error {
 		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "invalid request body", err.Error())
 	}
 
-	// Create a new screenshot
-	screenshotImg, screenshotContent, err := h.screenshotService.Retrieve(c.Context(), r.Options, r.BackDate)
+	// Retrieve the screenshot captured at the given time
+	screenshotImg, screenshotContent, err := h.screenshotService.Retrieve(c.Context(), r.Options, r.CapturedAt)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return sendDataResponse(c, fiber.StatusOK, "screenshot retrieved", map[string]any{
-		"screenshot": map[string]any{
-			"metadata": screenshotImg.Metadata,
-			"path":     screenshotImg.StoragePath,
-		},
-		"content": map[string]any{
-			"metadata": screenshotContent.Metadata,
-			"path":     screenshotContent.StoragePath,
-		},
-	})
+	return sendDataResponse(c, fiber.StatusOK, "screenshot retrieved", screenshotResponse(screenshotImg, screenshotContent))
 }
 
 func (h *ScreenshotHandler) Refresh(c *fiber.Ctx) error {
 	type req struct {
-		Options  models.ScreenshotRequestOptions `json:"options"`
-		BackDate bool                            `json:"backDate" default:"false"`
+		Options models.ScreenshotRequestOptions `json:"options"`
 	}
 
 	var r req
@@ -64,19 +56,31 @@ func (h *ScreenshotHandler) Refresh(c *fiber.Ctx) error {
 	}
 
 	// Create a new screenshot
-	screenshotImg, screenshotContent, err := h.screenshotService.Refresh(c.Context(), r.Options, r.BackDate)
+	screenshotImg, screenshotContent, err := h.screenshotService.Refresh(c.Context(), r.Options)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return sendDataResponse(c, fiber.StatusOK, "screenshot refreshed", map[string]any{
-		"screenshot": map[string]any{
-			"metadata": screenshotImg.Metadata,
-			"path":     screenshotImg.StoragePath,
-		},
+	return sendDataResponse(c, fiber.StatusOK, "screenshot refreshed", screenshotResponse(screenshotImg, screenshotContent))
+}
+
+// screenshotResponse describes the stored screenshot and content
+// The screenshot is omitted for providers which don't capture one
+func screenshotResponse(screenshotImg *models.ScreenshotImage, screenshotContent *models.ScreenshotContent) map[string]any {
+	resp := map[string]any{
+		"screenshot": nil,
 		"content": map[string]any{
 			"metadata": screenshotContent.Metadata,
 			"path":     screenshotContent.StoragePath,
 		},
-	})
+	}
+
+	if screenshotImg != nil {
+		resp["screenshot"] = map[string]any{
+			"metadata": screenshotImg.Metadata,
+			"path":     screenshotImg.StoragePath,
+		}
+	}
+
+	return resp
 }
@@ -3,6 +3,8 @@ package handlers
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/clerk/clerk-sdk-go/v2/user"
@@ -57,3 +59,22 @@ func getClerkUserIDFromContext(c *fiber.Ctx) (string, error) {
 
 	return clerkUserID, nil
 }
+
+// parseCaptureTime parses the time of a capture
+// It accepts either an RFC3339 timestamp or the seconds since the unix epoch
+func parseCaptureTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("capture time is required")
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	capturedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return capturedAt.UTC(), nil
+}
@@ -0,0 +1,184 @@
+// ./src/internal/api/handlers/webhook.go
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/wizenheimer/byrd/src/internal/api/commons"
+	api "github.com/wizenheimer/byrd/src/internal/models/api"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+type WebhookHandler struct {
+	webhookService webhook.WebhookService
+	logger         *logger.Logger
+}
+
+func NewWebhookHandler(webhookService webhook.WebhookService, logger *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger: logger.WithFields(map[string]interface{}{
+			"module": "webhook_handler",
+		}),
+	}
+}
+
+// ListWebhooks lists the webhooks of a workspace
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	params := api.PaginationParams{
+		Page:     max(1, c.QueryInt("_page", commons.DefaultPageNumber)),
+		PageSize: max(10, c.QueryInt("_limit", commons.DefaultPageSize)),
+	}
+	limit := params.GetLimit()
+	offset := params.GetOffset()
+
+	webhooks, hasMore, err := h.webhookService.ListWebhooks(c.Context(), workspaceID, &limit, &offset)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not list webhooks", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Listed webhooks successfully", map[string]any{
+		"webhooks": webhooks,
+		"has_more": hasMore,
+	})
+}
+
+// CreateWebhook creates a webhook for a workspace
+// The secret signing its payloads is only returned in the response
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	var req api.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Context(), workspaceID, req.ToProps())
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not create webhook", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusCreated, "Created webhook successfully", webhook)
+}
+
+// GetWebhook returns a webhook of a workspace
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	workspaceID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid ID format", err.Error())
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Context(), workspaceID, webhookID)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusNotFound, "Could not get webhook", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Retrieved webhook successfully", webhook)
+}
+
+// UpdateWebhook replaces the settings of a webhook of a workspace
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	workspaceID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid ID format", err.Error())
+	}
+
+	var req api.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Context(), workspaceID, webhookID, req.ToProps())
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not update webhook", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Updated webhook successfully", webhook)
+}
+
+// DeleteWebhook deletes a webhook of a workspace
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	workspaceID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid ID format", err.Error())
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Context(), workspaceID, webhookID); err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not delete webhook", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Deleted webhook successfully", nil)
+}
+
+// ListWebhookDeliveries lists the deliveries of a webhook of a workspace, latest first
+func (h *WebhookHandler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	workspaceID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid ID format", err.Error())
+	}
+
+	params := api.PaginationParams{
+		Page:     max(1, c.QueryInt("_page", commons.DefaultPageNumber)),
+		PageSize: max(10, c.QueryInt("_limit", commons.DefaultPageSize)),
+	}
+	limit := params.GetLimit()
+	offset := params.GetOffset()
+
+	deliveries, hasMore, err := h.webhookService.ListDeliveries(c.Context(), workspaceID, webhookID, &limit, &offset)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not list webhook deliveries", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Listed webhook deliveries successfully", map[string]any{
+		"deliveries": deliveries,
+		"has_more":   hasMore,
+	})
+}
+
+// SendTestWebhookEvent sends a test event to a webhook of a workspace
+// The delivery is returned along with its outcome, whether the endpoint acknowledged the event or not
+func (h *WebhookHandler) SendTestWebhookEvent(c *fiber.Ctx) error {
+	workspaceID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusBadRequest, "Invalid ID format", err.Error())
+	}
+
+	delivery, err := h.webhookService.SendTestEvent(c.Context(), workspaceID, webhookID)
+	if err != nil {
+		return sendErrorResponse(c, h.logger, fiber.StatusInternalServerError, "Could not send test event", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Sent test event", delivery)
+}
+
+func parseWebhookParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	webhookID, err := uuid.Parse(c.Params("webhookID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return workspaceID, webhookID, nil
+}
@@ -2,8 +2,12 @@
 package handlers
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -12,6 +16,7 @@ import (
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/service/workflow"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
 )
 
 type WorkflowHandler struct {
@@ -34,7 +39,20 @@ func (wh *WorkflowHandler) StartWorkflow(c *fiber.Ctx) error {
 		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
 	}
 
-	jobID, err := wh.workflowService.Submit(c.Context(), workflowType)
+	// The body is optional, the job covers every item without it
+	var req api.StartWorkflowRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+		}
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	scope := req.Scope()
+	jobID, err := wh.workflowService.Submit(c.Context(), workflowType, scope)
 	if err != nil {
 		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to start workflow", err.Error())
 	}
@@ -42,6 +60,45 @@ func (wh *WorkflowHandler) StartWorkflow(c *fiber.Ctx) error {
 	return sendDataResponse(c, fiber.StatusCreated, "workflow started successfully", map[string]any{
 		"workflowType": workflowType,
 		"jobID":        jobID,
+		"scope":        scope,
+	})
+}
+
+// RefreshWorkspace refreshes the pages of a workspace on demand, or those of some of its competitors or pages
+// The refresh is limited to the workspace, competitors and pages of other workspaces are out of its scope
+func (wh *WorkflowHandler) RefreshWorkspace(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	// The body is optional, every page of the workspace is refreshed without it
+	var req api.RefreshWorkspaceRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+		}
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	scope := models.JobScope{
+		WorkspaceIDs:  []uuid.UUID{workspaceID},
+		CompetitorIDs: req.CompetitorIDs,
+		PageIDs:       req.PageIDs,
+	}
+
+	jobID, err := wh.workflowService.Submit(c.Context(), models.ScreenshotWorkflowType, scope)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Failed to refresh workspace", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusAccepted, "Workspace refresh started", map[string]any{
+		"workflowType": models.ScreenshotWorkflowType,
+		"jobID":        jobID,
+		"scope":        scope,
 	})
 }
 
@@ -63,13 +120,154 @@ func (wh *WorkflowHandler) GetWorkflow(c *fiber.Ctx) error {
 		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to get workflow", err.Error())
 	}
 
-	return sendDataResponse(c, fiber.StatusOK, "workflow retrieved successfully", map[string]any{
+	data := map[string]any{
 		"workflowType": workflowType,
 		"jobID":        jobID,
 		"job":          job,
+	}
+
+	// The record holds the duration of the job, and its final state once it ended
+	if record, err := wh.workflowService.Record(c.Context(), workflowType, jobID); err == nil {
+		data["record"] = record
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "workflow retrieved successfully", data)
+}
+
+// eventHeartbeatInterval is the interval at which a comment is sent on idle event streams, so that proxies keep them open
+const eventHeartbeatInterval = 15 * time.Second
+
+// eventWriteTimeout bounds each write to an event stream
+// The write timeout of the server covers the whole response, so the deadline is pushed back before each write instead
+const eventWriteTimeout = 30 * time.Second
+
+// StreamJob streams the progress of a job as server-sent events
+// The stream starts with the current state of the job, and ends once the job completed or was aborted
+func (wh *WorkflowHandler) StreamJob(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	jobIDString := c.Params("jobID")
+	jobID, err := uuid.Parse(jobIDString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse job ID", err.Error())
+	}
+
+	// The stream outlives the handler, it's cancelled once the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Subscribe before reading the state, so that no event following the snapshot is missed
+	events, err := wh.workflowService.Events(ctx, workflowType, &jobID)
+	if err != nil {
+		cancel()
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to stream workflow", err.Error())
+	}
+
+	job, err := wh.workflowService.State(c.Context(), workflowType, jobID)
+	if err != nil {
+		cancel()
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "failed to get workflow", err.Error())
+	}
+
+	snapshot := models.NewJobEvent(jobID, workflowType, models.JobEventForState(job.JobState), job.JobState)
+	snapshot.Checkpoint = &job.Checkpoint
+
+	wh.streamEvents(c, cancel, &snapshot, events)
+	return nil
+}
+
+// StreamWorkflow streams the progress of every job of a workflow as server-sent events
+func (wh *WorkflowHandler) StreamWorkflow(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	// The stream outlives the handler, it's cancelled once the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := wh.workflowService.Events(ctx, workflowType, nil)
+	if err != nil {
+		cancel()
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to stream workflow", err.Error())
+	}
+
+	wh.streamEvents(c, cancel, nil, events)
+	return nil
+}
+
+// streamEvents writes the events to the client as server-sent events, starting with the snapshot when set
+// A snapshot or event ending its job ends the stream, the subscription is cancelled once the stream ends
+func (wh *WorkflowHandler) streamEvents(c *fiber.Ctx, cancel context.CancelFunc, snapshot *models.JobEvent, events <-chan models.JobEvent) {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The stream is written to the connection as it's flushed, so the deadline set before a write covers it
+	conn := c.Context().Conn()
+	extendDeadline := func() {
+		_ = conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+	}
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		if snapshot != nil {
+			extendDeadline()
+			if err := writeEvent(w, *snapshot); err != nil || snapshot.IsFinal() {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				extendDeadline()
+				if err := writeEvent(w, event); err != nil {
+					return
+				}
+				if snapshot != nil && event.IsFinal() {
+					return
+				}
+			case <-heartbeat.C:
+				// Writing fails once the client went away
+				extendDeadline()
+				if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
 	})
 }
 
+// writeEvent writes the event as a server-sent event, named after its type
+func writeEvent(w *bufio.Writer, event models.JobEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
 func (wh *WorkflowHandler) StopWorkflow(c *fiber.Ctx) error {
 	workflowTypeString := c.Params("workflowType")
 	workflowType, err := models.ParseWorkflowType(workflowTypeString)
@@ -93,6 +291,52 @@ func (wh *WorkflowHandler) StopWorkflow(c *fiber.Ctx) error {
 	})
 }
 
+func (wh *WorkflowHandler) PauseWorkflow(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	jobIDString := c.Params("jobID")
+	jobID, err := uuid.Parse(jobIDString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse job ID", err.Error())
+	}
+
+	if err := wh.workflowService.Pause(context.Background(), workflowType, jobID); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to pause workflow", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "workflow paused successfully", map[string]any{
+		"workflowType": workflowType,
+		"jobID":        jobID,
+	})
+}
+
+func (wh *WorkflowHandler) ResumeWorkflow(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	jobIDString := c.Params("jobID")
+	jobID, err := uuid.Parse(jobIDString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse job ID", err.Error())
+	}
+
+	if err := wh.workflowService.Resume(context.Background(), workflowType, jobID); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to resume workflow", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "workflow resumed successfully", map[string]any{
+		"workflowType": workflowType,
+		"jobID":        jobID,
+	})
+}
+
 func (wh *WorkflowHandler) ListCheckpoint(c *fiber.Ctx) error {
 	jobStatusString := c.Query("job_status")
 	workflowStatus, err := models.ParseJobStatus(jobStatusString)
@@ -136,10 +380,190 @@ func (wh *WorkflowHandler) ListHistory(c *fiber.Ctx) error {
 		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workflow type", err.Error())
 	}
 
-	schedules, err := wh.workflowService.History(c.Context(), &limits, &offsets, &wf)
+	filter := models.JobRecordFilter{
+		WorkflowType: &wf,
+		HasFailures:  c.QueryBool("has_failures", false),
+	}
+
+	if statusString := c.Query("status"); statusString != "" {
+		status, err := models.ParseJobStatus(statusString)
+		if err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid job status", err.Error())
+		}
+		filter.Status = &status
+	}
+
+	if startedAfter := c.Query("started_after"); startedAfter != "" {
+		after, err := parseCaptureTime(startedAfter)
+		if err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid started_after", err.Error())
+		}
+		filter.StartedAfter = &after
+	}
+
+	if startedBefore := c.Query("started_before"); startedBefore != "" {
+		before, err := parseCaptureTime(startedBefore)
+		if err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid started_before", err.Error())
+		}
+		filter.StartedBefore = &before
+	}
+
+	schedules, err := wh.workflowService.History(c.Context(), filter, &limits, &offsets)
 	if err != nil {
 		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Failed to list schedules", err.Error())
 	}
 
 	return sendDataResponse(c, http.StatusOK, "Successfully listed schedules", schedules)
 }
+
+func (wh *WorkflowHandler) ListFailures(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	jobIDString := c.Params("jobID")
+	jobID, err := uuid.Parse(jobIDString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse job ID", err.Error())
+	}
+
+	var status *models.JobFailureStatus
+	if statusString := c.Query("status"); statusString != "" {
+		failureStatus, err := models.ParseJobFailureStatus(statusString)
+		if err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse failure status", err.Error())
+		}
+		status = &failureStatus
+	}
+
+	pageNumber := max(1, c.QueryInt("_page", commons.DefaultPageNumber))
+	pageSize := max(10, c.QueryInt("_limit", commons.DefaultPageSize))
+
+	pagination := api.PaginationParams{
+		Page:     pageNumber,
+		PageSize: pageSize,
+	}
+
+	limits := pagination.GetLimit()
+	offsets := pagination.GetOffset()
+
+	failures, err := wh.workflowService.ListFailures(c.Context(), workflowType, jobID, status, &limits, &offsets)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to list failures", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "failures listed successfully", map[string]any{
+		"workflowType": workflowType,
+		"jobID":        jobID,
+		"failures":     failures,
+	})
+}
+
+func (wh *WorkflowHandler) RetryFailures(c *fiber.Ctx) error {
+	workflowTypeString := c.Params("workflowType")
+	workflowType, err := models.ParseWorkflowType(workflowTypeString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse workflow type", err.Error())
+	}
+
+	jobIDString := c.Params("jobID")
+	jobID, err := uuid.Parse(jobIDString)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse job ID", err.Error())
+	}
+
+	// The body is optional, every pending failure is retried without it
+	var req api.RetryJobFailuresRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+		}
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	failures, err := wh.workflowService.RetryFailures(c.Context(), workflowType, jobID, req.ItemIDs)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to retry failures", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusAccepted, "failures queued for retry", map[string]any{
+		"workflowType": workflowType,
+		"jobID":        jobID,
+		"failures":     failures,
+	})
+}
+
+func (wh *WorkflowHandler) StartPipeline(c *fiber.Ctx) error {
+	// The body is optional, the default pipeline runs across every item without it
+	var req api.StartPipelineRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+		}
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	stages := req.Stages
+	if len(stages) == 0 {
+		stages = models.DefaultPipeline
+	}
+
+	run, err := wh.workflowService.RunPipeline(c.Context(), stages, req.Scope())
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to start pipeline", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusCreated, "pipeline started successfully", run)
+}
+
+func (wh *WorkflowHandler) GetPipeline(c *fiber.Ctx) error {
+	runID, err := uuid.Parse(c.Params("runID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse run ID", err.Error())
+	}
+
+	run, err := wh.workflowService.Pipeline(c.Context(), runID)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusNotFound, "failed to get pipeline", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "pipeline retrieved successfully", run)
+}
+
+func (wh *WorkflowHandler) ListPipelines(c *fiber.Ctx) error {
+	var status *models.PipelineStatus
+	if statusString := c.Query("status"); statusString != "" {
+		pipelineStatus, err := models.ParsePipelineStatus(statusString)
+		if err != nil {
+			return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "failed to parse pipeline status", err.Error())
+		}
+		status = &pipelineStatus
+	}
+
+	pageNumber := max(1, c.QueryInt("_page", commons.DefaultPageNumber))
+	pageSize := max(10, c.QueryInt("_limit", commons.DefaultPageSize))
+
+	pagination := api.PaginationParams{
+		Page:     pageNumber,
+		PageSize: pageSize,
+	}
+
+	limits := pagination.GetLimit()
+	offsets := pagination.GetOffset()
+
+	runs, err := wh.workflowService.ListPipelines(c.Context(), status, &limits, &offsets)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "failed to list pipelines", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "pipelines listed successfully", runs)
+}
@@ -123,6 +123,193 @@ func (wh *WorkspaceHandler) UpdateWorkspaceByID(c *fiber.Ctx) error {
 		})
 }
 
+// GetWorkspaceNoiseRules gets the noise rules applied to the pages of a workspace
+func (wh *WorkspaceHandler) GetWorkspaceNoiseRules(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	ctx := c.Context()
+	noiseRules, err := wh.workspaceService.GetWorkspaceNoiseRules(ctx, workspaceID)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not get workspace noise rules", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Fetched workspace noise rules successfully", map[string]any{
+		"noise_rules": noiseRules,
+	})
+}
+
+// UpdateWorkspaceNoiseRules replaces the noise rules applied to the pages of a workspace
+func (wh *WorkspaceHandler) UpdateWorkspaceNoiseRules(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	var req api.WorkspaceNoiseRulesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	ctx := c.Context()
+	if err := wh.workspaceService.UpdateWorkspaceNoiseRules(ctx, workspaceID, req.NoiseRules); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not update workspace noise rules", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Updated workspace noise rules successfully", map[string]any{
+		"noise_rules": req.NoiseRules,
+	})
+}
+
+// GetWorkspaceReportMode gets how the reports of a workspace are dispatched to its members
+func (wh *WorkspaceHandler) GetWorkspaceReportMode(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	ctx := c.Context()
+	reportMode, err := wh.workspaceService.GetWorkspaceReportMode(ctx, workspaceID)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not get workspace report mode", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Fetched workspace report mode successfully", map[string]any{
+		"report_mode": reportMode,
+	})
+}
+
+// UpdateWorkspaceReportMode updates how the reports of a workspace are dispatched to its members
+func (wh *WorkspaceHandler) UpdateWorkspaceReportMode(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	var req api.WorkspaceReportModeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	ctx := c.Context()
+	if err := wh.workspaceService.UpdateWorkspaceReportMode(ctx, workspaceID, req.ReportMode); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not update workspace report mode", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Updated workspace report mode successfully", map[string]any{
+		"report_mode": req.ReportMode,
+	})
+}
+
+// GetDigestForWorkspace gets the digest of the latest reports of the competitors of a workspace
+func (wh *WorkspaceHandler) GetDigestForWorkspace(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	ctx := c.Context()
+	digest, err := wh.workspaceService.GetDigest(ctx, workspaceID, models.JobScope{})
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not get digest", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Retrieved digest successfully", digest)
+}
+
+// DispatchDigestForWorkspace dispatches the digest of a workspace to an email list
+func (wh *WorkspaceHandler) DispatchDigestForWorkspace(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	var req api.DispatchReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	ctx := c.Context()
+	if err := wh.workspaceService.DispatchDigest(ctx, workspaceID, req.Emails); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not dispatch digest", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Dispatched digest successfully", nil)
+}
+
+// GetNotificationSubscription gets the notification settings of the current member of a workspace
+func (wh *WorkspaceHandler) GetNotificationSubscription(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	clerkUser, err := getClerkUserFromContext(c)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	userEmail, err := utils.GetClerkUserEmail(clerkUser)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Couldn't locate user email", err.Error())
+	}
+
+	ctx := c.Context()
+	subscription, err := wh.workspaceService.GetNotificationSubscription(ctx, workspaceID, userEmail)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not get notification settings", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Fetched notification settings successfully", subscription)
+}
+
+// UpdateNotificationSubscription replaces the notification settings of the current member of a workspace
+func (wh *WorkspaceHandler) UpdateNotificationSubscription(c *fiber.Ctx) error {
+	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid workspace ID format", err.Error())
+	}
+
+	clerkUser, err := getClerkUserFromContext(c)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	userEmail, err := utils.GetClerkUserEmail(clerkUser)
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Couldn't locate user email", err.Error())
+	}
+
+	var req api.NotificationSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := utils.SetDefaultsAndValidate(&req); err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	ctx := c.Context()
+	subscription, err := wh.workspaceService.UpdateNotificationSubscription(ctx, workspaceID, userEmail, req.ToProps())
+	if err != nil {
+		return sendErrorResponse(c, wh.logger, fiber.StatusInternalServerError, "Could not update notification settings", err.Error())
+	}
+
+	return sendDataResponse(c, fiber.StatusOK, "Updated notification settings successfully", subscription)
+}
+
 // DeleteWorkspace deletes a workspace by ID
 func (wh *WorkspaceHandler) DeleteWorkspaceByID(c *fiber.Ctx) error {
 	workspaceID, err := uuid.Parse(c.Params("workspaceID"))
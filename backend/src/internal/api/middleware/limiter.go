@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/redis/go-redis/v9"
 	"github.com/wizenheimer/byrd/src/internal/config"
 )
 
@@ -16,9 +17,11 @@ type RateLimiters struct {
 	CompetitorCDLimiter fiber.Handler
 	PageCDLimiter       fiber.Handler
 	UserCDLimiter       fiber.Handler
+	// On demand refresh limiter, shared by the members of a workspace across the replicas of the server
+	WorkspaceRefreshLimiter fiber.Handler
 }
 
-func NewRateLimiters(cfg *config.Config) *RateLimiters {
+func NewRateLimiters(cfg *config.Config, redisClient *redis.Client) *RateLimiters {
 
 	gl := limiter.New(limiter.Config{
 		Max:        cfg.Server.GlobalRequestsPerMinute,
@@ -90,6 +93,22 @@ func NewRateLimiters(cfg *config.Config) *RateLimiters {
 		},
 	})
 
+	// Refreshes are counted in redis, each replica counting on its own would multiply the limit by the replicas
+	rl := newRedisLimiter(redisClient, limiter.Config{
+		Max:        cfg.Server.WorkspaceRefreshRequestsPerMinute,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			// Refreshes are limited per workspace, as every member triggers the same pages
+			return c.Params("workspaceID") + ":refresh"
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "Workspace refresh rate limit exceeded",
+				"details": "Exceeds the authorized number of refreshes per minute",
+			})
+		},
+	})
+
 	return &RateLimiters{
 		// Global base limiter
 		GlobalLimiter: gl,
@@ -99,5 +118,8 @@ func NewRateLimiters(cfg *config.Config) *RateLimiters {
 		CompetitorCDLimiter: cl,
 		PageCDLimiter:       pl,
 		UserCDLimiter:       ul,
+
+		// On demand refresh limiter
+		WorkspaceRefreshLimiter: rl,
 	}
 }
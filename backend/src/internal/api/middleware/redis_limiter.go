@@ -0,0 +1,48 @@
+// ./src/internal/api/middleware/redis_limiter.go
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/redis/go-redis/v9"
+)
+
+// Key format: ratelimit:{key}
+const rateLimitKeyFormat = "ratelimit:%s"
+
+// countRequestScript counts a request within the window of the key, the window starts with its first request
+// Returns the number of requests within the window, and the time left until it ends in milliseconds
+var countRequestScript = redis.NewScript(`
+	local count = redis.call('INCR', KEYS[1])
+	if count == 1 then
+		redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	end
+	return {count, redis.call('PTTL', KEYS[1])}`)
+
+// newRedisLimiter limits the requests as the limiter of fiber does, keeping the count of each key in redis
+// Every replica of the server shares the count, so that the limit holds however many replicas serve the requests
+func newRedisLimiter(client *redis.Client, cfg limiter.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := fmt.Sprintf(rateLimitKeyFormat, cfg.KeyGenerator(c))
+		result, err := countRequestScript.Run(c.UserContext(), client, []string{key}, cfg.Expiration.Milliseconds()).Int64Slice()
+		if err != nil {
+			// Requests can't be counted without redis, the endpoints limited this way rely on it anyway
+			return fmt.Errorf("failed to count request: %w", err)
+		}
+		count, resetIn := int(result[0]), time.Duration(result[1])*time.Millisecond
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(cfg.Max))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(max(cfg.Max-count, 0)))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(int(resetIn.Seconds())))
+
+		if count > cfg.Max {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(resetIn.Seconds())))
+			return cfg.LimitReached(c)
+		}
+		return c.Next()
+	}
+}
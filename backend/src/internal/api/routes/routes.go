@@ -13,6 +13,7 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/service/scheduler"
 	"github.com/wizenheimer/byrd/src/internal/service/screenshot"
 	"github.com/wizenheimer/byrd/src/internal/service/user"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/internal/service/workflow"
 	"github.com/wizenheimer/byrd/src/internal/service/workspace"
 	"github.com/wizenheimer/byrd/src/internal/transaction"
@@ -27,6 +28,7 @@ type HandlerContainer struct {
 	WorkflowHandler     *handlers.WorkflowHandler
 	ScheduleHandler     *handlers.ScheduleHandler
 	NotificationHandler *handlers.NotificationHandler
+	WebhookHandler      *handlers.WebhookHandler
 	SlackHandler        *intg_handler.SlackIntegrationHandler
 }
 
@@ -38,6 +40,7 @@ func NewHandlerContainer(
 	workflowService workflow.WorkflowService,
 	schedulerService scheduler.SchedulerService,
 	slackWorkspaceService slackworkspace.SlackWorkspaceService,
+	webhookService webhook.WebhookService,
 	library template.TemplateLibrary,
 	emailClient email.EmailClient,
 	tx *transaction.TxManager,
@@ -100,6 +103,11 @@ func NewHandlerContainer(
 		),
 		// Handlers for notification management
 		NotificationHandler: nh,
+		// Handlers for webhook management
+		WebhookHandler: handlers.NewWebhookHandler(
+			webhookService,
+			logger,
+		),
 		// Handlers for slack integration
 		SlackHandler: sh,
 	}
@@ -150,6 +158,12 @@ func setupPublicRoutes(
 
 	// Page management routes
 	setupPageRoutes(public, h.WorkspaceHandler, l, m, r)
+
+	// On demand refresh routes
+	setupRefreshRoutes(public, h.WorkflowHandler, l, m)
+
+	// Webhook management routes
+	setupWebhookRoutes(public, h.WebhookHandler, l, m)
 }
 
 // setupUserRoutes configures user management routes
@@ -188,6 +202,47 @@ func setupWorkspaceRoutes(
 		m.RequiresWorkspaceMember,
 		workspaceHandler.UpdateWorkspaceByID)
 
+	// Get the noise rules of a workspace
+	router.Get("/workspace/:workspaceID/noise-rules",
+		m.RequiresWorkspaceMember,
+		workspaceHandler.GetWorkspaceNoiseRules)
+
+	// Replace the noise rules of a workspace
+	router.Put("/workspace/:workspaceID/noise-rules",
+		m.RequiresWorkspaceAdmin,
+		workspaceHandler.UpdateWorkspaceNoiseRules)
+
+	// Get how the reports of a workspace are dispatched
+	router.Get("/workspace/:workspaceID/report-mode",
+		m.RequiresWorkspaceMember,
+		workspaceHandler.GetWorkspaceReportMode)
+
+	// Update how the reports of a workspace are dispatched
+	router.Put("/workspace/:workspaceID/report-mode",
+		m.RequiresWorkspaceAdmin,
+		workspaceHandler.UpdateWorkspaceReportMode)
+
+	// Get the digest of the latest reports of the competitors of a workspace
+	router.Get("/workspace/:workspaceID/digest",
+		m.RequiresWorkspaceMember,
+		workspaceHandler.GetDigestForWorkspace)
+
+	// Dispatch the digest of a workspace
+	router.Post("/workspace/:workspaceID/digest/dispatch",
+		l.CompetitorCDLimiter, // Rate limit digest dispatch
+		m.RequiresWorkspaceMember,
+		workspaceHandler.DispatchDigestForWorkspace)
+
+	// Get the notification settings of the current member of a workspace
+	router.Get("/workspace/:workspaceID/notifications",
+		m.RequiresWorkspaceMember,
+		workspaceHandler.GetNotificationSubscription)
+
+	// Replace the notification settings of the current member of a workspace
+	router.Put("/workspace/:workspaceID/notifications",
+		m.RequiresWorkspaceMember,
+		workspaceHandler.UpdateNotificationSubscription)
+
 	// Delete a workspace by ID
 	router.Delete("/workspace/:workspaceID",
 		m.RequiresWorkspaceAdmin,
@@ -289,6 +344,12 @@ func setupCompetitorRoutes(
 		m.RequiresWorkspaceMember,
 		r.ValidateCompetitorResource,
 		workspaceHandler.ListReportsForCompetitor)
+
+	// List the emails which carried a report, along with their delivery status
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/reports/:reportID/emails",
+		m.RequiresWorkspaceMember,
+		r.ValidateCompetitorResource,
+		workspaceHandler.ListReportEmails)
 }
 
 func setupPageRoutes(
@@ -323,6 +384,12 @@ func setupPageRoutes(
 		r.ValidatePageResource,
 		workspaceHandler.UpdatePageForCompetitor)
 
+	// Try out content selectors on the latest capture of a page
+	router.Post("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/selectors/dry-run",
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.DryRunContentSelectors)
+
 	// Delete a page from a competitor
 	router.Delete("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID",
 		l.PageCDLimiter, // Rate limit page deletion
@@ -335,6 +402,95 @@ func setupPageRoutes(
 		m.RequiresWorkspaceMember,
 		r.ValidatePageResource,
 		workspaceHandler.ListPageHistory)
+
+	// Get the raw diff of a page history
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/history/:historyID/diff",
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.GetPageHistoryDiff)
+
+	// List the timeline of captures for a page
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/captures",
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.ListPageCaptures)
+
+	// Diff two captures of a page
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/captures/diff",
+		l.PageCDLimiter, // Rate limit on demand diffs
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.ComparePageCaptures)
+
+	// Get the screenshot of a page at a capture
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/captures/:capturedAt/image",
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.GetPageCaptureImage)
+
+	// Get the content of a page at a capture
+	router.Get("/workspace/:workspaceID/competitors/:competitorID/pages/:pageID/captures/:capturedAt/content",
+		m.RequiresWorkspaceMember,
+		r.ValidatePageResource,
+		workspaceHandler.GetPageCaptureContent)
+}
+
+// setupRefreshRoutes configures the on demand refresh of a workspace
+func setupRefreshRoutes(
+	router fiber.Router,
+	workflowHandler *handlers.WorkflowHandler,
+	l *middleware.RateLimiters,
+	m *middleware.AccessMiddleware,
+) {
+	// Refresh the pages of a workspace, or those of some of its competitors or pages
+	router.Post("/workspace/:workspaceID/refresh",
+		m.RequiresWorkspaceMember,
+		l.WorkspaceRefreshLimiter, // Rate limit refreshes once membership is checked
+		workflowHandler.RefreshWorkspace)
+}
+
+// setupWebhookRoutes configures the webhooks of the workspaces
+func setupWebhookRoutes(
+	router fiber.Router,
+	webhookHandler *handlers.WebhookHandler,
+	l *middleware.RateLimiters,
+	m *middleware.AccessMiddleware,
+) {
+	// List the webhooks of a workspace
+	router.Get("/workspace/:workspaceID/webhooks",
+		m.RequiresWorkspaceMember,
+		webhookHandler.ListWebhooks)
+
+	// Create a webhook for a workspace
+	router.Post("/workspace/:workspaceID/webhooks",
+		m.RequiresWorkspaceAdmin,
+		webhookHandler.CreateWebhook)
+
+	// Get a webhook of a workspace
+	router.Get("/workspace/:workspaceID/webhooks/:webhookID",
+		m.RequiresWorkspaceMember,
+		webhookHandler.GetWebhook)
+
+	// Replace the settings of a webhook of a workspace
+	router.Put("/workspace/:workspaceID/webhooks/:webhookID",
+		m.RequiresWorkspaceAdmin,
+		webhookHandler.UpdateWebhook)
+
+	// Delete a webhook of a workspace
+	router.Delete("/workspace/:workspaceID/webhooks/:webhookID",
+		m.RequiresWorkspaceAdmin,
+		webhookHandler.DeleteWebhook)
+
+	// List the deliveries of a webhook of a workspace
+	router.Get("/workspace/:workspaceID/webhooks/:webhookID/deliveries",
+		m.RequiresWorkspaceMember,
+		webhookHandler.ListWebhookDeliveries)
+
+	// Send a test event to a webhook of a workspace
+	router.Post("/workspace/:workspaceID/webhooks/:webhookID/test",
+		l.CompetitorCDLimiter, // Rate limit test events
+		m.RequiresWorkspaceAdmin,
+		webhookHandler.SendTestWebhookEvent)
 }
 
 // setupPrivateRoutes configures all private API endpoints
@@ -366,10 +522,25 @@ func setupWorkflowRoutes(router fiber.Router, handler *handlers.WorkflowHandler)
 	router.Post("/workflow/:workflowType/job", handler.StartWorkflow)
 	router.Delete("/workflow/:workflowType/job/:jobID", handler.StopWorkflow)
 	router.Get("/workflow/:workflowType/job/:jobID", handler.GetWorkflow)
+	router.Post("/workflow/:workflowType/job/:jobID/pause", handler.PauseWorkflow)
+	router.Post("/workflow/:workflowType/job/:jobID/resume", handler.ResumeWorkflow)
+
+	// Workflow progress, streamed as server-sent events
+	router.Get("/workflow/:workflowType/job/:jobID/events", handler.StreamJob)
+	router.Get("/workflow/:workflowType/events", handler.StreamWorkflow)
+
+	// Workflow failures
+	router.Get("/workflow/:workflowType/job/:jobID/failures", handler.ListFailures)
+	router.Post("/workflow/:workflowType/job/:jobID/failures/retry", handler.RetryFailures)
 
 	// Workflow monitoring
 	router.Get("/workflow/checkpoint", handler.ListCheckpoint)
 	router.Get("/workflow/history", handler.ListHistory)
+
+	// Pipelines chaining the workflows
+	router.Post("/pipeline", handler.StartPipeline)
+	router.Get("/pipeline", handler.ListPipelines)
+	router.Get("/pipeline/:runID", handler.GetPipeline)
 }
 
 // setupScreenshotRoutes configures screenshot management endpoints
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
@@ -35,6 +36,13 @@ func WithRateLimit(rps float64, burst int) ClientOption {
 	}
 }
 
+// WithHTTPClient replaces the underlying http client, e.g. to bound it to public addresses
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *HTTPClient) {
+		c.httpClient = httpClient
+	}
+}
+
 func NewClient(logger *logger.Logger, opts ...ClientOption) (*HTTPClient, error) {
 	c := &HTTPClient{
 		httpClient: http.DefaultClient,
@@ -106,7 +114,7 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 			c.logger.Error("http request failed", zap.Error(err), zap.Any("attempt", attempt), zap.Any("maxRetries", c.maxRetries), zap.Any("url", req.URL))
 			// Network-level error
 			if attempt == c.maxRetries {
-				return nil, fmt.Errorf("max retries reached: %w", err)
+				return nil, &models.UpstreamError{Service: req.URL.Host, Err: fmt.Errorf("max retries reached: %w", err)}
 			}
 			continue
 		} else if resp == nil {
@@ -122,7 +130,7 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
 		if c.shouldRetry(resp.StatusCode) {
 			resp.Body.Close()
 			if attempt == c.maxRetries {
-				return nil, fmt.Errorf("max retries reached, last status code: %d", resp.StatusCode)
+				return nil, &models.UpstreamError{Service: req.URL.Host, StatusCode: resp.StatusCode, Err: errors.New("max retries reached")}
 			}
 			continue
 		}
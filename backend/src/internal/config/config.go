@@ -45,6 +45,10 @@ type ServerConfig struct {
 	// Defaults to 10 requests per minute.
 	ReportCDPerMinute int
 
+	// Limits the number of on demand refreshes of a workspace per minute, across its members.
+	// Defaults to 2 requests per minute.
+	WorkspaceRefreshRequestsPerMinute int
+
 	// CorsAllowedOrigins is a comma-separated list of origins that are allowed to make requests to the server.
 	CorsAllowedOrigins string
 
@@ -99,11 +103,40 @@ type ServicesConfig struct {
 	ScreenshotServiceOrigin      string
 	ScreenshotServiceQPS         float64
 	OpenAIKey                    string
+	AIProvider                   string
+	AIModel                      string
+	AnthropicAPIKey              string
+	LocalAIBaseURL               string
+	LocalAIKey                   string
+	AIValidateOnStartup          bool
+	AIServiceQPS                 float64
+	AIServiceBurst               int
 	ResendAPIKey                 string
 	ResendNotificationEmail      string
 	PostHogAPIKey                string
 	ManagementAPIKey             string
 	ManagementAPIRefreshInterval time.Duration
+	WebhookTimeout               time.Duration
+	WebhookPollInterval          time.Duration
+	WebhookBatchSize             int
+	WebhookRetryMaxAttempts      int
+	WebhookRetryBaseBackoff      time.Duration
+	WebhookRetryMaxBackoff       time.Duration
+	EmailProvider                string
+	SMTPHost                     string
+	SMTPPort                     int
+	SMTPUsername                 string
+	SMTPPassword                 string
+	SMTPSecurity                 string
+	SMTPFrom                     string
+	SMTPReplyTo                  string
+	LocalEmailDir                string
+	EmailSendTimeout             time.Duration
+	EmailOutboxPollInterval      time.Duration
+	EmailOutboxBatchSize         int
+	EmailRetryMaxAttempts        int
+	EmailRetryBaseBackoff        time.Duration
+	EmailRetryMaxBackoff         time.Duration
 }
 
 type WorkflowConfig struct {
@@ -129,6 +162,26 @@ type WorkflowConfig struct {
 	ReportExecutorUpperBound int
 	// ExecutorUpperBound is the upper bound for the executor
 	ScreenshotExecutorUpperBound int
+	// ScreenshotExecutorMinParallelism is the number of parallel pages the executor backs off to on rate limits and timeouts
+	ScreenshotExecutorMinParallelism int
+	// ScreenshotExecutorMaxPerHost is the number of parallel pages of the same host, zero when unlimited
+	ScreenshotExecutorMaxPerHost int
+	// ExecutorRetryMaxAttempts is the maximum number of attempts at processing an item
+	ExecutorRetryMaxAttempts int
+	// ExecutorRetryBaseBackoff is the backoff before the first retry of an item
+	ExecutorRetryBaseBackoff time.Duration
+	// ExecutorRetryMaxBackoff is the maximum backoff between two attempts at processing an item
+	ExecutorRetryMaxBackoff time.Duration
+	// ExecutorRetryJitter is the fraction of the backoff randomized
+	ExecutorRetryJitter float64
+	// ExecutorRetryableErrors are the classes of errors retried by the executors
+	ExecutorRetryableErrors []string
+	// ReplicaID is the unique identifier of this replica among the replicas sharing the workflows
+	ReplicaID string
+	// LeaseTTL is the time after which the leases of a replica which stopped renewing them expire
+	LeaseTTL time.Duration
+	// PollInterval is the interval at which the leases are renewed and the running jobs are checked
+	PollInterval time.Duration
 }
 
 func Load() (*Config, error) {
@@ -203,6 +256,9 @@ func LoadServerConfig() ServerConfig {
 		// ReportCDPerMinute is set to the value of the REPORT_CD_PER_MINUTE environment variable, or 10 if the variable is not set.
 		ReportCDPerMinute: GetEnv("REPORT_CD_PER_MINUTE", 10, utils.IntParser),
 
+		// WorkspaceRefreshRequestsPerMinute is set to the value of the WORKSPACE_REFRESH_REQUESTS_PER_MINUTE environment variable, or 2 if the variable is not set.
+		WorkspaceRefreshRequestsPerMinute: GetEnv("WORKSPACE_REFRESH_REQUESTS_PER_MINUTE", 2, utils.IntParser),
+
 		// CorsAllowedOrigins is set to the value of the CORS_ALLOWED_ORIGINS environment variable, or "http://localhost:5173" if the variable is not set.
 		CorsAllowedOrigins: GetEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173", utils.StrParser),
 
@@ -269,6 +325,25 @@ func LoadServicesConfig() ServicesConfig {
 		ScreenshotServiceQPS: GetEnv("SCREENSHOT_API_QPS", 0.667, utils.Float64Parser),
 		// OpenAIKey is set to the value of the OPENAI_API_KEY environment variable, or "" if the variable is not set.
 		OpenAIKey: GetEnv("OPENAI_API_KEY", "", utils.StrParser),
+		// AIProvider is set to the value of the AI_PROVIDER environment variable, or "openai" if the variable is not set.
+		// Supported providers are "openai", "anthropic", "local" and "fake".
+		AIProvider: GetEnv("AI_PROVIDER", "openai", utils.StrParser),
+		// AIModel is set to the value of the AI_MODEL environment variable, or "" if the variable is not set.
+		// It is required for the local provider, and overrides the default model of the anthropic provider.
+		AIModel: GetEnv("AI_MODEL", "", utils.StrParser),
+		// AnthropicAPIKey is set to the value of the ANTHROPIC_API_KEY environment variable, or "" if the variable is not set.
+		AnthropicAPIKey: GetEnv("ANTHROPIC_API_KEY", "", utils.StrParser),
+		// LocalAIBaseURL is set to the value of the LOCAL_AI_BASE_URL environment variable, or the default Ollama endpoint if the variable is not set.
+		LocalAIBaseURL: GetEnv("LOCAL_AI_BASE_URL", "http://localhost:11434/v1", utils.StrParser),
+		// LocalAIKey is set to the value of the LOCAL_AI_API_KEY environment variable, or "" if the variable is not set.
+		LocalAIKey: GetEnv("LOCAL_AI_API_KEY", "", utils.StrParser),
+		// AIValidateOnStartup is set to the value of the AI_VALIDATE_ON_STARTUP environment variable, or false if the variable is not set.
+		AIValidateOnStartup: GetEnv("AI_VALIDATE_ON_STARTUP", false, utils.BoolParser),
+		// AIServiceQPS is set to the value of the AI_API_QPS environment variable, or 2 if the variable is not set.
+		// Requests to the AI provider aren't rate limited when it isn't positive.
+		AIServiceQPS: GetEnv("AI_API_QPS", 2.0, utils.Float64Parser),
+		// AIServiceBurst is set to the value of the AI_API_BURST environment variable, or 4 if the variable is not set.
+		AIServiceBurst: GetEnv("AI_API_BURST", 4, utils.IntParser),
 		// ResendAPIKey is set to the value of the RESEND_API_KEY environment variable, or "" if the variable is not set.
 		ResendAPIKey: GetEnv("RESEND_API_KEY", "", utils.StrParser),
 		// ResendNotificationEmail is set to the value of the RESEND_NOTIFICATION_EMAIL environment variable, or "" if the variable is not set.
@@ -279,6 +354,53 @@ func LoadServicesConfig() ServicesConfig {
 		ManagementAPIKey: GetEnv("MANAGEMENT_API_KEY", "", utils.StrParser),
 		// ManagementAPIRefreshInterval is set to the value of the MANAGEMENT_API_REFRESH_INTERVAL environment variable, or 5 minutes if the variable is not set.
 		ManagementAPIRefreshInterval: time.Duration(GetEnv("MANAGEMENT_API_REFRESH_INTERVAL", 5, utils.IntParser)) * time.Minute,
+		// WebhookTimeout is set to the value of the WEBHOOK_TIMEOUT environment variable, or 10 seconds if the variable is not set.
+		WebhookTimeout: time.Duration(GetEnv("WEBHOOK_TIMEOUT", 10, utils.IntParser)) * time.Second,
+		// WebhookPollInterval is set to the value of the WEBHOOK_POLL_INTERVAL environment variable, or 10 seconds if the variable is not set.
+		WebhookPollInterval: time.Duration(GetEnv("WEBHOOK_POLL_INTERVAL", 10, utils.IntParser)) * time.Second,
+		// WebhookBatchSize is set to the value of the WEBHOOK_BATCH_SIZE environment variable, or 20 if the variable is not set.
+		WebhookBatchSize: GetEnv("WEBHOOK_BATCH_SIZE", 20, utils.IntParser),
+		// WebhookRetryMaxAttempts is set to the value of the WEBHOOK_RETRY_MAX_ATTEMPTS environment variable, or 5 if the variable is not set.
+		WebhookRetryMaxAttempts: GetEnv("WEBHOOK_RETRY_MAX_ATTEMPTS", 5, utils.IntParser),
+		// WebhookRetryBaseBackoff is set to the value of the WEBHOOK_RETRY_BASE_BACKOFF environment variable, or 30 seconds if the variable is not set.
+		WebhookRetryBaseBackoff: time.Duration(GetEnv("WEBHOOK_RETRY_BASE_BACKOFF", 30, utils.IntParser)) * time.Second,
+		// WebhookRetryMaxBackoff is set to the value of the WEBHOOK_RETRY_MAX_BACKOFF environment variable, or 30 minutes if the variable is not set.
+		WebhookRetryMaxBackoff: time.Duration(GetEnv("WEBHOOK_RETRY_MAX_BACKOFF", 30, utils.IntParser)) * time.Minute,
+		// EmailProvider is set to the value of the EMAIL_PROVIDER environment variable, or "" if the variable is not set.
+		// One of local, resend or smtp. When empty, emails are written to disk in the development profile and sent with resend otherwise.
+		EmailProvider: GetEnv("EMAIL_PROVIDER", "", utils.StrParser),
+		// SMTPHost is set to the value of the SMTP_HOST environment variable, or "localhost" if the variable is not set.
+		SMTPHost: GetEnv("SMTP_HOST", "localhost", utils.StrParser),
+		// SMTPPort is set to the value of the SMTP_PORT environment variable, or 1025 if the variable is not set.
+		// 1025 is the port local mail catchers such as Mailpit listen on.
+		SMTPPort: GetEnv("SMTP_PORT", 1025, utils.IntParser),
+		// SMTPUsername is set to the value of the SMTP_USERNAME environment variable, or "" if the variable is not set.
+		// The client doesn't authenticate when it's empty.
+		SMTPUsername: GetEnv("SMTP_USERNAME", "", utils.StrParser),
+		// SMTPPassword is set to the value of the SMTP_PASSWORD environment variable, or "" if the variable is not set.
+		SMTPPassword: GetEnv("SMTP_PASSWORD", "", utils.StrParser),
+		// SMTPSecurity is set to the value of the SMTP_SECURITY environment variable, or "none" if the variable is not set.
+		// One of none, starttls or tls.
+		SMTPSecurity: GetEnv("SMTP_SECURITY", "none", utils.StrParser),
+		// SMTPFrom is set to the value of the SMTP_FROM environment variable, or "Team Byrd <hey@byrdhq.com>" if the variable is not set.
+		SMTPFrom: GetEnv("SMTP_FROM", "Team Byrd <hey@byrdhq.com>", utils.StrParser),
+		// SMTPReplyTo is set to the value of the SMTP_REPLY_TO environment variable, or "" if the variable is not set.
+		SMTPReplyTo: GetEnv("SMTP_REPLY_TO", "", utils.StrParser),
+		// LocalEmailDir is set to the value of the LOCAL_EMAIL_DIR environment variable, or "tmp/emails" if the variable is not set.
+		// Emails are written to this directory rather than sent in the development profile.
+		LocalEmailDir: GetEnv("LOCAL_EMAIL_DIR", "tmp/emails", utils.StrParser),
+		// EmailSendTimeout is set to the value of the EMAIL_SEND_TIMEOUT environment variable, or 30 seconds if the variable is not set.
+		EmailSendTimeout: time.Duration(GetEnv("EMAIL_SEND_TIMEOUT", 30, utils.IntParser)) * time.Second,
+		// EmailOutboxPollInterval is set to the value of the EMAIL_OUTBOX_POLL_INTERVAL environment variable, or 10 seconds if the variable is not set.
+		EmailOutboxPollInterval: time.Duration(GetEnv("EMAIL_OUTBOX_POLL_INTERVAL", 10, utils.IntParser)) * time.Second,
+		// EmailOutboxBatchSize is set to the value of the EMAIL_OUTBOX_BATCH_SIZE environment variable, or 20 if the variable is not set.
+		EmailOutboxBatchSize: GetEnv("EMAIL_OUTBOX_BATCH_SIZE", 20, utils.IntParser),
+		// EmailRetryMaxAttempts is set to the value of the EMAIL_RETRY_MAX_ATTEMPTS environment variable, or 5 if the variable is not set.
+		EmailRetryMaxAttempts: GetEnv("EMAIL_RETRY_MAX_ATTEMPTS", 5, utils.IntParser),
+		// EmailRetryBaseBackoff is set to the value of the EMAIL_RETRY_BASE_BACKOFF environment variable, or 1 minute if the variable is not set.
+		EmailRetryBaseBackoff: time.Duration(GetEnv("EMAIL_RETRY_BASE_BACKOFF", 60, utils.IntParser)) * time.Second,
+		// EmailRetryMaxBackoff is set to the value of the EMAIL_RETRY_MAX_BACKOFF environment variable, or 1 hour if the variable is not set.
+		EmailRetryMaxBackoff: time.Duration(GetEnv("EMAIL_RETRY_MAX_BACKOFF", 60, utils.IntParser)) * time.Minute,
 	}
 }
 
@@ -302,11 +424,31 @@ func LoadWorkflowConfig() WorkflowConfig {
 		ScreenshotExecutorLowerBound: GetEnv("SCREENSHOT_EXECUTOR_LOWER_BOUND", 10, utils.IntParser),
 		// ExecutorUpperBound is set to the value of the EXECUTOR_UPPER_BOUND environment variable, or 20 seconds if the variable is not set.
 		ScreenshotExecutorUpperBound: GetEnv("SCREENSHOT_EXECUTOR_UPPER_BOUND", 120, utils.IntParser),
+		// ScreenshotExecutorMinParallelism is set to the value of the SCREENSHOT_EXECUTOR_MIN_PARALLELISM environment variable, or 1 if the variable is not set.
+		ScreenshotExecutorMinParallelism: GetEnv("SCREENSHOT_EXECUTOR_MIN_PARALLELISM", 1, utils.IntParser),
+		// ScreenshotExecutorMaxPerHost is set to the value of the SCREENSHOT_EXECUTOR_MAX_PER_HOST environment variable, or 2 if the variable is not set.
+		ScreenshotExecutorMaxPerHost: GetEnv("SCREENSHOT_EXECUTOR_MAX_PER_HOST", 2, utils.IntParser),
 		// ReportExecutorParallelism is set to the value of the REPORT_EXECUTOR_PARALLELISM environment variable, or 10 if the variable is not set.
 		ReportExecutorParallelism: GetEnv("REPORT_EXECUTOR_PARALLELISM", 10, utils.IntParser),
 		// ReportExecutorLowerBound is set to the value of the REPORT_EXECUTOR_LOWER_BOUND environment variable, or 10 seconds if the variable is not set.
 		ReportExecutorLowerBound: GetEnv("REPORT_EXECUTOR_LOWER_BOUND", 10, utils.IntParser),
 		// ReportExecutorUpperBound is set to the value of the REPORT_EXECUTOR_UPPER_BOUND environment variable, or 20 seconds if the variable is not set.
 		ReportExecutorUpperBound: GetEnv("REPORT_EXECUTOR_UPPER_BOUND", 120, utils.IntParser),
+		// ExecutorRetryMaxAttempts is set to the value of the EXECUTOR_RETRY_MAX_ATTEMPTS environment variable, or 3 if the variable is not set.
+		ExecutorRetryMaxAttempts: GetEnv("EXECUTOR_RETRY_MAX_ATTEMPTS", 3, utils.IntParser),
+		// ExecutorRetryBaseBackoff is set to the value of the EXECUTOR_RETRY_BASE_BACKOFF environment variable, or 2 seconds if the variable is not set.
+		ExecutorRetryBaseBackoff: time.Duration(GetEnv("EXECUTOR_RETRY_BASE_BACKOFF", 2, utils.IntParser)) * time.Second,
+		// ExecutorRetryMaxBackoff is set to the value of the EXECUTOR_RETRY_MAX_BACKOFF environment variable, or 30 seconds if the variable is not set.
+		ExecutorRetryMaxBackoff: time.Duration(GetEnv("EXECUTOR_RETRY_MAX_BACKOFF", 30, utils.IntParser)) * time.Second,
+		// ExecutorRetryJitter is set to the value of the EXECUTOR_RETRY_JITTER environment variable, or 0.2 if the variable is not set.
+		ExecutorRetryJitter: GetEnv("EXECUTOR_RETRY_JITTER", 0.2, utils.Float64Parser),
+		// ExecutorRetryableErrors is set to the value of the EXECUTOR_RETRYABLE_ERRORS environment variable, or every transient class of errors if the variable is not set.
+		ExecutorRetryableErrors: GetEnv("EXECUTOR_RETRYABLE_ERRORS", []string{"timeout", "rate_limited", "server", "network"}, utils.StrSliceParser),
+		// ReplicaID is set to the value of the REPLICA_ID environment variable, or a unique identifier derived from the hostname if the variable is not set.
+		ReplicaID: GetEnv("REPLICA_ID", "", utils.StrParser),
+		// LeaseTTL is set to the value of the WORKFLOW_LEASE_TTL environment variable, or 30 seconds if the variable is not set.
+		LeaseTTL: time.Duration(GetEnv("WORKFLOW_LEASE_TTL", 30, utils.IntParser)) * time.Second,
+		// PollInterval is set to the value of the WORKFLOW_POLL_INTERVAL environment variable, or 10 seconds if the variable is not set.
+		PollInterval: time.Duration(GetEnv("WORKFLOW_POLL_INTERVAL", 10, utils.IntParser)) * time.Second,
 	}
 }
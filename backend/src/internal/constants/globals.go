@@ -1,7 +0,0 @@
-// ./src/internal/constants/globals.go
-package constants
-
-const (
-	FirstRunID = "1"
-	LastRunID  = "2"
-)
@@ -4,10 +4,42 @@ package email
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 )
 
+// ErrBounced is returned when the email is rejected for its recipients, sending it again won't help
+var ErrBounced = errors.New("email bounced")
+
+// RejectedRecipientsError is returned when the email was sent, but some of its recipients were rejected
+type RejectedRecipientsError struct {
+	// Recipients are the recipients the email wasn't sent to
+	Recipients []string
+
+	// Err is why the recipients were rejected
+	Err error
+}
+
+func (e *RejectedRecipientsError) Error() string {
+	return fmt.Sprintf("rejected recipients %s: %v", strings.Join(e.Recipients, ", "), e.Err)
+}
+
+func (e *RejectedRecipientsError) Unwrap() error {
+	return e.Err
+}
+
 type EmailClient interface {
 	Send(ctx context.Context, email models.Email) error
 }
+
+// DeliveryTracker is implemented by the email clients able to identify the emails they send, and to tell whether they bounced
+type DeliveryTracker interface {
+	// SendTracked sends the email, and returns the identifier the provider assigned to it
+	SendTracked(ctx context.Context, email models.Email) (string, error)
+
+	// Bounced returns whether the email with the identifier bounced
+	Bounced(ctx context.Context, messageID string) (bool, error)
+}
@@ -2,18 +2,40 @@ package email
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
 )
 
+// compile time check if the interfaces are implemented
+var (
+	_ EmailClient     = (*localEmailClient)(nil)
+	_ DeliveryTracker = (*localEmailClient)(nil)
+)
+
+// localSender is the sender of the emails written to disk
+const localSender = "Team Byrd <notifications@localhost>"
+
+// localEmailClient writes the emails to a directory rather than sending them
+// Each email is a .eml file, which can be opened with most email clients
 type localEmailClient struct {
-	logger *logger.Logger
+	outputDir string
+	logger    *logger.Logger
 }
 
-func NewLocalEmailClient(ctx context.Context, logger *logger.Logger) (EmailClient, error) {
+func NewLocalEmailClient(ctx context.Context, outputDir string, logger *logger.Logger) (EmailClient, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email output directory: %w", err)
+	}
+
 	lc := localEmailClient{
+		outputDir: outputDir,
 		logger: logger.WithFields(map[string]any{
 			"module": "local_email_client",
 		}),
@@ -23,7 +45,40 @@ func NewLocalEmailClient(ctx context.Context, logger *logger.Logger) (EmailClien
 }
 
 func (lc *localEmailClient) Send(ctx context.Context, email models.Email) error {
-	// Mock email sending latency
-	time.Sleep(1 * time.Second)
-	return nil
+	_, err := lc.SendTracked(ctx, email)
+	return err
+}
+
+// SendTracked writes the email to the output directory, the name of the file identifies it
+func (lc *localEmailClient) SendTracked(ctx context.Context, email models.Email) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("%s-%s", now.UTC().Format("20060102T150405"), uuid.New())
+	messageID := id + ".eml"
+
+	msg, err := formatMessage(messageHeader{
+		MessageID: newMessageID(localSender, id),
+		From:      localSender,
+		Date:      now,
+	}, email)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(lc.outputDir, messageID)
+	if err := os.WriteFile(path, msg, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write email: %w", err)
+	}
+
+	lc.logger.Debug("wrote email", zap.String("path", path), zap.Strings("to", email.To), zap.String("subject", email.EmailSubject))
+
+	return messageID, nil
+}
+
+// Bounced is always false, the emails written to disk never bounce
+func (lc *localEmailClient) Bounced(ctx context.Context, messageID string) (bool, error) {
+	return false, nil
 }
@@ -0,0 +1,160 @@
+// ./src/internal/email/message.go
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// maxBase64LineLength is the length of the lines of base64 encoded attachments, as required by RFC 2045
+const maxBase64LineLength = 76
+
+// messageHeader are the headers of a message, besides the ones describing its body
+type messageHeader struct {
+	// MessageID identifies the message, without the angle brackets
+	MessageID string
+
+	// From is the sender of the message
+	From string
+
+	// ReplyTo is the address the replies are sent to, omitted when empty
+	ReplyTo string
+
+	// Date is when the message was sent
+	Date time.Time
+}
+
+// formatMessage formats the email as an RFC 5322 message
+// The body is sent as is when there are no attachments, and as the first part of a multipart message otherwise
+func formatMessage(header messageHeader, email models.Email) ([]byte, error) {
+	var msg bytes.Buffer
+	writeHeader(&msg, "Message-ID", "<"+header.MessageID+">")
+	writeHeader(&msg, "Date", header.Date.Format(time.RFC1123Z))
+	writeHeader(&msg, "From", header.From)
+	writeHeader(&msg, "To", strings.Join(email.To, ", "))
+	if header.ReplyTo != "" {
+		writeHeader(&msg, "Reply-To", header.ReplyTo)
+	}
+	writeHeader(&msg, "Subject", mime.QEncoding.Encode("utf-8", email.EmailSubject))
+	writeHeader(&msg, "MIME-Version", "1.0")
+
+	if len(email.Attachments) == 0 {
+		writeHeader(&msg, "Content-Type", bodyContentType(email.EmailFormat))
+		writeHeader(&msg, "Content-Transfer-Encoding", "quoted-printable")
+		msg.WriteString("\r\n")
+		if err := writeQuotedPrintable(&msg, email.EmailContent); err != nil {
+			return nil, err
+		}
+		return msg.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&msg)
+	writeHeader(&msg, "Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": parts.Boundary()}))
+	msg.WriteString("\r\n")
+
+	body, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {bodyContentType(email.EmailFormat)},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email body: %w", err)
+	}
+	if err := writeQuotedPrintable(body, email.EmailContent); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range email.Attachments {
+		if attachment.Filename == "" {
+			return nil, errors.New("email attachment has no filename")
+		}
+
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachmentContentType(attachment)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email attachment: %w", err)
+		}
+		if _, err := part.Write(wrapLines(base64.StdEncoding.EncodeToString(attachment.Content), maxBase64LineLength)); err != nil {
+			return nil, fmt.Errorf("failed to write email attachment: %w", err)
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close email: %w", err)
+	}
+
+	return msg.Bytes(), nil
+}
+
+// newMessageID generates an identifier for a message sent from the address
+func newMessageID(from string, id string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+			domain = address.Address[at+1:]
+		}
+	}
+	return id + "@" + domain
+}
+
+func writeHeader(msg *bytes.Buffer, key, value string) {
+	// Line breaks in a value would start another header
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	fmt.Fprintf(msg, "%s: %s\r\n", key, value)
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	return nil
+}
+
+func bodyContentType(format models.EmailFormat) string {
+	if format == models.EmailFormatHTML {
+		return "text/html; charset=UTF-8"
+	}
+	return "text/plain; charset=UTF-8"
+}
+
+// attachmentContentType returns the media type of the attachment, guessed from its name when it isn't set
+func attachmentContentType(attachment models.EmailAttachment) string {
+	if attachment.ContentType != "" {
+		return attachment.ContentType
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(attachment.Filename)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// wrapLines breaks the text into lines of the length
+func wrapLines(text string, length int) []byte {
+	var wrapped bytes.Buffer
+	for len(text) > length {
+		wrapped.WriteString(text[:length])
+		wrapped.WriteString("\r\n")
+		text = text[length:]
+	}
+	wrapped.WriteString(text)
+	return wrapped.Bytes()
+}
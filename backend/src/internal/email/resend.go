@@ -9,6 +9,15 @@ import (
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 )
 
+// compile time check if the interfaces are implemented
+var (
+	_ EmailClient     = (*resendClient)(nil)
+	_ DeliveryTracker = (*resendClient)(nil)
+)
+
+// resendBouncedEvent is the last event of the emails bounced by their recipients
+const resendBouncedEvent = "bounced"
+
 type resendClient struct {
 	client            *resend.Client
 	logger            *logger.Logger
@@ -29,6 +38,11 @@ func NewResendClient(ctx context.Context, resendKey, notificationEmail string, l
 }
 
 func (rc *resendClient) Send(ctx context.Context, email models.Email) error {
+	_, err := rc.SendTracked(ctx, email)
+	return err
+}
+
+func (rc *resendClient) SendTracked(ctx context.Context, email models.Email) (string, error) {
 	params := &resend.SendEmailRequest{
 		From:    fmt.Sprintf("Team Byrd <%s>", rc.notificationEmail),
 		To:      email.To,
@@ -44,10 +58,27 @@ func (rc *resendClient) Send(ctx context.Context, email models.Email) error {
 		params.Text = email.EmailContent
 	}
 
-	_, err := rc.client.Emails.Send(params)
+	for _, attachment := range email.Attachments {
+		params.Attachments = append(params.Attachments, &resend.Attachment{
+			Content:     attachment.Content,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+		})
+	}
+
+	sent, err := rc.client.Emails.SendWithContext(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	return sent.Id, nil
+}
+
+func (rc *resendClient) Bounced(ctx context.Context, messageID string) (bool, error) {
+	sent, err := rc.client.Emails.GetWithContext(ctx, messageID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	return sent.LastEvent == resendBouncedEvent, nil
 }
@@ -0,0 +1,272 @@
+// ./src/internal/email/smtp.go
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// compile time check if the interfaces are implemented
+var (
+	_ EmailClient     = (*smtpClient)(nil)
+	_ DeliveryTracker = (*smtpClient)(nil)
+)
+
+// SMTPSecurity is how the connection to the SMTP server is secured
+type SMTPSecurity string
+
+const (
+	// SMTPSecurityNone leaves the connection in plain text, as expected by local mail catchers
+	SMTPSecurityNone SMTPSecurity = "none"
+
+	// SMTPSecuritySTARTTLS upgrades the connection with STARTTLS, failing when the server doesn't support it
+	SMTPSecuritySTARTTLS SMTPSecurity = "starttls"
+
+	// SMTPSecurityTLS connects over implicit TLS
+	SMTPSecurityTLS SMTPSecurity = "tls"
+)
+
+// smtpTimeout bounds the session with the SMTP server when the context has no deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPConfig is the configuration of the SMTP client
+type SMTPConfig struct {
+	// Host is the host of the SMTP server
+	Host string
+
+	// Port is the port of the SMTP server
+	Port int
+
+	// Username authenticates the client, no authentication is made when empty
+	Username string
+
+	// Password authenticates the client along with the username
+	Password string
+
+	// Security is how the connection is secured
+	Security SMTPSecurity
+
+	// From is the sender of the emails, e.g. "Team Byrd <hey@byrdhq.com>"
+	From string
+
+	// ReplyTo is the address the replies are sent to, the sender when empty
+	ReplyTo string
+}
+
+type smtpClient struct {
+	config SMTPConfig
+
+	// sender is the address of the sender, used as the envelope sender
+	sender string
+
+	logger *logger.Logger
+}
+
+func NewSMTPClient(ctx context.Context, config SMTPConfig, logger *logger.Logger) (EmailClient, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if config.Port <= 0 {
+		return nil, errors.New("smtp port must be positive")
+	}
+
+	switch config.Security {
+	case SMTPSecurityNone, SMTPSecuritySTARTTLS, SMTPSecurityTLS:
+	default:
+		return nil, fmt.Errorf("unknown smtp security %q, expected one of none, starttls or tls", config.Security)
+	}
+
+	sender, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp sender: %w", err)
+	}
+
+	if config.ReplyTo != "" {
+		if _, err := mail.ParseAddress(config.ReplyTo); err != nil {
+			return nil, fmt.Errorf("invalid smtp reply-to address: %w", err)
+		}
+	}
+
+	sc := smtpClient{
+		config: config,
+		sender: sender.Address,
+		logger: logger.WithFields(map[string]any{
+			"module": "smtp_client",
+		}),
+	}
+
+	return &sc, nil
+}
+
+func (sc *smtpClient) Send(ctx context.Context, email models.Email) error {
+	_, err := sc.SendTracked(ctx, email)
+	return err
+}
+
+// SendTracked sends the email over SMTP, the Message-ID header identifies it
+// Recipients rejected by the server are skipped and returned in a RejectedRecipientsError, the email bounces when all of them are
+func (sc *smtpClient) SendTracked(ctx context.Context, email models.Email) (string, error) {
+	messageID := newMessageID(sc.config.From, uuid.New().String())
+	msg, err := formatMessage(messageHeader{
+		MessageID: messageID,
+		From:      sc.config.From,
+		ReplyTo:   sc.config.ReplyTo,
+		Date:      time.Now(),
+	}, email)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := sc.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	if err := client.Mail(sc.sender); err != nil {
+		return "", fmt.Errorf("smtp server rejected the sender: %w", err)
+	}
+
+	accepted := 0
+	var rejected []string
+	var rejections []error
+	for _, recipient := range email.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			sc.logger.Warn("skipping invalid recipient", zap.String("recipient", recipient), zap.Error(err))
+			rejected = append(rejected, recipient)
+			rejections = append(rejections, err)
+			continue
+		}
+
+		if err := client.Rcpt(address.Address); err != nil {
+			if !isPermanentFailure(err) {
+				return "", fmt.Errorf("smtp server failed to accept recipient: %w", err)
+			}
+			sc.logger.Warn("smtp server rejected recipient", zap.String("recipient", recipient), zap.Error(err))
+			rejected = append(rejected, recipient)
+			rejections = append(rejections, err)
+			continue
+		}
+		accepted++
+	}
+	if accepted == 0 {
+		return "", fmt.Errorf("%w: smtp server rejected every recipient", ErrBounced)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return "", fmt.Errorf("smtp server rejected the message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return "", fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("smtp server rejected the message: %w", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		// The message was accepted before quitting, failing to close the session doesn't undo it
+		sc.logger.Debug("failed to quit smtp session", zap.Error(err))
+	}
+
+	if len(rejected) > 0 {
+		return messageID, &RejectedRecipientsError{Recipients: rejected, Err: errors.Join(rejections...)}
+	}
+	return messageID, nil
+}
+
+// Bounced is always false, bounces are reported to the mailbox of the sender rather than to the client
+func (sc *smtpClient) Bounced(ctx context.Context, messageID string) (bool, error) {
+	return false, nil
+}
+
+// connect opens a session with the SMTP server, secured and authenticated according to the configuration
+// The session is bound to the deadline of the context, if any
+func (sc *smtpClient) connect(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(sc.config.Host, strconv.Itoa(sc.config.Port))
+	tlsConfig := &tls.Config{ServerName: sc.config.Host}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	dialCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if sc.config.Security == SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(dialCtx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(dialCtx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, sc.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+
+	if err := sc.secure(client, tlsConfig); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// secure upgrades the session with STARTTLS when configured, and authenticates it when a username is set
+func (sc *smtpClient) secure(client *smtp.Client, tlsConfig *tls.Config) error {
+	if sc.config.Security == SMTPSecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server doesn't support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if sc.config.Username == "" {
+		return nil
+	}
+
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return errors.New("smtp server doesn't support authentication")
+	}
+
+	// Plain authentication is refused over plain text connections, unless the server is local
+	auth := smtp.PlainAuth("", sc.config.Username, sc.config.Password, sc.config.Host)
+	if err := client.Auth(auth); err != nil {
+		return fmt.Errorf("failed to authenticate with smtp server: %w", err)
+	}
+
+	return nil
+}
+
+// isPermanentFailure returns whether the server rejected the command for good, with a 5xx reply
+func isPermanentFailure(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500 && protoErr.Code < 600
+}
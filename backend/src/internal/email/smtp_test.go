@@ -0,0 +1,163 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// mailCatcher is a minimal SMTP server keeping the messages it receives, rejecting the recipients of a domain
+type mailCatcher struct {
+	listener       net.Listener
+	rejectedDomain string
+	messages       chan string
+}
+
+func newMailCatcher(t *testing.T, rejectedDomain string) *mailCatcher {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mc := &mailCatcher{listener: listener, rejectedDomain: rejectedDomain, messages: make(chan string, 1)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go mc.serve(conn)
+		}
+	}()
+	return mc
+}
+
+func (mc *mailCatcher) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	_ = text.PrintfLine("220 localhost ready")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case command == "EHLO" || command == "HELO":
+			_ = text.PrintfLine("250 localhost")
+		case command == "RCPT" && strings.HasSuffix(strings.TrimSuffix(line, ">"), "@"+mc.rejectedDomain):
+			_ = text.PrintfLine("550 mailbox unavailable")
+		case command == "MAIL" || command == "RCPT":
+			_ = text.PrintfLine("250 ok")
+		case command == "DATA":
+			_ = text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mc.messages <- string(data)
+			_ = text.PrintfLine("250 queued")
+		case command == "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPClientSendsToMailCatcher(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	catcher := newMailCatcher(t, "rejected.test")
+	port := catcher.listener.Addr().(*net.TCPAddr).Port
+
+	client, err := NewSMTPClient(context.Background(), SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Security: SMTPSecurityNone,
+		From:     "Team Byrd <hey@byrd.test>",
+		ReplyTo:  "support@byrd.test",
+	}, log)
+	if err != nil {
+		t.Fatalf("failed to create smtp client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	messageID, err := client.(DeliveryTracker).SendTracked(ctx, models.Email{
+		To:           []string{"member@byrd.test", "gone@rejected.test"},
+		EmailFormat:  models.EmailFormatHTML,
+		EmailSubject: "Weekly Roundup for Acme",
+		EmailContent: "<h1>Changes</h1>",
+		Attachments: []models.EmailAttachment{
+			{Filename: "report.csv", Content: []byte("category,changes\npricing,2\n")},
+		},
+	})
+	var rejected *RejectedRecipientsError
+	if !errors.As(err, &rejected) || messageID == "" {
+		t.Fatalf("expected the email to be sent to the accepted recipient, got %v", err)
+	}
+	if len(rejected.Recipients) != 1 || rejected.Recipients[0] != "gone@rejected.test" {
+		t.Errorf("expected the rejected recipient to be returned, got %v", rejected.Recipients)
+	}
+
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(<-catcher.messages)))
+	if err != nil {
+		t.Fatalf("failed to parse the message: %v", err)
+	}
+	if got := msg.Header.Get("Reply-To"); got != "support@byrd.test" {
+		t.Errorf("expected the reply-to header to be set, got %q", got)
+	}
+	if got := msg.Header.Get("Subject"); got != "Weekly Roundup for Acme" {
+		t.Errorf("expected the subject to be kept, got %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("expected a multipart message, got %q (%v)", mediaType, err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var filenames []string
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			break
+		}
+		if part.FileName() != "" {
+			filenames = append(filenames, part.FileName())
+		}
+	}
+	if len(filenames) != 1 || filenames[0] != "report.csv" {
+		t.Errorf("expected the attachment to be sent, got %v", filenames)
+	}
+
+	// Every recipient being rejected bounces the email, so that it isn't retried
+	err = client.Send(ctx, models.Email{To: []string{"gone@rejected.test"}, EmailSubject: "Bounced", EmailContent: "text"})
+	if !errors.Is(err, ErrBounced) {
+		t.Fatalf("expected the email to bounce, got %v", err)
+	}
+
+	// The configuration is checked up front
+	if _, err := NewSMTPClient(context.Background(), SMTPConfig{Host: "127.0.0.1", Port: port, Security: "ssl", From: "hey@byrd.test"}, log); err == nil {
+		t.Fatal("expected an unknown security to be rejected")
+	}
+}
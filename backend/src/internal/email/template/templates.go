@@ -14,12 +14,16 @@ const (
 
 	// -- weekly roundup templates --
 	WeeklyRoundupTemplate TemplateName = "weekly_roundup"
+
+	// -- weekly digest templates --
+	WeeklyDigestTemplate TemplateName = "weekly_digest"
 )
 
 var templates = map[TemplateName]Template{
 	WorkspaceInvitePendingTemplate:  WorkspaceInvitePending,
 	WorkspaceInviteAcceptedTemplate: WorkspaceInviteAccepted,
 	WeeklyRoundupTemplate:           WeeklyRoundup,
+	WeeklyDigestTemplate:            WeeklyDigest,
 }
 
 // registerDefaultTemplates pre-registers all the default email templates
@@ -83,4 +87,7 @@ var (
 
 	// WeeklyRoundupTemplate is the template for a weekly roundup
 	WeeklyRoundup = &SectionedTemplate{}
+
+	// WeeklyDigestTemplate is the template for a weekly digest of every competitor of a workspace
+	WeeklyDigest = &DigestTemplate{}
 )
@@ -23,6 +23,18 @@ type CreatePageRequest struct {
 	// DiffProfile is the profile used to diff the page
 	// This is optional and defaults to an default diff profile
 	DiffProfile []string `json:"diff_profile,omitempty" validate:"omitempty,dive,oneof=branding customers integration product pricing partnerships messaging" default:"[\"branding\", \"customers\", \"integration\", \"product\", \"pricing\", \"partnerships\", \"messaging\"]"`
+
+	// CheckInterval is the interval between checks of the page, in minutes
+	// This is optional and defaults to a weekly check
+	CheckInterval int `json:"check_interval,omitempty" validate:"omitempty,min=60,max=43200"`
+
+	// ContentSelectors narrow down the content of the page before it is diffed
+	// This is optional and defaults to the whole page
+	ContentSelectors *models.ContentSelectors `json:"content_selectors,omitempty"`
+
+	// NoiseRules suppress dynamic content of the page before it is diffed
+	// This is optional and applies on top of the rules of the workspace
+	NoiseRules []models.NoiseRule `json:"noise_rules,omitempty" validate:"omitempty,max=50,dive"`
 }
 
 // ToProps converts the request to page properties.
@@ -31,6 +43,7 @@ type CreatePageRequest struct {
 // If the title is not provided, it fetches the title from the URL.
 // If the capture profile is not provided, it uses the default capture profile.
 // If the diff profile is not provided, it uses the default diff profile.
+// If the check interval is not provided, it uses the default check interval.
 func (r *CreatePageRequest) ToProps() (models.PageProps, error) {
 	// Get the default capture profile
 	defaults := models.GetDefaultCaptureProfile()
@@ -56,20 +69,30 @@ func (r *CreatePageRequest) ToProps() (models.PageProps, error) {
 		r.DiffProfile = models.GetDefaultDiffProfile()
 	}
 
+	if r.CheckInterval == 0 {
+		r.CheckInterval = models.DefaultCheckInterval
+	}
+
 	return models.PageProps{
-		Title:          r.Title,
-		URL:            r.URL,
-		CaptureProfile: r.CaptureProfile,
-		DiffProfile:    r.DiffProfile,
+		Title:            r.Title,
+		URL:              r.URL,
+		CaptureProfile:   r.CaptureProfile,
+		DiffProfile:      r.DiffProfile,
+		CheckInterval:    r.CheckInterval,
+		ContentSelectors: r.ContentSelectors,
+		NoiseRules:       r.NoiseRules,
 	}, nil
 }
 
 // UpdatePageRequest is the request to update a page
 type UpdatePageRequest struct {
-	Title          *string                `json:"title,omitempty"`
-	URL            *string                `json:"url,omitempty" validate:"omitempty,url"`
-	CaptureProfile *models.CaptureProfile `json:"capture_profile,omitempty"`
-	DiffProfile    []string               `json:"diff_profile,omitempty" validate:"omitempty,dive,oneof=branding customers integration product pricing partnerships messaging"`
+	Title            *string                  `json:"title,omitempty"`
+	URL              *string                  `json:"url,omitempty" validate:"omitempty,url"`
+	CaptureProfile   *models.CaptureProfile   `json:"capture_profile,omitempty"`
+	DiffProfile      []string                 `json:"diff_profile,omitempty" validate:"omitempty,dive,oneof=branding customers integration product pricing partnerships messaging"`
+	CheckInterval    *int                     `json:"check_interval,omitempty" validate:"omitempty,min=60,max=43200"`
+	ContentSelectors *models.ContentSelectors `json:"content_selectors,omitempty"`
+	NoiseRules       []models.NoiseRule       `json:"noise_rules,omitempty" validate:"omitempty,max=50,dive"`
 }
 
 // ToProps converts the request to page properties.
@@ -78,6 +101,9 @@ type UpdatePageRequest struct {
 // If the title is not provided, it fetches the title from the URL.
 // If the capture profile is not provided, it does not change the capture profile.
 // If the diff profile is not provided, it does not change the diff profile.
+// If the check interval is not provided, it does not change the check interval.
+// If the content selectors are not provided, they are not changed.
+// If the noise rules are not provided, they are not changed.
 func (r *UpdatePageRequest) ToProps() (models.PageProps, error) {
 	props := models.PageProps{}
 
@@ -115,6 +141,28 @@ func (r *UpdatePageRequest) ToProps() (models.PageProps, error) {
 		props.DiffProfile = r.DiffProfile
 	}
 
+	// If the check interval is provided, set it
+	if r.CheckInterval != nil {
+		props.CheckInterval = *r.CheckInterval
+	}
+
+	// If the content selectors are provided, set them
+	if r.ContentSelectors != nil {
+		props.ContentSelectors = r.ContentSelectors
+	}
+
+	// If the noise rules are provided, set them
+	if r.NoiseRules != nil {
+		props.NoiseRules = r.NoiseRules
+	}
+
 	// Return the page properties
 	return props, nil
 }
+
+// DryRunContentSelectorsRequest is the request to try out content selectors on a page
+type DryRunContentSelectorsRequest struct {
+	// ContentSelectors are the selectors to try out
+	// This is optional and defaults to the selectors of the page
+	ContentSelectors *models.ContentSelectors `json:"content_selectors,omitempty"`
+}
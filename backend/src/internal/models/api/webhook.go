@@ -0,0 +1,34 @@
+// ./src/internal/models/api/webhook.go
+package models
+
+import (
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// WebhookRequest is the request to create a webhook for a workspace, or to replace its settings
+type WebhookRequest struct {
+	// URL is the endpoint the events are posted to
+	URL string `json:"url" validate:"required,url,max=2048"`
+
+	// EventTypes are the events to be sent, all of them when empty
+	EventTypes []models.WebhookEventType `json:"event_types" validate:"omitempty,unique,dive,oneof=page.changed report.created job.failed"`
+
+	// Active is false to pause the webhook
+	Active *bool `json:"active" default:"true"`
+}
+
+// ToProps converts the request to webhook properties.
+func (r *WebhookRequest) ToProps() models.WebhookProps {
+	props := models.WebhookProps{
+		URL:        r.URL,
+		EventTypes: r.EventTypes,
+		Active:     true,
+	}
+	if r.Active != nil {
+		props.Active = *r.Active
+	}
+	if props.EventTypes == nil {
+		props.EventTypes = []models.WebhookEventType{}
+	}
+	return props
+}
@@ -0,0 +1,52 @@
+// ./src/internal/models/api/workflow.go
+package models
+
+import (
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// RetryJobFailuresRequest is the request to retry the failures of a job
+type RetryJobFailuresRequest struct {
+	// ItemIDs are the items to retry, every pending failure of the job is retried when empty
+	ItemIDs []uuid.UUID `json:"item_ids" validate:"omitempty,max=1000"`
+}
+
+// StartWorkflowRequest is the request to start a job, limited to the items within the scope
+type StartWorkflowRequest struct {
+	// WorkspaceIDs limits the job to the items of these workspaces
+	WorkspaceIDs []uuid.UUID `json:"workspace_ids" validate:"omitempty,max=1000"`
+
+	// CompetitorIDs limits the job to the items of these competitors
+	CompetitorIDs []uuid.UUID `json:"competitor_ids" validate:"omitempty,max=1000"`
+
+	// PageIDs limits the job to these pages
+	PageIDs []uuid.UUID `json:"page_ids" validate:"omitempty,max=1000"`
+}
+
+// Scope returns the scope of the job, empty when the job covers every item
+func (r StartWorkflowRequest) Scope() models.JobScope {
+	return models.JobScope{
+		WorkspaceIDs:  r.WorkspaceIDs,
+		CompetitorIDs: r.CompetitorIDs,
+		PageIDs:       r.PageIDs,
+	}
+}
+
+// StartPipelineRequest is the request to start a pipeline run, limited to the items within the scope
+type StartPipelineRequest struct {
+	// Stages are the workflows run one after the other, the default pipeline is run when empty
+	Stages []models.WorkflowType `json:"stages" validate:"omitempty,max=3,dive,oneof=screenshot report dispatch"`
+
+	// StartWorkflowRequest holds the scope shared by the jobs of every stage
+	StartWorkflowRequest
+}
+
+// RefreshWorkspaceRequest is the request to refresh the pages of a workspace on demand
+type RefreshWorkspaceRequest struct {
+	// CompetitorIDs limits the refresh to the pages of these competitors
+	CompetitorIDs []uuid.UUID `json:"competitor_ids" validate:"omitempty,max=100"`
+
+	// PageIDs limits the refresh to these pages
+	PageIDs []uuid.UUID `json:"page_ids" validate:"omitempty,max=100"`
+}
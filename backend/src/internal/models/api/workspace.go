@@ -2,6 +2,7 @@
 package models
 
 import (
+	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/pkg/utils"
 )
@@ -47,3 +48,40 @@ func (r *WorkspaceUpdateRequest) ToProps() models.WorkspaceProps {
 	}
 	return props
 }
+
+// WorkspaceNoiseRulesRequest is the request to replace the noise rules of a workspace
+type WorkspaceNoiseRulesRequest struct {
+	// NoiseRules suppress dynamic content from the pages of the workspace before they are diffed
+	NoiseRules []models.NoiseRule `json:"noise_rules" validate:"required,max=50,dive"`
+}
+
+// WorkspaceReportModeRequest is the request to update how the reports of a workspace are dispatched to its members
+type WorkspaceReportModeRequest struct {
+	// ReportMode is either a single digest of every competitor, or a report per competitor
+	ReportMode models.ReportMode `json:"report_mode" validate:"required,oneof=digest individual"`
+}
+
+// NotificationSubscriptionRequest is the request to replace the notification settings of the current member of a workspace
+type NotificationSubscriptionRequest struct {
+	// CompetitorIDs are the competitors to be notified about, all of them when empty
+	CompetitorIDs []uuid.UUID `json:"competitor_ids" validate:"omitempty,max=100"`
+
+	// Categories are the categories of changes to be notified about, all of them when empty
+	Categories []string `json:"categories" validate:"omitempty,dive,oneof=branding customers integration product pricing partnerships messaging"`
+
+	// Channels are the channels to be notified over, an empty list mutes the notifications
+	Channels []models.NotificationChannel `json:"channels" validate:"omitempty,unique,dive,oneof=email slack" default:"[\"email\"]"`
+
+	// Frequency is how often to be notified
+	Frequency models.NotificationFrequency `json:"frequency" validate:"required,oneof=instant daily weekly" default:"instant"`
+}
+
+// ToProps converts the request to notification subscription properties.
+func (r *NotificationSubscriptionRequest) ToProps() models.NotificationSubscriptionProps {
+	return models.NotificationSubscriptionProps{
+		CompetitorIDs: r.CompetitorIDs,
+		Categories:    r.Categories,
+		Channels:      r.Channels,
+		Frequency:     r.Frequency,
+	}
+}
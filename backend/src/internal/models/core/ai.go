@@ -79,6 +79,35 @@ func (d *DynamicChanges) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &d.Fields)
 }
 
+// HasChanges returns true if any field holds a change
+func (d *DynamicChanges) HasChanges() bool {
+	if d == nil {
+		return false
+	}
+
+	for _, value := range d.Fields {
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			if len(v) > 0 {
+				return true
+			}
+		case []string:
+			if len(v) > 0 {
+				return true
+			}
+		case string:
+			if v != "" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
 // Pretty prints the changes in a markdown-like format
 func (d *DynamicChanges) Pretty() {
 	if d == nil {
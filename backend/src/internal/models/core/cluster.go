@@ -0,0 +1,47 @@
+// ./src/internal/models/core/cluster.go
+package models
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClusterConfig is the configuration of a replica among the replicas sharing the workflows
+type ClusterConfig struct {
+	// ReplicaID is the unique identifier of the replica, it identifies the holder of the leases
+	ReplicaID string
+
+	// LeaseTTL is the time after which the leases of a replica which stopped renewing them expire
+	// A replica which died releases its jobs, items and schedules to the other replicas once its leases expire
+	LeaseTTL time.Duration
+
+	// PollInterval is the interval at which a replica renews its leases and looks for jobs to run
+	// It is kept under a third of the LeaseTTL, so that a lease is renewed twice before it expires
+	PollInterval time.Duration
+}
+
+// NewReplicaID creates a unique identifier for the replica, prefixed by its hostname when available
+func NewReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return uuid.NewString()
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+// Normalize fills in the replica ID when missing, and keeps the poll interval under a third of the lease TTL
+func (c ClusterConfig) Normalize() ClusterConfig {
+	if c.ReplicaID == "" {
+		c.ReplicaID = NewReplicaID()
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = 30 * time.Second
+	}
+	if c.PollInterval <= 0 || c.PollInterval > c.LeaseTTL/3 {
+		c.PollInterval = c.LeaseTTL / 3
+	}
+	return c
+}
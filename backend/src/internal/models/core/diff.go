@@ -0,0 +1,125 @@
+// ./src/internal/models/core/diff.go
+package models
+
+import (
+	"image"
+	"strings"
+)
+
+// SectionOperation is the kind of change a section underwent between two versions
+type SectionOperation string
+
+const (
+	// SectionAdded is a section that is only present in the newer version
+	SectionAdded SectionOperation = "added"
+	// SectionRemoved is a section that is only present in the older version
+	SectionRemoved SectionOperation = "removed"
+	// SectionModified is a section that is present in both versions with different content
+	SectionModified SectionOperation = "modified"
+)
+
+// SectionDiff captures the changes to a single section of a page
+// Sections are keyed by the heading that opens them
+type SectionDiff struct {
+	// Heading is the heading of the section, empty for content preceding the first heading
+	Heading string `json:"heading"`
+
+	// Operation is the kind of change the section underwent
+	Operation SectionOperation `json:"operation"`
+
+	// Removed are the lines which were present in the older version
+	Removed []string `json:"removed,omitempty"`
+
+	// Added are the lines which are present in the newer version
+	Added []string `json:"added,omitempty"`
+}
+
+// ContentDiff is the structural diff between two versions of a page
+type ContentDiff struct {
+	// Sections are the changed sections, in the order they appear in the page
+	Sections []SectionDiff `json:"sections"`
+}
+
+// IsEmpty returns true if the two versions are identical
+func (d *ContentDiff) IsEmpty() bool {
+	return d == nil || len(d.Sections) == 0
+}
+
+// Hunks renders the changed sections of each version as markdown
+// previous holds the removed lines and current holds the added lines, grouped by heading
+func (d *ContentDiff) Hunks() (previous, current string) {
+	if d.IsEmpty() {
+		return "", ""
+	}
+
+	var prev, curr strings.Builder
+	for _, section := range d.Sections {
+		if len(section.Removed) > 0 {
+			writeHunk(&prev, section.Heading, section.Removed)
+		}
+		if len(section.Added) > 0 {
+			writeHunk(&curr, section.Heading, section.Added)
+		}
+	}
+
+	return prev.String(), curr.String()
+}
+
+// writeHunk writes the lines of a section under its heading
+func writeHunk(builder *strings.Builder, heading string, lines []string) {
+	if heading != "" {
+		builder.WriteString(heading)
+		builder.WriteString("\n")
+	}
+	for _, line := range lines {
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+}
+
+// DiffResult is the outcome of comparing two versions of a page
+type DiffResult struct {
+	// Changes are the changes categorized by the AI service
+	Changes *DynamicChanges `json:"changes"`
+
+	// RawDiff is the unified diff of the minified markdown of both versions
+	RawDiff string `json:"raw_diff"`
+
+	// Visual is the visual diff of the screenshots of both versions
+	// This is only set for significant visual changes
+	Visual *VisualDiff `json:"visual,omitempty"`
+
+	// NoiseRules are the names of the noise rules which fired on either version
+	NoiseRules []string `json:"noise_rules,omitempty"`
+}
+
+// BoundingBox is a rectangular region of a screenshot, in pixels
+type BoundingBox struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// VisualDiff is the outcome of comparing the screenshots of two versions of a page
+type VisualDiff struct {
+	// ChangedPercentage is the percentage of pixels which changed perceptibly
+	ChangedPercentage float64 `json:"changed_percentage"`
+
+	// BoundingBoxes are the regions holding the changed pixels, largest first
+	BoundingBoxes []BoundingBox `json:"bounding_boxes"`
+
+	// Significant is true when the changed percentage reached the threshold of the capture profile
+	Significant bool `json:"significant"`
+
+	// OverlayPath is the path of the overlay highlighting the changed pixels
+	OverlayPath string `json:"overlay_path,omitempty"`
+
+	// Changes are the visual changes categorized by the AI service
+	// This is only set for significant visual changes
+	Changes *DynamicChanges `json:"changes,omitempty"`
+
+	// Overlay is the current screenshot with the changed pixels highlighted
+	Overlay image.Image `json:"-"`
+}
@@ -0,0 +1,198 @@
+// ./src/internal/models/core/digest.go
+package models
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportMode is how the reports of a workspace are dispatched to its members
+type ReportMode string
+
+const (
+	// ReportModeDigest sends a single digest combining the reports of every competitor
+	ReportModeDigest ReportMode = "digest"
+
+	// ReportModeIndividual sends the report of each competitor on its own
+	ReportModeIndividual ReportMode = "individual"
+)
+
+// categorySignificance weighs the categories of changes when ranking the competitors of a digest
+// Categories missing from the map weigh defaultCategorySignificance
+var categorySignificance = map[string]int{
+	"pricing":      5,
+	"product":      4,
+	"integration":  3,
+	"partnerships": 3,
+	"customers":    3,
+	"messaging":    2,
+	"branding":     1,
+}
+
+const defaultCategorySignificance = 1
+
+// CategorySignificance returns the weight of a change of the category
+func CategorySignificance(category string) int {
+	if significance, ok := categorySignificance[category]; ok {
+		return significance
+	}
+	return defaultCategorySignificance
+}
+
+// DigestEntry is the reports of a competitor within a digest, aggregated into one
+type DigestEntry struct {
+	// ReportID is the newest report the entry was built from
+	ReportID uuid.UUID `json:"report_id"`
+
+	// ReportIDs are every report the entry was built from
+	ReportIDs []uuid.UUID `json:"report_ids"`
+
+	// CompetitorID is the competitor the reports belong to
+	CompetitorID uuid.UUID `json:"competitor_id"`
+
+	// CompetitorName is the name of the competitor
+	CompetitorName string `json:"competitor_name"`
+
+	// Changes are the changes of the reports, most significant category first
+	Changes []CategoryChange `json:"changes"`
+
+	// Significance is the sum of the weights of the changes of the reports
+	Significance int `json:"significance"`
+
+	// Time is the time the newest report was created
+	Time time.Time `json:"time"`
+}
+
+// Digest combines the reports of every competitor of a workspace, most significant first
+type Digest struct {
+	// WorkspaceID is the workspace the digest belongs to
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+
+	// Entries are the reports of the competitors, most significant first
+	Entries []DigestEntry `json:"entries"`
+
+	// Since is the time the oldest report of the digest may date from
+	Since time.Time `json:"since"`
+
+	// GeneratedAt is the time the digest was generated
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// reports are the reports the entries were built from
+	// Narrowing the digest down aggregates the remaining reports again
+	reports []Report
+}
+
+// NewDigest aggregates the reports of each competitor of the workspace, and ranks them into a digest
+// Competitors without changes are left out
+func NewDigest(workspaceID uuid.UUID, reports []Report, since time.Time) Digest {
+	return Digest{
+		WorkspaceID: workspaceID,
+		Entries:     newDigestEntries(reports),
+		Since:       since,
+		GeneratedAt: time.Now(),
+		reports:     reports,
+	}
+}
+
+// newDigestEntries aggregates the reports of each competitor into an entry, most significant first
+func newDigestEntries(reports []Report) []DigestEntry {
+	competitorIDs := make([]uuid.UUID, 0)
+	reportsByCompetitor := make(map[uuid.UUID][]Report)
+	for _, report := range reports {
+		if _, ok := reportsByCompetitor[report.CompetitorID]; !ok {
+			competitorIDs = append(competitorIDs, report.CompetitorID)
+		}
+		reportsByCompetitor[report.CompetitorID] = append(reportsByCompetitor[report.CompetitorID], report)
+	}
+
+	entries := make([]DigestEntry, 0, len(competitorIDs))
+	for _, competitorID := range competitorIDs {
+		if entry, ok := newDigestEntry(reportsByCompetitor[competitorID]); ok {
+			entries = append(entries, entry)
+		}
+	}
+	sortDigestEntries(entries)
+	return entries
+}
+
+func newDigestEntry(reports []Report) (DigestEntry, bool) {
+	report, ok := AggregateReports(reports)
+	if !ok {
+		return DigestEntry{}, false
+	}
+
+	significance := 0
+	ranked := make([]CategoryChange, 0, len(report.Changes))
+	for _, change := range report.Changes {
+		if len(change.Changes) == 0 {
+			continue
+		}
+		significance += CategorySignificance(change.Category) * len(change.Changes)
+		ranked = append(ranked, change)
+	}
+	if len(ranked) == 0 {
+		return DigestEntry{}, false
+	}
+
+	slices.SortStableFunc(ranked, func(a, b CategoryChange) int {
+		return cmp.Compare(CategorySignificance(b.Category), CategorySignificance(a.Category))
+	})
+
+	return DigestEntry{
+		ReportID:       report.ID,
+		ReportIDs:      ReportIDs(reports),
+		CompetitorID:   report.CompetitorID,
+		CompetitorName: report.CompetitorName,
+		Changes:        ranked,
+		Significance:   significance,
+		Time:           report.Time,
+	}, true
+}
+
+func sortDigestEntries(entries []DigestEntry) {
+	slices.SortStableFunc(entries, func(a, b DigestEntry) int {
+		if c := cmp.Compare(b.Significance, a.Significance); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.CompetitorName, b.CompetitorName)
+	})
+}
+
+// Filter narrows the digest down to the competitors and categories, all of them when none is given
+// Competitors left without changes are dropped, and the rest ranked again
+func (d Digest) Filter(competitorIDs []uuid.UUID, categories []string) Digest {
+	reports := make([]Report, 0, len(d.reports))
+	for _, report := range d.reports {
+		if len(competitorIDs) > 0 && !slices.Contains(competitorIDs, report.CompetitorID) {
+			continue
+		}
+		report.Changes = FilterCategoryChanges(report.Changes, categories)
+		reports = append(reports, report)
+	}
+
+	d.reports = reports
+	d.Entries = newDigestEntries(reports)
+	return d
+}
+
+// ReportedAfter narrows the digest down to the reports created after the time
+func (d Digest) ReportedAfter(t time.Time) Digest {
+	reports := make([]Report, 0, len(d.reports))
+	for _, report := range d.reports {
+		if report.Time.After(t) {
+			reports = append(reports, report)
+		}
+	}
+
+	d.reports = reports
+	d.Entries = newDigestEntries(reports)
+	return d
+}
+
+// IsEmpty returns true if no competitor of the digest has changes
+func (d Digest) IsEmpty() bool {
+	return len(d.Entries) == 0
+}
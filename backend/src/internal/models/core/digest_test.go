@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDigestRanksCompetitorsBySignificance(t *testing.T) {
+	now := time.Now()
+	branding := Report{ID: uuid.New(), CompetitorID: uuid.New(), CompetitorName: "Acme", Time: now, Changes: []CategoryChange{
+		{Category: "branding", Changes: []string{"new logo", "new colors"}},
+	}}
+	pricing := Report{ID: uuid.New(), CompetitorID: uuid.New(), CompetitorName: "Globex", Time: now, Changes: []CategoryChange{
+		{Category: "branding", Changes: []string{"new tagline"}},
+		{Category: "pricing", Changes: []string{"raised the pro plan"}},
+	}}
+	quiet := Report{ID: uuid.New(), CompetitorID: uuid.New(), CompetitorName: "Initech", Time: now, Changes: []CategoryChange{
+		{Category: "product", Changes: []string{}},
+	}}
+
+	// Pricing outweighs branding, and competitors without changes are left out
+	digest := NewDigest(uuid.New(), []Report{branding, pricing, quiet}, now.Add(-7*24*time.Hour))
+	if len(digest.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(digest.Entries))
+	}
+	if digest.Entries[0].CompetitorName != "Globex" || digest.Entries[0].Significance != 6 {
+		t.Fatalf("expected Globex first with a significance of 6, got %s with %d", digest.Entries[0].CompetitorName, digest.Entries[0].Significance)
+	}
+	if digest.Entries[0].Changes[0].Category != "pricing" {
+		t.Fatalf("expected the pricing changes first, got %s", digest.Entries[0].Changes[0].Category)
+	}
+
+	// Narrowing the digest down to branding ranks the competitors again
+	filtered := digest.Filter(nil, []string{"branding"})
+	if len(filtered.Entries) != 2 || filtered.Entries[0].CompetitorName != "Acme" {
+		t.Fatalf("expected Acme first once filtered to branding, got %v", filtered.Entries)
+	}
+	if filtered := digest.Filter([]uuid.UUID{branding.CompetitorID}, []string{"pricing"}); !filtered.IsEmpty() {
+		t.Fatalf("expected no entry for the pricing changes of Acme, got %v", filtered.Entries)
+	}
+}
@@ -12,4 +12,17 @@ type Email struct {
 	EmailFormat  EmailFormat
 	EmailContent string
 	EmailSubject string
+	Attachments  []EmailAttachment
+}
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	// Filename is the name of the file, as shown to the recipients
+	Filename string `json:"filename"`
+
+	// ContentType is the media type of the file, guessed from its name when empty
+	ContentType string `json:"content_type,omitempty"`
+
+	// Content is the content of the file
+	Content []byte `json:"content"`
 }
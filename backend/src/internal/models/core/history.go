@@ -22,6 +22,9 @@ type PageHistory struct {
 	ID          uuid.UUID      `json:"id"`
 	PageID      uuid.UUID      `json:"page_id"`
 	DiffContent DynamicChanges `json:"diff_content"`
+	RawDiff     string         `json:"raw_diff"`
+	VisualDiff  *VisualDiff    `json:"visual_diff,omitempty"`
+	NoiseRules  []string       `json:"noise_rules"`
 	CreatedAt   time.Time      `json:"created_at"`
 	Status      HistoryStatus  `json:"history_status" default:"active"`
 	Prev        string         `json:"prev"`
@@ -6,7 +6,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,11 +24,14 @@ type Job struct {
 	JobState
 }
 
-func NewJob() *Job {
+func NewJob(scope JobScope) *Job {
+	now := time.Now()
 	return &Job{
 		JobID: uuid.New(),
 		JobState: JobState{
-			Status: JobStatusRunning,
+			Status:    JobStatusRunning,
+			Scope:     scope,
+			StartTime: &now,
 			Checkpoint: JobCheckpoint{
 				BatchID: nil,
 			},
@@ -39,6 +44,9 @@ type JobState struct {
 	// Status is the current status of the job
 	Status JobStatus `json:"status" validate:"required" default:"running"`
 
+	// Scope limits the items processed by the job, the job processes every item when the scope is empty
+	Scope JobScope `json:"scope"`
+
 	// Checkpoint is the current checkpoint of the job
 	Checkpoint JobCheckpoint `json:"checkpoint"`
 
@@ -47,6 +55,49 @@ type JobState struct {
 
 	// Failed is the number of iterations failed
 	Failed int64 `json:"failed"`
+
+	// Skipped is the number of iterations skipped as there was nothing to process
+	Skipped int64 `json:"skipped"`
+
+	// Total is the number of items of the job, counted as it starts, zero when unknown
+	Total int64 `json:"total,omitempty"`
+
+	// StartTime is the time when the job started
+	StartTime *time.Time `json:"start_time,omitempty"`
+
+	// Limits are the effective limits of the executor running the job, for executors adapting them as they run
+	Limits *JobLimits `json:"limits,omitempty"`
+}
+
+// JobLimits are the limits an executor currently processes items within
+type JobLimits struct {
+	// Concurrency is the number of items processed at once, backed off as upstream services push back
+	Concurrency int `json:"concurrency"`
+	// MaxConcurrency is the number of items processed at once when upstream services keep up
+	MaxConcurrency int `json:"max_concurrency"`
+	// PerHost is the number of items of the same host processed at once, zero when unlimited
+	PerHost int `json:"per_host,omitempty"`
+}
+
+// ETA estimates the time until the job ends, from the rate at which its items were processed so far
+// Returns nil when the job isn't running, or when its total or start time are unknown
+func (s JobState) ETA(now time.Time) *time.Duration {
+	processed := s.Completed + s.Failed + s.Skipped
+	if s.Status != JobStatusRunning || s.Total <= 0 || s.StartTime == nil || processed == 0 {
+		return nil
+	}
+
+	remaining := max(s.Total-processed, 0)
+	elapsed := now.Sub(*s.StartTime)
+	eta := time.Duration(float64(elapsed) / float64(processed) * float64(remaining))
+	return &eta
+}
+
+// JobProgress is the progress of a job, shared by the replicas running it
+type JobProgress struct {
+	Completed int64 `json:"completed"`
+	Failed    int64 `json:"failed"`
+	Skipped   int64 `json:"skipped"`
 }
 
 func NewJobState() *JobState {
@@ -61,7 +112,31 @@ func NewJobState() *JobState {
 // JobCheckpoint captures the current checkpoint of the workflow
 type JobCheckpoint struct {
 	// BatchID is the batch ID of the current checkpoint
+	// It is the cursor past the last batch listed, the items up to it are listed again only through Pending
 	BatchID *uuid.UUID `json:"batch_id"`
+
+	// Pending are the items of the last batch which are yet to finish
+	// A resumed job processes them before moving past the cursor
+	Pending []uuid.UUID `json:"pending,omitempty"`
+}
+
+// JobScope limits a job to the items of some workspaces, competitors or pages
+// An item is in scope when it matches every non-empty list, so that a scope within a workspace never reaches past it
+// An empty scope covers every item
+type JobScope struct {
+	// WorkspaceIDs limits the job to the items of these workspaces
+	WorkspaceIDs []uuid.UUID `json:"workspace_ids,omitempty"`
+
+	// CompetitorIDs limits the job to the items of these competitors
+	CompetitorIDs []uuid.UUID `json:"competitor_ids,omitempty"`
+
+	// PageIDs limits the job to these pages
+	PageIDs []uuid.UUID `json:"page_ids,omitempty"`
+}
+
+// IsEmpty returns true if the scope covers every item
+func (s JobScope) IsEmpty() bool {
+	return len(s.WorkspaceIDs) == 0 && len(s.CompetitorIDs) == 0 && len(s.PageIDs) == 0
 }
 
 // WorkflowStatus is an enum for the status of a workflow
@@ -73,12 +148,15 @@ const (
 	JobStatusFailed    JobStatus = "failed"
 	JobStatusAborted   JobStatus = "aborted"
 	JobStatusUnknown   JobStatus = "unknown"
+	// JobStatusPaused is the status of a job which stopped processing its items until it is resumed from its checkpoint
+	JobStatusPaused JobStatus = "paused"
 )
 
 func ParseJobStatus(s string) (JobStatus, error) {
 	switch JobStatus(s) {
 	case JobStatusRunning, JobStatusCompleted,
-		JobStatusFailed, JobStatusAborted, JobStatusUnknown:
+		JobStatusFailed, JobStatusAborted, JobStatusUnknown,
+		JobStatusPaused:
 		return JobStatus(s), nil
 	default:
 		return "", fmt.Errorf("invalid workflow status: %s", s)
@@ -96,7 +174,10 @@ type JobUpdate struct {
 	Time          time.Time     `json:"time"`
 	Completed     int64         `json:"completed"`
 	Failed        int64         `json:"failed"`
+	Skipped       int64         `json:"skipped"`
 	NewCheckpoint JobCheckpoint `json:"new_checkpoint"`
+	// Outcomes are the outcomes of the items processed since the last update
+	Outcomes []JobItemOutcome `json:"outcomes,omitempty"`
 }
 
 type JobContext struct {
@@ -106,19 +187,68 @@ type JobContext struct {
 	// cancel is the cancel function for the job
 	cancel context.CancelFunc
 
+	// pause is closed once the job is asked to pause, it then stops taking new batches
+	pause chan struct{}
+
+	// pauseOnce closes the pause channel once
+	pauseOnce sync.Once
+
+	// drained is set once the executor finished every item of the job
+	drained atomic.Bool
+
+	// errs are the errors of the job itself, as opposed to the errors of its items
+	errs []JobItemError
+
 	// mutex is the mutex for the job context
 	mutex sync.Mutex
 }
 
+// jobPauseKey is the key of the pause channel of a job in its execution context
+type jobPauseKey struct{}
+
+// jobDrainedKey is the key of the drained flag of a job in its execution context
+type jobDrainedKey struct{}
+
 func NewJobContextForJob(job *Job) (*JobContext, context.Context) {
 	ctx, cancel := context.WithCancel(context.Background())
 	jobContext := JobContext{
 		Job:    *job,
 		cancel: cancel,
+		pause:  make(chan struct{}),
 	}
+	ctx = context.WithValue(ctx, jobPauseKey{}, jobContext.pause)
+	ctx = context.WithValue(ctx, jobDrainedKey{}, &jobContext.drained)
 	return &jobContext, ctx
 }
 
+// PauseRequested returns true if the job executed within the context was asked to pause
+func PauseRequested(ctx context.Context) bool {
+	pause, ok := ctx.Value(jobPauseKey{}).(chan struct{})
+	if !ok {
+		return false
+	}
+
+	select {
+	case <-pause:
+		return true
+	default:
+		return false
+	}
+}
+
+// MarkDrained marks the job executed within the context as drained, once every one of its items finished
+func MarkDrained(ctx context.Context) {
+	if drained, ok := ctx.Value(jobDrainedKey{}).(*atomic.Bool); ok {
+		drained.Store(true)
+	}
+}
+
+// Drained returns true once the executor finished every item of the job
+// A pause requested while its last batch was in flight leaves nothing to resume, so the job completes instead
+func (jc *JobContext) Drained() bool {
+	return jc.drained.Load()
+}
+
 func (jc *JobContext) IncrementCompleted(completed int64) {
 	jc.mutex.Lock()
 	defer jc.mutex.Unlock()
@@ -176,6 +306,30 @@ func (jc *JobContext) GetState() (JobStatus, JobCheckpoint) {
 	return jc.Status, jc.Checkpoint
 }
 
+// SetTotal sets the number of items of the job
+func (jc *JobContext) SetTotal(total int64) {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	jc.Total = total
+}
+
+// SetLimits sets the effective limits of the executor running the job
+func (jc *JobContext) SetLimits(limits JobLimits) {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	jc.Limits = &limits
+}
+
+// GetJobState returns a copy of the state of the job
+func (jc *JobContext) GetJobState() JobState {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	return jc.JobState
+}
+
 func (jc *JobContext) HandleUpdate(jobUpdate *JobUpdate) {
 	jc.mutex.Lock()
 	defer jc.mutex.Unlock()
@@ -183,14 +337,25 @@ func (jc *JobContext) HandleUpdate(jobUpdate *JobUpdate) {
 	jc.Status = JobStatusRunning
 	jc.Completed += jobUpdate.Completed
 	jc.Failed += jobUpdate.Failed
+	jc.Skipped += jobUpdate.Skipped
 	jc.Checkpoint = jobUpdate.NewCheckpoint
 }
 
+// HandleError counts an error of the job itself, such as failing to list its items, the job fails once it ends
 func (jc *JobContext) HandleError(jobError *JobError) {
 	jc.mutex.Lock()
 	defer jc.mutex.Unlock()
 
 	jc.Failed += 1
+	jc.errs = append(jc.errs, JobItemError{Error: jobError.Error.Error()})
+}
+
+// Errors returns the errors of the job itself, which it fails on once it ends
+func (jc *JobContext) Errors() []JobItemError {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	return append([]JobItemError(nil), jc.errs...)
 }
 
 func (jc *JobContext) HandleCompletion() {
@@ -200,23 +365,141 @@ func (jc *JobContext) HandleCompletion() {
 	jc.Status = JobStatusCompleted
 }
 
+// HandleFailure marks the job failed, as it ended on errors of its own
+func (jc *JobContext) HandleFailure() {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	jc.Status = JobStatusFailed
+}
+
 func (jc *JobContext) HandleCancellation() {
 	// TODO: add pre and post hooks
 	jc.Status = JobStatusAborted
 	jc.cancel()
 }
 
+// RequestPause asks the job to stop taking new batches, the items being processed finish before it's paused
+func (jc *JobContext) RequestPause() {
+	jc.pauseOnce.Do(func() {
+		close(jc.pause)
+	})
+}
+
+// HandlePause stops the execution of the job once paused, its checkpoint is kept so that it's resumed from it
+func (jc *JobContext) HandlePause() {
+	jc.mutex.Lock()
+	jc.Status = JobStatusPaused
+	jc.mutex.Unlock()
+
+	jc.cancel()
+}
+
 // ExecutorConfig represents the configuration for an executor
 type JobExecutorConfig struct {
 	// Number of tasks to execute in parallel
 	// This is used to limit the number of tasks that can be executed concurrently
 	Parallelism int `json:"batch_size"`
+	// Minimum number of tasks executed in parallel, as the executor backs off
+	// Executors adapting their concurrency halve it on rate limits and timeouts, down to this floor
+	MinParallelism int `json:"min_parallelism"`
+	// Maximum number of tasks of the same host executed in parallel, zero when unlimited
+	// This is used to keep the executor polite with the sites it visits
+	MaxPerHost int `json:"max_per_host"`
 	// Lower bound for the time to wait before executing the next batch
 	// This is used to prevent the executor from executing tasks too frequently
 	LowerBound time.Duration `json:"lower_bound"`
 	// Upper bound for the time to wait before executing the next batch
 	// This is used to prevent the executor from getting stuck with the same batch
 	UpperBound time.Duration `json:"upper_bound"`
+	// Retry policy for the items of a batch
+	// This is used to retry transient failures before an item counts as failed
+	RetryPolicy JobRetryPolicy `json:"retry_policy"`
+}
+
+// JobErrorClass is the class of an error encountered while processing an item
+type JobErrorClass string
+
+const (
+	// JobErrorClassTimeout is the class of errors caused by an operation timing out
+	JobErrorClassTimeout JobErrorClass = "timeout"
+	// JobErrorClassRateLimited is the class of errors caused by an upstream rate limit
+	JobErrorClassRateLimited JobErrorClass = "rate_limited"
+	// JobErrorClassServer is the class of errors caused by an upstream server error
+	JobErrorClassServer JobErrorClass = "server"
+	// JobErrorClassNetwork is the class of errors caused by the network, such as a reset connection
+	JobErrorClassNetwork JobErrorClass = "network"
+	// JobErrorClassPermanent is the class of the remaining errors, which aren't expected to go away on retry
+	JobErrorClassPermanent JobErrorClass = "permanent"
+)
+
+// UpstreamError is an error returned by a service the items depend on, such as the screenshot or the AI service
+// It carries the status code of the response, so that the error is classified without parsing its message
+type UpstreamError struct {
+	// Service is the service which returned the error
+	Service string
+	// StatusCode is the status code the service responded with, zero when it didn't respond
+	StatusCode int
+	// Err is the error returned by the service
+	Err error
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s: %v", e.Service, e.Err)
+	}
+	return fmt.Sprintf("%s responded with status %d: %v", e.Service, e.StatusCode, e.Err)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// JobRetryPolicy represents the policy for retrying an item before it counts as failed
+type JobRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at processing an item, including the first one
+	MaxAttempts int `json:"max_attempts"`
+	// BaseBackoff is the time to wait before the first retry, doubled on every following retry
+	BaseBackoff time.Duration `json:"base_backoff"`
+	// MaxBackoff caps the time to wait between two attempts
+	MaxBackoff time.Duration `json:"max_backoff"`
+	// Jitter is the fraction of the backoff randomized, between 0 and 1
+	// This is used to prevent the items of a batch from retrying in lockstep
+	Jitter float64 `json:"jitter"`
+	// RetryableErrors are the classes of errors which are retried
+	RetryableErrors []JobErrorClass `json:"retryable_errors"`
+}
+
+// Retryable returns true if errors of the class are retried by the policy
+func (p JobRetryPolicy) Retryable(class JobErrorClass) bool {
+	for _, retryable := range p.RetryableErrors {
+		if retryable == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the time to wait after the given failed attempt, starting at 1
+func (p JobRetryPolicy) Backoff(attempt int) time.Duration {
+	if p.BaseBackoff <= 0 || attempt < 1 {
+		return 0
+	}
+
+	backoff := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		// Spread the backoff uniformly over [backoff * (1 - jitter), backoff]
+		backoff -= time.Duration(rand.Float64() * jitter * float64(backoff))
+	}
+
+	return backoff
 }
 
 // WorkflowRecord represents a historical record of a workflow
@@ -235,17 +518,69 @@ type JobRecord struct {
 	CancelTime sql.NullTime `json:"cancel_time,omitempty"`
 	// Preemptions is the number of times the job was pre-empted
 	Preemptions int `json:"preemptions"`
+	// Status is the status of the job, final once the job ended or was cancelled
+	Status JobStatus `json:"status"`
+	// Completed is the number of items completed by the job
+	Completed int64 `json:"completed"`
+	// Failed is the number of items failed by the job
+	Failed int64 `json:"failed"`
+	// Skipped is the number of items skipped by the job
+	Skipped int64 `json:"skipped"`
+	// Checkpoint is the last checkpoint of the job, if it was persisted
+	Checkpoint *JobCheckpoint `json:"checkpoint,omitempty"`
+	// Scope is the scope of the job, nil for jobs covering every item
+	Scope *JobScope `json:"scope,omitempty"`
+	// Duration is the time the job ran for, known once the job ended or was cancelled
+	Duration *time.Duration `json:"duration,omitempty"`
+}
+
+// JobState returns the state of the job as recorded
+func (j JobRecord) JobState() JobState {
+	state := JobState{
+		Status:    j.Status,
+		Completed: j.Completed,
+		Failed:    j.Failed,
+		Skipped:   j.Skipped,
+	}
+	if j.Checkpoint != nil {
+		state.Checkpoint = *j.Checkpoint
+	}
+	if j.Scope != nil {
+		state.Scope = *j.Scope
+	}
+	return state
+}
+
+// JobRecordFilter narrows down the job records listed
+type JobRecordFilter struct {
+	// WorkflowType filters the records by the type of the workflow
+	WorkflowType *WorkflowType
+	// Status filters the records by the status of the job
+	Status *JobStatus
+	// StartedAfter filters the records of jobs started at or after the time
+	StartedAfter *time.Time
+	// StartedBefore filters the records of jobs started before the time
+	StartedBefore *time.Time
+	// HasFailures filters the records of jobs with at least one failed item
+	HasFailures bool
 }
 
 // jobRecordJSON is an internal type for JSON marshaling/unmarshaling
 type jobRecordJSON struct {
-	ID           string       `json:"id"`
-	WorkflowType WorkflowType `json:"workflow_type"`
-	JobID        string       `json:"job_id"`
-	StartTime    *time.Time   `json:"start_time,omitempty"`
-	EndTime      *time.Time   `json:"end_time,omitempty"`
-	CancelTime   *time.Time   `json:"cancel_time,omitempty"`
-	Preemptions  int          `json:"preemptions"`
+	ID           string         `json:"id"`
+	WorkflowType WorkflowType   `json:"workflow_type"`
+	JobID        string         `json:"job_id"`
+	StartTime    *time.Time     `json:"start_time,omitempty"`
+	EndTime      *time.Time     `json:"end_time,omitempty"`
+	CancelTime   *time.Time     `json:"cancel_time,omitempty"`
+	Preemptions  int            `json:"preemptions"`
+	Status       JobStatus      `json:"status"`
+	Completed    int64          `json:"completed"`
+	Failed       int64          `json:"failed"`
+	Skipped      int64          `json:"skipped"`
+	Checkpoint   *JobCheckpoint `json:"checkpoint,omitempty"`
+	Scope        *JobScope      `json:"scope,omitempty"`
+	Duration     *time.Duration `json:"duration,omitempty"`
 }
 
 // MarshalJSON implements custom JSON marshaling for JobRecord
@@ -255,6 +590,13 @@ func (j JobRecord) MarshalJSON() ([]byte, error) {
 		WorkflowType: j.WorkflowType,
 		JobID:        j.JobID.String(),
 		Preemptions:  j.Preemptions,
+		Status:       j.Status,
+		Completed:    j.Completed,
+		Failed:       j.Failed,
+		Skipped:      j.Skipped,
+		Checkpoint:   j.Checkpoint,
+		Scope:        j.Scope,
+		Duration:     j.Duration,
 	}
 
 	if j.StartTime.Valid {
@@ -295,6 +637,13 @@ func (j *JobRecord) UnmarshalJSON(data []byte) error {
 
 	j.WorkflowType = record.WorkflowType
 	j.Preemptions = record.Preemptions
+	j.Status = record.Status
+	j.Completed = record.Completed
+	j.Failed = record.Failed
+	j.Skipped = record.Skipped
+	j.Checkpoint = record.Checkpoint
+	j.Scope = record.Scope
+	j.Duration = record.Duration
 
 	// Handle nullable times
 	if record.StartTime != nil {
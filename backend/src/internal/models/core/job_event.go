@@ -0,0 +1,105 @@
+// ./src/internal/models/core/job_event.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobEventType is an enum for the type of a job event
+type JobEventType string
+
+const (
+	// JobEventProgress is sent as items of the job are processed
+	JobEventProgress JobEventType = "progress"
+	// JobEventError is sent as items of the job fail, or the job fails to list its items
+	JobEventError JobEventType = "error"
+	// JobEventCompleted is sent once the job completed
+	JobEventCompleted JobEventType = "completed"
+	// JobEventFailed is sent once the job ended on errors of its own, such as failing to list its items
+	JobEventFailed JobEventType = "failed"
+	// JobEventAborted is sent once the job was cancelled
+	JobEventAborted JobEventType = "aborted"
+	// JobEventPaused is sent once the job was paused
+	JobEventPaused JobEventType = "paused"
+	// JobEventResumed is sent once the job was resumed, its progress events follow
+	JobEventResumed JobEventType = "resumed"
+)
+
+// JobItemError is an error encountered by a job
+type JobItemError struct {
+	// ItemID is the item which failed, nil for errors of the job itself
+	ItemID *uuid.UUID `json:"item_id,omitempty"`
+	// Error is the error encountered
+	Error string `json:"error"`
+}
+
+// JobEvent is an event in the progress of a job, streamed to the clients following it
+type JobEvent struct {
+	// JobID is the unique identifier of the job
+	JobID uuid.UUID `json:"job_id"`
+	// WorkflowType is the type of the workflow the job belongs to
+	WorkflowType WorkflowType `json:"workflow_type"`
+	// Type is the type of the event
+	Type JobEventType `json:"type"`
+	// Time is the time of the event
+	Time time.Time `json:"time"`
+	// Status is the status of the job
+	Status JobStatus `json:"status"`
+	// Completed is the number of items completed by the job so far
+	Completed int64 `json:"completed"`
+	// Failed is the number of items failed by the job so far
+	Failed int64 `json:"failed"`
+	// Skipped is the number of items skipped by the job so far
+	Skipped int64 `json:"skipped"`
+	// Total is the number of items of the job, zero when unknown
+	Total int64 `json:"total,omitempty"`
+	// Checkpoint is the checkpoint of the job, sent by the replica coordinating the job
+	Checkpoint *JobCheckpoint `json:"checkpoint,omitempty"`
+	// Errors are the errors encountered since the last event
+	Errors []JobItemError `json:"errors,omitempty"`
+	// ETA is the estimated time until the job ends, nil when it can't be estimated
+	ETA *time.Duration `json:"eta,omitempty"`
+	// Limits are the effective limits of the executor running the job
+	Limits *JobLimits `json:"limits,omitempty"`
+}
+
+// NewJobEvent creates an event of the job from its state
+func NewJobEvent(jobID uuid.UUID, workflowType WorkflowType, eventType JobEventType, state JobState) JobEvent {
+	now := time.Now()
+	return JobEvent{
+		JobID:        jobID,
+		WorkflowType: workflowType,
+		Type:         eventType,
+		Time:         now,
+		Status:       state.Status,
+		Completed:    state.Completed,
+		Failed:       state.Failed,
+		Skipped:      state.Skipped,
+		Total:        state.Total,
+		ETA:          state.ETA(now),
+		Limits:       state.Limits,
+	}
+}
+
+// JobEventForState returns the type of event describing a job in the state
+func JobEventForState(state JobState) JobEventType {
+	switch state.Status {
+	case JobStatusCompleted:
+		return JobEventCompleted
+	case JobStatusFailed:
+		return JobEventFailed
+	case JobStatusAborted:
+		return JobEventAborted
+	case JobStatusPaused:
+		return JobEventPaused
+	default:
+		return JobEventProgress
+	}
+}
+
+// IsFinal returns true if no event follows this one
+func (e JobEvent) IsFinal() bool {
+	return e.Type == JobEventCompleted || e.Type == JobEventFailed || e.Type == JobEventAborted
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobEventEstimatesRemainingTime(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-time.Minute)
+
+	// A quarter of the items were processed in a minute, the rest take three more
+	state := JobState{Status: JobStatusRunning, Completed: 20, Failed: 3, Skipped: 2, Total: 100, StartTime: &start}
+	eta := state.ETA(now)
+	if eta == nil || *eta != 3*time.Minute {
+		t.Fatalf("expected an ETA of 3m, got %v", eta)
+	}
+
+	// Jobs without a total, or which ended, have no ETA
+	state.Total = 0
+	if eta := state.ETA(now); eta != nil {
+		t.Fatalf("expected no ETA without a total, got %v", *eta)
+	}
+	state.Total, state.Status = 100, JobStatusCompleted
+	if eta := state.ETA(now); eta != nil {
+		t.Fatalf("expected no ETA once the job ended, got %v", *eta)
+	}
+
+	if event := NewJobEvent(NewJob(JobScope{}).JobID, ScreenshotWorkflowType, JobEventForState(state), state); !event.IsFinal() {
+		t.Fatalf("expected the event of a completed job to be final, got %s", event.Type)
+	}
+	state.Status = JobStatusFailed
+	if event := NewJobEvent(NewJob(JobScope{}).JobID, ScreenshotWorkflowType, JobEventForState(state), state); event.Type != JobEventFailed || !event.IsFinal() {
+		t.Fatalf("expected the event of a failed job to be final, got %s", event.Type)
+	}
+}
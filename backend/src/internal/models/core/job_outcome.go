@@ -0,0 +1,81 @@
+// ./src/internal/models/core/job_outcome.go
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobItemStatus is the status of a single item, such as a page or a workspace, processed by a job
+type JobItemStatus string
+
+const (
+	JobItemStatusSucceeded JobItemStatus = "succeeded"
+	JobItemStatusSkipped   JobItemStatus = "skipped"
+	JobItemStatusFailed    JobItemStatus = "failed"
+)
+
+// JobItemOutcome records the outcome of processing a single item in a job
+type JobItemOutcome struct {
+	// JobID is the unique identifier of the job
+	JobID uuid.UUID `json:"job_id"`
+	// WorkflowType is the type of the workflow the job belongs to
+	WorkflowType WorkflowType `json:"workflow_type"`
+	// ItemID is the unique identifier of the item, a page or a workspace depending on the workflow
+	ItemID uuid.UUID `json:"item_id"`
+	// Attempt is the attempt at processing the item, starting at 1
+	Attempt int `json:"attempt"`
+	// Status is the status of the item after the attempt
+	Status JobItemStatus `json:"status"`
+	// Error is the error encountered while processing the item, if any
+	Error string `json:"error,omitempty"`
+	// Duration is the time taken to process the item
+	Duration time.Duration `json:"duration"`
+	// Time is the time when the item was processed
+	Time time.Time `json:"time"`
+}
+
+// JobFailureStatus is the status of an item in the dead-letter list of a job
+type JobFailureStatus string
+
+const (
+	// JobFailureStatusPending is the status of a failed item awaiting a retry
+	JobFailureStatusPending JobFailureStatus = "pending"
+	// JobFailureStatusRetrying is the status of a failed item being retried
+	JobFailureStatusRetrying JobFailureStatus = "retrying"
+	// JobFailureStatusResolved is the status of a failed item which was processed on retry
+	JobFailureStatusResolved JobFailureStatus = "resolved"
+)
+
+func ParseJobFailureStatus(s string) (JobFailureStatus, error) {
+	switch JobFailureStatus(s) {
+	case JobFailureStatusPending, JobFailureStatusRetrying, JobFailureStatusResolved:
+		return JobFailureStatus(s), nil
+	default:
+		return "", fmt.Errorf("invalid job failure status: %s", s)
+	}
+}
+
+// JobFailure represents an item in the dead-letter list of a job
+type JobFailure struct {
+	// ID is the unique identifier for the failure
+	ID uuid.UUID `json:"id"`
+	// JobID is the unique identifier of the job
+	JobID uuid.UUID `json:"job_id"`
+	// WorkflowType is the type of the workflow the job belongs to
+	WorkflowType WorkflowType `json:"workflow_type"`
+	// ItemID is the unique identifier of the item which failed
+	ItemID uuid.UUID `json:"item_id"`
+	// Attempts is the number of attempts made at processing the item
+	Attempts int `json:"attempts"`
+	// LastError is the error encountered on the last failed attempt
+	LastError string `json:"last_error"`
+	// Status is the status of the failure
+	Status JobFailureStatus `json:"status"`
+	// CreatedAt is the time when the item first failed
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the time when the failure was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
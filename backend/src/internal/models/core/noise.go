@@ -0,0 +1,37 @@
+// ./src/internal/models/core/noise.go
+package models
+
+// NoiseRuleType is the way a noise rule suppresses the content it matches
+type NoiseRuleType string
+
+const (
+	// NoiseRuleRegex masks the matches of the pattern, such as a timestamp within a sentence
+	NoiseRuleRegex NoiseRuleType = "regex"
+
+	// NoiseRuleIgnore drops the lines matching the pattern, such as a view counter
+	NoiseRuleIgnore NoiseRuleType = "ignore"
+)
+
+// MaxNoiseRules caps the number of noise rules of a workspace or a page
+const MaxNoiseRules = 50
+
+// NoiseRule suppresses dynamic content from the markdown of a page before it is compared
+type NoiseRule struct {
+	// Name identifies the rule when it fires
+	Name string `json:"name" validate:"required,max=64"`
+
+	// Type is the way the rule suppresses the content it matches
+	Type NoiseRuleType `json:"type" validate:"required,oneof=regex ignore"`
+
+	// Pattern is the regular expression matching the content to suppress
+	Pattern string `json:"pattern" validate:"required,max=512,regexp"`
+}
+
+// ContentFilter narrows down and normalizes the content of a page before it is compared
+type ContentFilter struct {
+	// Selectors narrow down the content of the page
+	Selectors ContentSelectors `json:"selectors"`
+
+	// NoiseRules suppress dynamic content from the markdown of the page, on top of the built-in rules
+	NoiseRules []NoiseRule `json:"noise_rules"`
+}
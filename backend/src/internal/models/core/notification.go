@@ -0,0 +1,212 @@
+// ./src/internal/models/core/notification.go
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannel is a channel the reports are sent to a member over
+type NotificationChannel string
+
+const (
+	// NotificationChannelEmail sends the reports to the email address of the member
+	NotificationChannelEmail NotificationChannel = "email"
+
+	// NotificationChannelSlack sends the reports as a direct message to the member in the slack workspace
+	NotificationChannelSlack NotificationChannel = "slack"
+)
+
+// NotificationFrequency is how often the reports are sent to a member
+// Each notification aggregates the reports created since the previous one, so that none is missed in between
+type NotificationFrequency string
+
+const (
+	// NotificationFrequencyInstant sends the reports as soon as they are dispatched
+	NotificationFrequencyInstant NotificationFrequency = "instant"
+
+	// NotificationFrequencyDaily sends the reports of the day at most once a day
+	NotificationFrequencyDaily NotificationFrequency = "daily"
+
+	// NotificationFrequencyWeekly sends the reports of the week at most once a week
+	NotificationFrequencyWeekly NotificationFrequency = "weekly"
+)
+
+// NotificationWindow is how far back the reports sent to a member may date from
+// It spans the longest period between two notifications, older reports are stale
+const NotificationWindow = 7 * 24 * time.Hour
+
+// notificationFrequencyGrace absorbs the jitter of the dispatch schedule
+// so that a member notified daily isn't skipped by a run a few minutes early
+const notificationFrequencyGrace = time.Hour
+
+// Period returns the minimum time between two notifications at the frequency
+func (f NotificationFrequency) Period() time.Duration {
+	switch f {
+	case NotificationFrequencyDaily:
+		return 24 * time.Hour
+	case NotificationFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DigestTitle returns the title of a digest sent at the frequency
+func (f NotificationFrequency) DigestTitle() string {
+	switch f {
+	case NotificationFrequencyDaily:
+		return "Daily Digest"
+	case NotificationFrequencyWeekly:
+		return "Weekly Digest"
+	default:
+		return "Digest"
+	}
+}
+
+// NotificationSubscription is the notification settings of a member of a workspace
+type NotificationSubscription struct {
+	// WorkspaceID is the workspace the member belongs to
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+
+	// UserID is the member the settings belong to
+	UserID uuid.UUID `json:"user_id"`
+
+	// CompetitorIDs are the competitors the member is notified about, all of them when empty
+	CompetitorIDs []uuid.UUID `json:"competitor_ids"`
+
+	// Categories are the categories of changes the member is notified about, all of them when empty
+	Categories []string `json:"categories"`
+
+	// Channels are the channels the member is notified over, the member is muted when empty
+	Channels []NotificationChannel `json:"channels"`
+
+	// Frequency is how often the member is notified
+	Frequency NotificationFrequency `json:"frequency"`
+
+	// LastNotifiedAt is the time the member was last notified, nil when never notified
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+
+	// CreatedAt is the time the settings were created
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the settings were last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationSubscriptionProps are the editable notification settings of a member
+type NotificationSubscriptionProps struct {
+	// CompetitorIDs are the competitors the member is notified about, all of them when empty
+	CompetitorIDs []uuid.UUID
+
+	// Categories are the categories of changes the member is notified about, all of them when empty
+	Categories []string
+
+	// Channels are the channels the member is notified over, the member is muted when empty
+	Channels []NotificationChannel
+
+	// Frequency is how often the member is notified
+	Frequency NotificationFrequency
+}
+
+// DefaultNotificationSubscription returns the settings of a member who hasn't edited them
+// Members are notified by email about every change of every competitor as soon as it is dispatched
+func DefaultNotificationSubscription(workspaceID, userID uuid.UUID) NotificationSubscription {
+	return NotificationSubscription{
+		WorkspaceID:   workspaceID,
+		UserID:        userID,
+		CompetitorIDs: []uuid.UUID{},
+		Categories:    []string{},
+		Channels:      []NotificationChannel{NotificationChannelEmail},
+		Frequency:     NotificationFrequencyInstant,
+	}
+}
+
+// IsDue returns true if the member may be notified at the time, according to the frequency
+func (s NotificationSubscription) IsDue(now time.Time) bool {
+	if len(s.Channels) == 0 {
+		return false
+	}
+	if s.LastNotifiedAt == nil {
+		return true
+	}
+	period := s.Frequency.Period()
+	if period == 0 {
+		return true
+	}
+	return now.Sub(*s.LastNotifiedAt) >= period-notificationFrequencyGrace
+}
+
+// Notifies returns true if the member is notified over the channel
+func (s NotificationSubscription) Notifies(channel NotificationChannel) bool {
+	return slices.Contains(s.Channels, channel)
+}
+
+// Wants returns true if the member is notified about the competitor over the channel
+func (s NotificationSubscription) Wants(competitorID uuid.UUID, channel NotificationChannel) bool {
+	if !s.Notifies(channel) {
+		return false
+	}
+	return len(s.CompetitorIDs) == 0 || slices.Contains(s.CompetitorIDs, competitorID)
+}
+
+// FilterChanges returns the changes of the categories the member is notified about
+func (s NotificationSubscription) FilterChanges(changes []CategoryChange) []CategoryChange {
+	return FilterCategoryChanges(changes, s.Categories)
+}
+
+// NotifiedSince returns the time after which the reports are new to the member
+// Members never notified, or notified before the notification window, get the reports of the window
+func (s NotificationSubscription) NotifiedSince(now time.Time) time.Time {
+	since := now.Add(-NotificationWindow)
+	if s.LastNotifiedAt != nil && s.LastNotifiedAt.After(since) {
+		return *s.LastNotifiedAt
+	}
+	return since
+}
+
+// FilterReports returns the reports created since the member was last notified, within the notification window
+func (s NotificationSubscription) FilterReports(reports []Report, now time.Time) []Report {
+	since := s.NotifiedSince(now)
+	filtered := make([]Report, 0, len(reports))
+	for _, report := range reports {
+		if report.Time.After(since) {
+			filtered = append(filtered, report)
+		}
+	}
+	return filtered
+}
+
+// FilterDigest returns the digest of the competitors and categories the member is notified about
+// Reports created before the member was last notified were already sent to them, and are left out
+func (s NotificationSubscription) FilterDigest(digest Digest) Digest {
+	filtered := digest.Filter(s.CompetitorIDs, s.Categories)
+	if s.LastNotifiedAt == nil {
+		return filtered
+	}
+	return filtered.ReportedAfter(*s.LastNotifiedAt)
+}
+
+// FilterCategoryChanges returns the changes of the categories, all of them when no category is given
+func FilterCategoryChanges(changes []CategoryChange, categories []string) []CategoryChange {
+	if len(categories) == 0 {
+		return changes
+	}
+	filtered := make([]CategoryChange, 0, len(changes))
+	for _, change := range changes {
+		if slices.Contains(categories, change.Category) {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered
+}
+
+// NotificationSubscriber is a member of a workspace due to be notified, along with their settings
+type NotificationSubscriber struct {
+	NotificationSubscription
+
+	// Email is the email address of the member
+	Email string `json:"email"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNotificationSubscriptionHonorsSettings(t *testing.T) {
+	now := time.Now()
+	competitorID, otherCompetitorID := uuid.New(), uuid.New()
+
+	// Members who never edited their settings are emailed about everything, every time
+	subscription := DefaultNotificationSubscription(uuid.New(), uuid.New())
+	if !subscription.IsDue(now) || !subscription.Wants(competitorID, NotificationChannelEmail) {
+		t.Fatal("expected the default subscription to be emailed about every competitor")
+	}
+	if subscription.Wants(competitorID, NotificationChannelSlack) {
+		t.Fatal("expected the default subscription not to be sent slack messages")
+	}
+
+	// Daily subscribers are skipped until a day went by, give or take the schedule jitter
+	subscription.Frequency = NotificationFrequencyDaily
+	lastNotifiedAt := now.Add(-12 * time.Hour)
+	subscription.LastNotifiedAt = &lastNotifiedAt
+	if subscription.IsDue(now) {
+		t.Fatal("expected a daily subscriber notified 12h ago not to be due")
+	}
+	lastNotifiedAt = now.Add(-23*time.Hour - 30*time.Minute)
+	if !subscription.IsDue(now) {
+		t.Fatal("expected a daily subscriber notified 23h30m ago to be due")
+	}
+
+	// Subscribers only get the competitors and categories they follow
+	subscription.CompetitorIDs = []uuid.UUID{competitorID}
+	subscription.Categories = []string{"pricing"}
+	if subscription.Wants(otherCompetitorID, NotificationChannelEmail) {
+		t.Fatal("expected the subscriber not to want a competitor they don't follow")
+	}
+	changes := subscription.FilterChanges([]CategoryChange{{Category: "pricing"}, {Category: "branding"}})
+	if len(changes) != 1 || changes[0].Category != "pricing" {
+		t.Fatalf("expected the pricing changes only, got %v", changes)
+	}
+
+	// Muted subscribers are never due
+	subscription.Channels = nil
+	if subscription.IsDue(now) {
+		t.Fatal("expected a muted subscriber not to be due")
+	}
+}
+
+func TestNotificationSubscriptionSkipsReportsAlreadyNotified(t *testing.T) {
+	now := time.Now()
+	lastNotifiedAt := now.Add(-24 * time.Hour)
+	changes := []CategoryChange{{Category: "pricing", Changes: []string{"raised the pro plan"}}}
+	sent := Report{ID: uuid.New(), CompetitorID: uuid.New(), CompetitorName: "Acme", Time: lastNotifiedAt.Add(-time.Hour), Changes: changes}
+	fresh := Report{ID: uuid.New(), CompetitorID: uuid.New(), CompetitorName: "Globex", Time: lastNotifiedAt.Add(time.Hour), Changes: changes}
+	digest := NewDigest(uuid.New(), []Report{sent, fresh}, now.Add(-7*24*time.Hour))
+
+	// Members never notified get every report of the digest
+	subscription := DefaultNotificationSubscription(uuid.New(), uuid.New())
+	if filtered := subscription.FilterDigest(digest); len(filtered.Entries) != 2 {
+		t.Fatalf("expected 2 entries for a member never notified, got %d", len(filtered.Entries))
+	}
+
+	// The report created before the last notification was already sent
+	subscription.LastNotifiedAt = &lastNotifiedAt
+	filtered := subscription.FilterDigest(digest)
+	if len(filtered.Entries) != 1 || filtered.Entries[0].ReportID != fresh.ID {
+		t.Fatalf("expected the report created since the last notification only, got %v", filtered.Entries)
+	}
+
+	// Once notified of every report, the digest is empty
+	lastNotifiedAt = now
+	if filtered := subscription.FilterDigest(digest); !filtered.IsEmpty() {
+		t.Fatalf("expected no entry once notified of every report, got %v", filtered.Entries)
+	}
+}
+
+func TestNotificationSubscriptionAggregatesReportsSinceNotified(t *testing.T) {
+	now := time.Now()
+	competitorID := uuid.New()
+	lastNotifiedAt := now.Add(-6 * 24 * time.Hour)
+	sent := Report{ID: uuid.New(), CompetitorID: competitorID, CompetitorName: "Acme", Time: lastNotifiedAt.Add(-time.Hour), Changes: []CategoryChange{
+		{Category: "branding", Changes: []string{"new logo"}},
+	}}
+	earlier := Report{ID: uuid.New(), CompetitorID: competitorID, CompetitorName: "Acme", Time: now.Add(-4 * 24 * time.Hour), Changes: []CategoryChange{
+		{Category: "pricing", Changes: []string{"raised the pro plan"}},
+	}}
+	latest := Report{ID: uuid.New(), CompetitorID: competitorID, CompetitorName: "Acme", Time: now.Add(-time.Hour), Changes: []CategoryChange{
+		{Category: "pricing", Changes: []string{"raised the pro plan", "dropped the free plan"}},
+		{Category: "product", Changes: []string{"launched an API"}},
+	}}
+	reports := []Report{latest, earlier, sent}
+
+	// A weekly subscriber gets every report created since they were last notified, not the latest one only
+	subscription := DefaultNotificationSubscription(uuid.New(), uuid.New())
+	subscription.Frequency = NotificationFrequencyWeekly
+	subscription.LastNotifiedAt = &lastNotifiedAt
+	report, ok := AggregateReports(subscription.FilterReports(reports, now))
+	if !ok || report.ID != latest.ID {
+		t.Fatalf("expected the reports to be aggregated into the latest one, got %v", report)
+	}
+	if len(report.Changes) != 2 || report.Changes[0].Category != "pricing" || len(report.Changes[0].Changes) != 2 {
+		t.Fatalf("expected the pricing changes of both reports once, then the product changes, got %v", report.Changes)
+	}
+
+	// The digest aggregates the same reports into the entry of the competitor
+	digest := subscription.FilterDigest(NewDigest(uuid.New(), reports, now.Add(-NotificationWindow)))
+	if len(digest.Entries) != 1 || len(digest.Entries[0].ReportIDs) != 2 || digest.Entries[0].ReportID != latest.ID {
+		t.Fatalf("expected a single entry built from the 2 reports since the last notification, got %v", digest.Entries)
+	}
+}
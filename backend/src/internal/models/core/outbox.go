@@ -0,0 +1,105 @@
+// ./src/internal/models/core/outbox.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEmailStatus is the delivery status of an email of the outbox
+type OutboxEmailStatus string
+
+const (
+	// OutboxEmailStatusQueued is the status of an email waiting to be sent, or to be retried
+	OutboxEmailStatusQueued OutboxEmailStatus = "queued"
+
+	// OutboxEmailStatusSent is the status of an email accepted by the email provider
+	OutboxEmailStatusSent OutboxEmailStatus = "sent"
+
+	// OutboxEmailStatusFailed is the status of an email which couldn't be sent once its attempts were exhausted
+	OutboxEmailStatusFailed OutboxEmailStatus = "failed"
+
+	// OutboxEmailStatusBounced is the status of an email rejected for its recipients, it isn't retried
+	OutboxEmailStatusBounced OutboxEmailStatus = "bounced"
+)
+
+// OutboxEmail is an email queued in the outbox, along with the tracking of its delivery
+type OutboxEmail struct {
+	// ID is the unique identifier of the email
+	ID uuid.UUID `json:"id"`
+
+	// WorkspaceID is the workspace the email was sent on behalf of, nil for the emails outside of a workspace
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty"`
+
+	// ReportIDs are the reports carried by the email
+	ReportIDs []uuid.UUID `json:"report_ids"`
+
+	// To are the recipients of the email
+	To []string `json:"to"`
+
+	// Subject is the subject of the email
+	Subject string `json:"subject"`
+
+	// Content is the body of the email, left out of the responses
+	Content string `json:"-"`
+
+	// Format is the format of the body of the email
+	Format EmailFormat `json:"format"`
+
+	// Attachments are the files attached to the email, left out of the responses
+	Attachments []EmailAttachment `json:"-"`
+
+	// Status is the delivery status of the email
+	Status OutboxEmailStatus `json:"status"`
+
+	// Attempts is the number of attempts made at sending the email
+	Attempts int `json:"attempts"`
+
+	// LastError is the error of the latest failed attempt
+	LastError string `json:"last_error,omitempty"`
+
+	// ProviderMessageID is the identifier the email provider assigned to the email once sent
+	ProviderMessageID string `json:"provider_message_id,omitempty"`
+
+	// NextAttemptAt is when the email is due to be sent, nil once it's no longer retried
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// SentAt is when the email was accepted by the email provider
+	SentAt *time.Time `json:"sent_at,omitempty"`
+
+	// CreatedAt is when the email was queued
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the email was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Email returns the email to be handed to the email client
+func (e OutboxEmail) Email() Email {
+	return Email{
+		To:           e.To,
+		EmailFormat:  e.Format,
+		EmailContent: e.Content,
+		EmailSubject: e.Subject,
+		Attachments:  e.Attachments,
+	}
+}
+
+// OutboxEmailAttempt is the outcome of an attempt at sending an email of the outbox
+type OutboxEmailAttempt struct {
+	// Status is the status of the email following the attempt
+	Status OutboxEmailStatus
+
+	// Error is the error of the attempt, empty when it succeeded
+	Error string
+
+	// ProviderMessageID is the identifier the email provider assigned to the email, empty when it wasn't sent
+	ProviderMessageID string
+
+	// NextAttemptAt is when the email is retried, nil when it isn't
+	NextAttemptAt *time.Time
+
+	// AttemptedAt is when the attempt was made
+	AttemptedAt time.Time
+}
@@ -9,7 +9,6 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/wizenheimer/byrd/src/pkg/utils"
 )
@@ -20,6 +19,20 @@ type PageStatus string
 // DiffProfile is the profile used to diff the page
 type DiffProfile []string
 
+const (
+	// DefaultCheckInterval is the default interval between checks of a page, in minutes
+	DefaultCheckInterval = 7 * 24 * 60
+
+	// MinCheckInterval is the shortest interval between checks of a page, in minutes
+	MinCheckInterval = 60
+
+	// MaxCheckInterval is the longest interval between checks of a page, in minutes
+	MaxCheckInterval = 30 * 24 * 60
+
+	// DefaultVisualDiffThreshold is the default percentage of changed pixels from which a visual change is significant
+	DefaultVisualDiffThreshold = 5.0
+)
+
 const (
 	// When PageStatusActive, the page is active and will be checked for changes
 	PageStatusActive PageStatus = "active"
@@ -48,6 +61,15 @@ type Page struct {
 	// DiffProfile is the profile used to diff the page
 	DiffProfile DiffProfile `json:"diff_profile" default:"[\"branding\", \"customers\", \"integration\", \"product\", \"pricing\", \"partnerships\", \"messaging\"]"`
 
+	// CheckInterval is the interval between checks of the page, in minutes
+	CheckInterval int `json:"check_interval"`
+
+	// ContentSelectors narrow down the content of the page before it is diffed
+	ContentSelectors ContentSelectors `json:"content_selectors"`
+
+	// NoiseRules suppress dynamic content of the page before it is diffed
+	NoiseRules []NoiseRule `json:"noise_rules"`
+
 	// LastCheckedAt is the time the page was last checked
 	// this is updated after every check
 	LastCheckedAt sql.NullTime `json:"last_checked_at,omitempty"`
@@ -90,11 +112,57 @@ type PageProps struct {
 	// DiffProfile is the profile used to diff the page
 	// This is optional and defaults to an default diff profile
 	DiffProfile DiffProfile `json:"diff_profile" validate:"dive,oneof=branding customers integration product pricing partnerships messaging" default:"[\"branding\", \"customers\", \"integration\", \"product\", \"pricing\", \"partnerships\", \"messaging\"]"`
+
+	// CheckInterval is the interval between checks of the page, in minutes
+	// This is optional and defaults to the default check interval
+	CheckInterval int `json:"check_interval" validate:"omitempty,min=60,max=43200"`
+
+	// ContentSelectors narrow down the content of the page before it is diffed
+	// This is optional and defaults to the whole page
+	ContentSelectors *ContentSelectors `json:"content_selectors,omitempty"`
+
+	// NoiseRules suppress dynamic content of the page before it is diffed
+	// This is optional and applies on top of the rules of the workspace
+	NoiseRules []NoiseRule `json:"noise_rules,omitempty" validate:"omitempty,max=50,dive"`
+}
+
+// ContentSelectors are the CSS selectors narrowing down the content of a page before it is diffed
+// These don't affect the screenshot of the page
+type ContentSelectors struct {
+	// Include are the selectors of the elements to keep, the whole page is kept when empty
+	Include []string `json:"include,omitempty" validate:"omitempty,max=25,dive,required,css_selector"`
+
+	// Exclude are the selectors of the elements to drop, such as footers, cookie banners or testimonials
+	Exclude []string `json:"exclude,omitempty" validate:"omitempty,max=25,dive,required,css_selector"`
+}
+
+// IsEmpty returns true if the selectors keep the whole page
+func (cs ContentSelectors) IsEmpty() bool {
+	return len(cs.Include) == 0 && len(cs.Exclude) == 0
+}
+
+// ContentExtraction is the content of a capture narrowed down by the content selectors
+type ContentExtraction struct {
+	// CapturedAt is the time of the capture the content was extracted from
+	CapturedAt time.Time `json:"captured_at"`
+
+	// Selectors are the content selectors applied to the capture
+	Selectors ContentSelectors `json:"selectors"`
+
+	// Content is the extracted content, in markdown, as it is compared
+	Content string `json:"content"`
+
+	// NoiseRules are the names of the noise rules which fired on the content
+	NoiseRules []string `json:"noise_rules"`
 }
 
 // Capture Profile defines the options for capturing a screenshot
 // This is a sub set of the ScreenshotRequestOptions that are exposed to the user
 type CaptureProfile struct {
+	// Provider Options
+	// The html provider fetches the raw html without a screenshot, suited for text-only pages
+	Provider *CaptureProviderType `json:"provider,omitempty" validate:"omitempty,oneof=screenshotone html" default:"screenshotone"`
+
 	// Selector Options
 	Selector              *string `json:"selector,omitempty"`
 	ScrollIntoView        *string `json:"scroll_into_view,omitempty"`
@@ -110,6 +178,11 @@ type CaptureProfile struct {
 	MaxHeight         *int               `json:"max_height,omitempty"`
 	OmitBackground    *bool              `json:"omit_background,omitempty"`
 
+	// Visual Diff Options
+	// VisualDiffThreshold is the percentage of changed pixels from which a visual change is significant
+	// Significant visual changes are analyzed and recorded in the page history
+	VisualDiffThreshold *float64 `json:"visual_diff_threshold,omitempty" validate:"omitempty,gte=0,lte=100"`
+
 	// Clip Options
 	Clip *ClipOptions `json:"clip,omitempty"`
 
@@ -162,35 +235,42 @@ func NewPageProps(pageURL string, diffProfile DiffProfile) (PageProps, error) {
 		URL:            pageURL,
 		CaptureProfile: &cp,
 		DiffProfile:    diffProfile,
+		CheckInterval:  DefaultCheckInterval,
 	}, nil
 }
 
 // pageJSON is an internal type for JSON marshaling/unmarshaling
 type pageJSON struct {
-	ID             string         `json:"id"`
-	CompetitorID   string         `json:"competitor_id"`
-	URL            string         `json:"url"`
-	Title          string         `json:"title"`
-	CaptureProfile CaptureProfile `json:"capture_profile"`
-	DiffProfile    DiffProfile    `json:"diff_profile"`
-	LastCheckedAt  *time.Time     `json:"last_checked_at,omitempty"`
-	Status         PageStatus     `json:"status"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+	ID               string           `json:"id"`
+	CompetitorID     string           `json:"competitor_id"`
+	URL              string           `json:"url"`
+	Title            string           `json:"title"`
+	CaptureProfile   CaptureProfile   `json:"capture_profile"`
+	DiffProfile      DiffProfile      `json:"diff_profile"`
+	CheckInterval    int              `json:"check_interval"`
+	ContentSelectors ContentSelectors `json:"content_selectors"`
+	NoiseRules       []NoiseRule      `json:"noise_rules"`
+	LastCheckedAt    *time.Time       `json:"last_checked_at,omitempty"`
+	Status           PageStatus       `json:"status"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 // MarshalJSON implements custom JSON marshaling for Page
 func (p Page) MarshalJSON() ([]byte, error) {
 	page := pageJSON{
-		ID:             p.ID.String(),
-		CompetitorID:   p.CompetitorID.String(),
-		URL:            p.URL,
-		Title:          p.Title,
-		CaptureProfile: p.CaptureProfile,
-		DiffProfile:    p.DiffProfile,
-		Status:         p.Status,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:               p.ID.String(),
+		CompetitorID:     p.CompetitorID.String(),
+		URL:              p.URL,
+		Title:            p.Title,
+		CaptureProfile:   p.CaptureProfile,
+		DiffProfile:      p.DiffProfile,
+		CheckInterval:    p.CheckInterval,
+		ContentSelectors: p.ContentSelectors,
+		NoiseRules:       p.NoiseRules,
+		Status:           p.Status,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
 	}
 
 	if p.LastCheckedAt.Valid {
@@ -224,6 +304,9 @@ func (p *Page) UnmarshalJSON(data []byte) error {
 	p.URL = page.URL
 	p.CaptureProfile = page.CaptureProfile
 	p.DiffProfile = page.DiffProfile
+	p.CheckInterval = page.CheckInterval
+	p.ContentSelectors = page.ContentSelectors
+	p.NoiseRules = page.NoiseRules
 	p.Status = page.Status
 	p.CreatedAt = page.CreatedAt
 	p.UpdatedAt = page.UpdatedAt
@@ -240,7 +323,7 @@ func (p *Page) UnmarshalJSON(data []byte) error {
 	}
 
 	// Validate required fields and URL format
-	validate := validator.New()
+	validate := utils.GetValidator()
 	if err := validate.Struct(p); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -248,6 +331,14 @@ func (p *Page) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// GetVisualDiffThreshold returns the visual diff threshold of the capture profile, defaulting to DefaultVisualDiffThreshold
+func (cp CaptureProfile) GetVisualDiffThreshold() float64 {
+	if cp.VisualDiffThreshold == nil {
+		return DefaultVisualDiffThreshold
+	}
+	return *cp.VisualDiffThreshold
+}
+
 // GetDefaultDiffProfile returns the default diff profile
 func GetDefaultDiffProfile() DiffProfile {
 	return []string{
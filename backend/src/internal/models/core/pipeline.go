@@ -0,0 +1,244 @@
+// ./src/internal/models/core/pipeline.go
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPipeline is the pipeline run when none is specified
+// Pages are captured first, reports are generated from the captures, and dispatched once generated
+var DefaultPipeline = []WorkflowType{ScreenshotWorkflowType, ReportWorkflowType, DispatchWorkflowType}
+
+// ValidatePipeline checks that the pipeline has stages, and runs each workflow once
+func ValidatePipeline(stages []WorkflowType) error {
+	if len(stages) == 0 {
+		return fmt.Errorf("pipeline has no stages")
+	}
+
+	seen := make(map[WorkflowType]bool, len(stages))
+	for _, stage := range stages {
+		if _, err := ParseWorkflowType(string(stage)); err != nil {
+			return err
+		}
+		if seen[stage] {
+			return fmt.Errorf("pipeline runs %s more than once", stage)
+		}
+		seen[stage] = true
+	}
+
+	return nil
+}
+
+// PipelineStatus is an enum for the status of a pipeline run
+type PipelineStatus string
+
+const (
+	PipelineStatusRunning   PipelineStatus = "running"
+	PipelineStatusCompleted PipelineStatus = "completed"
+	PipelineStatusFailed    PipelineStatus = "failed"
+	PipelineStatusAborted   PipelineStatus = "aborted"
+)
+
+func ParsePipelineStatus(s string) (PipelineStatus, error) {
+	switch PipelineStatus(s) {
+	case PipelineStatusRunning, PipelineStatusCompleted,
+		PipelineStatusFailed, PipelineStatusAborted:
+		return PipelineStatus(s), nil
+	default:
+		return "", fmt.Errorf("invalid pipeline status: %s", s)
+	}
+}
+
+// PipelineStageStatus is an enum for the status of a stage of a pipeline run
+type PipelineStageStatus string
+
+const (
+	// pending - the stage waits for the previous stages to complete
+	PipelineStagePending PipelineStageStatus = "pending"
+
+	// starting - the job of the stage is being submitted, under the job ID of the stage
+	PipelineStageStarting PipelineStageStatus = "starting"
+
+	// running - the job of the stage is running, or paused until it's resumed
+	PipelineStageRunning PipelineStageStatus = "running"
+
+	// completed - the job of the stage completed, the next stage starts
+	PipelineStageCompleted PipelineStageStatus = "completed"
+
+	// failed - the job of the stage failed, the next stages are skipped
+	PipelineStageFailed PipelineStageStatus = "failed"
+
+	// aborted - the job of the stage was cancelled, the next stages are skipped
+	PipelineStageAborted PipelineStageStatus = "aborted"
+
+	// skipped - a previous stage didn't complete, the stage never ran
+	PipelineStageSkipped PipelineStageStatus = "skipped"
+)
+
+// IsFinal returns true if the stage won't change anymore
+func (s PipelineStageStatus) IsFinal() bool {
+	return s != PipelineStagePending && s != PipelineStageStarting && s != PipelineStageRunning
+}
+
+// StageStatusForJob returns the status of a stage from the state of its job
+// A job which completed without completing or skipping any of the items it failed on is a failed stage
+func StageStatusForJob(state JobState) PipelineStageStatus {
+	switch state.Status {
+	case JobStatusRunning, JobStatusPaused:
+		return PipelineStageRunning
+	case JobStatusCompleted:
+		if state.Failed > 0 && state.Completed == 0 && state.Skipped == 0 {
+			return PipelineStageFailed
+		}
+		return PipelineStageCompleted
+	case JobStatusAborted:
+		return PipelineStageAborted
+	default:
+		return PipelineStageFailed
+	}
+}
+
+// PipelineStage is a stage of a pipeline run
+type PipelineStage struct {
+	// WorkflowType is the workflow run by the stage
+	WorkflowType WorkflowType `json:"workflow_type"`
+
+	// Status is the status of the stage
+	Status PipelineStageStatus `json:"status"`
+
+	// JobID is the job running the stage, once the stage started
+	JobID *uuid.UUID `json:"job_id,omitempty"`
+
+	// Completed is the number of items completed by the job of the stage
+	Completed int64 `json:"completed"`
+
+	// Failed is the number of items failed by the job of the stage
+	Failed int64 `json:"failed"`
+
+	// Skipped is the number of items skipped by the job of the stage
+	Skipped int64 `json:"skipped"`
+
+	// StartTime is the time when the stage started
+	StartTime *time.Time `json:"start_time,omitempty"`
+
+	// EndTime is the time when the stage ended
+	EndTime *time.Time `json:"end_time,omitempty"`
+}
+
+// PipelineRun is a run of a pipeline, each stage starting once the previous one completed
+type PipelineRun struct {
+	// ID is the unique identifier of the run
+	ID uuid.UUID `json:"id"`
+
+	// Status is the status of the run
+	Status PipelineStatus `json:"status"`
+
+	// Stages are the stages of the run, in the order they run
+	Stages []PipelineStage `json:"stages"`
+
+	// Scope is the scope of the job of every stage
+	Scope JobScope `json:"scope"`
+
+	// StartTime is the time when the run started
+	StartTime time.Time `json:"start_time"`
+
+	// EndTime is the time when the run ended
+	EndTime *time.Time `json:"end_time,omitempty"`
+}
+
+// NewPipelineRun creates a run of the pipeline, limited to the items within the scope
+func NewPipelineRun(stages []WorkflowType, scope JobScope) *PipelineRun {
+	run := PipelineRun{
+		ID:        uuid.New(),
+		Status:    PipelineStatusRunning,
+		Stages:    make([]PipelineStage, 0, len(stages)),
+		Scope:     scope,
+		StartTime: time.Now(),
+	}
+
+	for _, stage := range stages {
+		run.Stages = append(run.Stages, PipelineStage{
+			WorkflowType: stage,
+			Status:       PipelineStagePending,
+		})
+	}
+
+	return &run
+}
+
+// CurrentStage returns the index of the first stage which didn't end, or false if every stage ended
+func (r *PipelineRun) CurrentStage() (int, bool) {
+	for index, stage := range r.Stages {
+		if !stage.Status.IsFinal() {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// PrepareStage marks the stage as starting the job, the job ID is set ahead of submitting the job
+// Persisting the run before the submission lets the replica taking it over submit the same job, rather than another one
+func (r *PipelineRun) PrepareStage(index int, jobID uuid.UUID) {
+	r.Stages[index].Status = PipelineStageStarting
+	r.Stages[index].JobID = &jobID
+}
+
+// StartStage marks the stage as running the job
+func (r *PipelineRun) StartStage(index int, jobID uuid.UUID) {
+	now := time.Now()
+	r.Stages[index].Status = PipelineStageRunning
+	r.Stages[index].JobID = &jobID
+	r.Stages[index].StartTime = &now
+}
+
+// UpdateStage updates the stage from the state of its job
+// The next stages are skipped and the run ends as soon as a stage fails or is aborted
+func (r *PipelineRun) UpdateStage(index int, state JobState) {
+	stage := &r.Stages[index]
+	stage.Status = StageStatusForJob(state)
+	stage.Completed = state.Completed
+	stage.Failed = state.Failed
+	stage.Skipped = state.Skipped
+
+	if !stage.Status.IsFinal() {
+		return
+	}
+
+	now := time.Now()
+	stage.EndTime = &now
+
+	switch stage.Status {
+	case PipelineStageCompleted:
+		if _, ok := r.CurrentStage(); !ok {
+			r.end(PipelineStatusCompleted)
+		}
+	case PipelineStageAborted:
+		r.end(PipelineStatusAborted)
+	default:
+		r.end(PipelineStatusFailed)
+	}
+}
+
+// end ends the run with the status, skipping the stages which didn't start
+func (r *PipelineRun) end(status PipelineStatus) {
+	for index := range r.Stages {
+		if r.Stages[index].Status == PipelineStagePending {
+			r.Stages[index].Status = PipelineStageSkipped
+		}
+	}
+
+	now := time.Now()
+	r.Status = status
+	r.EndTime = &now
+}
+
+// Fail ends the run as failed, along with the stage which couldn't run
+func (r *PipelineRun) Fail(index int) {
+	now := time.Now()
+	r.Stages[index].Status = PipelineStageFailed
+	r.Stages[index].EndTime = &now
+	r.end(PipelineStatusFailed)
+}
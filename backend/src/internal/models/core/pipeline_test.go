@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPipelineRunSkipsStagesAfterFailure(t *testing.T) {
+	run := NewPipelineRun(DefaultPipeline, JobScope{})
+
+	// The screenshot stage completes, the report stage starts next
+	run.StartStage(0, uuid.New())
+	run.UpdateStage(0, JobState{Status: JobStatusCompleted, Completed: 3, Failed: 1})
+	if index, ok := run.CurrentStage(); !ok || index != 1 {
+		t.Fatalf("expected the report stage to be current, got %d", index)
+	}
+
+	// The report stage fails every item, the dispatch stage never runs
+	run.StartStage(1, uuid.New())
+	run.UpdateStage(1, JobState{Status: JobStatusCompleted, Failed: 2})
+
+	if run.Status != PipelineStatusFailed || run.EndTime == nil {
+		t.Fatalf("expected the run to fail, got %s", run.Status)
+	}
+	if run.Stages[1].Status != PipelineStageFailed {
+		t.Fatalf("expected the report stage to fail, got %s", run.Stages[1].Status)
+	}
+	if run.Stages[2].Status != PipelineStageSkipped {
+		t.Fatalf("expected the dispatch stage to be skipped, got %s", run.Stages[2].Status)
+	}
+	if _, ok := run.CurrentStage(); ok {
+		t.Fatal("expected every stage to have ended")
+	}
+}
+
+func TestPipelineRunWaitsForPausedStage(t *testing.T) {
+	run := NewPipelineRun(DefaultPipeline, JobScope{})
+
+	// A paused job holds the pipeline until it's resumed, rather than failing its stage
+	run.StartStage(0, uuid.New())
+	run.UpdateStage(0, JobState{Status: JobStatusPaused, Completed: 2})
+
+	if run.Status != PipelineStatusRunning || run.EndTime != nil {
+		t.Fatalf("expected the run to keep running, got %s", run.Status)
+	}
+	if index, ok := run.CurrentStage(); !ok || index != 0 {
+		t.Fatalf("expected the screenshot stage to be current, got %d", index)
+	}
+}
+
+func TestPipelineRunKeepsJobIDOfStartingStage(t *testing.T) {
+	run := NewPipelineRun(DefaultPipeline, JobScope{})
+
+	// The job ID is set before the job is submitted, the stage starts under it once submitted
+	jobID := uuid.New()
+	run.PrepareStage(0, jobID)
+	if index, ok := run.CurrentStage(); !ok || index != 0 || run.Stages[0].Status != PipelineStageStarting {
+		t.Fatalf("expected the screenshot stage to be starting, got %s", run.Stages[0].Status)
+	}
+
+	run.StartStage(0, *run.Stages[0].JobID)
+	if run.Stages[0].Status != PipelineStageRunning || *run.Stages[0].JobID != jobID {
+		t.Fatalf("expected the screenshot stage to run job %s, got %v", jobID, run.Stages[0].JobID)
+	}
+}
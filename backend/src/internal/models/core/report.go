@@ -1,6 +1,7 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,3 +28,51 @@ func NewReport(workspaceID, competitorID uuid.UUID, competitorName string, chang
 		Time:           time.Now(),
 	}
 }
+
+// AggregateReports combines the reports of a competitor into the newest of them, carrying the changes of every one
+// The changes are merged by category, newest first, and a change repeated across the reports is kept once
+// Returns false when no report is given
+func AggregateReports(reports []Report) (Report, bool) {
+	if len(reports) == 0 {
+		return Report{}, false
+	}
+
+	sorted := slices.Clone(reports)
+	slices.SortStableFunc(sorted, func(a, b Report) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	merged := make([]CategoryChange, 0)
+	categories := make(map[string]int)
+	for _, report := range sorted {
+		for _, change := range report.Changes {
+			i, ok := categories[change.Category]
+			if !ok {
+				categories[change.Category] = len(merged)
+				merged = append(merged, CategoryChange{Category: change.Category, Summary: change.Summary, Changes: slices.Clone(change.Changes)})
+				continue
+			}
+			if change.Summary != "" && change.Summary != merged[i].Summary {
+				merged[i].Summary += " " + change.Summary
+			}
+			for _, detail := range change.Changes {
+				if !slices.Contains(merged[i].Changes, detail) {
+					merged[i].Changes = append(merged[i].Changes, detail)
+				}
+			}
+		}
+	}
+
+	aggregate := sorted[0]
+	aggregate.Changes = merged
+	return aggregate, true
+}
+
+// ReportIDs returns the IDs of the reports
+func ReportIDs(reports []Report) []uuid.UUID {
+	reportIDs := make([]uuid.UUID, 0, len(reports))
+	for _, report := range reports {
+		reportIDs = append(reportIDs, report.ID)
+	}
+	return reportIDs
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"image"
 	"strconv"
+	"time"
 )
 
 // ScreenshotContent defines the response structure for screenshot content requests
@@ -22,11 +23,31 @@ type ScreenshotImage struct {
 	Metadata    *ScreenshotMetadata `json:"metadata,omitempty"`
 }
 
+// ScreenshotCapture describes a single capture in the timeline of a page
+type ScreenshotCapture struct {
+	CapturedAt  time.Time `json:"captured_at"`
+	ImagePath   string    `json:"image_path,omitempty"`
+	ContentPath string    `json:"content_path,omitempty"`
+}
+
 // ScreenshotMetadata defines complete metadata for a screenshot
 type ScreenshotMetadata struct {
-	Year       int `json:"year"`
-	WeekNumber int `json:"week_number"`
-	WeekDay    int `json:"week_day"`
+	Year       int       `json:"year"`
+	WeekNumber int       `json:"week_number"`
+	WeekDay    int       `json:"week_day"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// NewScreenshotMetadata creates the metadata for a screenshot captured at the given time
+func NewScreenshotMetadata(capturedAt time.Time) *ScreenshotMetadata {
+	capturedAt = capturedAt.UTC().Truncate(time.Second)
+	year, weekNumber := capturedAt.ISOWeek()
+	return &ScreenshotMetadata{
+		Year:       year,
+		WeekNumber: weekNumber,
+		WeekDay:    int(capturedAt.Weekday()),
+		CapturedAt: capturedAt,
+	}
 }
 
 // ToMap safely converts ScreenshotMetadata to map[string]string.
@@ -53,6 +74,11 @@ func (s ScreenshotMetadata) ToMap() (map[string]string, error) {
 	}
 	result["week_number"] = strconv.Itoa(s.WeekNumber)
 
+	// CapturedAt is optional for screenshots stored prior to its introduction
+	if !s.CapturedAt.IsZero() {
+		result["captured_at"] = s.CapturedAt.UTC().Format(time.RFC3339)
+	}
+
 	// Return errors if any occurred
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("validation errors: %v", errs)
@@ -98,6 +124,15 @@ func ScreenshotMetadataFromMap(m map[string]string) (*ScreenshotMetadata, error)
 		errs = append(errs, errors.New("missing required field: week_number"))
 	}
 
+	// Optional time fields
+	if capturedAt, exists := m["captured_at"]; exists {
+		if t, err := time.Parse(time.RFC3339, capturedAt); err == nil {
+			result.CapturedAt = t
+		} else {
+			errs = append(errs, fmt.Errorf("invalid captured_at: %s", err))
+		}
+	}
+
 	// Return errors if any occurred
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("validation errors: %v", errs)
@@ -118,6 +118,21 @@ const (
 	IpCountryIE IpCountry = "ie"
 )
 
+// CaptureProviderType defines the provider used to capture a page
+type CaptureProviderType string
+
+const (
+	// CaptureProviderScreenshotOne captures a screenshot and the rendered html using ScreenshotOne
+	CaptureProviderScreenshotOne CaptureProviderType = "screenshotone"
+	// CaptureProviderHTML fetches the raw html over plain http, without a screenshot
+	CaptureProviderHTML CaptureProviderType = "html"
+)
+
+// CapturesScreenshot reports whether the provider captures a screenshot alongside the content
+func (p CaptureProviderType) CapturesScreenshot() bool {
+	return p != CaptureProviderHTML
+}
+
 // FullPageAlgorithm defines the algorithm for full page screenshots
 type FullPageAlgorithm string
 
@@ -128,6 +143,9 @@ const (
 
 type ScreenshotRequestOptions struct {
 	URL string `json:"url"`
+	// Provider Options
+	Provider *CaptureProviderType `json:"provider,omitempty" default:"screenshotone"`
+
 	// Selector Options
 	Selector              *string `json:"selector,omitempty"`
 	ScrollIntoView        *string `json:"scroll_into_view,omitempty"`
@@ -197,6 +215,9 @@ type ScreenshotRequestOptions struct {
 
 func GetScreenshotRequestOptions(url string, captureProfile CaptureProfile) ScreenshotRequestOptions {
 	options := GetDefaultScreenshotRequestOptions(url)
+	if captureProfile.Provider != nil {
+		options.Provider = captureProfile.Provider
+	}
 	if captureProfile.Selector != nil {
 		options.Selector = captureProfile.Selector
 	}
@@ -339,6 +360,11 @@ func GetDefaultScreenshotRequestOptions(url string) ScreenshotRequestOptions {
 	return defaultOpts
 }
 
+// GetProvider returns the provider used to capture the page, defaulting to ScreenshotOne
+func (s *ScreenshotRequestOptions) GetProvider() CaptureProviderType {
+	return getPointerValue(s.Provider, CaptureProviderScreenshotOne)
+}
+
 // Hash generates a deterministic hash of the ScreenshotRequestOptions
 func (s *ScreenshotRequestOptions) Hash() string {
 	// Create a normalized version of the struct for consistent hashing
@@ -355,6 +381,7 @@ func (s *ScreenshotRequestOptions) Hash() string {
 // normalizedOptions is a flat structure used for consistent hashing
 type normalizedOptions struct {
 	URL                      string            `json:"url"`
+	Provider                 string            `json:"provider,omitempty"`
 	Selector                 string            `json:"selector,omitempty"`
 	ScrollIntoView           string            `json:"scroll_into_view,omitempty"`
 	AdjustTop                int               `json:"adjust_top,omitempty"`
@@ -426,6 +453,11 @@ func normalizeOptions(s *ScreenshotRequestOptions) normalizedOptions {
 		NavigationTimeout:        getPointerValue(s.NavigationTimeout, 30),
 	}
 
+	// The default provider is left out, keeping the hash of pages captured before providers were introduced
+	if provider := s.GetProvider(); provider != CaptureProviderScreenshotOne {
+		normalized.Provider = string(provider)
+	}
+
 	// Handle optional string pointers
 	if s.Selector != nil {
 		normalized.Selector = *s.Selector
@@ -0,0 +1,243 @@
+// ./src/internal/models/core/webhook.go
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType is the type of an event delivered to the webhooks of a workspace
+type WebhookEventType string
+
+const (
+	// WebhookEventPageChanged is sent when the history of a page records changes
+	WebhookEventPageChanged WebhookEventType = "page.changed"
+
+	// WebhookEventReportCreated is sent when a report is created for a competitor
+	WebhookEventReportCreated WebhookEventType = "report.created"
+
+	// WebhookEventJobFailed is sent once a job ended with items of the workspace failed, or the job itself failed
+	WebhookEventJobFailed WebhookEventType = "job.failed"
+
+	// WebhookEventTest is sent on demand to check a webhook, regardless of the events it subscribed to
+	WebhookEventTest WebhookEventType = "webhook.test"
+)
+
+// Webhook is an endpoint notified of the events of a workspace
+type Webhook struct {
+	// ID is the unique identifier of the webhook
+	ID uuid.UUID `json:"id"`
+
+	// WorkspaceID is the workspace the webhook belongs to
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+
+	// URL is the endpoint the events are posted to
+	URL string `json:"url"`
+
+	// Secret signs the payloads posted to the endpoint, only returned as the webhook is created
+	Secret string `json:"secret,omitempty"`
+
+	// EventTypes are the events the webhook subscribed to, all of them when empty
+	EventTypes []WebhookEventType `json:"event_types"`
+
+	// Active is false when the webhook is paused
+	Active bool `json:"active"`
+
+	// CreatedAt is the time the webhook was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the webhook was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribes returns true if the webhook is sent events of the type
+// Test events are sent to every webhook
+func (w Webhook) Subscribes(eventType WebhookEventType) bool {
+	if eventType == WebhookEventTest {
+		return true
+	}
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+}
+
+// Redacted returns the webhook without its secret
+func (w Webhook) Redacted() Webhook {
+	w.Secret = ""
+	return w
+}
+
+// WebhookProps are the editable settings of a webhook
+type WebhookProps struct {
+	// URL is the endpoint the events are posted to
+	URL string
+
+	// EventTypes are the events the webhook subscribed to, all of them when empty
+	EventTypes []WebhookEventType
+
+	// Active is false when the webhook is paused
+	Active bool
+}
+
+// WebhookEvent is the payload posted to the webhooks
+type WebhookEvent struct {
+	// ID is the unique identifier of the event, shared by its deliveries to every webhook
+	ID uuid.UUID `json:"id"`
+
+	// Type is the type of the event
+	Type WebhookEventType `json:"type"`
+
+	// WorkspaceID is the workspace the event belongs to
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+
+	// CreatedAt is the time the event occurred
+	CreatedAt time.Time `json:"created_at"`
+
+	// Data is the content of the event, depending on its type
+	Data any `json:"data"`
+}
+
+func NewWebhookEvent(workspaceID uuid.UUID, eventType WebhookEventType, data any) WebhookEvent {
+	return WebhookEvent{
+		ID:          uuid.New(),
+		Type:        eventType,
+		WorkspaceID: workspaceID,
+		CreatedAt:   time.Now().UTC(),
+		Data:        data,
+	}
+}
+
+// PageChangedEvent is the content of a page.changed event
+type PageChangedEvent struct {
+	// PageID is the page which changed
+	PageID uuid.UUID `json:"page_id"`
+
+	// CompetitorID is the competitor the page belongs to
+	CompetitorID uuid.UUID `json:"competitor_id"`
+
+	// URL is the URL of the page
+	URL string `json:"url"`
+
+	// Changes are the changes recorded in the history of the page
+	Changes *DynamicChanges `json:"changes"`
+
+	// Visual is the visual diff of the page, when significant
+	Visual *VisualDiff `json:"visual,omitempty"`
+}
+
+// ReportCreatedEvent is the content of a report.created event
+type ReportCreatedEvent struct {
+	// ReportID is the report created
+	ReportID uuid.UUID `json:"report_id"`
+
+	// CompetitorID is the competitor the report belongs to
+	CompetitorID uuid.UUID `json:"competitor_id"`
+
+	// CompetitorName is the name of the competitor
+	CompetitorName string `json:"competitor_name"`
+
+	// Changes are the changes summarized by the report
+	Changes []CategoryChange `json:"changes"`
+
+	// Time is the time the report was created
+	Time time.Time `json:"time"`
+}
+
+// JobFailedEvent is the content of a job.failed event
+type JobFailedEvent struct {
+	// JobID is the job the items failed in
+	JobID uuid.UUID `json:"job_id"`
+
+	// WorkflowType is the workflow the job belongs to
+	WorkflowType WorkflowType `json:"workflow_type"`
+
+	// Failures are the items of the workspace which failed, along with the errors of the job when it failed
+	Failures []JobItemError `json:"failures"`
+}
+
+// WebhookTestEvent is the content of a webhook.test event
+type WebhookTestEvent struct {
+	// WebhookID is the webhook being tested
+	WebhookID uuid.UUID `json:"webhook_id"`
+
+	// Message describes the event
+	Message string `json:"message"`
+}
+
+// WebhookDeliveryStatus is the status of the delivery of an event to a webhook
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending is the status of a delivery yet to succeed, which is retried
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+
+	// WebhookDeliveryStatusSucceeded is the status of a delivery acknowledged by the endpoint
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+
+	// WebhookDeliveryStatusFailed is the status of a delivery given up on after its last attempt
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the log of the delivery of an event to a webhook
+type WebhookDelivery struct {
+	// ID is the unique identifier of the delivery
+	ID uuid.UUID `json:"id"`
+
+	// WebhookID is the webhook the event is delivered to
+	WebhookID uuid.UUID `json:"webhook_id"`
+
+	// EventID is the event delivered
+	EventID uuid.UUID `json:"event_id"`
+
+	// EventType is the type of the event delivered
+	EventType WebhookEventType `json:"event_type"`
+
+	// Payload is the body posted to the webhook
+	Payload json.RawMessage `json:"payload"`
+
+	// Status is the status of the delivery
+	Status WebhookDeliveryStatus `json:"status"`
+
+	// Attempts is the number of attempts at delivering the event
+	Attempts int `json:"attempts"`
+
+	// ResponseStatus is the status code of the last response of the endpoint, nil when it didn't respond
+	ResponseStatus *int `json:"response_status,omitempty"`
+
+	// Error is the error of the last attempt, empty once the event was delivered
+	Error string `json:"error,omitempty"`
+
+	// NextAttemptAt is the time of the next attempt, nil once the delivery succeeded or failed
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// LastAttemptAt is the time of the last attempt, nil until the event is first posted
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+
+	// CreatedAt is the time the delivery was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the delivery was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryAttempt is the outcome of an attempt at delivering an event
+type WebhookDeliveryAttempt struct {
+	// Status is the status of the delivery after the attempt
+	Status WebhookDeliveryStatus
+
+	// Attempt is the attempt, starting at 1
+	Attempt int
+
+	// ResponseStatus is the status code of the response, nil when the endpoint didn't respond
+	ResponseStatus *int
+
+	// Error is the error of the attempt, empty when it succeeded
+	Error string
+
+	// NextAttemptAt is the time of the next attempt, nil when the delivery isn't retried
+	NextAttemptAt *time.Time
+
+	// AttemptedAt is the time of the attempt
+	AttemptedAt time.Time
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -46,6 +47,9 @@ type WorkflowSchedule struct {
 	// Spec is the cron specification for the workflow
 	Spec string `json:"spec"`
 
+	// Pipeline are the stages run by the schedule, one after the other, instead of the workflow alone
+	Pipeline []WorkflowType `json:"pipeline,omitempty"`
+
 	// LastRun is the time when the workflow was last run
 	// Using sql.NullTime for NULL handling
 	LastRun sql.NullTime `json:"last_run,omitempty"`
@@ -70,18 +74,43 @@ type WorkflowScheduleProps struct {
 
 	// Spec is the cron specification for the workflow
 	Spec string `json:"spec" required:"true" validate:"required"`
+
+	// Pipeline are the stages run by the schedule, one after the other, instead of the workflow alone
+	// The workflow type of a pipeline schedule is its first stage
+	Pipeline []WorkflowType `json:"pipeline,omitempty" validate:"omitempty,dive,oneof=screenshot report dispatch"`
+}
+
+// IsPipeline returns true if the schedule runs a pipeline rather than a workflow
+func (p WorkflowScheduleProps) IsPipeline() bool {
+	return len(p.Pipeline) > 0
+}
+
+// Equal returns true if the props schedule the same runs
+func (p WorkflowScheduleProps) Equal(other WorkflowScheduleProps) bool {
+	return p.WorkflowType == other.WorkflowType && p.Spec == other.Spec && slices.Equal(p.Pipeline, other.Pipeline)
+}
+
+// Props returns the props of the schedule
+func (w WorkflowSchedule) Props() WorkflowScheduleProps {
+	return WorkflowScheduleProps{
+		WorkflowType: w.WorkflowType,
+		About:        w.About,
+		Spec:         w.Spec,
+		Pipeline:     w.Pipeline,
+	}
 }
 
 // WorkflowScheduleJSON is an internal type for JSON marshaling/unmarshaling
 type workflowScheduleJSON struct {
-	ID           string       `json:"id"`
-	WorkflowType WorkflowType `json:"workflow_type"`
-	About        string       `json:"about"`
-	Spec         string       `json:"spec"`
-	LastRun      *time.Time   `json:"last_run,omitempty"`
-	NextRun      *time.Time   `json:"next_run,omitempty"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
+	ID           string         `json:"id"`
+	WorkflowType WorkflowType   `json:"workflow_type"`
+	About        string         `json:"about"`
+	Spec         string         `json:"spec"`
+	Pipeline     []WorkflowType `json:"pipeline,omitempty"`
+	LastRun      *time.Time     `json:"last_run,omitempty"`
+	NextRun      *time.Time     `json:"next_run,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
 
 // MarshalJSON implements custom JSON marshaling for WorkflowSchedule
@@ -91,6 +120,7 @@ func (w WorkflowSchedule) MarshalJSON() ([]byte, error) {
 		WorkflowType: w.WorkflowType,
 		About:        w.About,
 		Spec:         w.Spec,
+		Pipeline:     w.Pipeline,
 		CreatedAt:    w.CreatedAt,
 		UpdatedAt:    w.UpdatedAt,
 	}
@@ -123,6 +153,7 @@ func (w *WorkflowSchedule) UnmarshalJSON(data []byte) error {
 	w.WorkflowType = j.WorkflowType
 	w.About = j.About
 	w.Spec = j.Spec
+	w.Pipeline = j.Pipeline
 	w.CreatedAt = j.CreatedAt
 	w.UpdatedAt = j.UpdatedAt
 
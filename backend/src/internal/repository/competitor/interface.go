@@ -22,6 +22,10 @@ type CompetitorRepository interface {
 
 	ListCompetitorsForWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Competitor, bool, error)
 
+	// ListCompetitorsInScope lists the competitors of the workspace within the scope of a job
+	// Competitors are limited to the competitors of the scope, and to the ones owning its pages
+	ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error)
+
 	UpdateCompetitorForWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string) (*models.Competitor, error)
 
 	RemoveCompetitorForWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID) error
@@ -216,6 +216,53 @@ func (r *competitorRepo) ListCompetitorsForWorkspace(ctx context.Context, worksp
 	return competitors, hasMore, rows.Err()
 }
 
+func (r *competitorRepo) ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error) {
+	query := `
+		SELECT id, workspace_id, name, status, created_at, updated_at
+		FROM competitors
+		WHERE workspace_id = $1 AND status != $2`
+
+	args := []interface{}{workspaceID, models.CompetitorStatusInactive}
+
+	if len(scope.CompetitorIDs) > 0 {
+		query += fmt.Sprintf(` AND id = ANY($%d)`, len(args)+1)
+		args = append(args, scope.CompetitorIDs)
+	}
+
+	if len(scope.PageIDs) > 0 {
+		query += fmt.Sprintf(` AND id IN (SELECT competitor_id FROM pages WHERE id = ANY($%d))`, len(args)+1)
+		args = append(args, scope.PageIDs)
+	}
+
+	query += `
+		ORDER BY created_at DESC`
+
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list competitors in scope: %w", err)
+	}
+	defer rows.Close()
+
+	var competitors []models.Competitor
+	for rows.Next() {
+		var competitor models.Competitor
+		err := rows.Scan(
+			&competitor.ID,
+			&competitor.WorkspaceID,
+			&competitor.Name,
+			&competitor.Status,
+			&competitor.CreatedAt,
+			&competitor.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan competitor: %w", err)
+		}
+		competitors = append(competitors, competitor)
+	}
+
+	return competitors, rows.Err()
+}
+
 func (r *competitorRepo) UpdateCompetitorForWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string) (*models.Competitor, error) {
 	competitor := &models.Competitor{}
 
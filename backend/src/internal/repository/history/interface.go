@@ -12,10 +12,12 @@ import (
 // This is used to interact with the page history repository
 
 type PageHistoryRepository interface {
-	CreateHistoryForPage(ctx context.Context, pageID uuid.UUID, diffContent *models.DynamicChanges, prev, curr string) error
+	CreateHistoryForPage(ctx context.Context, pageID uuid.UUID, diff *models.DiffResult, prev, curr string) error
 
 	BatchGetPageHistory(ctx context.Context, pageID uuid.UUID, limit, offset *int) ([]models.PageHistory, bool, error)
 
+	GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error)
+
 	BatchRemovePageHistory(ctx context.Context, pageIDs []uuid.UUID) error
 
 	GetLatestPageHistory(ctx context.Context, pageID []uuid.UUID) ([]models.PageHistory, error)
@@ -4,6 +4,7 @@ package history
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -35,37 +36,58 @@ func (r *historyRepo) getQuerier(ctx context.Context) interface {
 	return r.tm.GetQuerier(ctx)
 }
 
-func (r *historyRepo) CreateHistoryForPage(ctx context.Context, pageID uuid.UUID, diffContent *models.DynamicChanges, prev, curr string) error {
+func (r *historyRepo) CreateHistoryForPage(ctx context.Context, pageID uuid.UUID, diff *models.DiffResult, prev, curr string) error {
 	// Validate diffContent as well
 	if pageID == uuid.Nil {
 		return fmt.Errorf("page ID is required")
 	}
 
-	if diffContent == nil {
+	if diff == nil || diff.Changes == nil {
 		return fmt.Errorf("diff content is required")
 	}
 
 	// Convert diffContent to JSONB
-	diffContentJSON, err := json.Marshal(diffContent)
+	diffContentJSON, err := json.Marshal(diff.Changes)
 	if err != nil {
 		return fmt.Errorf("failed to marshal diff content: %w", err)
 	}
 
+	// The visual diff is only recorded for significant visual changes
+	var visualDiffJSON []byte
+	if diff.Visual != nil {
+		visualDiffJSON, err = json.Marshal(diff.Visual)
+		if err != nil {
+			return fmt.Errorf("failed to marshal visual diff: %w", err)
+		}
+	}
+
+	// The noise rules which fired are recorded to help tune them
+	noiseRules := diff.NoiseRules
+	if noiseRules == nil {
+		noiseRules = []string{}
+	}
+
 	query := `
         INSERT INTO page_history (
             page_id,
             diff_content,
+            raw_diff,
+            visual_diff,
+            noise_rules,
             status,
             prev,
             current
         )
-        VALUES ($1, $2, $3, $4, $5)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         RETURNING id`
 
 	var id uuid.UUID
 	err = r.getQuerier(ctx).QueryRow(ctx, query,
 		pageID,
 		diffContentJSON,
+		diff.RawDiff,
+		visualDiffJSON,
+		noiseRules,
 		models.HistoryStatusActive,
 		prev,
 		curr,
@@ -89,6 +111,9 @@ func (r *historyRepo) BatchGetPageHistory(ctx context.Context, pageID uuid.UUID,
             id,
             page_id,
             diff_content,
+            raw_diff,
+            visual_diff,
+            noise_rules,
             created_at,
             status,
             prev,
@@ -121,12 +146,15 @@ func (r *historyRepo) BatchGetPageHistory(ctx context.Context, pageID uuid.UUID,
 	histories := make([]models.PageHistory, 0)
 	for rows.Next() {
 		var history models.PageHistory
-		var diffContentJSON []byte
+		var diffContentJSON, visualDiffJSON []byte
 
 		err := rows.Scan(
 			&history.ID,
 			&history.PageID,
 			&diffContentJSON,
+			&history.RawDiff,
+			&visualDiffJSON,
+			&history.NoiseRules,
 			&history.CreatedAt,
 			&history.Status,
 			&history.Prev,
@@ -142,6 +170,11 @@ func (r *historyRepo) BatchGetPageHistory(ctx context.Context, pageID uuid.UUID,
 			return nil, false, fmt.Errorf("failed to unmarshal diff content: %w", err)
 		}
 
+		history.VisualDiff, err = unmarshalVisualDiff(visualDiffJSON)
+		if err != nil {
+			return nil, false, err
+		}
+
 		histories = append(histories, history)
 	}
 
@@ -158,6 +191,68 @@ func (r *historyRepo) BatchGetPageHistory(ctx context.Context, pageID uuid.UUID,
 	return histories, hasMore, nil
 }
 
+func (r *historyRepo) GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error) {
+	if pageID == uuid.Nil {
+		return nil, fmt.Errorf("page ID is required")
+	}
+
+	if historyID == uuid.Nil {
+		return nil, fmt.Errorf("history ID is required")
+	}
+
+	query := `
+        SELECT
+            id,
+            page_id,
+            diff_content,
+            raw_diff,
+            visual_diff,
+            noise_rules,
+            created_at,
+            status,
+            prev,
+            current
+        FROM page_history
+        WHERE id = $1
+        AND page_id = $2
+        AND status = $3`
+
+	var history models.PageHistory
+	var diffContentJSON, visualDiffJSON []byte
+
+	err := r.getQuerier(ctx).QueryRow(ctx, query, historyID, pageID, models.HistoryStatusActive).Scan(
+		&history.ID,
+		&history.PageID,
+		&diffContentJSON,
+		&history.RawDiff,
+		&visualDiffJSON,
+		&history.NoiseRules,
+		&history.CreatedAt,
+		&history.Status,
+		&history.Prev,
+		&history.Curr,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("page history not found")
+		}
+		return nil, fmt.Errorf("failed to get page history: %w", err)
+	}
+
+	// Unmarshal JSON content
+	err = json.Unmarshal(diffContentJSON, &history.DiffContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal diff content: %w", err)
+	}
+
+	history.VisualDiff, err = unmarshalVisualDiff(visualDiffJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
 func (r *historyRepo) BatchRemovePageHistory(ctx context.Context, pageIDs []uuid.UUID) error {
 	if len(pageIDs) == 0 {
 		return nil
@@ -195,6 +290,9 @@ func (r *historyRepo) GetLatestPageHistory(ctx context.Context, pageIDs []uuid.U
             id,
             page_id,
             diff_content,
+            raw_diff,
+            visual_diff,
+            noise_rules,
             created_at,
             status,
             prev,
@@ -214,12 +312,15 @@ func (r *historyRepo) GetLatestPageHistory(ctx context.Context, pageIDs []uuid.U
 	histories := make([]models.PageHistory, 0)
 	for rows.Next() {
 		var history models.PageHistory
-		var diffContentJSON []byte
+		var diffContentJSON, visualDiffJSON []byte
 
 		err := rows.Scan(
 			&history.ID,
 			&history.PageID,
 			&diffContentJSON,
+			&history.RawDiff,
+			&visualDiffJSON,
+			&history.NoiseRules,
 			&history.CreatedAt,
 			&history.Status,
 			&history.Prev,
@@ -235,6 +336,11 @@ func (r *historyRepo) GetLatestPageHistory(ctx context.Context, pageIDs []uuid.U
 			return nil, fmt.Errorf("failed to unmarshal diff content: %w", err)
 		}
 
+		history.VisualDiff, err = unmarshalVisualDiff(visualDiffJSON)
+		if err != nil {
+			return nil, err
+		}
+
 		histories = append(histories, history)
 	}
 
@@ -244,3 +350,17 @@ func (r *historyRepo) GetLatestPageHistory(ctx context.Context, pageIDs []uuid.U
 
 	return histories, nil
 }
+
+// unmarshalVisualDiff unmarshals the visual diff of a page history, which is null unless significant
+func unmarshalVisualDiff(visualDiffJSON []byte) (*models.VisualDiff, error) {
+	if len(visualDiffJSON) == 0 {
+		return nil, nil
+	}
+
+	var visualDiff models.VisualDiff
+	if err := json.Unmarshal(visualDiffJSON, &visualDiff); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal visual diff: %w", err)
+	}
+
+	return &visualDiff, nil
+}
@@ -0,0 +1,38 @@
+// ./src/internal/repository/outbox/interface.go
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// OutboxRepository is the interface that provides email outbox operations
+// This is used to interact with the email_outbox table
+type OutboxRepository interface {
+	// Enqueue queues the email for delivery, along with the workspace and the reports it carries
+	// The email is written in the transaction of the context, if any
+	Enqueue(ctx context.Context, workspaceID *uuid.UUID, reportIDs []uuid.UUID, email models.Email) (*models.OutboxEmail, error)
+
+	// ClaimDue claims up to limit queued emails due to be sent, counting an attempt for each of them
+	// The claimed emails aren't due again before the lease expires, so that an attempt interrupted midway is eventually retried
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEmail, error)
+
+	// RecordAttempt records the outcome of an attempt at sending the email
+	RecordAttempt(ctx context.Context, emailID uuid.UUID, attempt models.OutboxEmailAttempt) error
+
+	// ClaimDeliveryChecks claims up to limit emails sent between the times whose delivery wasn't checked yet
+	// The claimed emails aren't claimed again before the lease expires, a check which failed is then retried
+	ClaimDeliveryChecks(ctx context.Context, sentAfter, sentBefore time.Time, limit int, lease time.Duration) ([]models.OutboxEmail, error)
+
+	// MarkDeliveryChecked records that the delivery of a sent email was checked, so that it isn't checked again
+	MarkDeliveryChecked(ctx context.Context, emailID uuid.UUID) error
+
+	// MarkBounced marks a sent email bounced, its delivery is then checked
+	MarkBounced(ctx context.Context, emailID uuid.UUID, reason string) error
+
+	// ListReportEmails lists the emails of the workspace carrying the report, latest first
+	ListReportEmails(ctx context.Context, workspaceID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error)
+}
@@ -0,0 +1,266 @@
+// ./src/internal/repository/outbox/repository.go
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/transaction"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+type outboxRepo struct {
+	tm     *transaction.TxManager
+	logger *logger.Logger
+}
+
+func NewOutboxRepository(tm *transaction.TxManager, logger *logger.Logger) OutboxRepository {
+	return &outboxRepo{
+		tm:     tm,
+		logger: logger.WithFields(map[string]interface{}{"module": "outbox_repository"}),
+	}
+}
+
+func (r *outboxRepo) getQuerier(ctx context.Context) interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, arguments ...interface{}) pgx.Row
+} {
+	return r.tm.GetQuerier(ctx)
+}
+
+const outboxEmailColumns = `id, workspace_id, report_ids, recipients, subject, content, format, attachments, status, attempts, last_error, provider_message_id, next_attempt_at, sent_at, created_at, updated_at`
+
+func scanOutboxEmail(row pgx.Row) (*models.OutboxEmail, error) {
+	var email models.OutboxEmail
+	var lastError, providerMessageID *string
+	err := row.Scan(
+		&email.ID,
+		&email.WorkspaceID,
+		&email.ReportIDs,
+		&email.To,
+		&email.Subject,
+		&email.Content,
+		&email.Format,
+		&email.Attachments,
+		&email.Status,
+		&email.Attempts,
+		&lastError,
+		&providerMessageID,
+		&email.NextAttemptAt,
+		&email.SentAt,
+		&email.CreatedAt,
+		&email.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError != nil {
+		email.LastError = *lastError
+	}
+	if providerMessageID != nil {
+		email.ProviderMessageID = *providerMessageID
+	}
+
+	return &email, nil
+}
+
+func (r *outboxRepo) queryOutboxEmails(ctx context.Context, query string, args ...interface{}) ([]models.OutboxEmail, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := make([]models.OutboxEmail, 0)
+	for rows.Next() {
+		email, err := scanOutboxEmail(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, *email)
+	}
+
+	return emails, rows.Err()
+}
+
+func (r *outboxRepo) Enqueue(ctx context.Context, workspaceID *uuid.UUID, reportIDs []uuid.UUID, email models.Email) (*models.OutboxEmail, error) {
+	if reportIDs == nil {
+		reportIDs = []uuid.UUID{}
+	}
+
+	format := email.EmailFormat
+	if format == "" {
+		format = models.EmailFormatText
+	}
+
+	attachments := email.Attachments
+	if attachments == nil {
+		attachments = []models.EmailAttachment{}
+	}
+
+	queued, err := scanOutboxEmail(r.getQuerier(ctx).QueryRow(ctx, `
+		INSERT INTO email_outbox (workspace_id, report_ids, recipients, subject, content, format, attachments, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING `+outboxEmailColumns,
+		workspaceID, reportIDs, email.To, email.EmailSubject, email.EmailContent, format, attachments, models.OutboxEmailStatusQueued,
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return queued, nil
+}
+
+func (r *outboxRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEmail, error) {
+	emails, err := r.queryOutboxEmails(ctx, `
+		UPDATE email_outbox
+		SET attempts = attempts + 1, next_attempt_at = NOW() + $1 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = $2 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxEmailColumns,
+		lease.Milliseconds(), models.OutboxEmailStatusQueued, limit,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+func (r *outboxRepo) RecordAttempt(ctx context.Context, emailID uuid.UUID, attempt models.OutboxEmailAttempt) error {
+	var lastError, providerMessageID *string
+	if attempt.Error != "" {
+		lastError = &attempt.Error
+	}
+	if attempt.ProviderMessageID != "" {
+		providerMessageID = &attempt.ProviderMessageID
+	}
+
+	var sentAt *time.Time
+	if attempt.Status == models.OutboxEmailStatusSent {
+		sentAt = &attempt.AttemptedAt
+	}
+
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		UPDATE email_outbox
+		SET status = $1, last_error = COALESCE($2, last_error), provider_message_id = $3, next_attempt_at = $4, sent_at = $5
+		WHERE id = $6`,
+		attempt.Status, lastError, providerMessageID, attempt.NextAttemptAt, sentAt, emailID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record email attempt: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("email not found")
+	}
+
+	return nil
+}
+
+func (r *outboxRepo) ClaimDeliveryChecks(ctx context.Context, sentAfter, sentBefore time.Time, limit int, lease time.Duration) ([]models.OutboxEmail, error) {
+	// Sent emails aren't due to be sent again, their next attempt leases the check of their delivery instead
+	emails, err := r.queryOutboxEmails(ctx, `
+		UPDATE email_outbox
+		SET next_attempt_at = NOW() + $1 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = $2 AND delivery_checked_at IS NULL AND provider_message_id IS NOT NULL
+			AND sent_at > $3 AND sent_at <= $4
+			AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+			ORDER BY sent_at
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxEmailColumns,
+		lease.Milliseconds(), models.OutboxEmailStatusSent, sentAfter, sentBefore, limit,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim email delivery checks: %w", err)
+	}
+
+	return emails, nil
+}
+
+func (r *outboxRepo) MarkDeliveryChecked(ctx context.Context, emailID uuid.UUID) error {
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		UPDATE email_outbox
+		SET delivery_checked_at = NOW(), next_attempt_at = NULL
+		WHERE id = $1`,
+		emailID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark email delivery checked: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("email not found")
+	}
+
+	return nil
+}
+
+func (r *outboxRepo) MarkBounced(ctx context.Context, emailID uuid.UUID, reason string) error {
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		UPDATE email_outbox
+		SET status = $1, last_error = $2, delivery_checked_at = NOW(), next_attempt_at = NULL
+		WHERE id = $3`,
+		models.OutboxEmailStatusBounced, reason, emailID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark email bounced: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("email not found")
+	}
+
+	return nil
+}
+
+func (r *outboxRepo) ListReportEmails(ctx context.Context, workspaceID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error) {
+	query := `
+		SELECT ` + outboxEmailColumns + `
+		FROM email_outbox
+		WHERE workspace_id = $1 AND $2 = ANY(report_ids)
+		ORDER BY created_at DESC`
+
+	args := []interface{}{workspaceID, reportID}
+
+	if limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, *limit)
+	}
+
+	if offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
+		args = append(args, *offset)
+	}
+
+	emails, err := r.queryOutboxEmails(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list report emails: %w", err)
+	}
+
+	hasMore := limit != nil && len(emails) == *limit
+	return emails, hasMore, nil
+}
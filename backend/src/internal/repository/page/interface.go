@@ -3,6 +3,7 @@ package page
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -29,6 +30,12 @@ type PageRepository interface {
 
 	UpdateCompetitorDiffProfile(ctx context.Context, competitorID, pageID uuid.UUID, diffProfile []string) (*models.Page, error)
 
+	UpdateCompetitorCheckInterval(ctx context.Context, competitorID, pageID uuid.UUID, checkInterval int) (*models.Page, error)
+
+	UpdateCompetitorContentSelectors(ctx context.Context, competitorID, pageID uuid.UUID, contentSelectors models.ContentSelectors) (*models.Page, error)
+
+	UpdateCompetitorNoiseRules(ctx context.Context, competitorID, pageID uuid.UUID, noiseRules []models.NoiseRule) (*models.Page, error)
+
 	UpdateCompetitorURL(ctx context.Context, competitorID, pageID uuid.UUID, url string) (*models.Page, error)
 
 	DeleteCompetitorPageByID(ctx context.Context, competitorID, pageID uuid.UUID) error
@@ -39,9 +46,28 @@ type PageRepository interface {
 
 	BatchDeleteAllCompetitorPages(ctx context.Context, competitorIDs []uuid.UUID) error
 
-	GetActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID) (models.ActivePageBatch, error)
+	// GetActivePages returns a batch of active pages which are due for a check
+	// Pages within a non-empty scope are returned whether they are due or not
+	GetActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID, scope models.JobScope) (models.ActivePageBatch, error)
+
+	// CountActivePages returns the number of pages GetActivePages goes through for the scope
+	CountActivePages(ctx context.Context, scope models.JobScope) (int64, error)
 
 	GetPageByPageID(ctx context.Context, pageID uuid.UUID) (*models.Page, error)
 
+	// GetPageURLs returns the URL of each of the pages, pages which aren't found are left out
+	GetPageURLs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]string, error)
+
 	GetActivePageCountsByCompetitors(ctx context.Context, competitorIDs []uuid.UUID) (map[uuid.UUID]int, error)
+
+	GetContentFingerprint(ctx context.Context, pageID uuid.UUID) (string, error)
+
+	// UpdatePageCheck records the content fingerprint and the capture time of the latest check
+	UpdatePageCheck(ctx context.Context, pageID uuid.UUID, fingerprint string, checkedAt time.Time) error
+
+	// GetWorkspaceNoiseRules returns the noise rules of the workspace the page belongs to
+	GetWorkspaceNoiseRules(ctx context.Context, pageID uuid.UUID) ([]models.NoiseRule, error)
+
+	// GetPageWorkspaceIDs returns the workspace each of the pages belongs to, pages which aren't found are left out
+	GetPageWorkspaceIDs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -16,6 +17,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// checkIntervalTolerance is the fraction of the check interval by which a page may be checked early
+const checkIntervalTolerance = 0.1
+
 type pageRepo struct {
 	tm     *transaction.TxManager
 	logger *logger.Logger
@@ -40,10 +44,10 @@ func (r *pageRepo) AddPageToCompetitor(ctx context.Context, competitorID uuid.UU
 	result := &models.Page{}
 
 	err := r.getQuerier(ctx).QueryRow(ctx, `
-      INSERT INTO pages (competitor_id, url, title, capture_profile, diff_profile, status)
-      VALUES ($1, $2, $3, $4, $5, $6)
-      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
-		competitorID, page.URL, page.Title, page.CaptureProfile, page.DiffProfile, models.PageStatusActive,
+      INSERT INTO pages (competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, status)
+      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
+		competitorID, page.URL, page.Title, page.CaptureProfile, page.DiffProfile, checkIntervalOrDefault(page.CheckInterval), contentSelectorsOrDefault(page.ContentSelectors), noiseRulesOrDefault(page.NoiseRules), models.PageStatusActive,
 	).Scan(
 		&result.ID,
 		&result.CompetitorID,
@@ -51,6 +55,9 @@ func (r *pageRepo) AddPageToCompetitor(ctx context.Context, competitorID uuid.UU
 		&result.Title,
 		&result.CaptureProfile,
 		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
 		&result.LastCheckedAt,
 		&result.Status,
 		&result.CreatedAt,
@@ -71,24 +78,27 @@ func (r *pageRepo) BatchAddPageToCompetitor(ctx context.Context, competitorID uu
 
 	// Create values for bulk insert
 	valueStrings := make([]string, 0, len(pages))
-	valueArgs := make([]interface{}, 0, len(pages)*6)
+	valueArgs := make([]interface{}, 0, len(pages)*9)
 	for i, page := range pages {
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)",
-			i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6))
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9))
 		valueArgs = append(valueArgs,
 			competitorID,
 			page.URL,
 			page.Title,
 			page.CaptureProfile,
 			page.DiffProfile,
+			checkIntervalOrDefault(page.CheckInterval),
+			contentSelectorsOrDefault(page.ContentSelectors),
+			noiseRulesOrDefault(page.NoiseRules),
 			models.PageStatusActive,
 		)
 	}
 
 	query := fmt.Sprintf(`
-        INSERT INTO pages (competitor_id, url, title, capture_profile, diff_profile, status)
+        INSERT INTO pages (competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, status)
         VALUES %s
-        RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
+        RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
 		strings.Join(valueStrings, ","))
 
 	rows, err := r.getQuerier(ctx).Query(ctx, query, valueArgs...)
@@ -107,6 +117,9 @@ func (r *pageRepo) BatchAddPageToCompetitor(ctx context.Context, competitorID uu
 			&page.Title,
 			&page.CaptureProfile,
 			&page.DiffProfile,
+			&page.CheckInterval,
+			&page.ContentSelectors,
+			&page.NoiseRules,
 			&page.LastCheckedAt,
 			&page.Status,
 			&page.CreatedAt,
@@ -125,7 +138,7 @@ func (r *pageRepo) GetCompetitorPageByID(ctx context.Context, competitorID, page
 	page := &models.Page{}
 
 	err := r.getQuerier(ctx).QueryRow(ctx, `
-        SELECT id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at
+        SELECT id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at
         FROM pages
         WHERE competitor_id = $1 AND id = $2 AND status != $3`,
 		competitorID, pageID, models.PageStatusInactive,
@@ -136,6 +149,9 @@ func (r *pageRepo) GetCompetitorPageByID(ctx context.Context, competitorID, page
 		&page.Title,
 		&page.CaptureProfile,
 		&page.DiffProfile,
+		&page.CheckInterval,
+		&page.ContentSelectors,
+		&page.NoiseRules,
 		&page.LastCheckedAt,
 		&page.Status,
 		&page.CreatedAt,
@@ -158,7 +174,7 @@ func (r *pageRepo) BatchGetCompetitorPagesByIDs(ctx context.Context, competitorI
 	}
 
 	query := `
-		SELECT id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at
+		SELECT id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at
     FROM pages
 		WHERE competitor_id = $1 AND id = ANY($2) AND status != $3
 		ORDER BY created_at DESC`
@@ -191,6 +207,9 @@ func (r *pageRepo) BatchGetCompetitorPagesByIDs(ctx context.Context, competitorI
 			&page.Title,
 			&page.CaptureProfile,
 			&page.DiffProfile,
+			&page.CheckInterval,
+			&page.ContentSelectors,
+			&page.NoiseRules,
 			&page.LastCheckedAt,
 			&page.Status,
 			&page.CreatedAt,
@@ -209,7 +228,7 @@ func (r *pageRepo) BatchGetCompetitorPagesByIDs(ctx context.Context, competitorI
 
 func (r *pageRepo) GetCompetitorPages(ctx context.Context, competitorID uuid.UUID, limit, offset *int) ([]models.Page, bool, error) {
 	query := `
-		SELECT id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at
+		SELECT id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at
     FROM pages
 		WHERE competitor_id = $1 AND status != $2
 		ORDER BY created_at DESC`
@@ -242,6 +261,9 @@ func (r *pageRepo) GetCompetitorPages(ctx context.Context, competitorID uuid.UUI
 			&page.Title,
 			&page.CaptureProfile,
 			&page.DiffProfile,
+			&page.CheckInterval,
+			&page.ContentSelectors,
+			&page.NoiseRules,
 			&page.LastCheckedAt,
 			&page.Status,
 			&page.CreatedAt,
@@ -265,7 +287,7 @@ func (r *pageRepo) UpdateCompetitorPage(ctx context.Context, competitorID, pageI
       UPDATE pages
       SET url = $1, title = $2, capture_profile = $3, diff_profile = $4
       WHERE competitor_id = $5 AND id = $6 AND status != $7
-      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
 		page.URL, page.Title, page.CaptureProfile, page.DiffProfile, competitorID, pageID, models.PageStatusInactive,
 	).Scan(
 		&result.ID,
@@ -274,6 +296,9 @@ func (r *pageRepo) UpdateCompetitorPage(ctx context.Context, competitorID, pageI
 		&result.Title,
 		&result.CaptureProfile,
 		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
 		&result.LastCheckedAt,
 		&result.Status,
 		&result.CreatedAt,
@@ -297,7 +322,7 @@ func (r *pageRepo) UpdateCompetitorPageURL(ctx context.Context, competitorID, pa
       UPDATE pages
       SET url = $1
       WHERE competitor_id = $2 AND id = $3 AND status != $4
-      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
 		url, competitorID, pageID, models.PageStatusInactive,
 	).Scan(
 		&result.ID,
@@ -306,6 +331,9 @@ func (r *pageRepo) UpdateCompetitorPageURL(ctx context.Context, competitorID, pa
 		&result.Title,
 		&result.CaptureProfile,
 		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
 		&result.LastCheckedAt,
 		&result.Status,
 		&result.CreatedAt,
@@ -329,7 +357,7 @@ func (r *pageRepo) UpdateCompetitorCaptureProfile(ctx context.Context, competito
       UPDATE pages
       SET capture_profile = $1, url = $2
       WHERE competitor_id = $3 AND id = $4 AND status != $5
-      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
 		captureProfile, url, competitorID, pageID, models.PageStatusInactive,
 	).Scan(
 		&result.ID,
@@ -338,6 +366,9 @@ func (r *pageRepo) UpdateCompetitorCaptureProfile(ctx context.Context, competito
 		&result.Title,
 		&result.CaptureProfile,
 		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
 		&result.LastCheckedAt,
 		&result.Status,
 		&result.CreatedAt,
@@ -361,7 +392,7 @@ func (r *pageRepo) UpdateCompetitorDiffProfile(ctx context.Context, competitorID
       UPDATE pages
       SET diff_profile = $1
       WHERE competitor_id = $2 AND id = $3 AND status != $4
-      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, last_checked_at, status, created_at, updated_at`,
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
 		diffProfile, competitorID, pageID, models.PageStatusInactive,
 	).Scan(
 		&result.ID,
@@ -370,6 +401,9 @@ func (r *pageRepo) UpdateCompetitorDiffProfile(ctx context.Context, competitorID
 		&result.Title,
 		&result.CaptureProfile,
 		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
 		&result.LastCheckedAt,
 		&result.Status,
 		&result.CreatedAt,
@@ -386,6 +420,111 @@ func (r *pageRepo) UpdateCompetitorDiffProfile(ctx context.Context, competitorID
 	return result, nil
 }
 
+func (r *pageRepo) UpdateCompetitorCheckInterval(ctx context.Context, competitorID, pageID uuid.UUID, checkInterval int) (*models.Page, error) {
+	result := &models.Page{}
+
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+      UPDATE pages
+      SET check_interval = $1
+      WHERE competitor_id = $2 AND id = $3 AND status != $4
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
+		checkInterval, competitorID, pageID, models.PageStatusInactive,
+	).Scan(
+		&result.ID,
+		&result.CompetitorID,
+		&result.URL,
+		&result.Title,
+		&result.CaptureProfile,
+		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
+		&result.LastCheckedAt,
+		&result.Status,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("page not found")
+		}
+		return nil, fmt.Errorf("failed to update check interval: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *pageRepo) UpdateCompetitorContentSelectors(ctx context.Context, competitorID, pageID uuid.UUID, contentSelectors models.ContentSelectors) (*models.Page, error) {
+	result := &models.Page{}
+
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+      UPDATE pages
+      SET content_selectors = $1
+      WHERE competitor_id = $2 AND id = $3 AND status != $4
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
+		contentSelectors, competitorID, pageID, models.PageStatusInactive,
+	).Scan(
+		&result.ID,
+		&result.CompetitorID,
+		&result.URL,
+		&result.Title,
+		&result.CaptureProfile,
+		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
+		&result.LastCheckedAt,
+		&result.Status,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("page not found")
+		}
+		return nil, fmt.Errorf("failed to update content selectors: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *pageRepo) UpdateCompetitorNoiseRules(ctx context.Context, competitorID, pageID uuid.UUID, noiseRules []models.NoiseRule) (*models.Page, error) {
+	result := &models.Page{}
+
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+      UPDATE pages
+      SET noise_rules = $1
+      WHERE competitor_id = $2 AND id = $3 AND status != $4
+      RETURNING id, competitor_id, url, title, capture_profile, diff_profile, check_interval, content_selectors, noise_rules, last_checked_at, status, created_at, updated_at`,
+		noiseRulesOrDefault(noiseRules), competitorID, pageID, models.PageStatusInactive,
+	).Scan(
+		&result.ID,
+		&result.CompetitorID,
+		&result.URL,
+		&result.Title,
+		&result.CaptureProfile,
+		&result.DiffProfile,
+		&result.CheckInterval,
+		&result.ContentSelectors,
+		&result.NoiseRules,
+		&result.LastCheckedAt,
+		&result.Status,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("page not found")
+		}
+		return nil, fmt.Errorf("failed to update noise rules: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *pageRepo) UpdateCompetitorURL(ctx context.Context, competitorID, pageID uuid.UUID, url string) (*models.Page, error) {
 	if competitorID == uuid.Nil || pageID == uuid.Nil || url == "" {
 		return nil, errors.New("invalid competitor ID, page ID, or URL")
@@ -413,6 +552,9 @@ func (r *pageRepo) UpdateCompetitorURL(ctx context.Context, competitorID, pageID
           title,
           capture_profile,
           diff_profile,
+          check_interval,
+          content_selectors,
+          noise_rules,
           last_checked_at,
           status,
           created_at,
@@ -428,6 +570,9 @@ func (r *pageRepo) UpdateCompetitorURL(ctx context.Context, competitorID, pageID
 		&page.Title,
 		&page.CaptureProfile,
 		&page.DiffProfile,
+		&page.CheckInterval,
+		&page.ContentSelectors,
+		&page.NoiseRules,
 		&page.LastCheckedAt,
 		&page.Status,
 		&page.CreatedAt,
@@ -528,20 +673,54 @@ func (r *pageRepo) BatchDeleteAllCompetitorPages(ctx context.Context, competitor
 	return nil
 }
 
+// activePagesFilter returns the conditions selecting the active pages due for a check within the scope
+func activePagesFilter(scope models.JobScope) (string, []interface{}) {
+	query := `
+        WHERE status = $1`
+	args := []interface{}{models.PageStatusActive}
+
+	if scope.IsEmpty() {
+		// Only pages whose check interval has elapsed since the last check are due
+		// The interval is relaxed by checkIntervalTolerance so that pages checked on every run stay due despite jitter
+		query += fmt.Sprintf(`
+        AND (
+            last_checked_at IS NULL
+            OR last_checked_at <= NOW() - check_interval * INTERVAL '1 minute' * %.2f
+        )`, 1-checkIntervalTolerance)
+	}
+
+	// Pages in scope are refreshed on demand, whether they are due or not
+	if len(scope.PageIDs) > 0 {
+		query += fmt.Sprintf(` AND id = ANY($%d)`, len(args)+1)
+		args = append(args, scope.PageIDs)
+	}
+
+	if len(scope.CompetitorIDs) > 0 {
+		query += fmt.Sprintf(` AND competitor_id = ANY($%d)`, len(args)+1)
+		args = append(args, scope.CompetitorIDs)
+	}
+
+	if len(scope.WorkspaceIDs) > 0 {
+		query += fmt.Sprintf(` AND competitor_id IN (SELECT id FROM competitors WHERE workspace_id = ANY($%d))`, len(args)+1)
+		args = append(args, scope.WorkspaceIDs)
+	}
+
+	return query, args
+}
+
 // In repository/page/repository.go
-func (r *pageRepo) GetActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID) (models.ActivePageBatch, error) {
+func (r *pageRepo) GetActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID, scope models.JobScope) (models.ActivePageBatch, error) {
 	if batchSize <= 0 {
 		return models.ActivePageBatch{}, errors.New("invalid batch size")
 	}
 
+	filter, args := activePagesFilter(scope)
 	query := `
         SELECT id
-        FROM pages
-        WHERE status = $1`
-	args := []interface{}{models.PageStatusActive}
+        FROM pages` + filter
 
 	if lastPageID != nil {
-		query += ` AND id > $2`
+		query += fmt.Sprintf(` AND id > $%d`, len(args)+1)
 		args = append(args, *lastPageID)
 	}
 
@@ -601,6 +780,9 @@ func (r *pageRepo) GetPageByPageID(ctx context.Context, pageID uuid.UUID) (*mode
         title,
         capture_profile,
         diff_profile,
+        check_interval,
+        content_selectors,
+        noise_rules,
         last_checked_at,
         status,
         created_at,
@@ -616,6 +798,9 @@ func (r *pageRepo) GetPageByPageID(ctx context.Context, pageID uuid.UUID) (*mode
 		&page.Title,
 		&page.CaptureProfile,
 		&page.DiffProfile,
+		&page.CheckInterval,
+		&page.ContentSelectors,
+		&page.NoiseRules,
 		&page.LastCheckedAt,
 		&page.Status,
 		&page.CreatedAt,
@@ -676,3 +861,193 @@ func (r *pageRepo) GetActivePageCountsByCompetitors(ctx context.Context, competi
 
 	return counts, nil
 }
+
+// checkIntervalOrDefault returns the check interval, falling back to the default when unspecified
+func checkIntervalOrDefault(checkInterval int) int {
+	if checkInterval <= 0 {
+		return models.DefaultCheckInterval
+	}
+	return checkInterval
+}
+
+// contentSelectorsOrDefault returns the content selectors, falling back to the whole page when unspecified
+func contentSelectorsOrDefault(contentSelectors *models.ContentSelectors) models.ContentSelectors {
+	if contentSelectors == nil {
+		return models.ContentSelectors{}
+	}
+	return *contentSelectors
+}
+
+// noiseRulesOrDefault returns the noise rules, falling back to no rules when unspecified
+func noiseRulesOrDefault(noiseRules []models.NoiseRule) []models.NoiseRule {
+	if noiseRules == nil {
+		return []models.NoiseRule{}
+	}
+	return noiseRules
+}
+
+func (r *pageRepo) GetContentFingerprint(ctx context.Context, pageID uuid.UUID) (string, error) {
+	if pageID == uuid.Nil {
+		return "", errors.New("invalid page ID")
+	}
+
+	var fingerprint *string
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+    SELECT content_fingerprint
+    FROM pages
+    WHERE id = $1 AND status != $2`,
+		pageID,
+		models.PageStatusInactive,
+	).Scan(&fingerprint)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", errors.New("page not found")
+		}
+		return "", fmt.Errorf("failed to get content fingerprint: %w", err)
+	}
+
+	// Pages which were never checked don't have a fingerprint yet
+	if fingerprint == nil {
+		return "", nil
+	}
+
+	return *fingerprint, nil
+}
+
+func (r *pageRepo) UpdatePageCheck(ctx context.Context, pageID uuid.UUID, fingerprint string, checkedAt time.Time) error {
+	if pageID == uuid.Nil {
+		return errors.New("invalid page ID")
+	}
+
+	// An empty fingerprint clears the previous one, forcing a comparison during the next check
+	var contentFingerprint *string
+	if fingerprint != "" {
+		contentFingerprint = &fingerprint
+	}
+
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+    UPDATE pages
+    SET content_fingerprint = $1, last_checked_at = $2
+    WHERE id = $3 AND status != $4`,
+		contentFingerprint,
+		checkedAt,
+		pageID,
+		models.PageStatusInactive,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update page check: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("page not found")
+	}
+
+	return nil
+}
+
+func (r *pageRepo) GetWorkspaceNoiseRules(ctx context.Context, pageID uuid.UUID) ([]models.NoiseRule, error) {
+	if pageID == uuid.Nil {
+		return nil, errors.New("invalid page ID")
+	}
+
+	var noiseRules []models.NoiseRule
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+    SELECT w.noise_rules
+    FROM pages p
+    JOIN competitors c ON c.id = p.competitor_id
+    JOIN workspaces w ON w.id = c.workspace_id
+    WHERE p.id = $1 AND p.status != $2`,
+		pageID,
+		models.PageStatusInactive,
+	).Scan(&noiseRules)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("page not found")
+		}
+		return nil, fmt.Errorf("failed to get workspace noise rules: %w", err)
+	}
+
+	return noiseRules, nil
+}
+
+func (r *pageRepo) CountActivePages(ctx context.Context, scope models.JobScope) (int64, error) {
+	filter, args := activePagesFilter(scope)
+	query := `
+        SELECT COUNT(*)
+        FROM pages` + filter
+
+	var count int64
+	if err := r.getQuerier(ctx).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active pages: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *pageRepo) GetPageURLs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	urls := make(map[uuid.UUID]string, len(pageIDs))
+	if len(pageIDs) == 0 {
+		return urls, nil
+	}
+
+	rows, err := r.getQuerier(ctx).Query(ctx, `
+        SELECT id, url
+        FROM pages
+        WHERE id = ANY($1)`,
+		pageIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query page urls: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pageID uuid.UUID
+		var url string
+		if err := rows.Scan(&pageID, &url); err != nil {
+			return nil, fmt.Errorf("failed to scan page url: %w", err)
+		}
+		urls[pageID] = url
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating page urls: %w", err)
+	}
+
+	return urls, nil
+}
+
+func (r *pageRepo) GetPageWorkspaceIDs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	workspaceIDs := make(map[uuid.UUID]uuid.UUID, len(pageIDs))
+	if len(pageIDs) == 0 {
+		return workspaceIDs, nil
+	}
+
+	rows, err := r.getQuerier(ctx).Query(ctx, `
+        SELECT p.id, c.workspace_id
+        FROM pages p
+        JOIN competitors c ON c.id = p.competitor_id
+        WHERE p.id = ANY($1)`,
+		pageIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query page workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pageID, workspaceID uuid.UUID
+		if err := rows.Scan(&pageID, &workspaceID); err != nil {
+			return nil, fmt.Errorf("failed to scan page workspace: %w", err)
+		}
+		workspaceIDs[pageID] = workspaceID
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating page workspaces: %w", err)
+	}
+
+	return workspaceIDs, nil
+}
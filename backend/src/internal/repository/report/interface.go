@@ -22,6 +22,10 @@ type ReportRepository interface {
 	// GetLatest returns the latest report for the given workspace and competitor
 	GetLatest(ctx context.Context, workspaceID, competitorID uuid.UUID) (*models.Report, error)
 
+	// ListSince returns the reports of the active competitors of the workspace created after the given time, newest first
+	// Every competitor of the workspace is listed when no competitor is given
+	ListSince(ctx context.Context, workspaceID uuid.UUID, competitorIDs []uuid.UUID, since time.Time) ([]models.Report, error)
+
 	// GetForPeriod returns a report for the given workspace, competitor and time period
 	GetForPeriod(ctx context.Context, workspaceID, competitorID uuid.UUID, since time.Time) (*models.Report, bool, error)
 
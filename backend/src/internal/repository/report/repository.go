@@ -210,6 +210,60 @@ func (r *reportRespository) List(ctx context.Context, workspaceID, competitorID
 	return reports, hasMore, nil
 }
 
+// ListSince returns the reports of the active competitors of the workspace created after the given time, newest first
+// Every competitor of the workspace is listed when no competitor is given
+func (r *reportRespository) ListSince(ctx context.Context, workspaceID uuid.UUID, competitorIDs []uuid.UUID, since time.Time) ([]models.Report, error) {
+	querier := r.getQuerier(ctx)
+
+	query := `
+        SELECT r.id, r.workspace_id, r.competitor_id, r.competitor_name, r.changes, r.uri, r.time
+        FROM reports r
+        JOIN competitors c ON c.id = r.competitor_id
+        WHERE r.workspace_id = $1 AND r.time > $2 AND c.status = 'active'`
+	args := []interface{}{workspaceID, since}
+
+	if len(competitorIDs) > 0 {
+		query += fmt.Sprintf(` AND r.competitor_id = ANY($%d)`, len(args)+1)
+		args = append(args, competitorIDs)
+	}
+	query += `
+        ORDER BY r.time DESC`
+
+	rows, err := querier.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]models.Report, 0)
+	for rows.Next() {
+		var report models.Report
+		var changesJSON []byte
+
+		err := rows.Scan(
+			&report.ID,
+			&report.WorkspaceID,
+			&report.CompetitorID,
+			&report.CompetitorName,
+			&changesJSON,
+			&report.URI,
+			&report.Time,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+
+		err = json.Unmarshal(changesJSON, &report.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal changes: %w", err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
 // GetLatest returns the latest report for the given workspace and competitor
 func (r *reportRespository) GetLatest(ctx context.Context, workspaceID, competitorID uuid.UUID) (*models.Report, error) {
 	querier := r.getQuerier(ctx)
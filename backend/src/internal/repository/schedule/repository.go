@@ -41,9 +41,9 @@ func (r *scheduleRepo) CreateScheduleWithID(ctx context.Context, id models.Sched
 
 	sql := `
         INSERT INTO workflow_schedules (
-            id, workflow_type, about, spec, created_at, updated_at
+            id, workflow_type, about, spec, pipeline, created_at, updated_at
         ) VALUES (
-            $1, $2, $3, $4, NOW(), NOW()
+            $1, $2, $3, $4, $5, NOW(), NOW()
         )
         RETURNING id`
 
@@ -52,6 +52,7 @@ func (r *scheduleRepo) CreateScheduleWithID(ctx context.Context, id models.Sched
 		workflowProps.WorkflowType,
 		workflowProps.About,
 		workflowProps.Spec,
+		pipelineToText(workflowProps.Pipeline),
 	).Scan(&id)
 
 	if err != nil {
@@ -74,16 +75,18 @@ func (r *scheduleRepo) GetSchedule(ctx context.Context, scheduleID models.Schedu
 
 	sql := `
         SELECT
-            id, workflow_type, about, spec,
+            id, workflow_type, about, spec, pipeline,
             last_run, next_run, created_at, updated_at
         FROM workflow_schedules
         WHERE id = $1 AND deleted_at IS NULL`
 
+	var pipeline []string
 	err := q.QueryRow(ctx, sql, scheduleID).Scan(
 		&schedule.ID,
 		&schedule.WorkflowType,
 		&schedule.About,
 		&schedule.Spec,
+		&pipeline,
 		&schedule.LastRun, // sql.NullTime will handle NULL properly
 		&schedule.NextRun, // sql.NullTime will handle NULL properly
 		&schedule.CreatedAt,
@@ -96,6 +99,7 @@ func (r *scheduleRepo) GetSchedule(ctx context.Context, scheduleID models.Schedu
 		}
 		return models.WorkflowSchedule{}, fmt.Errorf("failed to get schedule: %w", err)
 	}
+	schedule.Pipeline = pipelineFromText(pipeline)
 
 	return schedule, nil
 }
@@ -110,13 +114,15 @@ func (r *scheduleRepo) UpdateSchedule(ctx context.Context, scheduleID models.Sch
             workflow_type = $1,
             about = $2,
             spec = $3,
+            pipeline = $4,
             updated_at = NOW()
-        WHERE id = $4 AND deleted_at IS NULL`
+        WHERE id = $5 AND deleted_at IS NULL`
 
 	result, err := q.Exec(ctx, sql,
 		workflowProps.WorkflowType,
 		workflowProps.About,
 		workflowProps.Spec,
+		pipelineToText(workflowProps.Pipeline),
 		scheduleID,
 	)
 
@@ -160,7 +166,7 @@ func (r *scheduleRepo) ListScheduledWorkflows(ctx context.Context, limit, offset
 
 	sql := `
         SELECT
-            id, workflow_type, about, spec,
+            id, workflow_type, about, spec, pipeline,
             last_run, next_run, created_at, updated_at
         FROM workflow_schedules
         WHERE deleted_at IS NULL`
@@ -193,11 +199,13 @@ func (r *scheduleRepo) ListScheduledWorkflows(ctx context.Context, limit, offset
 	var schedules []models.WorkflowSchedule
 	for rows.Next() {
 		var schedule models.WorkflowSchedule
+		var pipeline []string
 		err := rows.Scan(
 			&schedule.ID,
 			&schedule.WorkflowType,
 			&schedule.About,
 			&schedule.Spec,
+			&pipeline,
 			&schedule.LastRun, // sql.NullTime will handle NULL properly
 			&schedule.NextRun, // sql.NullTime will handle NULL properly
 			&schedule.CreatedAt,
@@ -206,6 +214,7 @@ func (r *scheduleRepo) ListScheduledWorkflows(ctx context.Context, limit, offset
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
+		schedule.Pipeline = pipelineFromText(pipeline)
 		schedules = append(schedules, schedule)
 	}
 
@@ -247,3 +256,30 @@ func (r *scheduleRepo) Sync(ctx context.Context, scheduleID models.ScheduleID, l
 
 	return nil
 }
+
+// pipelineToText converts the stages of a pipeline to the text array they are stored as
+// Schedules running a single workflow are stored without a pipeline
+func pipelineToText(pipeline []models.WorkflowType) []string {
+	if len(pipeline) == 0 {
+		return nil
+	}
+
+	stages := make([]string, 0, len(pipeline))
+	for _, stage := range pipeline {
+		stages = append(stages, string(stage))
+	}
+	return stages
+}
+
+// pipelineFromText converts the text array the stages of a pipeline are stored as
+func pipelineFromText(stages []string) []models.WorkflowType {
+	if len(stages) == 0 {
+		return nil
+	}
+
+	pipeline := make([]models.WorkflowType, 0, len(stages))
+	for _, stage := range stages {
+		pipeline = append(pipeline, models.WorkflowType(stage))
+	}
+	return pipeline
+}
@@ -22,4 +22,8 @@ type ScreenshotRepository interface {
 
 	// RetrieveScreenshotContent retrieves screenshot content from the storage
 	RetrieveScreenshotContent(ctx context.Context, path string) (*models.ScreenshotContent, error)
+
+	// ListScreenshots lists the paths of the objects stored under the given prefix
+	// Paths are returned in lexical order
+	ListScreenshots(ctx context.Context, prefix string) ([]string, error)
 }
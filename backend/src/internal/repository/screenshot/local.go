@@ -10,13 +10,18 @@ import (
 	"image"
 	_ "image/jpeg" // Register JPEG format
 	_ "image/png"  // Register PNG format
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 )
 
+// metadataSuffix is the suffix of the files holding the metadata of a screenshot
+const metadataSuffix = ".metadata.json"
+
 // localScreenshotRepo is a storage repository that uses the local filesystem as the backend
 type localScreenshotRepo struct {
 	// directory is the directory where the files are stored
@@ -147,6 +152,39 @@ func (s *localScreenshotRepo) RetrieveScreenshotContent(ctx context.Context, pat
 	return &screenshotResp, nil
 }
 
+// ListScreenshots lists the paths of the files stored under the given prefix
+func (s *localScreenshotRepo) ListScreenshots(ctx context.Context, prefix string) ([]string, error) {
+	paths := make([]string, 0)
+	root := filepath.Join(s.directory, prefix)
+
+	err := filepath.WalkDir(root, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip directories and the metadata files stored alongside the screenshots
+		if d.IsDir() || strings.HasSuffix(fullPath, metadataSuffix) {
+			return nil
+		}
+
+		path, err := filepath.Rel(s.directory, fullPath)
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, filepath.ToSlash(path))
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return paths, nil
+		}
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return paths, nil
+}
+
 // Get retrieves a binary from the local storage
 func (s *localScreenshotRepo) Get(ctx context.Context, path string) ([]byte, map[string]string, error) {
 	fullPath := filepath.Join(s.directory, path)
@@ -189,7 +227,7 @@ func (s *localScreenshotRepo) loadMetadata(path string) (*models.ScreenshotMetad
 
 // getMetadataPath returns the path for the metadata file
 func (s *localScreenshotRepo) getMetadataPath(path string) string {
-	return filepath.Join(s.directory, path+".metadata.json")
+	return filepath.Join(s.directory, path+metadataSuffix)
 }
 
 // saveMetadata saves metadata to a separate file
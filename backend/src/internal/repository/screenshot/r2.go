@@ -171,6 +171,28 @@ func (r *r2ScreenshotRepo) RetrieveScreenshotContent(ctx context.Context, path s
 	return &resp, nil
 }
 
+// ListScreenshots lists the paths of the objects stored under the given prefix
+func (r *r2ScreenshotRepo) ListScreenshots(ctx context.Context, prefix string) ([]string, error) {
+	paths := make([]string, 0)
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			paths = append(paths, aws.ToString(object.Key))
+		}
+	}
+
+	return paths, nil
+}
+
 func (s *r2ScreenshotRepo) Get(ctx context.Context, path string) ([]byte, map[string]string, error) {
 	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
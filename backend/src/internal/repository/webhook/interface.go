@@ -0,0 +1,49 @@
+// ./src/internal/repository/webhook/interface.go
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// WebhookRepository is the interface that provides webhook operations
+// This is used to interact with the webhooks and webhook_deliveries tables
+type WebhookRepository interface {
+	// CreateWebhook creates a webhook for the workspace, signing its payloads with the secret
+	CreateWebhook(ctx context.Context, workspaceID uuid.UUID, secret string, props models.WebhookProps) (*models.Webhook, error)
+
+	// GetWebhook returns a webhook of the workspace
+	GetWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.Webhook, error)
+
+	// GetWebhookByID returns a webhook, whichever workspace it belongs to
+	GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error)
+
+	// ListWebhooks lists the webhooks of the workspace
+	ListWebhooks(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Webhook, bool, error)
+
+	// ListSubscribedWebhooks lists the active webhooks of the workspace sent events of the type
+	ListSubscribedWebhooks(ctx context.Context, workspaceID uuid.UUID, eventType models.WebhookEventType) ([]models.Webhook, error)
+
+	// UpdateWebhook replaces the settings of a webhook of the workspace
+	UpdateWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID, props models.WebhookProps) (*models.Webhook, error)
+
+	// DeleteWebhook deletes a webhook of the workspace, along with its deliveries
+	DeleteWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) error
+
+	// CreateDelivery logs a pending delivery of the event to the webhook
+	// The delivery is due at the next attempt time, it's never claimed when the time is nil
+	CreateDelivery(ctx context.Context, webhookID uuid.UUID, event models.WebhookEvent, payload []byte, nextAttemptAt *time.Time) (*models.WebhookDelivery, error)
+
+	// ClaimDueDeliveries claims the pending deliveries to active webhooks whose next attempt is due
+	// Claimed deliveries aren't due again until the lease expires, so that replicas don't attempt them twice
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+
+	// RecordDeliveryAttempt records the outcome of an attempt at a delivery
+	RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, attempt models.WebhookDeliveryAttempt) (*models.WebhookDelivery, error)
+
+	// ListDeliveries lists the deliveries of a webhook of the workspace, latest first
+	ListDeliveries(ctx context.Context, workspaceID, webhookID uuid.UUID, limit, offset *int) ([]models.WebhookDelivery, bool, error)
+}
@@ -0,0 +1,361 @@
+// ./src/internal/repository/webhook/repository.go
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/transaction"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+type webhookRepo struct {
+	tm     *transaction.TxManager
+	logger *logger.Logger
+}
+
+func NewWebhookRepository(tm *transaction.TxManager, logger *logger.Logger) WebhookRepository {
+	return &webhookRepo{
+		tm:     tm,
+		logger: logger.WithFields(map[string]interface{}{"module": "webhook_repository"}),
+	}
+}
+
+func (r *webhookRepo) getQuerier(ctx context.Context) interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, arguments ...interface{}) pgx.Row
+} {
+	return r.tm.GetQuerier(ctx)
+}
+
+const webhookColumns = `id, workspace_id, url, secret, event_types, active, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var eventTypes []string
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.WorkspaceID,
+		&webhook.URL,
+		&webhook.Secret,
+		&eventTypes,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.EventTypes = make([]models.WebhookEventType, len(eventTypes))
+	for i, eventType := range eventTypes {
+		webhook.EventTypes[i] = models.WebhookEventType(eventType)
+	}
+
+	return &webhook, nil
+}
+
+func eventTypeStrings(eventTypes []models.WebhookEventType) []string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return values
+}
+
+func (r *webhookRepo) CreateWebhook(ctx context.Context, workspaceID uuid.UUID, secret string, props models.WebhookProps) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.getQuerier(ctx).QueryRow(ctx, `
+		INSERT INTO webhooks (workspace_id, url, secret, event_types, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+webhookColumns,
+		workspaceID, props.URL, secret, eventTypeStrings(props.EventTypes), props.Active,
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepo) GetWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.getQuerier(ctx).QueryRow(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE workspace_id = $1 AND id = $2`,
+		workspaceID, webhookID,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepo) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.getQuerier(ctx).QueryRow(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE id = $1`,
+		webhookID,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepo) ListWebhooks(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Webhook, bool, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE workspace_id = $1
+		ORDER BY created_at DESC`
+
+	args := []interface{}{workspaceID}
+
+	if limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, *limit)
+	}
+
+	if offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
+		args = append(args, *offset)
+	}
+
+	webhooks, err := r.queryWebhooks(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := limit != nil && len(webhooks) == *limit
+
+	return webhooks, hasMore, nil
+}
+
+func (r *webhookRepo) ListSubscribedWebhooks(ctx context.Context, workspaceID uuid.UUID, eventType models.WebhookEventType) ([]models.Webhook, error) {
+	// Webhooks without event types are sent every event
+	return r.queryWebhooks(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE workspace_id = $1 AND active
+		AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+		ORDER BY created_at`,
+		workspaceID, string(eventType),
+	)
+}
+
+func (r *webhookRepo) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *webhookRepo) UpdateWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID, props models.WebhookProps) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.getQuerier(ctx).QueryRow(ctx, `
+		UPDATE webhooks
+		SET url = $1, event_types = $2, active = $3
+		WHERE workspace_id = $4 AND id = $5
+		RETURNING `+webhookColumns,
+		props.URL, eventTypeStrings(props.EventTypes), props.Active, workspaceID, webhookID,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepo) DeleteWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) error {
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		DELETE FROM webhooks
+		WHERE workspace_id = $1 AND id = $2`,
+		workspaceID, webhookID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+
+	return nil
+}
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, response_status, error, next_attempt_at, last_attempt_at, created_at, updated_at`
+
+func scanWebhookDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var deliveryError *string
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&deliveryError,
+		&delivery.NextAttemptAt,
+		&delivery.LastAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveryError != nil {
+		delivery.Error = *deliveryError
+	}
+
+	return &delivery, nil
+}
+
+func (r *webhookRepo) CreateDelivery(ctx context.Context, webhookID uuid.UUID, event models.WebhookEvent, payload []byte, nextAttemptAt *time.Time) (*models.WebhookDelivery, error) {
+	delivery, err := scanWebhookDelivery(r.getQuerier(ctx).QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+webhookDeliveryColumns,
+		webhookID, event.ID, string(event.Type), payload, models.WebhookDeliveryStatusPending, nextAttemptAt,
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $1 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = $2 AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns,
+		lease.Milliseconds(), models.WebhookDeliveryStatusPending, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepo) RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, attempt models.WebhookDeliveryAttempt) (*models.WebhookDelivery, error) {
+	var deliveryError *string
+	if attempt.Error != "" {
+		deliveryError = &attempt.Error
+	}
+
+	delivery, err := scanWebhookDelivery(r.getQuerier(ctx).QueryRow(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_status = $3, error = $4, next_attempt_at = $5, last_attempt_at = $6
+		WHERE id = $7
+		RETURNING `+webhookDeliveryColumns,
+		attempt.Status, attempt.Attempt, attempt.ResponseStatus, deliveryError, attempt.NextAttemptAt, attempt.AttemptedAt, deliveryID,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("webhook delivery not found")
+		}
+		return nil, fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, workspaceID, webhookID uuid.UUID, limit, offset *int) ([]models.WebhookDelivery, bool, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id IN (
+			SELECT id FROM webhooks WHERE workspace_id = $1 AND id = $2
+		)
+		ORDER BY created_at DESC`
+
+	args := []interface{}{workspaceID, webhookID}
+
+	if limit != nil {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, *limit)
+	}
+
+	if offset != nil {
+		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
+		args = append(args, *offset)
+	}
+
+	rows, err := r.getQuerier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	hasMore := limit != nil && len(deliveries) == *limit
+
+	return deliveries, hasMore, rows.Err()
+}
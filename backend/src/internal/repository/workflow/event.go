@@ -0,0 +1,79 @@
+// ./src/internal/repository/workflow/event.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"go.uber.org/zap"
+)
+
+const (
+	// Channel format: workflow:events:{type}
+	eventChannelFormat = "workflow:events:%s"
+	// eventBufferSize is the number of events buffered for a subscriber
+	// Once the buffer is full, receiving more events blocks until the subscriber catches up
+	eventBufferSize = 64
+)
+
+func (r *workflowRepo) getEventChannel(workflowType models.WorkflowType) string {
+	return fmt.Sprintf(eventChannelFormat, workflowType)
+}
+
+func (r *workflowRepo) PublishEvent(ctx context.Context, event models.JobEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job event: %w", err)
+	}
+
+	if err := r.client.Publish(ctx, r.getEventChannel(event.WorkflowType), payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish job event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) SubscribeEvents(ctx context.Context, workflowType models.WorkflowType) (<-chan models.JobEvent, error) {
+	pubsub := r.client.Subscribe(ctx, r.getEventChannel(workflowType))
+
+	// Wait for the subscription, so that no event published from now on is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to job events: %w", err)
+	}
+
+	events := make(chan models.JobEvent, eventBufferSize)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event models.JobEvent
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					r.logger.Error("failed to unmarshal job event", zap.Error(err))
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
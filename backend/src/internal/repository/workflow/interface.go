@@ -3,6 +3,7 @@ package workflow
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -13,6 +14,10 @@ import (
 type WorkflowRepository interface {
 	CheckpointRepository
 	StateRepository
+	OutcomeRepository
+	LeaseRepository
+	PipelineRepository
+	EventRepository
 }
 
 // CheckpointRepository is the interface that provides checkpoint operations
@@ -26,20 +31,125 @@ type CheckpointRepository interface {
 
 	// Active returns the list of active jobs in the workflow
 	ListActiveJobs(ctx context.Context, workflowType models.WorkflowType) ([]models.Job, error)
+
+	// ListJobs returns the list of jobs in the workflow with the given status
+	ListJobs(ctx context.Context, workflowType models.WorkflowType, status models.JobStatus) ([]models.Job, error)
+
+	// AddProgress adds the items processed by a replica to the progress of the job
+	// The progress is shared by the replicas running the job, and replaces the counts of its stored state
+	// Returns the progress of the job including the items added
+	AddProgress(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, completed, failed, skipped int64) (models.JobProgress, error)
+
+	// RequestCancel requests the cancellation of the job from the replica running it
+	RequestCancel(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error
+
+	// CancelRequested returns true if the cancellation of the job was requested
+	CancelRequested(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (bool, error)
+
+	// RequestPause requests the pause of the job from the replica running it
+	RequestPause(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error
+
+	// PauseRequested returns true if the pause of the job was requested, and the job wasn't paused since
+	PauseRequested(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (bool, error)
 }
 
 // StateRepository is the interface that provides state operations
 // This is used by workflow scheduler and workflow service to store and retrieve the state of a workflow
 type StateRepository interface {
 	// StartJob initializes a new workflow in the repository
-	StartJob(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error
+	// The scope is kept in the state of the job, so that a recovered job covers the same items
+	StartJob(ctx context.Context, jobID uuid.UUID, scope models.JobScope, workflowType models.WorkflowType) error
 
 	// CompleteJob completes a workflow in the repository
+	// The final state of the job is persisted in its record, beyond the lifetime of its checkpoint
 	CompleteJob(ctx context.Context, jobID uuid.UUID, jobContext *models.JobState, workflowType models.WorkflowType) error
 
 	// CancelJob cancels a workflow in the repository
+	// The final state of the job is persisted in its record, beyond the lifetime of its checkpoint
 	CancelJob(ctx context.Context, jobID uuid.UUID, jobContext *models.JobState, workflowType models.WorkflowType) error
 
-	// ListRecords returns the list of jobs records in the repository
-	ListRecords(ctx context.Context, workflowType *models.WorkflowType, limit, offset *int) ([]models.JobRecord, error)
+	// PauseJob pauses a workflow in the repository
+	// The state of a paused job, along with its progress and finished items, is kept until it's resumed
+	PauseJob(ctx context.Context, jobID uuid.UUID, jobContext *models.JobState, workflowType models.WorkflowType) error
+
+	// ResumeJob resumes a paused workflow in the repository
+	// Returns the state of the job, to be run again from its checkpoint
+	ResumeJob(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (models.JobState, error)
+
+	// GetRecord returns the record of a job, including its final state once it ended
+	// It returns ErrJobNotFound when the job was never started
+	GetRecord(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (models.JobRecord, error)
+
+	// ListRecords returns the list of jobs records in the repository matching the filter
+	ListRecords(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error)
+
+	// IncrementPreemptions increments the preemption count for a job
+	// This is used when a job is taken over from a replica which stopped running it
+	IncrementPreemptions(ctx context.Context, jobID uuid.UUID) error
+}
+
+// OutcomeRepository is the interface that provides outcome operations
+// This is used by workflow observers to record the outcome of each item and manage the dead-letter list of failed items
+type OutcomeRepository interface {
+	// RecordOutcomes records the outcomes of the items processed by a job
+	// Failed items are added to the dead-letter list, while items processed on a later attempt are resolved
+	RecordOutcomes(ctx context.Context, outcomes []models.JobItemOutcome) error
+
+	// ListFailures returns the dead-letter list of a job, optionally filtered by status
+	ListFailures(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error)
+
+	// ClaimFailures marks the pending failures of a job as retrying and returns them
+	// When itemIDs is empty, every pending failure of the job is claimed
+	ClaimFailures(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, itemIDs []uuid.UUID) ([]models.JobFailure, error)
+}
+
+// LeaseRepository is the interface that provides lease operations
+// This is used by replicas to elect the one in charge of a job or a schedule, and to share the items of a job
+type LeaseRepository interface {
+	// AcquireLease acquires the named lease for the holder, or extends it if the holder already holds it
+	// Returns false if the lease is held by another holder
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease releases the named lease, if it is held by the holder
+	ReleaseLease(ctx context.Context, name, holder string) error
+
+	// LeaseItems leases the items of a job which are neither finished nor leased by another holder
+	// Returns the items leased by the holder, and the items held by other holders
+	LeaseItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID, ttl time.Duration) ([]uuid.UUID, []uuid.UUID, error)
+
+	// RenewItems extends the leases of the holder on the items of a job
+	RenewItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID, ttl time.Duration) error
+
+	// ReleaseItems releases the leases of the holder on the items of a job, so that other holders can lease them
+	ReleaseItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID) error
+
+	// FinishItems marks the items of a job as finished, and releases the leases of the holder on them
+	FinishItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID) error
+}
+
+// PipelineRepository is the interface that provides pipeline run operations
+// This is used by the workflow service to persist the stages of pipeline runs
+type PipelineRepository interface {
+	// CreatePipelineRun persists a new pipeline run
+	CreatePipelineRun(ctx context.Context, run models.PipelineRun) error
+
+	// UpdatePipelineRun persists the status and the stages of the pipeline run
+	UpdatePipelineRun(ctx context.Context, run models.PipelineRun) error
+
+	// GetPipelineRun returns the pipeline run
+	GetPipelineRun(ctx context.Context, runID uuid.UUID) (models.PipelineRun, error)
+
+	// ListPipelineRuns returns the pipeline runs with the given status, most recent first
+	ListPipelineRuns(ctx context.Context, status *models.PipelineStatus, limit, offset *int) ([]models.PipelineRun, error)
+}
+
+// EventRepository is the interface that provides job event operations
+// This is used by workflow observers to stream the progress of jobs across the replicas running them
+type EventRepository interface {
+	// PublishEvent publishes the event to the subscribers of its workflow, on every replica
+	PublishEvent(ctx context.Context, event models.JobEvent) error
+
+	// SubscribeEvents subscribes to the events of the workflow, until the context is done
+	// The channel is closed once the subscription ends
+	SubscribeEvents(ctx context.Context, workflowType models.WorkflowType) (<-chan models.JobEvent, error)
 }
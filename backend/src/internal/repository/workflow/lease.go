@@ -0,0 +1,207 @@
+// ./src/internal/repository/workflow/lease.go
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+const (
+	// Key format: lease:{name}
+	leaseKeyFormat = "lease:%s"
+	// Key format: workflow:lease:{type}:{jobId}:{itemId}
+	itemLeaseKeyFormat = "workflow:lease:%s:%s:%s"
+	// Key format: workflow:finished:{type}:{jobId}
+	finishedKeyFormat = "workflow:finished:%s:%s"
+)
+
+// Item lease states returned by the lease script
+const (
+	itemLeased int64 = iota
+	itemHeld
+	itemFinished
+)
+
+var (
+	// acquireLeaseScript acquires the lease for the holder, or extends it if the holder already holds it
+	acquireLeaseScript = redis.NewScript(`
+		local holder = redis.call('GET', KEYS[1])
+		if holder == ARGV[1] then
+			redis.call('PEXPIRE', KEYS[1], ARGV[2])
+			return 1
+		end
+		if not holder then
+			redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+			return 1
+		end
+		return 0`)
+
+	// releaseLeaseScript releases the leases held by the holder, leases held by other holders are left untouched
+	releaseLeaseScript = redis.NewScript(`
+		for i = 1, #KEYS do
+			if redis.call('GET', KEYS[i]) == ARGV[1] then
+				redis.call('DEL', KEYS[i])
+			end
+		end
+		return 0`)
+
+	// renewLeaseScript extends the leases held by the holder
+	renewLeaseScript = redis.NewScript(`
+		for i = 1, #KEYS do
+			if redis.call('GET', KEYS[i]) == ARGV[1] then
+				redis.call('PEXPIRE', KEYS[i], ARGV[2])
+			end
+		end
+		return 0`)
+
+	// leaseItemsScript leases the items which are neither finished nor held by another holder
+	// KEYS[1] is the set of finished items, followed by the lease of each item, ARGV[3:] are the items
+	leaseItemsScript = redis.NewScript(`
+		local states = {}
+		for i = 2, #KEYS do
+			if redis.call('SISMEMBER', KEYS[1], ARGV[i + 1]) == 1 then
+				states[i - 1] = 2
+			else
+				local holder = redis.call('GET', KEYS[i])
+				if not holder or holder == ARGV[1] then
+					redis.call('SET', KEYS[i], ARGV[1], 'PX', ARGV[2])
+					states[i - 1] = 0
+				else
+					states[i - 1] = 1
+				end
+			end
+		end
+		return states`)
+
+	// finishItemsScript adds the items to the set of finished items and releases the leases held by the holder
+	// KEYS[1] is the set of finished items, followed by the lease of each item, ARGV[3:] are the items
+	finishItemsScript = redis.NewScript(`
+		for i = 2, #KEYS do
+			redis.call('SADD', KEYS[1], ARGV[i + 1])
+			if redis.call('GET', KEYS[i]) == ARGV[1] then
+				redis.call('DEL', KEYS[i])
+			end
+		end
+		redis.call('PEXPIRE', KEYS[1], ARGV[2])
+		return 0`)
+)
+
+func (r *workflowRepo) getLeaseKey(name string) string {
+	return fmt.Sprintf(leaseKeyFormat, name)
+}
+
+func (r *workflowRepo) getFinishedKey(jobID uuid.UUID, workflowType models.WorkflowType) string {
+	return fmt.Sprintf(finishedKeyFormat, workflowType, jobID.String())
+}
+
+// getItemKeys returns the set of finished items of the job, followed by the lease of each item
+func (r *workflowRepo) getItemKeys(jobID uuid.UUID, workflowType models.WorkflowType, itemIDs []uuid.UUID) []string {
+	keys := make([]string, 0, len(itemIDs)+1)
+	keys = append(keys, r.getFinishedKey(jobID, workflowType))
+	for _, itemID := range itemIDs {
+		keys = append(keys, fmt.Sprintf(itemLeaseKeyFormat, workflowType, jobID.String(), itemID.String()))
+	}
+	return keys
+}
+
+// getItemArgs returns the holder and the ttl, followed by each item
+func (r *workflowRepo) getItemArgs(holder string, ttl time.Duration, itemIDs []uuid.UUID) []interface{} {
+	args := make([]interface{}, 0, len(itemIDs)+2)
+	args = append(args, holder, ttl.Milliseconds())
+	for _, itemID := range itemIDs {
+		args = append(args, itemID.String())
+	}
+	return args
+}
+
+func (r *workflowRepo) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireLeaseScript.Run(ctx, r.client, []string{r.getLeaseKey(name)}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+
+	return acquired == 1, nil
+}
+
+func (r *workflowRepo) ReleaseLease(ctx context.Context, name, holder string) error {
+	if err := releaseLeaseScript.Run(ctx, r.client, []string{r.getLeaseKey(name)}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) LeaseItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID, ttl time.Duration) ([]uuid.UUID, []uuid.UUID, error) {
+	if len(itemIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	keys := r.getItemKeys(jobID, workflowType, itemIDs)
+	states, err := leaseItemsScript.Run(ctx, r.client, keys, r.getItemArgs(holder, ttl, itemIDs)...).Int64Slice()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lease items: %w", err)
+	}
+
+	if len(states) != len(itemIDs) {
+		return nil, nil, fmt.Errorf("failed to lease items: expected %d states, got %d", len(itemIDs), len(states))
+	}
+
+	leased := make([]uuid.UUID, 0, len(itemIDs))
+	held := make([]uuid.UUID, 0)
+	for i, state := range states {
+		switch state {
+		case itemLeased:
+			leased = append(leased, itemIDs[i])
+		case itemHeld:
+			held = append(held, itemIDs[i])
+		}
+	}
+
+	return leased, held, nil
+}
+
+func (r *workflowRepo) RenewItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID, ttl time.Duration) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	// The set of finished items isn't renewed, it is skipped along with its argument
+	keys := r.getItemKeys(jobID, workflowType, itemIDs)[1:]
+	if err := renewLeaseScript.Run(ctx, r.client, keys, holder, ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("failed to renew item leases: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) ReleaseItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	keys := r.getItemKeys(jobID, workflowType, itemIDs)[1:]
+	if err := releaseLeaseScript.Run(ctx, r.client, keys, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release item leases: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) FinishItems(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, holder string, itemIDs []uuid.UUID) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	// The set of finished items lives as long as the state of the job
+	keys := r.getItemKeys(jobID, workflowType, itemIDs)
+	if err := finishItemsScript.Run(ctx, r.client, keys, r.getItemArgs(holder, defaultTTL, itemIDs)...).Err(); err != nil {
+		return fmt.Errorf("failed to finish items: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// ./src/internal/repository/workflow/outcome.go
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// staleRetryInterval is the interval after which a failure stuck in retrying can be claimed again
+// This covers retries interrupted by a restart
+const staleRetryInterval = "1 hour"
+
+func (r *workflowRepo) RecordOutcomes(ctx context.Context, outcomes []models.JobItemOutcome) error {
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	return r.tm.RunInTx(ctx, nil, func(ctx context.Context) error {
+		q := r.getQuerier(ctx)
+
+		for _, outcome := range outcomes {
+			var outcomeError *string
+			if outcome.Error != "" {
+				outcomeError = &outcome.Error
+			}
+
+			_, err := q.Exec(ctx, `
+                INSERT INTO job_item_outcomes (
+                    job_id, workflow_type, item_id, attempt, status, error, duration_ms, created_at
+                ) VALUES (
+                    $1, $2, $3, $4, $5, $6, $7, $8
+                )`,
+				outcome.JobID, outcome.WorkflowType, outcome.ItemID, outcome.Attempt,
+				outcome.Status, outcomeError, outcome.Duration.Milliseconds(), outcome.Time,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to record outcome: %w", err)
+			}
+
+			if outcome.Status == models.JobItemStatusFailed {
+				_, err = q.Exec(ctx, `
+                    INSERT INTO job_failures (
+                        job_id, workflow_type, item_id, attempts, last_error
+                    ) VALUES (
+                        $1, $2, $3, $4, $5
+                    )
+                    ON CONFLICT (job_id, item_id) DO UPDATE
+                    SET
+                        attempts = GREATEST(job_failures.attempts, EXCLUDED.attempts),
+                        last_error = EXCLUDED.last_error,
+                        status = 'pending',
+                        updated_at = NOW()`,
+					outcome.JobID, outcome.WorkflowType, outcome.ItemID, outcome.Attempt, outcome.Error,
+				)
+			} else {
+				_, err = q.Exec(ctx, `
+                    UPDATE job_failures
+                    SET
+                        attempts = GREATEST(attempts, $3),
+                        status = 'resolved',
+                        updated_at = NOW()
+                    WHERE job_id = $1
+                    AND item_id = $2
+                    AND status <> 'resolved'`,
+					outcome.JobID, outcome.ItemID, outcome.Attempt,
+				)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update dead-letter list: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *workflowRepo) ListFailures(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error) {
+	args := []interface{}{jobID, workflowType}
+	argPosition := 3
+
+	sql := `
+        SELECT
+            id, job_id, workflow_type, item_id,
+            attempts, last_error, status,
+            created_at, updated_at
+        FROM job_failures
+        WHERE job_id = $1
+        AND workflow_type = $2`
+
+	if status != nil {
+		sql += fmt.Sprintf(" AND status = $%d", argPosition)
+		args = append(args, *status)
+		argPosition++
+	}
+
+	sql += " ORDER BY created_at ASC"
+
+	if limit != nil {
+		sql += fmt.Sprintf(" LIMIT $%d", argPosition)
+		args = append(args, *limit)
+		argPosition++
+	}
+
+	if offset != nil {
+		sql += fmt.Sprintf(" OFFSET $%d", argPosition)
+		args = append(args, *offset)
+	}
+
+	return r.queryFailures(ctx, sql, args...)
+}
+
+func (r *workflowRepo) ClaimFailures(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, itemIDs []uuid.UUID) ([]models.JobFailure, error) {
+	if itemIDs == nil {
+		itemIDs = []uuid.UUID{}
+	}
+
+	sql := `
+        UPDATE job_failures
+        SET
+            status = 'retrying',
+            updated_at = NOW()
+        WHERE job_id = $1
+        AND workflow_type = $2
+        AND (cardinality($3::uuid[]) = 0 OR item_id = ANY($3))
+        AND (
+            status = 'pending'
+            OR (status = 'retrying' AND updated_at < NOW() - INTERVAL '` + staleRetryInterval + `')
+        )
+        RETURNING
+            id, job_id, workflow_type, item_id,
+            attempts, last_error, status,
+            created_at, updated_at`
+
+	return r.queryFailures(ctx, sql, jobID, workflowType, itemIDs)
+}
+
+// queryFailures runs the query and scans the failures it returns
+func (r *workflowRepo) queryFailures(ctx context.Context, sql string, args ...interface{}) ([]models.JobFailure, error) {
+	rows, err := r.getQuerier(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := make([]models.JobFailure, 0)
+	for rows.Next() {
+		var failure models.JobFailure
+		err := rows.Scan(
+			&failure.ID,
+			&failure.JobID,
+			&failure.WorkflowType,
+			&failure.ItemID,
+			&failure.Attempts,
+			&failure.LastError,
+			&failure.Status,
+			&failure.CreatedAt,
+			&failure.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan failure: %w", err)
+		}
+		failures = append(failures, failure)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating failures: %w", err)
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,184 @@
+// ./src/internal/repository/workflow/pipeline.go
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// pipelineRunColumns are the columns scanned by scanPipelineRun
+const pipelineRunColumns = `
+            id, status, stages, scope, start_time, end_time`
+
+func (r *workflowRepo) scanPipelineRun(row pgx.Row) (models.PipelineRun, error) {
+	var run models.PipelineRun
+	var stages, scope []byte
+	err := row.Scan(
+		&run.ID,
+		&run.Status,
+		&stages,
+		&scope,
+		&run.StartTime,
+		&run.EndTime,
+	)
+	if err != nil {
+		return models.PipelineRun{}, err
+	}
+
+	if err := json.Unmarshal(stages, &run.Stages); err != nil {
+		return models.PipelineRun{}, fmt.Errorf("failed to unmarshal pipeline stages: %w", err)
+	}
+
+	if len(scope) > 0 {
+		if err := json.Unmarshal(scope, &run.Scope); err != nil {
+			return models.PipelineRun{}, fmt.Errorf("failed to unmarshal pipeline scope: %w", err)
+		}
+	}
+
+	return run, nil
+}
+
+// marshalPipelineRun returns the stages and the scope of the run as stored
+// Runs covering every item are stored without a scope
+func (r *workflowRepo) marshalPipelineRun(run models.PipelineRun) ([]byte, []byte, error) {
+	stages, err := json.Marshal(run.Stages)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal pipeline stages: %w", err)
+	}
+
+	var scope []byte
+	if !run.Scope.IsEmpty() {
+		scope, err = json.Marshal(run.Scope)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal pipeline scope: %w", err)
+		}
+	}
+
+	return stages, scope, nil
+}
+
+func (r *workflowRepo) CreatePipelineRun(ctx context.Context, run models.PipelineRun) error {
+	stages, scope, err := r.marshalPipelineRun(run)
+	if err != nil {
+		return err
+	}
+
+	q := r.getQuerier(ctx)
+
+	sql := `
+        INSERT INTO pipeline_runs (
+            id, status, stages, scope, start_time, end_time, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, NOW(), NOW()
+        )`
+
+	if _, err := q.Exec(ctx, sql, run.ID, run.Status, stages, scope, run.StartTime, run.EndTime); err != nil {
+		return fmt.Errorf("failed to create pipeline run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) UpdatePipelineRun(ctx context.Context, run models.PipelineRun) error {
+	stages, _, err := r.marshalPipelineRun(run)
+	if err != nil {
+		return err
+	}
+
+	q := r.getQuerier(ctx)
+
+	sql := `
+        UPDATE pipeline_runs
+        SET
+            status = $1,
+            stages = $2,
+            end_time = $3,
+            updated_at = NOW()
+        WHERE id = $4`
+
+	result, err := q.Exec(ctx, sql, run.Status, stages, run.EndTime, run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update pipeline run: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("pipeline run not found")
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) GetPipelineRun(ctx context.Context, runID uuid.UUID) (models.PipelineRun, error) {
+	q := r.getQuerier(ctx)
+
+	sql := `
+        SELECT` + pipelineRunColumns + `
+        FROM pipeline_runs
+        WHERE id = $1`
+
+	run, err := r.scanPipelineRun(q.QueryRow(ctx, sql, runID))
+	if err == pgx.ErrNoRows {
+		return models.PipelineRun{}, fmt.Errorf("pipeline run not found")
+	}
+	if err != nil {
+		return models.PipelineRun{}, fmt.Errorf("failed to get pipeline run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *workflowRepo) ListPipelineRuns(ctx context.Context, status *models.PipelineStatus, limit, offset *int) ([]models.PipelineRun, error) {
+	q := r.getQuerier(ctx)
+	var args []interface{}
+	argPosition := 1
+
+	sql := `
+        SELECT` + pipelineRunColumns + `
+        FROM pipeline_runs
+        WHERE TRUE`
+
+	if status != nil {
+		sql += fmt.Sprintf(" AND status = $%d", argPosition)
+		args = append(args, *status)
+		argPosition++
+	}
+
+	sql += " ORDER BY start_time DESC"
+
+	if limit != nil {
+		sql += fmt.Sprintf(" LIMIT $%d", argPosition)
+		args = append(args, *limit)
+		argPosition++
+	}
+
+	if offset != nil {
+		sql += fmt.Sprintf(" OFFSET $%d", argPosition)
+		args = append(args, *offset)
+	}
+
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]models.PipelineRun, 0)
+	for rows.Next() {
+		run, err := r.scanPipelineRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pipeline run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pipeline runs: %w", err)
+	}
+
+	return runs, nil
+}
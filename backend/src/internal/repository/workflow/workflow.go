@@ -4,6 +4,7 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,6 +24,12 @@ const (
 	keyFormat = "workflow:%s:%s:%s"
 	// Key pattern for listing: workflow:{type}:{status}:*
 	keyPattern = "workflow:%s:%s:*"
+	// Key format: workflow:progress:{type}:{jobId}
+	progressKeyFormat = "workflow:progress:%s:%s"
+	// Key format: workflow:cancel:{type}:{jobId}
+	cancelKeyFormat = "workflow:cancel:%s:%s"
+	// Key format: workflow:pause:{type}:{jobId}
+	pauseKeyFormat = "workflow:pause:%s:%s"
 	// Default TTL for workflow keys (3 days)
 	defaultTTL = 72 * time.Hour
 )
@@ -83,6 +90,7 @@ func (r *workflowRepo) GetState(ctx context.Context, jobID uuid.UUID, workflowTy
 		models.JobStatusFailed,
 		models.JobStatusAborted,
 		models.JobStatusUnknown,
+		models.JobStatusPaused,
 	} {
 		key := r.getKey(jobID, workflowType, status)
 		data, err := r.client.Get(ctx, key).Bytes()
@@ -98,12 +106,111 @@ func (r *workflowRepo) GetState(ctx context.Context, jobID uuid.UUID, workflowTy
 			return models.JobState{}, fmt.Errorf("failed to unmarshal job state: %w", err)
 		}
 
+		if err := r.applyProgress(ctx, jobID, workflowType, &state); err != nil {
+			return models.JobState{}, err
+		}
+
 		return state, nil
 	}
 
 	return models.JobState{}, fmt.Errorf("job not found")
 }
 
+func (r *workflowRepo) getProgressKey(jobID uuid.UUID, workflowType models.WorkflowType) string {
+	return fmt.Sprintf(progressKeyFormat, workflowType, jobID.String())
+}
+
+func (r *workflowRepo) getPauseKey(jobID uuid.UUID, workflowType models.WorkflowType) string {
+	return fmt.Sprintf(pauseKeyFormat, workflowType, jobID.String())
+}
+
+func (r *workflowRepo) getCancelKey(jobID uuid.UUID, workflowType models.WorkflowType) string {
+	return fmt.Sprintf(cancelKeyFormat, workflowType, jobID.String())
+}
+
+// applyProgress replaces the counts of the state with the progress shared by the replicas running the job
+// States of jobs which haven't made any progress yet keep their own counts
+func (r *workflowRepo) applyProgress(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, state *models.JobState) error {
+	progress, err := r.client.HGetAll(ctx, r.getProgressKey(jobID, workflowType)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	if len(progress) == 0 {
+		return nil
+	}
+
+	counts := map[string]*int64{
+		"completed": &state.Completed,
+		"failed":    &state.Failed,
+		"skipped":   &state.Skipped,
+	}
+	for field, count := range counts {
+		*count = 0
+		if value, ok := progress[field]; ok {
+			if _, err := fmt.Sscan(value, count); err != nil {
+				return fmt.Errorf("failed to parse job progress: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) AddProgress(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, completed, failed, skipped int64) (models.JobProgress, error) {
+	key := r.getProgressKey(jobID, workflowType)
+
+	// Every field is created, so that the progress replaces the counts of the state from the first update on
+	pipe := r.client.TxPipeline()
+	completedCmd := pipe.HIncrBy(ctx, key, "completed", completed)
+	failedCmd := pipe.HIncrBy(ctx, key, "failed", failed)
+	skippedCmd := pipe.HIncrBy(ctx, key, "skipped", skipped)
+	pipe.Expire(ctx, key, defaultTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return models.JobProgress{}, fmt.Errorf("failed to add job progress: %w", err)
+	}
+
+	return models.JobProgress{
+		Completed: completedCmd.Val(),
+		Failed:    failedCmd.Val(),
+		Skipped:   skippedCmd.Val(),
+	}, nil
+}
+
+func (r *workflowRepo) RequestCancel(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error {
+	if err := r.client.Set(ctx, r.getCancelKey(jobID, workflowType), time.Now().Unix(), defaultTTL).Err(); err != nil {
+		return fmt.Errorf("failed to request job cancellation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) CancelRequested(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (bool, error) {
+	count, err := r.client.Exists(ctx, r.getCancelKey(jobID, workflowType)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check job cancellation: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *workflowRepo) RequestPause(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error {
+	if err := r.client.Set(ctx, r.getPauseKey(jobID, workflowType), time.Now().Unix(), defaultTTL).Err(); err != nil {
+		return fmt.Errorf("failed to request job pause: %w", err)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) PauseRequested(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (bool, error) {
+	count, err := r.client.Exists(ctx, r.getPauseKey(jobID, workflowType)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check job pause: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 func (r *workflowRepo) SetState(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, jobState models.JobState) error {
 	// Delete old status key if it exists
 	oldState, err := r.GetState(ctx, jobID, workflowType)
@@ -120,8 +227,14 @@ func (r *workflowRepo) SetState(ctx context.Context, jobID uuid.UUID, workflowTy
 		return fmt.Errorf("failed to marshal job state: %w", err)
 	}
 
+	// Paused jobs may stay paused for longer than running ones, their state is kept until they are resumed
+	ttl := defaultTTL
+	if jobState.Status == models.JobStatusPaused {
+		ttl = 0
+	}
+
 	key := r.getKey(jobID, workflowType, jobState.Status)
-	if err := r.client.Set(ctx, key, data, defaultTTL).Err(); err != nil {
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set job state: %w", err)
 	}
 
@@ -129,7 +242,11 @@ func (r *workflowRepo) SetState(ctx context.Context, jobID uuid.UUID, workflowTy
 }
 
 func (r *workflowRepo) ListActiveJobs(ctx context.Context, workflowType models.WorkflowType) ([]models.Job, error) {
-	pattern := fmt.Sprintf(keyPattern, workflowType, models.JobStatusRunning)
+	return r.ListJobs(ctx, workflowType, models.JobStatusRunning)
+}
+
+func (r *workflowRepo) ListJobs(ctx context.Context, workflowType models.WorkflowType, status models.JobStatus) ([]models.Job, error) {
+	pattern := fmt.Sprintf(keyPattern, workflowType, status)
 	keys, err := r.client.Keys(ctx, pattern).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list keys: %w", err)
@@ -167,6 +284,12 @@ func (r *workflowRepo) ListActiveJobs(ctx context.Context, workflowType models.W
 			continue
 		}
 
+		if err := r.applyProgress(ctx, jobID, workflowType, &state); err != nil {
+			r.logger.Warn("failed to apply job progress",
+				zap.String("key", key),
+				zap.Error(err))
+		}
+
 		jobs = append(jobs, models.Job{
 			JobID:    jobID,
 			JobState: state,
@@ -176,23 +299,37 @@ func (r *workflowRepo) ListActiveJobs(ctx context.Context, workflowType models.W
 	return jobs, nil
 }
 
-func (r *workflowRepo) StartJob(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) error {
+func (r *workflowRepo) StartJob(ctx context.Context, jobID uuid.UUID, scope models.JobScope, workflowType models.WorkflowType) error {
 	// Set the state of the job to running in checkpoint repository
-	if err := r.SetState(ctx, jobID, workflowType, *models.NewJobState()); err != nil {
+	now := time.Now()
+	state := models.NewJobState()
+	state.Scope = scope
+	state.StartTime = &now
+	if err := r.SetState(ctx, jobID, workflowType, *state); err != nil {
 		return fmt.Errorf("failed to set job state: %w", err)
 	}
 
+	// Jobs covering every item are recorded without a scope
+	var scopeJSON []byte
+	if !scope.IsEmpty() {
+		var err error
+		scopeJSON, err = json.Marshal(scope)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job scope: %w", err)
+		}
+	}
+
 	// Start the job in the state repository
 	q := r.getQuerier(ctx)
 
 	sql := `
         INSERT INTO job_records (
-            job_id, workflow_type, start_time, created_at, updated_at
+            job_id, workflow_type, scope, start_time, created_at, updated_at
         ) VALUES (
-            $1, $2, NOW(), NOW(), NOW()
+            $1, $2, $3, NOW(), NOW(), NOW()
         )`
 
-	_, err := q.Exec(ctx, sql, jobID, workflowType)
+	_, err := q.Exec(ctx, sql, jobID, workflowType, scopeJSON)
 	if err != nil {
 		return fmt.Errorf("failed to start job: %w", err)
 	}
@@ -210,6 +347,12 @@ func (r *workflowRepo) CompleteJob(ctx context.Context, jobID uuid.UUID, jobCont
 		return fmt.Errorf("failed to set job state: %w", err)
 	}
 
+	// The final state outlives the state in the checkpoint repository
+	state, checkpoint, err := r.finalState(ctx, jobID, workflowType, *jobContext)
+	if err != nil {
+		return err
+	}
+
 	// Set the state of the job to completed in the state repository
 	q := r.getQuerier(ctx)
 
@@ -217,6 +360,12 @@ func (r *workflowRepo) CompleteJob(ctx context.Context, jobID uuid.UUID, jobCont
         UPDATE job_records
         SET
             end_time = NOW(),
+            status = $3,
+            completed = $4,
+            failed = $5,
+            skipped = $6,
+            checkpoint = $7,
+            duration_ms = (EXTRACT(EPOCH FROM (NOW() - start_time)) * 1000)::BIGINT,
             updated_at = NOW()
         WHERE job_id = $1
         AND workflow_type = $2
@@ -224,7 +373,7 @@ func (r *workflowRepo) CompleteJob(ctx context.Context, jobID uuid.UUID, jobCont
         AND end_time IS NULL
         AND cancel_time IS NULL`
 
-	result, err := q.Exec(ctx, sql, jobID, workflowType)
+	result, err := q.Exec(ctx, sql, jobID, workflowType, state.Status, state.Completed, state.Failed, state.Skipped, checkpoint)
 	if err != nil {
 		return fmt.Errorf("failed to complete job: %w", err)
 	}
@@ -246,6 +395,20 @@ func (r *workflowRepo) CancelJob(ctx context.Context, jobID uuid.UUID, jobContex
 		return fmt.Errorf("failed to set job state: %w", err)
 	}
 
+	// The final state outlives the state in the checkpoint repository
+	state, checkpoint, err := r.finalState(ctx, jobID, workflowType, *jobContext)
+	if err != nil {
+		return err
+	}
+
+	// The progress and the finished items of a job cancelled while paused were kept, they expire along with its state
+	pipe := r.client.TxPipeline()
+	pipe.Expire(ctx, r.getProgressKey(jobID, workflowType), defaultTTL)
+	pipe.Expire(ctx, r.getFinishedKey(jobID, workflowType), defaultTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to expire job progress: %w", err)
+	}
+
 	// Cancel the job in the state repository
 	q := r.getQuerier(ctx)
 
@@ -253,6 +416,12 @@ func (r *workflowRepo) CancelJob(ctx context.Context, jobID uuid.UUID, jobContex
         UPDATE job_records
         SET
             cancel_time = NOW(),
+            status = $3,
+            completed = $4,
+            failed = $5,
+            skipped = $6,
+            checkpoint = $7,
+            duration_ms = (EXTRACT(EPOCH FROM (NOW() - start_time)) * 1000)::BIGINT,
             updated_at = NOW()
         WHERE job_id = $1
         AND workflow_type = $2
@@ -260,7 +429,7 @@ func (r *workflowRepo) CancelJob(ctx context.Context, jobID uuid.UUID, jobContex
         AND end_time IS NULL
         AND cancel_time IS NULL`
 
-	result, err := q.Exec(ctx, sql, jobID, workflowType)
+	result, err := q.Exec(ctx, sql, jobID, workflowType, state.Status, state.Completed, state.Failed, state.Skipped, checkpoint)
 	if err != nil {
 		return fmt.Errorf("failed to cancel job: %w", err)
 	}
@@ -272,25 +441,237 @@ func (r *workflowRepo) CancelJob(ctx context.Context, jobID uuid.UUID, jobContex
 	return nil
 }
 
-func (r *workflowRepo) ListRecords(ctx context.Context, workflowType *models.WorkflowType, limit, offset *int) ([]models.JobRecord, error) {
+func (r *workflowRepo) PauseJob(ctx context.Context, jobID uuid.UUID, jobContext *models.JobState, workflowType models.WorkflowType) error {
+	if jobContext == nil {
+		return fmt.Errorf("job state is required")
+	}
+
+	// The paused state holds the progress of every replica, along with the checkpoint to resume from
+	state, checkpoint, err := r.finalState(ctx, jobID, workflowType, *jobContext)
+	if err != nil {
+		return err
+	}
+	state.Status = models.JobStatusPaused
+	if err := r.SetState(ctx, jobID, workflowType, state); err != nil {
+		return fmt.Errorf("failed to set job state: %w", err)
+	}
+
+	// Keep the progress and the finished items until the job is resumed, the request is fulfilled
+	pipe := r.client.TxPipeline()
+	pipe.Persist(ctx, r.getProgressKey(jobID, workflowType))
+	pipe.Persist(ctx, r.getFinishedKey(jobID, workflowType))
+	pipe.Del(ctx, r.getPauseKey(jobID, workflowType))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to pause job: %w", err)
+	}
+
+	// Pause the job in the state repository
 	q := r.getQuerier(ctx)
-	var args []interface{}
-	argPosition := 1
 
 	sql := `
-        SELECT
+        UPDATE job_records
+        SET
+            status = $3,
+            completed = $4,
+            failed = $5,
+            skipped = $6,
+            checkpoint = $7,
+            updated_at = NOW()
+        WHERE job_id = $1
+        AND workflow_type = $2
+        AND deleted_at IS NULL
+        AND end_time IS NULL
+        AND cancel_time IS NULL`
+
+	result, err := q.Exec(ctx, sql, jobID, workflowType, state.Status, state.Completed, state.Failed, state.Skipped, checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to pause job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("no active job found with id %s", jobID)
+	}
+
+	return nil
+}
+
+func (r *workflowRepo) ResumeJob(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (models.JobState, error) {
+	state, err := r.GetState(ctx, jobID, workflowType)
+	if err != nil {
+		return models.JobState{}, err
+	}
+
+	if state.Status != models.JobStatusPaused {
+		return models.JobState{}, fmt.Errorf("job is not paused, its status is %s", state.Status)
+	}
+
+	state.Status = models.JobStatusRunning
+	if err := r.SetState(ctx, jobID, workflowType, state); err != nil {
+		return models.JobState{}, fmt.Errorf("failed to set job state: %w", err)
+	}
+
+	// The progress and the finished items expire along with the state of the running job again
+	pipe := r.client.TxPipeline()
+	pipe.Expire(ctx, r.getProgressKey(jobID, workflowType), defaultTTL)
+	pipe.Expire(ctx, r.getFinishedKey(jobID, workflowType), defaultTTL)
+	pipe.Del(ctx, r.getPauseKey(jobID, workflowType))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return models.JobState{}, fmt.Errorf("failed to resume job: %w", err)
+	}
+
+	// Resume the job in the state repository
+	q := r.getQuerier(ctx)
+
+	sql := `
+        UPDATE job_records
+        SET
+            status = $3,
+            updated_at = NOW()
+        WHERE job_id = $1
+        AND workflow_type = $2
+        AND deleted_at IS NULL
+        AND end_time IS NULL
+        AND cancel_time IS NULL`
+
+	if _, err := q.Exec(ctx, sql, jobID, workflowType, state.Status); err != nil {
+		return models.JobState{}, fmt.Errorf("failed to resume job: %w", err)
+	}
+
+	return state, nil
+}
+
+// finalState returns the state of the job including the progress of every replica, along with its encoded checkpoint
+func (r *workflowRepo) finalState(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType, state models.JobState) (models.JobState, []byte, error) {
+	if err := r.applyProgress(ctx, jobID, workflowType, &state); err != nil {
+		return models.JobState{}, nil, err
+	}
+
+	checkpoint, err := json.Marshal(state.Checkpoint)
+	if err != nil {
+		return models.JobState{}, nil, fmt.Errorf("failed to marshal job checkpoint: %w", err)
+	}
+
+	return state, checkpoint, nil
+}
+
+// jobRecordColumns are the columns scanned by scanRecord
+const jobRecordColumns = `
             id, job_id, workflow_type,
             start_time, end_time, cancel_time,
-            preemptions
+            preemptions, status,
+            completed, failed, skipped,
+            checkpoint, scope, duration_ms`
+
+func (r *workflowRepo) scanRecord(row pgx.Row) (models.JobRecord, error) {
+	var job models.JobRecord
+	var checkpoint, scope []byte
+	var durationMs *int64
+	err := row.Scan(
+		&job.ID,
+		&job.JobID,
+		&job.WorkflowType,
+		&job.StartTime,
+		&job.EndTime,
+		&job.CancelTime,
+		&job.Preemptions,
+		&job.Status,
+		&job.Completed,
+		&job.Failed,
+		&job.Skipped,
+		&checkpoint,
+		&scope,
+		&durationMs,
+	)
+	if err != nil {
+		return models.JobRecord{}, err
+	}
+
+	if len(checkpoint) > 0 {
+		job.Checkpoint = &models.JobCheckpoint{}
+		if err := json.Unmarshal(checkpoint, job.Checkpoint); err != nil {
+			return models.JobRecord{}, fmt.Errorf("failed to unmarshal job checkpoint: %w", err)
+		}
+	}
+
+	if len(scope) > 0 {
+		job.Scope = &models.JobScope{}
+		if err := json.Unmarshal(scope, job.Scope); err != nil {
+			return models.JobRecord{}, fmt.Errorf("failed to unmarshal job scope: %w", err)
+		}
+	}
+
+	if durationMs != nil {
+		duration := time.Duration(*durationMs) * time.Millisecond
+		job.Duration = &duration
+	}
+
+	return job, nil
+}
+
+// ErrJobNotFound is returned when the record of a job doesn't exist
+var ErrJobNotFound = errors.New("job not found")
+
+func (r *workflowRepo) GetRecord(ctx context.Context, jobID uuid.UUID, workflowType models.WorkflowType) (models.JobRecord, error) {
+	q := r.getQuerier(ctx)
+
+	sql := `
+        SELECT` + jobRecordColumns + `
+        FROM job_records
+        WHERE job_id = $1
+        AND workflow_type = $2
+        AND deleted_at IS NULL
+        ORDER BY start_time DESC
+        LIMIT 1`
+
+	job, err := r.scanRecord(q.QueryRow(ctx, sql, jobID, workflowType))
+	if err == pgx.ErrNoRows {
+		return models.JobRecord{}, ErrJobNotFound
+	}
+	if err != nil {
+		return models.JobRecord{}, fmt.Errorf("failed to get job record: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *workflowRepo) ListRecords(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error) {
+	q := r.getQuerier(ctx)
+	var args []interface{}
+	argPosition := 1
+
+	sql := `
+        SELECT` + jobRecordColumns + `
         FROM job_records
         WHERE deleted_at IS NULL`
 
-	if workflowType != nil {
+	if filter.WorkflowType != nil {
 		sql += fmt.Sprintf(" AND workflow_type = $%d", argPosition)
-		args = append(args, *workflowType)
+		args = append(args, *filter.WorkflowType)
 		argPosition++
 	}
 
+	if filter.Status != nil {
+		sql += fmt.Sprintf(" AND status = $%d", argPosition)
+		args = append(args, *filter.Status)
+		argPosition++
+	}
+
+	if filter.StartedAfter != nil {
+		sql += fmt.Sprintf(" AND start_time >= $%d", argPosition)
+		args = append(args, *filter.StartedAfter)
+		argPosition++
+	}
+
+	if filter.StartedBefore != nil {
+		sql += fmt.Sprintf(" AND start_time < $%d", argPosition)
+		args = append(args, *filter.StartedBefore)
+		argPosition++
+	}
+
+	if filter.HasFailures {
+		sql += " AND failed > 0"
+	}
+
 	sql += " ORDER BY start_time DESC"
 
 	if limit != nil {
@@ -312,16 +693,7 @@ func (r *workflowRepo) ListRecords(ctx context.Context, workflowType *models.Wor
 
 	var jobs []models.JobRecord
 	for rows.Next() {
-		var job models.JobRecord
-		err := rows.Scan(
-			&job.ID,
-			&job.JobID,
-			&job.WorkflowType,
-			&job.StartTime,
-			&job.EndTime,
-			&job.CancelTime,
-			&job.Preemptions,
-		)
+		job, err := r.scanRecord(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
@@ -3,6 +3,7 @@ package workspace
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -52,7 +53,35 @@ type WorkspaceRepository interface {
 
 	BatchDeleteWorkspaces(ctx context.Context, workspaceIDs []uuid.UUID) error
 
-	ListActiveWorkspaces(ctx context.Context, batchSize int, lastPageID *uuid.UUID) (models.ActiveWorkspaceBatch, error)
+	// ListActiveWorkspaces returns a batch of active workspaces within the scope
+	ListActiveWorkspaces(ctx context.Context, batchSize int, lastPageID *uuid.UUID, scope models.JobScope) (models.ActiveWorkspaceBatch, error)
+
+	// CountActiveWorkspaces returns the number of active workspaces within the scope
+	CountActiveWorkspaces(ctx context.Context, scope models.JobScope) (int64, error)
+
+	// GetWorkspaceNoiseRules returns the noise rules applied to the pages of a workspace
+	GetWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID) ([]models.NoiseRule, error)
+
+	// UpdateWorkspaceNoiseRules replaces the noise rules applied to the pages of a workspace
+	UpdateWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID, noiseRules []models.NoiseRule) error
+
+	// GetWorkspaceReportMode returns how the reports of a workspace are dispatched to its members
+	GetWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID) (models.ReportMode, error)
+
+	// UpdateWorkspaceReportMode updates how the reports of a workspace are dispatched to its members
+	UpdateWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID, reportMode models.ReportMode) error
+
+	// GetNotificationSubscription returns the notification settings of a member, nil when the member hasn't edited them
+	GetNotificationSubscription(ctx context.Context, workspaceID, userID uuid.UUID) (*models.NotificationSubscription, error)
+
+	// ListNotificationSubscriptions returns the notification settings of the members which edited them
+	ListNotificationSubscriptions(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID) ([]models.NotificationSubscription, error)
+
+	// UpsertNotificationSubscription replaces the notification settings of a member
+	UpsertNotificationSubscription(ctx context.Context, workspaceID, userID uuid.UUID, props models.NotificationSubscriptionProps) (*models.NotificationSubscription, error)
+
+	// MarkMembersNotified records the time the members were last notified
+	MarkMembersNotified(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID, notifiedAt time.Time) error
 
 	// UpdateWorkspacePlan updates the plan of a workspace
 	UpdateWorkspacePlan(ctx context.Context, workspaceID uuid.UUID, plan models.WorkspacePlan) error
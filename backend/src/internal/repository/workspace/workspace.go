@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
@@ -580,6 +581,219 @@ func (r *workspaceRepo) UpdateWorkspaceDetails(ctx context.Context, workspaceID
 	return nil
 }
 
+func (r *workspaceRepo) GetWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID) ([]models.NoiseRule, error) {
+	var noiseRules []models.NoiseRule
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+		SELECT noise_rules
+		FROM workspaces
+		WHERE id = $1 AND workspace_status != $2`,
+		workspaceID, models.WorkspaceInactive,
+	).Scan(&noiseRules)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("workspace not found")
+		}
+		return nil, fmt.Errorf("failed to get workspace noise rules: %w", err)
+	}
+
+	return noiseRules, nil
+}
+
+func (r *workspaceRepo) UpdateWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID, noiseRules []models.NoiseRule) error {
+	if noiseRules == nil {
+		noiseRules = []models.NoiseRule{}
+	}
+
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		UPDATE workspaces
+		SET noise_rules = $1
+		WHERE id = $2 AND workspace_status != $3`,
+		noiseRules, workspaceID, models.WorkspaceInactive,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update workspace noise rules: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("workspace not found")
+	}
+
+	return nil
+}
+
+func (r *workspaceRepo) GetWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID) (models.ReportMode, error) {
+	var reportMode models.ReportMode
+	err := r.getQuerier(ctx).QueryRow(ctx, `
+		SELECT report_mode
+		FROM workspaces
+		WHERE id = $1 AND workspace_status != $2`,
+		workspaceID, models.WorkspaceInactive,
+	).Scan(&reportMode)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("workspace not found")
+		}
+		return "", fmt.Errorf("failed to get workspace report mode: %w", err)
+	}
+
+	return reportMode, nil
+}
+
+func (r *workspaceRepo) UpdateWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID, reportMode models.ReportMode) error {
+	result, err := r.getQuerier(ctx).Exec(ctx, `
+		UPDATE workspaces
+		SET report_mode = $1
+		WHERE id = $2 AND workspace_status != $3`,
+		reportMode, workspaceID, models.WorkspaceInactive,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update workspace report mode: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("workspace not found")
+	}
+
+	return nil
+}
+
+const notificationSubscriptionColumns = `workspace_id, user_id, competitor_ids, categories, channels, frequency, last_notified_at, created_at, updated_at`
+
+func scanNotificationSubscription(row pgx.Row) (*models.NotificationSubscription, error) {
+	var subscription models.NotificationSubscription
+	var channels []string
+	err := row.Scan(
+		&subscription.WorkspaceID,
+		&subscription.UserID,
+		&subscription.CompetitorIDs,
+		&subscription.Categories,
+		&channels,
+		&subscription.Frequency,
+		&subscription.LastNotifiedAt,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Channels = make([]models.NotificationChannel, len(channels))
+	for i, channel := range channels {
+		subscription.Channels[i] = models.NotificationChannel(channel)
+	}
+
+	return &subscription, nil
+}
+
+func (r *workspaceRepo) GetNotificationSubscription(ctx context.Context, workspaceID, userID uuid.UUID) (*models.NotificationSubscription, error) {
+	subscription, err := scanNotificationSubscription(r.getQuerier(ctx).QueryRow(ctx, `
+		SELECT `+notificationSubscriptionColumns+`
+		FROM notification_subscriptions
+		WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *workspaceRepo) ListNotificationSubscriptions(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID) ([]models.NotificationSubscription, error) {
+	if len(userIDs) == 0 {
+		return []models.NotificationSubscription{}, nil
+	}
+
+	rows, err := r.getQuerier(ctx).Query(ctx, `
+		SELECT `+notificationSubscriptionColumns+`
+		FROM notification_subscriptions
+		WHERE workspace_id = $1 AND user_id = ANY($2)`,
+		workspaceID, userIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]models.NotificationSubscription, 0, len(userIDs))
+	for rows.Next() {
+		subscription, err := scanNotificationSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *workspaceRepo) UpsertNotificationSubscription(ctx context.Context, workspaceID, userID uuid.UUID, props models.NotificationSubscriptionProps) (*models.NotificationSubscription, error) {
+	competitorIDs := props.CompetitorIDs
+	if competitorIDs == nil {
+		competitorIDs = []uuid.UUID{}
+	}
+	categories := props.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	channels := make([]string, len(props.Channels))
+	for i, channel := range props.Channels {
+		channels[i] = string(channel)
+	}
+
+	subscription, err := scanNotificationSubscription(r.getQuerier(ctx).QueryRow(ctx, `
+		INSERT INTO notification_subscriptions (workspace_id, user_id, competitor_ids, categories, channels, frequency)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE
+		SET competitor_ids = EXCLUDED.competitor_ids,
+			categories = EXCLUDED.categories,
+			channels = EXCLUDED.channels,
+			frequency = EXCLUDED.frequency
+		RETURNING `+notificationSubscriptionColumns,
+		workspaceID, userID, competitorIDs, categories, channels, props.Frequency,
+	))
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("user is not a member of the workspace")
+		}
+		return nil, fmt.Errorf("failed to update notification subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *workspaceRepo) MarkMembersNotified(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID, notifiedAt time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	// Members without settings get the default ones, so that the time they were notified is kept
+	_, err := r.getQuerier(ctx).Exec(ctx, `
+		INSERT INTO notification_subscriptions (workspace_id, user_id, last_notified_at)
+		SELECT $1, user_id, $3
+		FROM UNNEST($2::uuid[]) AS user_id
+		ON CONFLICT (workspace_id, user_id) DO UPDATE
+		SET last_notified_at = EXCLUDED.last_notified_at`,
+		workspaceID, userIDs, notifiedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark members notified: %w", err)
+	}
+
+	return nil
+}
+
 func (r *workspaceRepo) UpdateWorkspacePlan(ctx context.Context, workspaceID uuid.UUID, plan models.WorkspacePlan) error {
 	result, err := r.getQuerier(ctx).Exec(ctx, `
         UPDATE workspaces
@@ -663,22 +877,52 @@ func (r *workspaceRepo) BatchDeleteWorkspaces(ctx context.Context, workspaceIDs
 	return nil
 }
 
-func (r *workspaceRepo) ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID) (models.ActiveWorkspaceBatch, error) {
+// activeWorkspacesFilter returns the conditions selecting the active workspaces within the scope
+func activeWorkspacesFilter(scope models.JobScope) (string, []interface{}) {
+	query := `
+		WHERE workspace_status = $1`
+	args := []interface{}{models.WorkspaceActive}
+
+	// Limit the workspaces to the scope, competitors and pages narrow it down to the workspaces holding them
+	if len(scope.WorkspaceIDs) > 0 {
+		query += fmt.Sprintf(` AND id = ANY($%d)`, len(args)+1)
+		args = append(args, scope.WorkspaceIDs)
+	}
+
+	if len(scope.CompetitorIDs) > 0 {
+		query += fmt.Sprintf(` AND id IN (SELECT workspace_id FROM competitors WHERE id = ANY($%d))`, len(args)+1)
+		args = append(args, scope.CompetitorIDs)
+	}
+
+	if len(scope.PageIDs) > 0 {
+		query += fmt.Sprintf(`
+		AND id IN (
+			SELECT c.workspace_id
+			FROM pages p
+			JOIN competitors c ON c.id = p.competitor_id
+			WHERE p.id = ANY($%d)
+		)`, len(args)+1)
+		args = append(args, scope.PageIDs)
+	}
+
+	return query, args
+}
+
+func (r *workspaceRepo) ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID, scope models.JobScope) (models.ActiveWorkspaceBatch, error) {
 	if batchSize <= 0 {
 		return models.ActiveWorkspaceBatch{}, errors.New("invalid batch size")
 	}
 
 	// Build base query
+	filter, args := activeWorkspacesFilter(scope)
 	query := `
 		SELECT id
-		FROM workspaces
-		WHERE workspace_status = $1`
-	args := []interface{}{models.WorkspaceActive}
+		FROM workspaces` + filter
 
 	// Add cursor-based pagination using lastWorkspaceID
 	if lastWorkspaceID != nil {
 		// Ensure UUID ordering is appropriate for pagination
-		query += ` AND id > $2`
+		query += fmt.Sprintf(` AND id > $%d`, len(args)+1)
 		args = append(args, *lastWorkspaceID)
 	}
 
@@ -802,3 +1046,17 @@ func getSlug(name string) string {
 	// remove non alphanumeric characters
 	return slug.Make(name) + "-" + uuid.New().String()
 }
+
+func (r *workspaceRepo) CountActiveWorkspaces(ctx context.Context, scope models.JobScope) (int64, error) {
+	filter, args := activeWorkspacesFilter(scope)
+	query := `
+		SELECT COUNT(*)
+		FROM workspaces` + filter
+
+	var count int64
+	if err := r.getQuerier(ctx).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active workspaces: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,315 @@
+// ./src/internal/service/ai/anthropic.go
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wizenheimer/byrd/src/internal/client"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+const (
+	// anthropicOrigin is the origin of the Anthropic API
+	anthropicOrigin = "https://api.anthropic.com"
+
+	// anthropicVersion is the version of the Anthropic API the requests are built for
+	anthropicVersion = "2023-06-01"
+
+	// anthropicDefaultModel is the model used when none is configured
+	anthropicDefaultModel = "claude-3-5-sonnet-latest"
+
+	// anthropicChangesTool is the tool the model is forced to call with the changes
+	// Tool inputs follow their schema, which is how structured outputs are obtained
+	anthropicChangesTool = "dynamic_intelligence_tracking"
+
+	// anthropicSummaryTool is the tool the model is forced to call with the summary of a category
+	anthropicSummaryTool = "changes_summary"
+)
+
+// compile time check if the interface is implemented
+var _ AIService = (*anthropicService)(nil)
+var _ Validator = (*anthropicService)(nil)
+
+type anthropicService struct {
+	httpClient *client.HTTPClient
+	apiKey     string
+	model      string
+	logger     *logger.Logger
+	builder    *ProfileBuilder
+}
+
+// NewAnthropicService creates an AI service backed by Anthropic
+// The service isn't validated against the API, use Validate to do so
+func NewAnthropicService(apiKey, model string, logger *logger.Logger) (AIService, error) {
+	if apiKey == "" {
+		return nil, errors.New("anthropic api key is required")
+	}
+
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+
+	// Overloaded responses are retried on top of the default retry codes
+	httpClient, err := client.NewClient(logger, client.WithRetry(3, []int{408, 429, 500, 502, 503, 504, 529}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &anthropicService{
+		httpClient: httpClient,
+		apiKey:     apiKey,
+		model:      model,
+		logger:     logger.WithFields(map[string]interface{}{"module": "ai_service"}),
+		builder:    newProfileBuilder(),
+	}, nil
+}
+
+// anthropicMessageRequest is the request body of the messages endpoint
+type anthropicMessageRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int64              `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  map[string]string  `json:"tool_choice,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *anthropicImageSource `json:"source,omitempty"`
+	Name   string                `json:"name,omitempty"`
+	Input  json.RawMessage       `json:"input,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type anthropicTool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema interface{} `json:"input_schema"`
+}
+
+// anthropicMessageResponse is the response body of the messages endpoint
+type anthropicMessageResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Error      *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// AnalyzeContentDifferences analyzes the content differences between two versions of a URL
+func (s *anthropicService) AnalyzeContentDifferences(ctx context.Context, version1, version2 string, fields []string) (*models.DynamicChanges, error) {
+	profile, err := s.builder.BuildProfile(ProfileRequest{
+		Name:        "competitor_updates",
+		Description: "Carefully compare these two versions of content, identify and surface changes",
+		FieldNames:  fields,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	userPrompt := fmt.Sprintf("Compare these two versions of content and identify changes:\n\nVersion 1:\n%s\n\nVersion 2:\n%s", version1, version2)
+
+	return s.analyze(ctx, profile, []anthropicContentBlock{
+		{Type: "text", Text: userPrompt},
+	})
+}
+
+// AnalyzeVisualDifferences analyzes the visual differences between two screenshots
+func (s *anthropicService) AnalyzeVisualDifferences(ctx context.Context, screenshot1, screenshot2 image.Image, fields []string) (*models.DynamicChanges, error) {
+	profile, err := s.builder.BuildProfile(ProfileRequest{
+		Name:        "competitor_updates",
+		Description: "Carefully compare and contrast visual changes in the webpage",
+		FieldNames:  fields,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	version1Base64, err := imageToBase64(screenshot1)
+	if err != nil {
+		return nil, ErrConvertingImageToBase64
+	}
+
+	version2Base64, err := imageToBase64(screenshot2)
+	if err != nil {
+		return nil, ErrConvertingImageToBase64
+	}
+
+	return s.analyze(ctx, profile, []anthropicContentBlock{
+		{Type: "text", Text: "Carefully compare these two versions of images and identify changes"},
+		{Type: "image", Source: &anthropicImageSource{Type: "base64", MediaType: "image/png", Data: version1Base64}},
+		{Type: "image", Source: &anthropicImageSource{Type: "base64", MediaType: "image/png", Data: version2Base64}},
+	})
+}
+
+// SummarizeChanges summarizes the changes in a report
+func (s *anthropicService) SummarizeChanges(ctx context.Context, changeList []*models.DynamicChanges) ([]models.CategoryChange, error) {
+	return summarizeChanges(s.logger, changeList, s.summarizeCategory)
+}
+
+// Validate checks if the API key is valid by sending a test request
+func (s *anthropicService) Validate(ctx context.Context) error {
+	_, err := s.createMessage(ctx, anthropicMessageRequest{
+		Model:     s.model,
+		MaxTokens: 3,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: "Yo"}}},
+		},
+	})
+	return err
+}
+
+// analyze forces the model to report the changes through a tool whose input schema is the dynamic schema of the profile
+func (s *anthropicService) analyze(ctx context.Context, profile models.Profile, content []anthropicContentBlock) (*models.DynamicChanges, error) {
+	temperature := 0.7
+	response, err := s.createMessage(ctx, anthropicMessageRequest{
+		Model:       s.model,
+		MaxTokens:   2048,
+		System:      models.BuildCompetitorSystemPrompt(profile.Fields),
+		Temperature: &temperature,
+		Messages:    []anthropicMessage{{Role: "user", Content: content}},
+		Tools: []anthropicTool{{
+			Name:        anthropicChangesTool,
+			Description: "Track changes in specified intelligence categories",
+			InputSchema: models.GenerateDynamicSchema(profile.Fields),
+		}},
+		ToolChoice: map[string]string{"type": "tool", "name": anthropicChangesTool},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := toolInput(response, anthropicChangesTool)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &models.DynamicChanges{
+		Fields: make(map[string]interface{}),
+	}
+	if err := json.Unmarshal(input, changes); err != nil {
+		return nil, ErrParsingChanges
+	}
+
+	if err := conformChanges(changes, profile.Fields); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// summarizeCategory summarizes the changes of a category through a tool whose input schema is the change summary
+func (s *anthropicService) summarizeCategory(ctx context.Context, category string, changes []string) (models.ChangeSummary, error) {
+	prompt := fmt.Sprintf("Give a brief 1-2 line summary of these changes for %s category:\n\n%s",
+		category,
+		strings.Join(changes, "\n"),
+	)
+
+	response, err := s.createMessage(ctx, anthropicMessageRequest{
+		Model:     s.model,
+		MaxTokens: 512,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: prompt}}},
+		},
+		Tools: []anthropicTool{{
+			Name:        anthropicSummaryTool,
+			Description: "Brief summary of changes",
+			InputSchema: ChangeSummaryResponseSchema,
+		}},
+		ToolChoice: map[string]string{"type": "tool", "name": anthropicSummaryTool},
+	})
+	if err != nil {
+		return models.ChangeSummary{}, err
+	}
+
+	input, err := toolInput(response, anthropicSummaryTool)
+	if err != nil {
+		return models.ChangeSummary{}, err
+	}
+
+	var summary models.ChangeSummary
+	if err := json.Unmarshal(input, &summary); err != nil {
+		return models.ChangeSummary{}, fmt.Errorf("JSON parsing error: %v", err)
+	}
+
+	return summary, nil
+}
+
+// createMessage sends a request to the messages endpoint
+func (s *anthropicService) createMessage(ctx context.Context, request anthropicMessageRequest) (*anthropicMessageResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, ErrPreparingChatCompletion
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicOrigin+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrPreparingChatCompletion
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("x-api-key", s.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anthropic response: %w", err)
+	}
+
+	var response anthropicMessageResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := errors.New("unexpected response")
+		if response.Error != nil {
+			err = fmt.Errorf("%s: %s", response.Error.Type, response.Error.Message)
+		}
+		return nil, &models.UpstreamError{Service: "anthropic api", StatusCode: resp.StatusCode, Err: err}
+	}
+
+	if response.StopReason == "refusal" {
+		return nil, ErrEncounteredRefusal
+	}
+
+	return &response, nil
+}
+
+// toolInput returns the input of the first call to the tool in the response
+func toolInput(response *anthropicMessageResponse, tool string) (json.RawMessage, error) {
+	for _, block := range response.Content {
+		if block.Type == "tool_use" && block.Name == tool {
+			return block.Input, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no call to %s in response", ErrParsingChanges, tool)
+}
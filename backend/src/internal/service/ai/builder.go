@@ -20,6 +20,16 @@ type ProfileBuilder struct {
 	registry *FieldRegistry
 }
 
+// newProfileBuilder creates a profile builder over the predefined fields
+// The predefined fields are made available for sanitizing profiles along the way
+func newProfileBuilder() *ProfileBuilder {
+	for _, field := range fields {
+		AvailableFields[field.Name] = field
+	}
+
+	return NewProfileBuilder(NewFieldRegistry())
+}
+
 // NewProfileBuilder creates a new profile builder
 func NewProfileBuilder(registry *FieldRegistry) *ProfileBuilder {
 	return &ProfileBuilder{
@@ -0,0 +1,261 @@
+// ./src/internal/service/ai/fake.go
+package ai
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// compile time check if the interface is implemented
+var _ AIService = (*fakeService)(nil)
+
+// fakeKeywords route the changed lines to the fields of the profile, the first match wins
+// Lines matching none of the keywords are routed to messaging, or else to the first field by name
+var fakeKeywords = []struct {
+	field    string
+	keywords []string
+}{
+	{field: "pricing", keywords: []string{"$", "€", "£", "price", "pricing", "plan", "discount", "per month", "/mo", "free trial"}},
+	{field: "integration", keywords: []string{"integration", "integrates", "connect", "api", "plugin"}},
+	{field: "integrations", keywords: []string{"integration", "integrates", "connect", "api", "plugin"}},
+	{field: "partnerships", keywords: []string{"partner", "partnership", "alliance"}},
+	{field: "testimonials", keywords: []string{"testimonial", "review", "rated"}},
+	{field: "customers", keywords: []string{"customer", "trusted by", "case study", "clients"}},
+	{field: "product", keywords: []string{"feature", "launch", "introducing", "new", "release", "product"}},
+	{field: "roadmap", keywords: []string{"coming soon", "roadmap", "beta"}},
+	{field: "branding", keywords: []string{"logo", "brand", "color", "font"}},
+	{field: "content", keywords: []string{"post", "article", "blog"}},
+}
+
+// fakeService is a deterministic, rule based AI service
+// It is meant for development and tests, where outputs need to be reproducible without any network access
+type fakeService struct {
+	logger  *logger.Logger
+	builder *ProfileBuilder
+}
+
+// NewFakeAIService creates a deterministic AI service which doesn't depend on any provider
+func NewFakeAIService(logger *logger.Logger) (AIService, error) {
+	return &fakeService{
+		logger:  logger.WithFields(map[string]interface{}{"module": "ai_service"}),
+		builder: newProfileBuilder(),
+	}, nil
+}
+
+// AnalyzeContentDifferences routes the lines added and removed between the versions to the fields by keywords
+func (s *fakeService) AnalyzeContentDifferences(ctx context.Context, version1, version2 string, fields []string) (*models.DynamicChanges, error) {
+	profile, err := s.builder.BuildProfile(ProfileRequest{
+		Name:        "competitor_updates",
+		Description: "Carefully compare these two versions of content, identify and surface changes",
+		FieldNames:  fields,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := emptyChanges(profile.Fields)
+	removed, added := changedLines(version1, version2)
+	for _, line := range removed {
+		addChange(changes, profile.Fields, "Removed: "+line)
+	}
+	for _, line := range added {
+		addChange(changes, profile.Fields, "Added: "+line)
+	}
+
+	if err := conformChanges(changes, profile.Fields); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// AnalyzeVisualDifferences reports a change when the dimensions or the pixels of the screenshots differ
+func (s *fakeService) AnalyzeVisualDifferences(ctx context.Context, screenshot1, screenshot2 image.Image, fields []string) (*models.DynamicChanges, error) {
+	profile, err := s.builder.BuildProfile(ProfileRequest{
+		Name:        "competitor_updates",
+		Description: "Carefully compare and contrast visual changes in the webpage",
+		FieldNames:  fields,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if screenshot1 == nil || screenshot2 == nil {
+		return nil, ErrConvertingImageToBase64
+	}
+
+	changes := emptyChanges(profile.Fields)
+
+	bounds1, bounds2 := screenshot1.Bounds(), screenshot2.Bounds()
+	switch {
+	case bounds1.Dx() != bounds2.Dx() || bounds1.Dy() != bounds2.Dy():
+		addChange(changes, profile.Fields, fmt.Sprintf("Layout changed from %dx%d to %dx%d", bounds1.Dx(), bounds1.Dy(), bounds2.Dx(), bounds2.Dy()))
+	case !samePixels(screenshot1, screenshot2):
+		addChange(changes, profile.Fields, "Visual changes detected on the page")
+	}
+
+	if err := conformChanges(changes, profile.Fields); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SummarizeChanges summarizes each category by counting its changes, sorted by category
+func (s *fakeService) SummarizeChanges(ctx context.Context, changeList []*models.DynamicChanges) ([]models.CategoryChange, error) {
+	changes, err := models.MergeDynamicChanges(changeList)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]string, 0, len(changes.Fields))
+	for category := range changes.Fields {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	summaries := make([]models.CategoryChange, 0, len(categories))
+	for _, category := range categories {
+		items, ok := changes.Fields[category].([]interface{})
+		if !ok {
+			s.logger.Debug("skipping category without a list of changes", zap.String("category", category))
+			continue
+		}
+
+		categoryChanges := make([]string, 0, len(items))
+		for _, item := range items {
+			if change, ok := item.(string); ok {
+				categoryChanges = append(categoryChanges, change)
+			}
+		}
+
+		summary := fmt.Sprintf("No changes detected in %s", category)
+		if len(categoryChanges) == 1 {
+			summary = fmt.Sprintf("1 change detected in %s", category)
+		} else if len(categoryChanges) > 1 {
+			summary = fmt.Sprintf("%d changes detected in %s", len(categoryChanges), category)
+		}
+
+		summaries = append(summaries, models.CategoryChange{
+			Category: category,
+			Summary:  summary,
+			Changes:  categoryChanges,
+		})
+	}
+
+	return summaries, nil
+}
+
+// emptyChanges returns the changes with every field of the profile set to its zero value
+func emptyChanges(fields []models.FieldConfig) *models.DynamicChanges {
+	changes := &models.DynamicChanges{
+		Fields: make(map[string]interface{}, len(fields)),
+	}
+	for _, field := range fields {
+		changes.Fields[field.Name] = zeroValue(field)
+	}
+	return changes
+}
+
+// addChange appends the change to the field it is routed to
+// Only string array fields hold changes, the remaining fields keep their zero value
+func addChange(changes *models.DynamicChanges, fields []models.FieldConfig, change string) {
+	field, ok := routeChange(fields, change)
+	if !ok {
+		return
+	}
+	list, _ := changes.Fields[field].([]interface{})
+	changes.Fields[field] = append(list, change)
+}
+
+// routeChange returns the field the change is routed to
+func routeChange(fields []models.FieldConfig, change string) (string, bool) {
+	available := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field.Type == models.TypeStringArray {
+			available[field.Name] = true
+		}
+	}
+
+	lowered := strings.ToLower(change)
+	for _, route := range fakeKeywords {
+		if !available[route.field] {
+			continue
+		}
+		for _, keyword := range route.keywords {
+			if strings.Contains(lowered, keyword) {
+				return route.field, true
+			}
+		}
+	}
+
+	if available["messaging"] {
+		return "messaging", true
+	}
+
+	// The order of the fields isn't stable across profiles, so the first field is picked by name
+	fallback := ""
+	for name := range available {
+		if fallback == "" || name < fallback {
+			fallback = name
+		}
+	}
+	return fallback, fallback != ""
+}
+
+// changedLines returns the non-empty lines only present in the first and the second version, in order
+func changedLines(version1, version2 string) (removed, added []string) {
+	lines1, lines2 := nonEmptyLines(version1), nonEmptyLines(version2)
+
+	present1 := make(map[string]bool, len(lines1))
+	for _, line := range lines1 {
+		present1[line] = true
+	}
+	present2 := make(map[string]bool, len(lines2))
+	for _, line := range lines2 {
+		present2[line] = true
+	}
+
+	for _, line := range lines1 {
+		if !present2[line] {
+			removed = append(removed, line)
+		}
+	}
+	for _, line := range lines2 {
+		if !present1[line] {
+			added = append(added, line)
+		}
+	}
+	return removed, added
+}
+
+// nonEmptyLines returns the trimmed, non-empty lines of the content
+func nonEmptyLines(content string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// samePixels returns true if the screenshots, of the same dimensions, hold the same pixels
+func samePixels(screenshot1, screenshot2 image.Image) bool {
+	bounds1, bounds2 := screenshot1.Bounds(), screenshot2.Bounds()
+	for y := 0; y < bounds1.Dy(); y++ {
+		for x := 0; x < bounds1.Dx(); x++ {
+			r1, g1, b1, a1 := screenshot1.At(bounds1.Min.X+x, bounds1.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := screenshot2.At(bounds2.Min.X+x, bounds2.Min.Y+y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+func TestFakeAIServiceContentDifferences(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service, err := NewFakeAIService(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	version1 := "# Acme\nStarter plan $10 per month\nTrusted by 500 customers"
+	version2 := "# Acme\nStarter plan $12 per month\nTrusted by 500 customers\nIntroducing the new workflow builder"
+
+	changes, err := service.AnalyzeContentDifferences(context.Background(), version1, version2, []string{"pricing", "product", "customers"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"pricing":   []interface{}{"Removed: Starter plan $10 per month", "Added: Starter plan $12 per month"},
+		"product":   []interface{}{"Added: Introducing the new workflow builder"},
+		"customers": []interface{}{},
+	}
+	if !reflect.DeepEqual(changes.Fields, want) {
+		t.Fatalf("expected %v, got %v", want, changes.Fields)
+	}
+
+	// Outputs are reproducible
+	again, err := service.AnalyzeContentDifferences(context.Background(), version1, version2, []string{"customers", "product", "pricing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(again.Fields, changes.Fields) {
+		t.Fatalf("expected reproducible changes, got %v and %v", changes.Fields, again.Fields)
+	}
+}
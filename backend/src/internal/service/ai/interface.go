@@ -20,6 +20,12 @@ type AIService interface {
 	SummarizeChanges(ctx context.Context, changes []*models.DynamicChanges) ([]models.CategoryChange, error)
 }
 
+// Validator is implemented by the AI services which can verify their configuration with a live request
+// Validation is optional, so that the services can be created offline
+type Validator interface {
+	Validate(ctx context.Context) error
+}
+
 var (
 	ErrBuildingProfile         = errors.New("failed to build profile")
 	ErrPreparingChatCompletion = errors.New("failed to prepare chat completion")
@@ -0,0 +1,62 @@
+// ./src/internal/service/ai/limiter.go
+package ai
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"golang.org/x/time/rate"
+)
+
+// compile time check if the interface is implemented
+var _ AIService = (*rateLimitedService)(nil)
+
+// rateLimitedService waits for a token before each request to the AI service it wraps
+// The token bucket is shared by every report generated by the replica, so that bursts don't trip the limits of the provider
+type rateLimitedService struct {
+	service AIService
+	limiter *rate.Limiter
+}
+
+// NewRateLimitedAIService limits the requests to the AI service to qps per second, with bursts of up to burst requests
+// The service is returned as is when qps isn't positive
+func NewRateLimitedAIService(service AIService, qps float64, burst int) AIService {
+	if qps <= 0 {
+		return service
+	}
+
+	return &rateLimitedService{
+		service: service,
+		limiter: rate.NewLimiter(rate.Limit(qps), max(1, burst)),
+	}
+}
+
+func (s *rateLimitedService) wait(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("ai provider rate limit wait: %w", err)
+	}
+	return nil
+}
+
+func (s *rateLimitedService) AnalyzeContentDifferences(ctx context.Context, version1, version2 string, fields []string) (*models.DynamicChanges, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.service.AnalyzeContentDifferences(ctx, version1, version2, fields)
+}
+
+func (s *rateLimitedService) AnalyzeVisualDifferences(ctx context.Context, version1, version2 image.Image, fields []string) (*models.DynamicChanges, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.service.AnalyzeVisualDifferences(ctx, version1, version2, fields)
+}
+
+func (s *rateLimitedService) SummarizeChanges(ctx context.Context, changes []*models.DynamicChanges) ([]models.CategoryChange, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.service.SummarizeChanges(ctx, changes)
+}
@@ -10,162 +10,82 @@ import (
 	"fmt"
 	"image"
 	"image/png"
-	"sync"
-	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
-	"go.uber.org/zap"
 )
 
+// compile time check if the interface is implemented
+var _ AIService = (*openAIService)(nil)
+var _ Validator = (*openAIService)(nil)
+
 type openAIService struct {
-	client  *openai.Client
-	logger  *logger.Logger
-	builder *ProfileBuilder
+	client       *openai.Client
+	logger       *logger.Logger
+	builder      *ProfileBuilder
+	model        string
+	summaryModel string
 }
 
+// NewOpenAIService creates an AI service backed by OpenAI
+// The service isn't validated against the API, use Validate to do so
 func NewOpenAIService(apiKey string, logger *logger.Logger) (AIService, error) {
-	client := openai.NewClient(
-		option.WithAPIKey(
-			apiKey,
-		),
-		option.WithMaxRetries(
-			3,
-		),
-	)
-
-	fieldRegistry := NewFieldRegistry()
-	builder := NewProfileBuilder(fieldRegistry)
-
-	s := openAIService{
-		client:  client,
-		logger:  logger.WithFields(map[string]interface{}{"module": "ai_service"}),
-		builder: builder,
+	if apiKey == "" {
+		return nil, errors.New("openai api key is required")
 	}
 
-	for _, field := range fields {
-		AvailableFields[field.Name] = field
-	}
-
-	return &s, s.validate()
+	return newOpenAIService(
+		openai.ChatModelGPT4oMini,
+		openai.ChatModelGPT4o2024_08_06,
+		logger,
+		option.WithAPIKey(apiKey),
+	), nil
 }
 
-func (s *openAIService) SummarizeChanges(ctx context.Context, changeList []*models.DynamicChanges) ([]models.CategoryChange, error) {
-	changes, err := models.MergeDynamicChanges(changeList)
-	if err != nil {
-		return nil, err
+// NewLocalAIService creates an AI service backed by an OpenAI compatible endpoint, such as Ollama or vLLM
+// The model is used both to analyze and to summarize changes, and must support structured outputs
+func NewLocalAIService(baseURL, apiKey, model string, logger *logger.Logger) (AIService, error) {
+	if baseURL == "" {
+		return nil, errors.New("local ai base url is required")
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-	defer cancel()
-
-	numCategories := len(changes.Fields)
-	resultChan := make(chan result, numCategories)
-	var wg sync.WaitGroup
-
-	processedCategories := make(map[string]bool)
-	var mu sync.Mutex
-
-	// Fixed type conversion in goroutines
-	for category, changesList := range changes.Fields {
-		wg.Add(1)
-		go func(cat string, list interface{}) {
-			defer wg.Done()
-
-			interfaceList, ok := list.([]interface{})
-			if !ok {
-				s.logger.Error("error converting changes list to interface list")
-				return
-			}
-			stringList := make([]string, len(interfaceList))
-			for i, v := range interfaceList {
-				stringList[i], ok = v.(string)
-				if !ok {
-					s.logger.Error("error converting changes list to string list")
-					return
-				}
-			}
-
-			processCategoryAsync(ctx, s.client, cat, stringList, resultChan)
-		}(category, changesList)
+	if model == "" {
+		return nil, errors.New("local ai model is required")
 	}
 
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	response := models.ChangeResponse{
-		Changes: make([]models.CategoryChange, 0, numCategories),
+	// Local endpoints seldom require an API key, but the client always sends one
+	if apiKey == "" {
+		apiKey = "local"
 	}
 
-	for res := range resultChan {
-		if res.err != nil {
-			s.logger.Error("error processing category", zap.Error(res.err))
-			continue
-		}
-
-		mu.Lock()
-		processedCategories[res.summary.Category] = true
-		mu.Unlock()
-
-		// Fixed type conversion here too
-		interfaceList, ok := changes.Fields[res.summary.Category].([]interface{})
-		if !ok {
-			s.logger.Error("error converting changes list to interface list")
-			continue
-		}
-		stringList := make([]string, len(interfaceList))
-		for i, v := range interfaceList {
-			stringList[i], ok = v.(string)
-			if !ok {
-				s.logger.Error("error converting changes list to string list")
-				continue
-			}
-		}
-
-		categoryChange := models.CategoryChange{
-			Category: res.summary.Category,
-			Summary:  res.summary.Summary,
-			Changes:  stringList,
-		}
-
-		response.Changes = append(response.Changes, categoryChange)
-	}
+	return newOpenAIService(
+		model,
+		model,
+		logger,
+		option.WithBaseURL(baseURL),
+		option.WithAPIKey(apiKey),
+	), nil
+}
 
-	for category, changesList := range changes.Fields {
-		mu.Lock()
-		if !processedCategories[category] {
-			// And here
-			interfaceList, ok := changesList.([]interface{})
-			if !ok {
-				s.logger.Error("error converting changes list to interface list")
-				continue
-			}
-			stringList := make([]string, len(interfaceList))
-			for i, v := range interfaceList {
-				stringList[i], ok = v.(string)
-				if !ok {
-					s.logger.Error("error converting changes list to string list")
-					continue
-				}
-			}
-
-			categoryChange := models.CategoryChange{
-				Category: category,
-				Summary:  getFallbackSummary(category, stringList),
-				Changes:  stringList,
-			}
-
-			response.Changes = append(response.Changes, categoryChange)
-			s.logger.Error("using fallback summary", zap.String("category", category))
-		}
-		mu.Unlock()
+func newOpenAIService(model, summaryModel string, logger *logger.Logger, opts ...option.RequestOption) *openAIService {
+	client := openai.NewClient(
+		append(opts, option.WithMaxRetries(3))...,
+	)
+
+	return &openAIService{
+		client:       client,
+		logger:       logger.WithFields(map[string]interface{}{"module": "ai_service"}),
+		builder:      newProfileBuilder(),
+		model:        model,
+		summaryModel: summaryModel,
 	}
+}
 
-	return response.Changes, nil
+func (s *openAIService) SummarizeChanges(ctx context.Context, changeList []*models.DynamicChanges) ([]models.CategoryChange, error) {
+	return summarizeChanges(s.logger, changeList, func(ctx context.Context, category string, changes []string) (models.ChangeSummary, error) {
+		return generateCategorySummary(ctx, s.client, s.summaryModel, category, changes)
+	})
 }
 
 // AnalyzeContentDifferences analyzes the content differences between two versions of a URL
@@ -186,7 +106,16 @@ func (s *openAIService) AnalyzeContentDifferences(ctx context.Context, version1,
 		return nil, err
 	}
 
-	return s.parseCompletion(chat)
+	changes, err := s.parseCompletion(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conformChanges(changes, profile.Fields); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
 }
 
 // AnalyzeVisualDifferences analyzes the visual differences between two screenshots
@@ -207,20 +136,29 @@ func (s *openAIService) AnalyzeVisualDifferences(ctx context.Context, screenshot
 		return nil, err
 	}
 
-	return s.parseCompletion(chat)
+	changes, err := s.parseCompletion(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conformChanges(changes, profile.Fields); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
 }
 
 func (s *openAIService) Close() {
 	s.logger.Debug("closing openAI service")
 }
 
-// Check if the client is valid by sending a test request
-func (s *openAIService) validate() error {
-	_, err := s.client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
+// Validate checks if the client is valid by sending a test request
+func (s *openAIService) Validate(ctx context.Context) error {
+	_, err := s.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
 		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
 			openai.UserMessage("Yo"),
 		}),
-		Model:     openai.F(openai.ChatModelGPT4o),
+		Model:     openai.F(s.model),
 		MaxTokens: openai.Int(3),
 	})
 
@@ -256,7 +194,16 @@ func (s *openAIService) prepareTextCompletion(ctx context.Context, version1, ver
 		MaxTokens:   openai.F(opts.MaxTokens),
 	})
 
-	return chat, err
+	return chat, upstreamError(err)
+}
+
+// upstreamError carries the status code of the errors of the OpenAI API, so that they're classified by it
+func upstreamError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return &models.UpstreamError{Service: "openai api", StatusCode: apiErr.StatusCode, Err: err}
+	}
+	return err
 }
 
 func (s *openAIService) prepareImageCompletion(ctx context.Context, version1, version2 image.Image, profile models.Profile) (*openai.ChatCompletion, error) {
@@ -303,7 +250,7 @@ func (s *openAIService) prepareImageCompletion(ctx context.Context, version1, ve
 		MaxTokens:   openai.F(opts.MaxTokens),
 	})
 
-	return chat, err
+	return chat, upstreamError(err)
 }
 
 func (s *openAIService) parseCompletion(chat *openai.ChatCompletion) (*models.DynamicChanges, error) {
@@ -335,13 +282,25 @@ func (s *openAIService) parseCompletion(chat *openai.ChatCompletion) (*models.Dy
 func (s *openAIService) prepareCompareOptions(profile *models.Profile) models.CompareOptions {
 	return models.CompareOptions{
 		SystemPrompt: models.BuildCompetitorSystemPrompt(profile.Fields),
-		Model:        openai.ChatModelGPT4oMini,
+		Model:        s.model,
 		Temperature:  0.7,
 		MaxTokens:    2048,
 	}
 }
 
 func imageToBase64URL(img image.Image) (string, error) {
+	base64Str, err := imageToBase64(img)
+	if err != nil {
+		return "", err
+	}
+
+	// Add prefix for base64 URL
+	base64Str = "data:image/png;base64," + base64Str
+
+	return base64Str, nil
+}
+
+func imageToBase64(img image.Image) (string, error) {
 	// Create a buffer to store the image
 	var buf bytes.Buffer
 
@@ -352,10 +311,5 @@ func imageToBase64URL(img image.Image) (string, error) {
 	}
 
 	// Convert the buffer bytes to base64 string
-	base64Str := base64.StdEncoding.EncodeToString(buf.Bytes())
-
-	// Add prefix for base64 URL
-	base64Str = "data:image/png;base64," + base64Str
-
-	return base64Str, nil
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
 }
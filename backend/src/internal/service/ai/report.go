@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"math/rand"
@@ -13,6 +14,8 @@ import (
 	"github.com/invopop/jsonschema"
 	"github.com/openai/openai-go"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
 )
 
 type result struct {
@@ -20,6 +23,10 @@ type result struct {
 	err     error
 }
 
+// categorySummarizer summarizes the changes of a single category
+// It is only invoked for categories with changes
+type categorySummarizer func(ctx context.Context, category string, changes []string) (models.ChangeSummary, error)
+
 var fallbackTemplates = []string{
 	// Bold statements
 	"👀 Look who's making moves in %s",
@@ -148,14 +155,7 @@ func getFallbackSummary(category string, changes []string) string {
 	return fmt.Sprintf(template, category)
 }
 
-func generateCategorySummary(ctx context.Context, client *openai.Client, category string, changes []string) (models.ChangeSummary, error) {
-	if len(changes) == 0 {
-		return models.ChangeSummary{
-			Category: category,
-			Summary:  getNoChangeSummary(category),
-		}, nil
-	}
-
+func generateCategorySummary(ctx context.Context, client *openai.Client, model string, category string, changes []string) (models.ChangeSummary, error) {
 	prompt := fmt.Sprintf("Give a brief 1-2 line summary of these changes for %s category:\n\n%s",
 		category,
 		strings.Join(changes, "\n"),
@@ -178,11 +178,11 @@ func generateCategorySummary(ctx context.Context, client *openai.Client, categor
 				JSONSchema: openai.F(schemaParam),
 			},
 		),
-		Model: openai.F(openai.ChatModelGPT4o2024_08_06),
+		Model: openai.F(model),
 	})
 
 	if err != nil {
-		return models.ChangeSummary{}, fmt.Errorf("OpenAI API error: %v", err)
+		return models.ChangeSummary{}, fmt.Errorf("OpenAI API error: %w", upstreamError(err))
 	}
 
 	if chat == nil {
@@ -203,11 +203,138 @@ func generateCategorySummary(ctx context.Context, client *openai.Client, categor
 	return summary, nil
 }
 
-func processCategoryAsync(ctx context.Context, client *openai.Client, category string, changes []string, resultChan chan<- result) {
-	summary, err := generateCategorySummary(ctx, client, category, changes)
+func processCategoryAsync(ctx context.Context, summarize categorySummarizer, category string, changes []string, resultChan chan<- result) {
+	var summary models.ChangeSummary
+	var err error
+	if len(changes) == 0 {
+		summary = models.ChangeSummary{
+			Category: category,
+			Summary:  getNoChangeSummary(category),
+		}
+	} else {
+		summary, err = summarize(ctx, category, changes)
+	}
 	select {
 	case <-ctx.Done():
 		return
 	case resultChan <- result{summary, err}:
 	}
 }
+
+// summarizeChanges merges the changes and summarizes each category concurrently
+// Categories which couldn't be summarized fallback to a templated summary
+func summarizeChanges(logger *logger.Logger, changeList []*models.DynamicChanges, summarize categorySummarizer) ([]models.CategoryChange, error) {
+	changes, err := models.MergeDynamicChanges(changeList)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	numCategories := len(changes.Fields)
+	resultChan := make(chan result, numCategories)
+	var wg sync.WaitGroup
+
+	processedCategories := make(map[string]bool)
+	var mu sync.Mutex
+
+	// Fixed type conversion in goroutines
+	for category, changesList := range changes.Fields {
+		wg.Add(1)
+		go func(cat string, list interface{}) {
+			defer wg.Done()
+
+			interfaceList, ok := list.([]interface{})
+			if !ok {
+				logger.Error("error converting changes list to interface list")
+				return
+			}
+			stringList := make([]string, len(interfaceList))
+			for i, v := range interfaceList {
+				stringList[i], ok = v.(string)
+				if !ok {
+					logger.Error("error converting changes list to string list")
+					return
+				}
+			}
+
+			processCategoryAsync(ctx, summarize, cat, stringList, resultChan)
+		}(category, changesList)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	response := models.ChangeResponse{
+		Changes: make([]models.CategoryChange, 0, numCategories),
+	}
+
+	for res := range resultChan {
+		if res.err != nil {
+			logger.Error("error processing category", zap.Error(res.err))
+			continue
+		}
+
+		mu.Lock()
+		processedCategories[res.summary.Category] = true
+		mu.Unlock()
+
+		// Fixed type conversion here too
+		interfaceList, ok := changes.Fields[res.summary.Category].([]interface{})
+		if !ok {
+			logger.Error("error converting changes list to interface list")
+			continue
+		}
+		stringList := make([]string, len(interfaceList))
+		for i, v := range interfaceList {
+			stringList[i], ok = v.(string)
+			if !ok {
+				logger.Error("error converting changes list to string list")
+				continue
+			}
+		}
+
+		categoryChange := models.CategoryChange{
+			Category: res.summary.Category,
+			Summary:  res.summary.Summary,
+			Changes:  stringList,
+		}
+
+		response.Changes = append(response.Changes, categoryChange)
+	}
+
+	for category, changesList := range changes.Fields {
+		mu.Lock()
+		if !processedCategories[category] {
+			// And here
+			interfaceList, ok := changesList.([]interface{})
+			if !ok {
+				logger.Error("error converting changes list to interface list")
+				continue
+			}
+			stringList := make([]string, len(interfaceList))
+			for i, v := range interfaceList {
+				stringList[i], ok = v.(string)
+				if !ok {
+					logger.Error("error converting changes list to string list")
+					continue
+				}
+			}
+
+			categoryChange := models.CategoryChange{
+				Category: category,
+				Summary:  getFallbackSummary(category, stringList),
+				Changes:  stringList,
+			}
+
+			response.Changes = append(response.Changes, categoryChange)
+			logger.Error("using fallback summary", zap.String("category", category))
+		}
+		mu.Unlock()
+	}
+
+	return response.Changes, nil
+}
@@ -0,0 +1,103 @@
+// ./src/internal/service/ai/schema.go
+package ai
+
+import (
+	"fmt"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// conformChanges ensures the changes follow the schema generated by models.GenerateDynamicSchema for the fields
+// Missing fields are set to their zero value, while fields of the wrong type are rejected
+// Providers without strict structured outputs rely on this to honour the schema
+func conformChanges(changes *models.DynamicChanges, fields []models.FieldConfig) error {
+	if changes == nil {
+		return ErrParsingChanges
+	}
+
+	if changes.Fields == nil {
+		changes.Fields = make(map[string]interface{})
+	}
+
+	conformed := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, ok := changes.Fields[field.Name]
+		if !ok || value == nil {
+			conformed[field.Name] = zeroValue(field)
+			continue
+		}
+
+		if !matchesField(value, field) {
+			return fmt.Errorf("%w: field %s isn't a valid %s", ErrParsingChanges, field.Name, field.Type)
+		}
+		conformed[field.Name] = value
+	}
+
+	// Fields outside of the schema are dropped, as additional properties aren't allowed
+	changes.Fields = conformed
+	return nil
+}
+
+// zeroValue returns the value of a field without any changes, as decoded from JSON
+func zeroValue(field models.FieldConfig) interface{} {
+	switch field.Type {
+	case models.TypeStringArray, models.TypeNumberArray:
+		return []interface{}{}
+	case models.TypeNumber:
+		return float64(0)
+	case models.TypeBoolean:
+		return false
+	case models.TypeObject:
+		object := make(map[string]interface{}, len(field.Properties))
+		for _, property := range field.Properties {
+			object[property.Name] = zeroValue(property)
+		}
+		return object
+	default:
+		return ""
+	}
+}
+
+// matchesField returns true if the value, as decoded from JSON, is of the type of the field
+func matchesField(value interface{}, field models.FieldConfig) bool {
+	switch field.Type {
+	case models.TypeStringArray:
+		return isArrayOf[string](value)
+	case models.TypeNumberArray:
+		return isArrayOf[float64](value)
+	case models.TypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case models.TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case models.TypeObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, property := range field.Properties {
+			if propertyValue, ok := object[property.Name]; ok && !matchesField(propertyValue, property) {
+				return false
+			}
+		}
+		return true
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+// isArrayOf returns true if the value is an array holding only elements of the given type
+func isArrayOf[T any](value interface{}) bool {
+	items, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if _, ok := item.(T); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package competitor
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -28,6 +29,9 @@ type CompetitorService interface {
 
 	ListCompetitorsForWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Competitor, bool, error)
 
+	// ListCompetitorsInScope lists the competitors of the workspace within the scope of a job
+	ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error)
+
 	UpdateCompetitorForWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string) (*models.Competitor, error)
 
 	RemoveCompetitorForWorkspace(ctx context.Context, workspaceID uuid.UUID, competitorIDs []uuid.UUID) error
@@ -48,14 +52,36 @@ type CompetitorService interface {
 
 	ListPageHistory(ctx context.Context, pageID uuid.UUID, limit, offset *int) ([]models.PageHistory, bool, error)
 
+	GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error)
+
+	ListPageCaptures(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error)
+
+	GetPageCapture(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+
+	ComparePageCaptures(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error)
+
+	ExtractPageContent(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error)
+
 	// ListReports lists the reports for a competitor.
 	ListReports(ctx context.Context, workspaceID, competitorID uuid.UUID, limit, offset *int) ([]models.Report, bool, error)
 
+	// ListReportEmails lists the emails which carried a report of a competitor, along with their delivery status.
+	ListReportEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error)
+
 	// CreateReport creates a report for a competitor.
 	CreateReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) (*models.Report, error)
 
-	// DispatchReport dispatches a report for a competitor.
-	DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscriberEmails []string) error
+	// DispatchReport dispatches a report for a competitor, narrowed down to the categories when any is given.
+	// The reports created after the time are aggregated into one, only the latest report is dispatched when no time is given.
+	// Returns whether the report was sent.
+	DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, categories []string, since *time.Time, subscriberEmails []string) (bool, error)
+
+	// GetDigest gets the digest of the recent reports of the competitors of a workspace.
+	GetDigest(ctx context.Context, workspaceID uuid.UUID) (*models.Digest, error)
+
+	// DispatchDigest dispatches a digest to the subscribers.
+	// Returns whether the digest was sent.
+	DispatchDigest(ctx context.Context, digest models.Digest, workspaceName string, frequency models.NotificationFrequency, subscriberEmails []string) (bool, error)
 
 	CountPagesForCompetitors(ctx context.Context, competitorIDs []uuid.UUID) (int, error)
 
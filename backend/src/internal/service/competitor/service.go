@@ -4,6 +4,7 @@ package competitor
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -180,6 +181,10 @@ func (cs *competitorService) ListCompetitorsForWorkspace(ctx context.Context, wo
 	)
 }
 
+func (cs *competitorService) ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error) {
+	return cs.competitorRepository.ListCompetitorsInScope(ctx, workspaceID, scope)
+}
+
 func (cs *competitorService) UpdateCompetitorForWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string) (*models.Competitor, error) {
 	return cs.competitorRepository.UpdateCompetitorForWorkspace(
 		ctx,
@@ -333,6 +338,30 @@ func (cs *competitorService) ListPageHistory(ctx context.Context, pageID uuid.UU
 	)
 }
 
+func (cs *competitorService) GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error) {
+	return cs.pageService.GetPageHistory(
+		ctx,
+		pageID,
+		historyID,
+	)
+}
+
+func (cs *competitorService) ListPageCaptures(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error) {
+	return cs.pageService.ListPageCaptures(ctx, competitorID, pageID)
+}
+
+func (cs *competitorService) GetPageCapture(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
+	return cs.pageService.GetPageCapture(ctx, competitorID, pageID, capturedAt)
+}
+
+func (cs *competitorService) ComparePageCaptures(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error) {
+	return cs.pageService.ComparePageCaptures(ctx, competitorID, pageID, from, to)
+}
+
+func (cs *competitorService) ExtractPageContent(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error) {
+	return cs.pageService.ExtractPageContent(ctx, competitorID, pageID, selectors)
+}
+
 // ListReports returns a list of reports for a competitor.
 // The limit and offset parameters are used for pagination.
 func (cs *competitorService) ListReports(ctx context.Context, workspaceID, competitorID uuid.UUID, limit, offset *int) ([]models.Report, bool, error) {
@@ -397,23 +426,34 @@ func (cs *competitorService) CreateReport(ctx context.Context, workspaceID uuid.
 	return report, nil
 }
 
+// ListReportEmails returns the emails which carried a report of a competitor.
+func (cs *competitorService) ListReportEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error) {
+	return cs.reportService.ListEmails(ctx, workspaceID, competitorID, reportID, limit, offset)
+}
+
 // DispatchReport sends the report to the subscribers.
-func (cs *competitorService) DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscriberEmails []string) error {
+func (cs *competitorService) DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, categories []string, since *time.Time, subscriberEmails []string) (bool, error) {
 	// Get the competitor
 	competitor, err := cs.GetCompetitorForWorkspace(ctx, workspaceID, []uuid.UUID{competitorID})
 	if err != nil {
-		return err
+		return false, err
 	}
 	if len(competitor) == 0 {
-		return errors.New("competitor not found")
+		return false, errors.New("competitor not found")
 	}
 
 	// Send the report to the subscribers
-	if err := cs.reportService.Dispatch(ctx, workspaceID, competitorID, competitor[0].Name, subscriberEmails); err != nil {
-		return err
-	}
+	return cs.reportService.Dispatch(ctx, workspaceID, competitorID, competitor[0].Name, categories, since, subscriberEmails)
+}
+
+// GetDigest returns the digest of the recent reports of the competitors of a workspace.
+func (cs *competitorService) GetDigest(ctx context.Context, workspaceID uuid.UUID) (*models.Digest, error) {
+	return cs.reportService.GetDigest(ctx, workspaceID)
+}
 
-	return nil
+// DispatchDigest sends the digest to the subscribers.
+func (cs *competitorService) DispatchDigest(ctx context.Context, digest models.Digest, workspaceName string, frequency models.NotificationFrequency, subscriberEmails []string) (bool, error) {
+	return cs.reportService.DispatchDigest(ctx, digest, workspaceName, frequency, subscriberEmails)
 }
 
 func (cs *competitorService) CountPagesForCompetitors(ctx context.Context, competitorIDs []uuid.UUID) (int, error) {
@@ -0,0 +1,141 @@
+// ./src/internal/service/diff/engine.go
+package diff
+
+import (
+	"regexp"
+	"strings"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+// headingRegex matches markdown ATX headings as emitted by the markdown minifier
+var headingRegex = regexp.MustCompile(`^#{1,6} `)
+
+// sectionKey identifies a section across versions
+// occurrence disambiguates sections which share the same heading
+type sectionKey struct {
+	heading    string
+	occurrence int
+}
+
+// section is a heading and the lines that follow it until the next heading
+type section struct {
+	key   sectionKey
+	lines []string
+}
+
+// splitSections splits minified markdown into sections keyed by heading
+// Content preceding the first heading is grouped under an empty heading
+func splitSections(markdown string) []section {
+	sections := make([]section, 0)
+	occurrences := make(map[string]int)
+	current := section{key: sectionKey{}}
+	inFencedBlock := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFencedBlock = !inFencedBlock
+		}
+
+		if !inFencedBlock && headingRegex.MatchString(trimmed) {
+			if current.key.heading != "" || len(current.lines) > 0 {
+				sections = append(sections, current)
+			}
+			current = section{
+				key: sectionKey{
+					heading:    trimmed,
+					occurrence: occurrences[trimmed],
+				},
+			}
+			occurrences[trimmed]++
+			continue
+		}
+
+		// Blank lines carry no content, skip them so that reflowing doesn't register as a change
+		if trimmed == "" {
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+
+	if current.key.heading != "" || len(current.lines) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections
+}
+
+// compareMarkdown computes the structural diff between two versions of minified markdown
+// Sections present in both versions are diffed line by line, the rest are reported as added or removed
+func compareMarkdown(previous, current string) *models.ContentDiff {
+	contentDiff := &models.ContentDiff{
+		Sections: make([]models.SectionDiff, 0),
+	}
+
+	if previous == current {
+		return contentDiff
+	}
+
+	previousSections := splitSections(previous)
+	currentSections := splitSections(current)
+
+	previousByKey := make(map[sectionKey]section, len(previousSections))
+	for _, s := range previousSections {
+		previousByKey[s.key] = s
+	}
+
+	currentKeys := make(map[sectionKey]bool, len(currentSections))
+	for _, curr := range currentSections {
+		currentKeys[curr.key] = true
+
+		prev, ok := previousByKey[curr.key]
+		if !ok {
+			contentDiff.Sections = append(contentDiff.Sections, models.SectionDiff{
+				Heading:   curr.key.heading,
+				Operation: models.SectionAdded,
+				Added:     curr.lines,
+			})
+			continue
+		}
+
+		removed, added := diffSectionLines(prev.lines, curr.lines)
+		if len(removed) == 0 && len(added) == 0 {
+			continue
+		}
+
+		contentDiff.Sections = append(contentDiff.Sections, models.SectionDiff{
+			Heading:   curr.key.heading,
+			Operation: models.SectionModified,
+			Removed:   removed,
+			Added:     added,
+		})
+	}
+
+	for _, prev := range previousSections {
+		if currentKeys[prev.key] {
+			continue
+		}
+		contentDiff.Sections = append(contentDiff.Sections, models.SectionDiff{
+			Heading:   prev.key.heading,
+			Operation: models.SectionRemoved,
+			Removed:   prev.lines,
+		})
+	}
+
+	return contentDiff
+}
+
+// diffSectionLines returns the lines removed from and added to a section
+func diffSectionLines(previous, current []string) (removed, added []string) {
+	for _, edit := range utils.DiffLines(previous, current) {
+		switch edit.Operation {
+		case utils.LineDelete:
+			removed = append(removed, edit.Text)
+		case utils.LineInsert:
+			added = append(added, edit.Text)
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+func TestCompareMarkdownIdentical(t *testing.T) {
+	content := "# Pricing\nStarter $10\n\n## Enterprise\nContact us\n"
+
+	contentDiff := compareMarkdown(content, content)
+	if !contentDiff.IsEmpty() {
+		t.Fatalf("expected no changes, got %+v", contentDiff.Sections)
+	}
+
+	// Blank lines alone shouldn't register as a change
+	contentDiff = compareMarkdown(content, "# Pricing\n\nStarter $10\n## Enterprise\n\nContact us\n")
+	if !contentDiff.IsEmpty() {
+		t.Fatalf("expected no changes for reflowed content, got %+v", contentDiff.Sections)
+	}
+}
+
+func TestCompareMarkdownSections(t *testing.T) {
+	previous := "Intro\n# Pricing\nStarter $10\nPro $20\n# Careers\nWe are hiring\n"
+	current := "Intro\n# Pricing\nStarter $12\nPro $20\n# Integrations\nSlack\n"
+
+	got := compareMarkdown(previous, current).Sections
+	want := []models.SectionDiff{
+		{
+			Heading:   "# Pricing",
+			Operation: models.SectionModified,
+			Removed:   []string{"Starter $10"},
+			Added:     []string{"Starter $12"},
+		},
+		{
+			Heading:   "# Integrations",
+			Operation: models.SectionAdded,
+			Added:     []string{"Slack"},
+		},
+		{
+			Heading:   "# Careers",
+			Operation: models.SectionRemoved,
+			Removed:   []string{"We are hiring"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected sections\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestContentDiffHunks(t *testing.T) {
+	contentDiff := compareMarkdown("# Pricing\nStarter $10\n", "# Pricing\nStarter $12\n")
+
+	previous, current := contentDiff.Hunks()
+	if previous != "# Pricing\nStarter $10\n\n" {
+		t.Fatalf("unexpected previous hunks: %q", previous)
+	}
+	if current != "# Pricing\nStarter $12\n\n" {
+		t.Fatalf("unexpected current hunks: %q", current)
+	}
+}
+
+func TestCompareMarkdownOfProcessedHTML(t *testing.T) {
+	processor, err := utils.NewMarkdownProcessor()
+	if err != nil {
+		t.Fatalf("failed to create markdown processor: %v", err)
+	}
+
+	page := `<html><body>
+<h1>Pricing</h1>
+<p>Starter plan <b>$10</b> per month, billed <a href="https://acme.test/billing">yearly</a>.</p>
+<ul><li>5 seats</li><li>Email support</li></ul>
+<h2>Enterprise</h2>
+<p>%s</p>
+</body></html>`
+
+	previous, err := processor.Process(fmt.Sprintf(page, "Contact us"))
+	if err != nil {
+		t.Fatalf("failed to process previous page: %v", err)
+	}
+	current, err := processor.Process(fmt.Sprintf(page, "Starting at $500 per month"))
+	if err != nil {
+		t.Fatalf("failed to process current page: %v", err)
+	}
+
+	got := compareMarkdown(previous, current).Sections
+	want := []models.SectionDiff{
+		{
+			Heading:   "## Enterprise",
+			Operation: models.SectionModified,
+			Removed:   []string{"Contact us"},
+			Added:     []string{"Starting at $500 per month"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected only the changed section to be a hunk\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -9,6 +9,18 @@ import (
 
 // DiffService is the interface that provides diff operations
 type DiffService interface {
-	// Compare: compares two HTML contents and returns the differences using the given profile
-	Compare(ctx context.Context, content1, content2 *models.ScreenshotContent, profileFields []string) (*models.DynamicChanges, error)
+	// Compare: compares two HTML contents narrowed down and normalized by the filter and returns the differences using the given profile
+	// The result holds the categorized changes along with the raw unified diff they were derived from
+	Compare(ctx context.Context, content1, content2 *models.ScreenshotContent, filter models.ContentFilter, profileFields []string) (*models.DiffResult, error)
+
+	// CompareVisual: compares two screenshots pixel by pixel, locating the changed regions and highlighting them in an overlay
+	// When the changed pixel percentage reaches the threshold, the visual change is significant and is analyzed using the given profile
+	CompareVisual(ctx context.Context, screenshot1, screenshot2 *models.ScreenshotImage, threshold float64, profileFields []string) (*models.VisualDiff, error)
+
+	// Fingerprint: returns a hash of the content narrowed down and normalized by the filter, identical pages share the same fingerprint
+	// The names of the noise rules which fired on the content are returned alongside
+	Fingerprint(content *models.ScreenshotContent, filter models.ContentFilter) (string, []string, error)
+
+	// Extract: returns the markdown of the HTML content narrowed down and normalized by the filter, as it is compared
+	Extract(content *models.ScreenshotContent, filter models.ContentFilter) (*models.ContentExtraction, error)
 }
@@ -0,0 +1,143 @@
+// ./src/internal/service/diff/noise.go
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// builtinNoiseRules suppress the dynamic content common to most pages
+// They are applied after the rules of the workspace and the page, so that those can match the original content
+var builtinNoiseRules = []models.NoiseRule{
+	{
+		Name:    "builtin:iso_date",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?\b`,
+	},
+	{
+		Name:    "builtin:date",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `(?i)\b(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.? \d{1,2}(?:st|nd|rd|th)?,? \d{4}|\d{1,2}(?:st|nd|rd|th)? (?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?,? \d{4}|\d{1,2}/\d{1,2}/\d{2,4})\b`,
+	},
+	{
+		Name:    "builtin:time",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `(?i)\b\d{1,2}:\d{2}(?::\d{2})?(?:\s?[ap]m)?\b`,
+	},
+	{
+		Name:    "builtin:relative_time",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `(?i)\b(?:(?:\d+|an?|one) (?:second|sec|minute|min|hour|hr|day|week|month|year)s? ago|just now|yesterday)\b`,
+	},
+	{
+		Name:    "builtin:uuid",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`,
+	},
+	{
+		Name:    "builtin:tracking_id",
+		Type:    models.NoiseRuleRegex,
+		Pattern: `(?i)[?&](?:utm_[a-z]+|gclid|fbclid|msclkid|mc_cid|mc_eid|_ga|_gl|sessionid|sid)=[^&\s)"']*|\b(?:UA-\d{4,10}-\d{1,4}|G-[A-Z0-9]{6,12}|GTM-[A-Z0-9]{4,10})\b`,
+	},
+}
+
+// compiledBuiltinNoiseRules are the built-in rules, compiled once
+var compiledBuiltinNoiseRules = mustCompileNoiseRules(builtinNoiseRules)
+
+// noiseRule is a compiled noise rule
+type noiseRule struct {
+	name     string
+	ruleType models.NoiseRuleType
+	pattern  *regexp.Regexp
+}
+
+// noiseSuppressor suppresses dynamic content from markdown, keeping track of the rules which fired
+type noiseSuppressor struct {
+	rules []noiseRule
+	fired map[string]bool
+}
+
+// newNoiseSuppressor compiles the rules, and appends the built-in rules to them
+func newNoiseSuppressor(rules []models.NoiseRule) (*noiseSuppressor, error) {
+	compiledRules, err := compileNoiseRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &noiseSuppressor{
+		rules: append(compiledRules, compiledBuiltinNoiseRules...),
+		fired: make(map[string]bool),
+	}, nil
+}
+
+// Suppress drops the lines matching an ignore rule, then masks the matches of the regex rules
+// Masked content is replaced by the name of the rule, so that both versions line up when compared
+func (s *noiseSuppressor) Suppress(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := make([]string, 0, len(lines))
+
+LINES:
+	for _, line := range lines {
+		for _, rule := range s.rules {
+			if rule.ruleType == models.NoiseRuleIgnore && rule.pattern.MatchString(line) {
+				s.fired[rule.name] = true
+				continue LINES
+			}
+		}
+		kept = append(kept, line)
+	}
+	content = strings.Join(kept, "\n")
+
+	for _, rule := range s.rules {
+		if rule.ruleType != models.NoiseRuleRegex || !rule.pattern.MatchString(content) {
+			continue
+		}
+		s.fired[rule.name] = true
+		content = rule.pattern.ReplaceAllLiteralString(content, "["+rule.name+"]")
+	}
+
+	return content
+}
+
+// Fired returns the names of the rules which fired, in the order the rules are applied
+func (s *noiseSuppressor) Fired() []string {
+	fired := make([]string, 0, len(s.fired))
+	reported := make(map[string]bool, len(s.fired))
+	for _, rule := range s.rules {
+		// Rules sharing a name are reported once
+		if s.fired[rule.name] && !reported[rule.name] {
+			fired = append(fired, rule.name)
+			reported[rule.name] = true
+		}
+	}
+	return fired
+}
+
+// compileNoiseRules compiles the patterns of the rules
+func compileNoiseRules(rules []models.NoiseRule) ([]noiseRule, error) {
+	compiledRules := make([]noiseRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for noise rule %s: %w", rule.Name, err)
+		}
+		compiledRules = append(compiledRules, noiseRule{
+			name:     rule.Name,
+			ruleType: rule.Type,
+			pattern:  pattern,
+		})
+	}
+	return compiledRules, nil
+}
+
+// mustCompileNoiseRules compiles the rules, panicking on an invalid pattern
+func mustCompileNoiseRules(rules []models.NoiseRule) []noiseRule {
+	compiledRules, err := compileNoiseRules(rules)
+	if err != nil {
+		panic(err)
+	}
+	return compiledRules
+}
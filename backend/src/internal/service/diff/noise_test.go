@@ -0,0 +1,49 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+func TestNoiseSuppressorBuiltins(t *testing.T) {
+	suppressor, err := newNoiseSuppressor(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previous := suppressor.Suppress("Updated 2024-05-01T10:00:00Z\nPosted 3 hours ago\n[Docs](https://example.com/?utm_source=mail)")
+	current := suppressor.Suppress("Updated 2024-06-12T08:30:00Z\nPosted 5 minutes ago\n[Docs](https://example.com/?utm_source=feed)")
+	if previous != current {
+		t.Fatalf("expected dynamic content to be suppressed, got %q and %q", previous, current)
+	}
+
+	want := []string{"builtin:iso_date", "builtin:relative_time", "builtin:tracking_id"}
+	if got := suppressor.Fired(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected fired rules %v, got %v", want, got)
+	}
+}
+
+func TestNoiseSuppressorCustomRules(t *testing.T) {
+	suppressor, err := newNoiseSuppressor([]models.NoiseRule{
+		{Name: "views", Type: models.NoiseRuleIgnore, Pattern: `\d+ views`},
+		{Name: "sku", Type: models.NoiseRuleRegex, Pattern: `SKU-\d+`},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := suppressor.Suppress("# Pricing\n1,024 views\nStarter SKU-1234 $10")
+	if want := "# Pricing\nStarter [sku] $10"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if fired := suppressor.Fired(); !reflect.DeepEqual(fired, []string{"views", "sku"}) {
+		t.Fatalf("expected custom rules to fire, got %v", fired)
+	}
+
+	if _, err := newNoiseSuppressor([]models.NoiseRule{{Name: "broken", Type: models.NoiseRuleRegex, Pattern: "("}}); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,82 @@
+// ./src/internal/service/diff/selector.go
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"golang.org/x/net/html"
+)
+
+// applySelectors narrows down the HTML content to the elements matched by the selectors
+// Excluded elements are removed first, then the included elements are kept in document order
+// The content is returned untouched when no selectors are set
+func applySelectors(content string, selectors models.ContentSelectors) (string, error) {
+	if selectors.IsEmpty() {
+		return content, nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse content: %w", err)
+	}
+
+	for _, selector := range selectors.Exclude {
+		matcher, err := cascadia.Compile(selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid exclude selector %q: %w", selector, err)
+		}
+
+		for _, node := range matcher.MatchAll(doc) {
+			if node.Parent != nil {
+				node.Parent.RemoveChild(node)
+			}
+		}
+	}
+
+	if len(selectors.Include) == 0 {
+		return renderNodes([]*html.Node{doc})
+	}
+
+	matchers := make([]cascadia.Matcher, 0, len(selectors.Include))
+	for _, selector := range selectors.Include {
+		matcher, err := cascadia.Compile(selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid include selector %q: %w", selector, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	// Walk the document in order, keeping the outermost matches only
+	// This avoids duplicating content when selectors match nested elements
+	var included []*html.Node
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for _, matcher := range matchers {
+			if node.Type == html.ElementNode && matcher.Match(node) {
+				included = append(included, node)
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return renderNodes(included)
+}
+
+// renderNodes renders the nodes back to HTML, one after the other
+func renderNodes(nodes []*html.Node) (string, error) {
+	var sb strings.Builder
+	for _, node := range nodes {
+		if err := html.Render(&sb, node); err != nil {
+			return "", fmt.Errorf("failed to render content: %w", err)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
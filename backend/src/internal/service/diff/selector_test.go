@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+const selectorPage = `<html><body>
+<nav>Home | Pricing</nav>
+<main>
+  <section class="pricing"><h1>Pricing</h1><p>Starter $10</p><div class="pricing">Pro $20</div></section>
+  <section class="testimonials"><p>Loved by teams</p></section>
+</main>
+<footer>Copyright 2024</footer>
+</body></html>`
+
+func TestApplySelectorsEmpty(t *testing.T) {
+	got, err := applySelectors(selectorPage, models.ContentSelectors{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != selectorPage {
+		t.Fatalf("expected content to be untouched, got %q", got)
+	}
+}
+
+func TestApplySelectorsIncludeExclude(t *testing.T) {
+	got, err := applySelectors(selectorPage, models.ContentSelectors{
+		Include: []string{"main", ".pricing"},
+		Exclude: []string{".testimonials"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"Starter $10", "Pro $20"} {
+		if strings.Count(got, want) != 1 {
+			t.Errorf("expected %q exactly once, got %q", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Home", "Loved by teams", "Copyright"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("expected %q to be dropped, got %q", unwanted, got)
+		}
+	}
+}
+
+func TestApplySelectorsInvalid(t *testing.T) {
+	if _, err := applySelectors(selectorPage, models.ContentSelectors{Include: []string{"main["}}); err == nil {
+		t.Fatal("expected an error for an invalid selector")
+	}
+}
@@ -3,14 +3,21 @@ package diff
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/service/ai"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"github.com/wizenheimer/byrd/src/pkg/utils"
+	"go.uber.org/zap"
 )
 
+// unifiedDiffContext is the number of unchanged lines surrounding each hunk of the raw diff
+const unifiedDiffContext = 3
+
 type diffService struct {
 	aiService ai.AIService
 	processor *utils.MarkdownProcessor
@@ -30,21 +37,140 @@ func NewDiffService(aiService ai.AIService, logger *logger.Logger) (DiffService,
 	}, nil
 }
 
-func (d *diffService) Compare(ctx context.Context, content1, content2 *models.ScreenshotContent, profileFields []string) (*models.DynamicChanges, error) {
-	markdownContent1, err := d.processor.Process(content1.Content)
+// Compare converts both versions to markdown, suppressing noise, and computes a structural diff between them
+// When the versions are identical, it returns empty changes without invoking the AI service
+// Otherwise only the changed hunks are sent to the AI service for categorization
+// The unified diff of both versions is returned alongside the categorized changes
+func (d *diffService) Compare(ctx context.Context, content1, content2 *models.ScreenshotContent, filter models.ContentFilter, profileFields []string) (*models.DiffResult, error) {
+	suppressor, err := newNoiseSuppressor(filter.NoiseRules)
+	if err != nil {
+		return nil, err
+	}
+
+	markdownContent1, err := d.extract(content1, filter.Selectors, suppressor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process markdown content 1: %w", err)
 	}
 
-	markdownContent2, err := d.processor.Process(content2.Content)
+	markdownContent2, err := d.extract(content2, filter.Selectors, suppressor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process markdown content 2: %w", err)
 	}
 
-	aiAnalysis, err := d.aiService.AnalyzeContentDifferences(ctx, markdownContent1, markdownContent2, profileFields)
+	// Compute the structural diff locally, and short circuit when nothing changed
+	contentDiff := compareMarkdown(markdownContent1, markdownContent2)
+	if contentDiff.IsEmpty() {
+		d.logger.Debug("no changes detected, skipping analysis")
+		emptyChanges, err := models.NewEmptyDynamicChanges(profileFields)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DiffResult{
+			Changes:    emptyChanges,
+			NoiseRules: suppressor.Fired(),
+		}, nil
+	}
+
+	// The minified markdown keeps a line per heading, list item and paragraph, so the raw diff only holds the changed ones
+	rawDiff := utils.UnifiedDiff(
+		"previous",
+		"current",
+		strings.Split(markdownContent1, "\n"),
+		strings.Split(markdownContent2, "\n"),
+		unifiedDiffContext,
+	)
+
+	// Only the changed hunks are sent for analysis
+	previousHunks, currentHunks := contentDiff.Hunks()
+	d.logger.Debug("changes detected, analyzing content differences", zap.Int("sections", len(contentDiff.Sections)))
+
+	aiAnalysis, err := d.aiService.AnalyzeContentDifferences(ctx, previousHunks, currentHunks, profileFields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze content differences: %w", err)
 	}
 
-	return aiAnalysis, nil
+	return &models.DiffResult{
+		Changes:    aiAnalysis,
+		RawDiff:    rawDiff,
+		NoiseRules: suppressor.Fired(),
+	}, nil
+}
+
+// CompareVisual computes a local perceptual diff of the screenshots
+// Only significant visual changes are sent to the AI service for categorization
+func (d *diffService) CompareVisual(ctx context.Context, screenshot1, screenshot2 *models.ScreenshotImage, threshold float64, profileFields []string) (*models.VisualDiff, error) {
+	if screenshot1 == nil || screenshot1.Image == nil || screenshot2 == nil || screenshot2.Image == nil {
+		return nil, fmt.Errorf("both screenshots are required")
+	}
+
+	visualDiff := compareImages(screenshot1.Image, screenshot2.Image)
+	visualDiff.Significant = visualDiff.ChangedPercentage > 0 && visualDiff.ChangedPercentage >= threshold
+	if !visualDiff.Significant {
+		d.logger.Debug("visual changes below threshold, skipping analysis", zap.Float64("changed_percentage", visualDiff.ChangedPercentage))
+		return visualDiff, nil
+	}
+
+	d.logger.Debug("significant visual changes detected, analyzing visual differences",
+		zap.Float64("changed_percentage", visualDiff.ChangedPercentage),
+		zap.Int("regions", len(visualDiff.BoundingBoxes)))
+
+	aiAnalysis, err := d.aiService.AnalyzeVisualDifferences(ctx, screenshot1.Image, screenshot2.Image, profileFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze visual differences: %w", err)
+	}
+	visualDiff.Changes = aiAnalysis
+
+	return visualDiff, nil
+}
+
+// Fingerprint hashes the minified markdown of the selected content, after suppressing noise
+// Markup changes which don't alter the markdown, such as attributes or scripts, don't alter the fingerprint
+// Neither do changes to dynamic content, such as timestamps
+func (d *diffService) Fingerprint(content *models.ScreenshotContent, filter models.ContentFilter) (string, []string, error) {
+	extraction, err := d.Extract(content, filter)
+	if err != nil {
+		return "", nil, err
+	}
+	markdownContent := extraction.Content
+
+	checksum := sha256.Sum256([]byte(markdownContent))
+	return hex.EncodeToString(checksum[:]), extraction.NoiseRules, nil
+}
+
+// Extract applies the filter to the content, as it is compared, recording the noise rules which fired
+func (d *diffService) Extract(content *models.ScreenshotContent, filter models.ContentFilter) (*models.ContentExtraction, error) {
+	suppressor, err := newNoiseSuppressor(filter.NoiseRules)
+	if err != nil {
+		return nil, err
+	}
+
+	markdownContent, err := d.extract(content, filter.Selectors, suppressor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ContentExtraction{
+		Selectors:  filter.Selectors,
+		Content:    markdownContent,
+		NoiseRules: suppressor.Fired(),
+	}, nil
+}
+
+// extract applies the selectors to the content before converting it to markdown and suppressing noise
+func (d *diffService) extract(content *models.ScreenshotContent, selectors models.ContentSelectors, suppressor *noiseSuppressor) (string, error) {
+	if content == nil {
+		return "", fmt.Errorf("content is required")
+	}
+
+	selectedContent, err := applySelectors(content.Content, selectors)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply content selectors: %w", err)
+	}
+
+	markdownContent, err := d.processor.Process(selectedContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to process markdown content: %w", err)
+	}
+
+	return suppressor.Suppress(markdownContent), nil
 }
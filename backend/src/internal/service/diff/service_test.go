@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/service/ai"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+func TestCompareRawDiffOfChangedLines(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aiService, err := ai.NewFakeAIService(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service, err := NewDiffService(aiService, log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page := `<html><body>
+<h1>Pricing</h1>
+<p>Starter plan <b>$10</b> per month.</p>
+<ul><li>5 seats</li><li>Email support</li></ul>
+<h2>Enterprise</h2>
+<p>%s</p>
+</body></html>`
+
+	result, err := service.Compare(context.Background(),
+		&models.ScreenshotContent{Content: fmt.Sprintf(page, "Contact us")},
+		&models.ScreenshotContent{Content: fmt.Sprintf(page, "Starting at $500 per month")},
+		models.ContentFilter{}, []string{"pricing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var changed []string
+	for _, line := range strings.Split(result.RawDiff, "\n") {
+		if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changed = append(changed, line)
+		}
+	}
+
+	want := []string{"-Contact us", "+Starting at $500 per month"}
+	if !reflect.DeepEqual(changed, want) {
+		t.Fatalf("expected only the changed lines in the raw diff, got %v\n%s", changed, result.RawDiff)
+	}
+}
@@ -0,0 +1,180 @@
+// ./src/internal/service/diff/visual.go
+package diff
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+const (
+	// maxColorDelta is the largest perceptual difference between two colors in the YIQ color space
+	maxColorDelta = 35215.0
+
+	// pixelThreshold is the fraction of the maximum color delta from which a pixel is considered changed
+	// This absorbs the minor differences introduced by compression and anti-aliasing
+	pixelThreshold = 0.1
+
+	// cellSize is the size of the square cells, in pixels, which changed pixels are grouped into
+	cellSize = 16
+
+	// maxBoundingBoxes caps the number of bounding boxes reported for a visual diff
+	maxBoundingBoxes = 25
+)
+
+var (
+	// overlayHighlight is the color of the changed pixels in the overlay
+	overlayHighlight = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+)
+
+// compareImages compares two screenshots pixel by pixel
+// Pixels are compared using their perceptual difference, with images of different sizes compared over their union
+// It returns the changed pixel percentage, the bounding boxes of the changed regions and an overlay of the current image
+func compareImages(previous, current image.Image) *models.VisualDiff {
+	pb, cb := previous.Bounds(), current.Bounds()
+	width, height := max(pb.Dx(), cb.Dx()), max(pb.Dy(), cb.Dy())
+
+	overlay := image.NewRGBA(image.Rect(0, 0, width, height))
+	cols, rows := (width+cellSize-1)/cellSize, (height+cellSize-1)/cellSize
+	cells := make([]bool, cols*rows)
+
+	threshold := maxColorDelta * pixelThreshold * pixelThreshold
+	changed := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p, pok := pixelAt(previous, pb, x, y)
+			c, cok := pixelAt(current, cb, x, y)
+
+			// Pixels outside of either image are changed, as the page was resized
+			if !pok || !cok || colorDelta(p, c) > threshold {
+				changed++
+				cells[(y/cellSize)*cols+x/cellSize] = true
+				overlay.SetRGBA(x, y, overlayHighlight)
+				continue
+			}
+
+			overlay.SetRGBA(x, y, fadedGray(c))
+		}
+	}
+
+	visualDiff := models.VisualDiff{
+		BoundingBoxes: boundingBoxes(cells, cols, rows, width, height),
+		Overlay:       overlay,
+	}
+	if total := width * height; total > 0 {
+		visualDiff.ChangedPercentage = float64(changed) * 100 / float64(total)
+	}
+
+	return &visualDiff
+}
+
+// pixelAt returns the color of the pixel at the given offset from the origin of the image
+// It returns false when the offset lies outside of the image
+func pixelAt(img image.Image, bounds image.Rectangle, x, y int) (color.RGBA, bool) {
+	if x >= bounds.Dx() || y >= bounds.Dy() {
+		return color.RGBA{}, false
+	}
+
+	r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+	return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}, true
+}
+
+// colorDelta returns the squared perceptual difference between two colors
+// Colors are blended against a white background and compared in the YIQ color space
+func colorDelta(c1, c2 color.RGBA) float64 {
+	r1, g1, b1 := blendWhite(c1)
+	r2, g2, b2 := blendWhite(c2)
+
+	y := rgbToY(r1, g1, b1) - rgbToY(r2, g2, b2)
+	i := rgbToI(r1, g1, b1) - rgbToI(r2, g2, b2)
+	q := rgbToQ(r1, g1, b1) - rgbToQ(r2, g2, b2)
+
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+// blendWhite blends a color with its alpha against a white background
+func blendWhite(c color.RGBA) (float64, float64, float64) {
+	alpha := float64(c.A) / 255
+	blend := func(v uint8) float64 {
+		return 255 + (float64(v)-255)*alpha
+	}
+	return blend(c.R), blend(c.G), blend(c.B)
+}
+
+func rgbToY(r, g, b float64) float64 {
+	return r*0.29889531 + g*0.58662247 + b*0.11448223
+}
+
+func rgbToI(r, g, b float64) float64 {
+	return r*0.59597799 - g*0.27417610 - b*0.32180189
+}
+
+func rgbToQ(r, g, b float64) float64 {
+	return r*0.21147017 - g*0.52261711 + b*0.31114694
+}
+
+// fadedGray returns a faded grayscale version of the color
+// Unchanged pixels are faded in the overlay so that the changed pixels stand out
+func fadedGray(c color.RGBA) color.RGBA {
+	r, g, b := blendWhite(c)
+	v := uint8(255 + (rgbToY(r, g, b)-255)*0.1)
+	return color.RGBA{R: v, G: v, B: v, A: 255}
+}
+
+// boundingBoxes groups the changed cells into connected regions and returns their bounding boxes
+// The boxes are returned in pixels, largest first, clipped to the dimensions of the image
+func boundingBoxes(cells []bool, cols, rows, width, height int) []models.BoundingBox {
+	boxes := make([]models.BoundingBox, 0)
+	visited := make([]bool, len(cells))
+
+	for start := range cells {
+		if !cells[start] || visited[start] {
+			continue
+		}
+
+		// Flood fill the region of adjacent changed cells
+		minCol, minRow, maxCol, maxRow := cols, rows, 0, 0
+		stack := []int{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			cell := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			col, row := cell%cols, cell/cols
+			minCol, minRow = min(minCol, col), min(minRow, row)
+			maxCol, maxRow = max(maxCol, col), max(maxRow, row)
+
+			neighbours := [][2]int{{col - 1, row}, {col + 1, row}, {col, row - 1}, {col, row + 1}}
+			for _, n := range neighbours {
+				if n[0] < 0 || n[0] >= cols || n[1] < 0 || n[1] >= rows {
+					continue
+				}
+				next := n[1]*cols + n[0]
+				if cells[next] && !visited[next] {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+
+		x, y := minCol*cellSize, minRow*cellSize
+		boxes = append(boxes, models.BoundingBox{
+			X:      x,
+			Y:      y,
+			Width:  min((maxCol+1)*cellSize, width) - x,
+			Height: min((maxRow+1)*cellSize, height) - y,
+		})
+	}
+
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return boxes[i].Width*boxes[i].Height > boxes[j].Width*boxes[j].Height
+	})
+
+	if len(boxes) > maxBoundingBoxes {
+		boxes = boxes[:maxBoundingBoxes]
+	}
+
+	return boxes
+}
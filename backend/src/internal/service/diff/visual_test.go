@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+func filledImage(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareImagesIdentical(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// Slight color shifts, as introduced by compression, aren't perceptible changes
+	previous := filledImage(64, 64, white)
+	current := filledImage(64, 64, color.RGBA{R: 252, G: 253, B: 255, A: 255})
+
+	visualDiff := compareImages(previous, current)
+	if visualDiff.ChangedPercentage != 0 {
+		t.Fatalf("expected no changed pixels, got %v%%", visualDiff.ChangedPercentage)
+	}
+	if len(visualDiff.BoundingBoxes) != 0 {
+		t.Fatalf("expected no bounding boxes, got %+v", visualDiff.BoundingBoxes)
+	}
+}
+
+func TestCompareImagesRegions(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	black := color.RGBA{A: 255}
+
+	previous := filledImage(64, 64, white)
+	current := filledImage(64, 64, white)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			current.SetRGBA(x, y, black)
+			current.SetRGBA(40+x, 40+y, black)
+		}
+	}
+
+	visualDiff := compareImages(previous, current)
+	if want := float64(128) * 100 / (64 * 64); visualDiff.ChangedPercentage != want {
+		t.Fatalf("expected %v%% changed pixels, got %v%%", want, visualDiff.ChangedPercentage)
+	}
+
+	want := []models.BoundingBox{
+		{X: 0, Y: 0, Width: 16, Height: 16},
+		{X: 32, Y: 32, Width: 16, Height: 16},
+	}
+	if !reflect.DeepEqual(visualDiff.BoundingBoxes, want) {
+		t.Fatalf("unexpected bounding boxes\ngot:  %+v\nwant: %+v", visualDiff.BoundingBoxes, want)
+	}
+
+	if got := visualDiff.Overlay.At(0, 0); got != overlayHighlight {
+		t.Fatalf("expected changed pixel to be highlighted, got %v", got)
+	}
+}
+
+func TestCompareImagesResized(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// The pixels only present in the taller screenshot are changed
+	visualDiff := compareImages(filledImage(10, 10, white), filledImage(10, 20, white))
+	if visualDiff.ChangedPercentage != 50 {
+		t.Fatalf("expected 50%% changed pixels, got %v%%", visualDiff.ChangedPercentage)
+	}
+}
@@ -43,7 +43,7 @@ func NewDispatchExecutor(
 	return &d, nil
 }
 
-func (e *dispatchExecutor) Execute(executionContext context.Context, jobState models.JobState) (<-chan models.JobUpdate, <-chan models.JobError) {
+func (e *dispatchExecutor) Execute(executionContext context.Context, jobState models.JobState, leaser ItemLeaser) (<-chan models.JobUpdate, <-chan models.JobError) {
 	updates := make(chan models.JobUpdate, 1)
 	errors := make(chan models.JobError, 1)
 
@@ -51,9 +51,17 @@ func (e *dispatchExecutor) Execute(executionContext context.Context, jobState mo
 		defer close(updates)
 		defer close(errors)
 
-		checkpoint := jobState.Checkpoint.BatchID
+		// Items are leased before being processed, as other replicas may be sharing the job
+		run := newLeasedRun(leaser, updates, withScope(e.processBatch, jobState.Scope), e.logger)
 
-		workspaceBatchChan, errBatchChan := e.ws.ListActiveWorkspaces(executionContext, e.runtimeConfig.Parallelism, checkpoint)
+		// Resume the unfinished items of the checkpoint before moving past its cursor
+		checkpoint := jobState.Checkpoint
+		cursor := checkpoint.BatchID
+		if !run.runBatch(executionContext, cursor, checkpoint.Pending) {
+			return
+		}
+
+		workspaceBatchChan, errBatchChan := e.ws.ListActiveWorkspaces(executionContext, e.runtimeConfig.Parallelism, checkpoint.BatchID, jobState.Scope)
 
 		batchStartTime := time.Now()
 
@@ -61,34 +69,15 @@ func (e *dispatchExecutor) Execute(executionContext context.Context, jobState mo
 			select {
 			case workspaceBatch, ok := <-workspaceBatchChan:
 				if !ok {
+					// Wait for the items held by other replicas to finish, or take them over
+					run.drain(executionContext, cursor)
 					return
 				}
 
-				// Get completion channel for the batch
-				completionChan := e.processBatch(executionContext, workspaceBatch, errors)
-
-				// Find the max index of the batch
-				maxIndex := -1
-				for index := range completionChan {
-					if index > maxIndex {
-						maxIndex = index
-					}
-				}
-
-				// Send the update
-				if maxIndex >= 0 && maxIndex < len(workspaceBatch) {
-					select {
-					case updates <- models.JobUpdate{
-						Time:      time.Now(),
-						Completed: int64(maxIndex + 1),
-						Failed:    int64(len(workspaceBatch) - (maxIndex + 1)),
-						NewCheckpoint: models.JobCheckpoint{
-							BatchID: &workspaceBatch[maxIndex],
-						},
-					}:
-					case <-executionContext.Done():
-						return
-					}
+				// Process the leased items of this batch, sending an update as each of them finishes
+				cursor = &workspaceBatch[len(workspaceBatch)-1]
+				if !run.runBatch(executionContext, cursor, workspaceBatch) {
+					return
 				}
 
 				// Handle rate limiting
@@ -126,8 +115,16 @@ func (e *dispatchExecutor) Execute(executionContext context.Context, jobState mo
 	return updates, errors
 }
 
-func (e *dispatchExecutor) processBatch(ctx context.Context, workspaceBatch []uuid.UUID, errors chan models.JobError) <-chan int {
-	completions := make(chan int, len(workspaceBatch))
+func (e *dispatchExecutor) Retry(ctx context.Context, scope models.JobScope, itemIDs []uuid.UUID) <-chan models.JobItemOutcome {
+	return retryItems(ctx, itemIDs, e.runtimeConfig.Parallelism, withScope(e.processBatch, scope))
+}
+
+func (e *dispatchExecutor) CountItems(ctx context.Context, scope models.JobScope) (int64, error) {
+	return e.ws.CountActiveWorkspaces(ctx, scope)
+}
+
+func (e *dispatchExecutor) processBatch(ctx context.Context, workspaceBatch []uuid.UUID, scope models.JobScope) <-chan itemCompletion {
+	completions := make(chan itemCompletion, len(workspaceBatch))
 
 	// Validate timeout
 	if e.runtimeConfig.UpperBound <= 0 {
@@ -147,25 +144,17 @@ func (e *dispatchExecutor) processBatch(ctx context.Context, workspaceBatch []uu
 			defer wg.Done()
 
 			start := time.Now()
-			err := e.processWorkspace(timeoutCtx, workspaceID)
+			attempts, err := e.processWorkspace(timeoutCtx, workspaceID, scope)
 			duration := time.Since(start)
 
+			// Completions are buffered to the size of the batch, so that every outcome is reported
+			completions <- itemCompletion{index: workspaceIndex, outcome: newItemOutcome(workspaceID, false, attempts, err, duration)}
+
 			if err != nil {
 				e.logger.Error("workspace processing failed",
 					zap.Any("workspaceID", workspaceID),
 					zap.Duration("duration", duration),
 					zap.Error(err))
-
-				select {
-				case errors <- models.JobError{Error: err, Time: time.Now()}:
-				case <-timeoutCtx.Done():
-				}
-				return
-			}
-
-			select {
-			case completions <- workspaceIndex:
-			case <-timeoutCtx.Done():
 			}
 		}(index, workspaceID)
 	}
@@ -180,31 +169,185 @@ func (e *dispatchExecutor) processBatch(ctx context.Context, workspaceBatch []uu
 	return completions
 }
 
-func (e *dispatchExecutor) processWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+// processWorkspace dispatches the reports of the competitors of the workspace within the scope of the job, as a digest unless the workspace opted out
+// Returns the highest number of attempts made for a step of the workspace
+func (e *dispatchExecutor) processWorkspace(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) (int, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return 1, ctx.Err()
 	default:
 		// Process the workspace
-		competitors, _, err := e.ws.ListCompetitorsForWorkspace(ctx, workspaceID, nil, nil)
+		var reportMode models.ReportMode
+		_, attempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+			var err error
+			reportMode, err = e.ws.GetWorkspaceReportMode(ctx, workspaceID)
+			return false, err
+		})
 		if err != nil {
-			return err
+			return attempts, err
 		}
-		errs := make([]error, 0)
-		for _, competitor := range competitors {
-			if err := e.processCompetitor(ctx, workspaceID, competitor.ID); err != nil {
-				e.logger.Error("failed to process competitor", zap.Any("workspaceID", workspaceID), zap.Any("competitorID", competitor.ID), zap.Error(err))
-				errs = append(errs, err)
+
+		// The members due to be notified are listed once, so that their frequency holds across the competitors
+		now := time.Now()
+		var subscribers []models.NotificationSubscriber
+		_, subscriberAttempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+			var err error
+			subscribers, err = e.ws.ListNotificationSubscribers(ctx, workspaceID, now)
+			return false, err
+		})
+		attempts = max(attempts, subscriberAttempts)
+		if err != nil {
+			return attempts, err
+		}
+
+		var dispatchAttempts int
+		var notified []uuid.UUID
+		if reportMode == models.ReportModeIndividual {
+			dispatchAttempts, notified, err = e.processCompetitors(ctx, workspaceID, scope, subscribers)
+		} else {
+			dispatchAttempts, notified, err = e.processDigest(ctx, workspaceID, scope, subscribers)
+		}
+		attempts = max(attempts, dispatchAttempts)
+
+		// Only the members a report reached are marked notified, the others stay due for the next dispatch
+		if len(notified) > 0 {
+			if markErr := e.ws.MarkSubscribersNotified(ctx, workspaceID, notified, now); markErr != nil {
+				e.logger.Error("failed to mark subscribers notified", zap.Any("workspaceID", workspaceID), zap.Error(markErr))
+				err = errors.Join(err, markErr)
 			}
 		}
-		if len(errs) > 0 {
-			err = fmt.Errorf("failed to process some competitors %v", errs)
+
+		return attempts, err
+	}
+}
+
+// processCompetitors dispatches the report of each competitor of the workspace within the scope on its own
+// Returns the subscribers any report was sent to
+func (e *dispatchExecutor) processCompetitors(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope, subscribers []models.NotificationSubscriber) (int, []uuid.UUID, error) {
+	var competitors []models.Competitor
+	_, attempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+		var err error
+		competitors, err = e.ws.ListCompetitorsInScope(ctx, workspaceID, scope)
+		return false, err
+	})
+	if err != nil {
+		return attempts, nil, err
+	}
+
+	errs := make([]error, 0)
+	var notified []uuid.UUID
+	for _, competitor := range competitors {
+		competitorAttempts, competitorNotified, err := e.processCompetitor(ctx, workspaceID, competitor.ID, subscribers)
+		attempts = max(attempts, competitorAttempts)
+		notified = append(notified, competitorNotified...)
+		if err != nil {
+			e.logger.Error("failed to process competitor", zap.Any("workspaceID", workspaceID), zap.Any("competitorID", competitor.ID), zap.Int("attempts", competitorAttempts), zap.Error(err))
+			errs = append(errs, err)
 		}
-		return err
 	}
+
+	if len(errs) > 0 {
+		err = fmt.Errorf("failed to process some competitors %v", errs)
+	}
+	return attempts, notified, err
 }
 
-func (e *dispatchExecutor) processCompetitor(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) error {
+// processDigest dispatches a digest of the reports of the competitors of the workspace within the scope to the slack channel, and to the subscribers over slack and email
+// Each channel is retried on its own, so that the digest isn't sent twice over the same channel
+// Returns the subscribers the digest was sent to, over any channel
+func (e *dispatchExecutor) processDigest(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope, subscribers []models.NotificationSubscriber) (int, []uuid.UUID, error) {
+	var digest *models.Digest
+	_, attempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+		var err error
+		digest, err = e.ws.GetDigest(ctx, workspaceID, scope)
+		return false, err
+	})
+	if err != nil {
+		return attempts, nil, err
+	}
+	if digest.IsEmpty() {
+		e.logger.Debug("no changes to dispatch", zap.Any("workspaceID", workspaceID))
+		return attempts, nil, nil
+	}
+
+	slackWorkspaceExists, err := e.slackWorkspace.IntegrationExistsForWorkspace(ctx, workspaceID)
+	if err != nil {
+		e.logger.Error("failed to check if slack workspace exists", zap.Any("workspaceID", workspaceID), zap.Error(err))
+		slackWorkspaceExists = false
+	}
+
+	// The channel of the slack workspace reaches no subscriber in particular
+	steps := []struct {
+		name     string
+		enabled  bool
+		dispatch func(ctx context.Context) (int, []uuid.UUID, error)
+	}{
+		{"slack workspace", slackWorkspaceExists, func(ctx context.Context) (int, []uuid.UUID, error) {
+			_, attempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+				return false, e.slackWorkspace.DispatchDigestToWorkspaceMembers(ctx, *digest)
+			})
+			return attempts, nil, err
+		}},
+		{"slack subscribers", slackWorkspaceExists, func(ctx context.Context) (int, []uuid.UUID, error) {
+			return e.dispatchToEach(ctx, subscribers, func(ctx context.Context, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error) {
+				return e.slackWorkspace.DispatchDigestToSubscribers(ctx, *digest, subscribers)
+			})
+		}},
+		{"email", true, func(ctx context.Context) (int, []uuid.UUID, error) {
+			// The emails are queued in a single transaction, a failed attempt queues none of them
+			var notified []uuid.UUID
+			_, attempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+				var err error
+				notified, err = e.ws.DispatchDigestToSubscribers(ctx, workspaceID, *digest, subscribers)
+				return false, err
+			})
+			return attempts, notified, err
+		}},
+	}
+
+	errs := make([]error, 0)
+	var notified []uuid.UUID
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+		stepAttempts, stepNotified, err := step.dispatch(ctx)
+		attempts = max(attempts, stepAttempts)
+		notified = append(notified, stepNotified...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send digest to %s: %v", step.name, err))
+		}
+	}
+
+	return attempts, notified, errors.Join(errs...)
+}
+
+// dispatchToEach dispatches to each subscriber on their own, so that only the dispatches which failed are retried
+// A subscriber is never sent the same message twice because the dispatch to another one failed
+// Returns the highest number of attempts made for a subscriber, and the subscribers the message was sent to
+func (e *dispatchExecutor) dispatchToEach(ctx context.Context, subscribers []models.NotificationSubscriber, dispatch func(ctx context.Context, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error)) (int, []uuid.UUID, error) {
+	attempts := 0
+	errs := make([]error, 0)
+	var notified []uuid.UUID
+	for _, subscriber := range subscribers {
+		_, subscriberAttempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+			subscriberNotified, err := dispatch(ctx, []models.NotificationSubscriber{subscriber})
+			notified = append(notified, subscriberNotified...)
+			return false, err
+		})
+		attempts = max(attempts, subscriberAttempts)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return attempts, notified, errors.Join(errs...)
+}
+
+// processCompetitor dispatches the report of the competitor to the slack channel, and to the subscribers over slack and email
+// Each channel is retried on its own, and each subscriber over slack, so that a report isn't sent twice to anyone
+// Returns the subscribers the report was sent to
+func (e *dispatchExecutor) processCompetitor(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscribers []models.NotificationSubscriber) (int, []uuid.UUID, error) {
 	slackWorkspaceExists, err := e.slackWorkspace.IntegrationExistsForWorkspace(ctx, workspaceID)
 	if err != nil {
 		e.logger.Error("failed to check if slack workspace exists", zap.Any("workspaceID", workspaceID), zap.Error(err))
@@ -213,26 +356,45 @@ func (e *dispatchExecutor) processCompetitor(ctx context.Context, workspaceID uu
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return 1, nil, ctx.Err()
 	default:
-		// Send report to slack workspace
-		var slackError error
+		errs := make([]error, 0)
+		attempts := 0
+		var notified []uuid.UUID
+
+		// Send report to slack workspace, and to the subscribers of the slack workspace
 		if slackWorkspaceExists {
-			slackError = e.slackWorkspace.DispatchReportToWorkspaceMembers(ctx, workspaceID, competitorID)
-		}
+			_, slackAttempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+				return false, e.slackWorkspace.DispatchReportToWorkspaceMembers(ctx, workspaceID, competitorID)
+			})
+			attempts = max(attempts, slackAttempts)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to send report to slack workspace: %v", err))
+			}
 
-		// Process the competitor
-		var emailError error
-		emailError = e.ws.DispatchReportToWorkspaceMembers(ctx, workspaceID, competitorID)
+			slackAttempts, slackNotified, err := e.dispatchToEach(ctx, subscribers, func(ctx context.Context, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error) {
+				return e.slackWorkspace.DispatchReportToSubscribers(ctx, workspaceID, competitorID, subscribers)
+			})
+			attempts = max(attempts, slackAttempts)
+			notified = append(notified, slackNotified...)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to send report to slack subscribers: %v", err))
+			}
+		}
 
-		if slackError != nil && emailError != nil {
-			return fmt.Errorf("failed to send report to slack workspace and email: %v, %v", slackError, emailError)
-		} else if slackError != nil {
-			return fmt.Errorf("failed to send report to slack workspace: %v", slackError)
-		} else if emailError != nil {
-			return fmt.Errorf("failed to send report to email: %v", emailError)
+		// Send report to the email subscribers
+		// The emails are queued in a single transaction, a failed attempt queues none of them and is retried as a whole
+		_, emailAttempts, err := withRetry(ctx, e.runtimeConfig.RetryPolicy, e.logger, func(ctx context.Context) (bool, error) {
+			emailNotified, err := e.ws.DispatchReportToSubscribers(ctx, workspaceID, competitorID, subscribers)
+			notified = append(notified, emailNotified...)
+			return false, err
+		})
+		attempts = max(attempts, emailAttempts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send report to email: %v", err))
 		}
-		return nil
+
+		return attempts, notified, errors.Join(errs...)
 	}
 }
 
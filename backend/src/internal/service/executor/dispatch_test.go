@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+func TestDispatchToEachRetriesOnlyFailures(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e := &dispatchExecutor{
+		logger: log,
+		runtimeConfig: models.JobExecutorConfig{
+			RetryPolicy: models.JobRetryPolicy{
+				MaxAttempts:     3,
+				BaseBackoff:     time.Millisecond,
+				MaxBackoff:      time.Millisecond,
+				RetryableErrors: []models.JobErrorClass{models.JobErrorClassServer},
+			},
+		},
+	}
+
+	delivered, flaky, failing := uuid.New(), uuid.New(), uuid.New()
+	subscribers := []models.NotificationSubscriber{
+		{NotificationSubscription: models.NotificationSubscription{UserID: delivered}},
+		{NotificationSubscription: models.NotificationSubscription{UserID: flaky}},
+		{NotificationSubscription: models.NotificationSubscription{UserID: failing}},
+	}
+
+	sends := make(map[uuid.UUID]int)
+	attempts, notified, err := e.dispatchToEach(context.Background(), subscribers, func(ctx context.Context, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error) {
+		if len(subscribers) != 1 {
+			t.Fatalf("expected a single subscriber per dispatch, got %d", len(subscribers))
+		}
+		userID := subscribers[0].UserID
+		sends[userID]++
+		if userID == failing || (userID == flaky && sends[userID] == 1) {
+			return nil, &models.UpstreamError{Service: "slack api", StatusCode: 502, Err: errors.New("bad gateway")}
+		}
+		return []uuid.UUID{userID}, nil
+	})
+
+	if err == nil {
+		t.Fatal("expected the error of the failing subscriber")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts for the failing subscriber, got %d", attempts)
+	}
+	if sends[delivered] != 1 || sends[flaky] != 2 || sends[failing] != 3 {
+		t.Errorf("expected only the failed dispatches to be retried, got %v", sends)
+	}
+	if len(notified) != 2 || !slices.Contains(notified, delivered) || !slices.Contains(notified, flaky) {
+		t.Errorf("expected the delivered and flaky subscribers to be notified, got %v", notified)
+	}
+}
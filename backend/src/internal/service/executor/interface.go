@@ -13,8 +13,12 @@ type WorkflowObserver interface {
 	// Recover recovers any pre-empted workflow jobs
 	Recover(ctx context.Context) error
 
-	// Submit submits a new job to the workflow
-	Submit(ctx context.Context) (uuid.UUID, error)
+	// Submit submits a new job to the workflow, limited to the items within the scope
+	Submit(ctx context.Context, scope models.JobScope) (uuid.UUID, error)
+
+	// SubmitJob submits a new job to the workflow under the job ID, limited to the items within the scope
+	// Submitting a job which was already submitted does nothing, so that the submission can be retried
+	SubmitJob(ctx context.Context, jobID uuid.UUID, scope models.JobScope) error
 
 	// Status returns the status of the job submitted to the workflow
 	Status(ctx context.Context, jobID uuid.UUID) (*models.JobStatus, error)
@@ -28,22 +32,58 @@ type WorkflowObserver interface {
 	// Cancel cancels the job submitted to the workflow
 	Cancel(ctx context.Context, jobID uuid.UUID) error
 
+	// Pause stops the job from processing its items, its checkpoint is kept until it's resumed
+	Pause(ctx context.Context, jobID uuid.UUID) error
+
+	// Resume runs the paused job again from its checkpoint
+	Resume(ctx context.Context, jobID uuid.UUID) error
+
 	// List returns the list of jobs filtered by status
 	List(ctx context.Context, status models.JobStatus) ([]models.Job, error)
 
 	// Shutdown stops the workflow executor
 	Shutdown(ctx context.Context) error
 
-	// History returns the history of job runs
-	History(ctx context.Context, limit, offset *int) ([]models.JobRecord, error)
+	// History returns the history of job runs matching the filter
+	History(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error)
+
+	// Events returns the events of the jobs of the workflow, from every replica, until the context is done
+	// When jobID is set, only the events of the job are returned
+	Events(ctx context.Context, jobID *uuid.UUID) (<-chan models.JobEvent, error)
+
+	// Record returns the record of the job, which outlives its state once it ended
+	Record(ctx context.Context, jobID uuid.UUID) (*models.JobRecord, error)
+
+	// Failures returns the dead-letter list of the job, optionally filtered by status
+	Failures(ctx context.Context, jobID uuid.UUID, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error)
+
+	// RetryFailures retries the pending failures of the job in the background
+	// When itemIDs is empty, every pending failure of the job is retried
+	RetryFailures(ctx context.Context, jobID uuid.UUID, itemIDs []uuid.UUID) ([]models.JobFailure, error)
 }
 
 // JobExecutor represents the executor for performing jobs
 type JobExecutor interface {
 	// Execute executes the task
-	Execute(ctx context.Context, jobState models.JobState) (<-chan models.JobUpdate, <-chan models.JobError)
+	// Items are processed once leased through the leaser, so that replicas sharing the job don't process them twice
+	Execute(ctx context.Context, jobState models.JobState, leaser ItemLeaser) (<-chan models.JobUpdate, <-chan models.JobError)
+
+	// Retry processes the given items again, outside of any batch of the job
+	// It emits the outcome of every item, the attempt is left for the caller to set
+	// Items are processed within the scope of the job, as the items of report and dispatch jobs span competitors outside of it
+	Retry(ctx context.Context, scope models.JobScope, itemIDs []uuid.UUID) <-chan models.JobItemOutcome
+
+	// CountItems returns the number of items a job processes for the scope
+	CountItems(ctx context.Context, scope models.JobScope) (int64, error)
 
 	// Terminate terminates the task
 	// It handles cleanup and termination of shared resources for jobs
 	Terminate(ctx context.Context) error
 }
+
+// WorkspaceResolver is implemented by the executors whose items aren't workspaces
+// The items of the other executors are the workspaces themselves
+type WorkspaceResolver interface {
+	// ItemWorkspaces returns the workspace each of the items belongs to, items which aren't found are left out
+	ItemWorkspaces(ctx context.Context, itemIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+}
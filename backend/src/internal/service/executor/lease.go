@@ -0,0 +1,232 @@
+// ./src/internal/service/executor/lease.go
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/repository/workflow"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ItemLeaser leases the items of a job to the replica processing them
+// Replicas sharing a job only process the items they hold a lease on, so that no item is processed twice
+type ItemLeaser interface {
+	// Lease leases the items which are neither finished nor leased by another replica
+	// Returns the leased items, and the items held by other replicas
+	Lease(ctx context.Context, itemIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error)
+
+	// Renew extends the leases on the items, while they are being processed
+	Renew(ctx context.Context, itemIDs []uuid.UUID) error
+
+	// Release releases the leases on the items without finishing them, so that another replica takes them over
+	Release(ctx context.Context, itemIDs []uuid.UUID) error
+
+	// Finish marks the items as finished, and releases their leases
+	Finish(ctx context.Context, itemIDs []uuid.UUID) error
+
+	// Interval is the interval at which the leases are renewed, and at which items held by other replicas are checked
+	Interval() time.Duration
+}
+
+// compile time check if the interface is implemented
+var _ ItemLeaser = (*jobLeaser)(nil)
+var _ ItemLeaser = (*exclusiveLeaser)(nil)
+
+// jobLeaser leases the items of a job through the workflow repository
+type jobLeaser struct {
+	repository   workflow.LeaseRepository
+	jobID        uuid.UUID
+	workflowType models.WorkflowType
+	cluster      models.ClusterConfig
+}
+
+// NewJobLeaser creates a leaser for the items of the job, held by the replica of the cluster configuration
+func NewJobLeaser(repository workflow.LeaseRepository, jobID uuid.UUID, workflowType models.WorkflowType, cluster models.ClusterConfig) ItemLeaser {
+	return &jobLeaser{
+		repository:   repository,
+		jobID:        jobID,
+		workflowType: workflowType,
+		cluster:      cluster,
+	}
+}
+
+func (l *jobLeaser) Lease(ctx context.Context, itemIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	return l.repository.LeaseItems(ctx, l.jobID, l.workflowType, l.cluster.ReplicaID, itemIDs, l.cluster.LeaseTTL)
+}
+
+func (l *jobLeaser) Renew(ctx context.Context, itemIDs []uuid.UUID) error {
+	return l.repository.RenewItems(ctx, l.jobID, l.workflowType, l.cluster.ReplicaID, itemIDs, l.cluster.LeaseTTL)
+}
+
+func (l *jobLeaser) Release(ctx context.Context, itemIDs []uuid.UUID) error {
+	return l.repository.ReleaseItems(ctx, l.jobID, l.workflowType, l.cluster.ReplicaID, itemIDs)
+}
+
+func (l *jobLeaser) Finish(ctx context.Context, itemIDs []uuid.UUID) error {
+	return l.repository.FinishItems(ctx, l.jobID, l.workflowType, l.cluster.ReplicaID, itemIDs)
+}
+
+func (l *jobLeaser) Interval() time.Duration {
+	return l.cluster.PollInterval
+}
+
+// exclusiveLeaser leases every item, it is used when a job isn't shared with other replicas
+type exclusiveLeaser struct{}
+
+func (exclusiveLeaser) Lease(ctx context.Context, itemIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	return itemIDs, nil, nil
+}
+
+func (exclusiveLeaser) Renew(ctx context.Context, itemIDs []uuid.UUID) error {
+	return nil
+}
+
+func (exclusiveLeaser) Release(ctx context.Context, itemIDs []uuid.UUID) error {
+	return nil
+}
+
+func (exclusiveLeaser) Finish(ctx context.Context, itemIDs []uuid.UUID) error {
+	return nil
+}
+
+func (exclusiveLeaser) Interval() time.Duration {
+	return time.Second
+}
+
+// leasedRun runs through the batches of a job, processing the items leased by the replica
+// Items held by other replicas are deferred, and leased again along with the next batch
+// This way items of a replica which died are taken over once their leases expire
+type leasedRun struct {
+	leaser       ItemLeaser
+	updates      chan<- models.JobUpdate
+	processBatch batchProcessor
+	logger       *logger.Logger
+
+	// deferred are the items held by other replicas, in the order they were listed
+	deferred []uuid.UUID
+}
+
+func newLeasedRun(leaser ItemLeaser, updates chan<- models.JobUpdate, processBatch batchProcessor, logger *logger.Logger) *leasedRun {
+	if leaser == nil {
+		leaser = exclusiveLeaser{}
+	}
+
+	return &leasedRun{
+		leaser:       leaser,
+		updates:      updates,
+		processBatch: processBatch,
+		logger:       logger,
+	}
+}
+
+// runBatch leases the deferred items along with the batch, processes the leased ones and sends their updates
+// The checkpoint moves to the cursor, with the unfinished and deferred items pending
+// Returns false if the job was cancelled before every update was sent, or was asked to pause before the batch
+// A paused job leaves the batch untouched, its checkpoint stays at the previous batch
+func (r *leasedRun) runBatch(ctx context.Context, cursor *uuid.UUID, batch []uuid.UUID) bool {
+	if models.PauseRequested(ctx) {
+		return false
+	}
+
+	candidates := make([]uuid.UUID, 0, len(r.deferred)+len(batch))
+	candidates = append(candidates, r.deferred...)
+	candidates = append(candidates, batch...)
+	if len(candidates) == 0 {
+		return true
+	}
+
+	leased, held, err := r.leaser.Lease(ctx, candidates)
+	if err != nil {
+		// Without leases the items can't be processed safely, they are deferred to the next batch instead
+		r.logger.Error("failed to lease items, deferring them", zap.Int("items", len(candidates)), zap.Error(err))
+		leased, held = nil, candidates
+	}
+	r.deferred = held
+
+	checkpoint := models.JobCheckpoint{
+		BatchID: cursor,
+		Pending: held,
+	}
+
+	if len(leased) == 0 {
+		// Nothing was leased, the checkpoint still moves past the batch
+		select {
+		case r.updates <- models.JobUpdate{Time: time.Now(), NewCheckpoint: checkpoint}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	completions := r.track(ctx, leased, r.processBatch(ctx, leased))
+	return sendBatchUpdates(ctx, r.updates, checkpoint, leased, completions)
+}
+
+// drain runs through the deferred items until none is left, waiting for the replicas holding them in between
+// Returns false if the job was cancelled or paused before every deferred item finished, otherwise marks the job drained
+func (r *leasedRun) drain(ctx context.Context, cursor *uuid.UUID) bool {
+	for len(r.deferred) > 0 {
+		select {
+		case <-time.After(r.leaser.Interval()):
+		case <-ctx.Done():
+			return false
+		}
+
+		if !r.runBatch(ctx, cursor, nil) {
+			return false
+		}
+	}
+	models.MarkDrained(ctx)
+	return true
+}
+
+// track renews the leases of the leased items until they finish, and marks each of them finished as it completes
+// Items completing after the job was cancelled are released instead, so that another replica takes them over
+func (r *leasedRun) track(ctx context.Context, leased []uuid.UUID, completions <-chan itemCompletion) <-chan itemCompletion {
+	tracked := make(chan itemCompletion, len(leased))
+
+	go func() {
+		defer close(tracked)
+
+		unfinished := make(map[uuid.UUID]bool, len(leased))
+		for _, itemID := range leased {
+			unfinished[itemID] = true
+		}
+
+		ticker := time.NewTicker(r.leaser.Interval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case completion, ok := <-completions:
+				if !ok {
+					return
+				}
+
+				itemID := completion.outcome.ItemID
+				delete(unfinished, itemID)
+				if ctx.Err() != nil {
+					if err := r.leaser.Release(context.Background(), []uuid.UUID{itemID}); err != nil {
+						r.logger.Error("failed to release item lease", zap.Any("itemID", itemID), zap.Error(err))
+					}
+				} else if err := r.leaser.Finish(ctx, []uuid.UUID{itemID}); err != nil {
+					r.logger.Error("failed to finish item", zap.Any("itemID", itemID), zap.Error(err))
+				}
+
+				// Tracked completions are buffered to the number of leased items
+				tracked <- completion
+
+			case <-ticker.C:
+				if err := r.leaser.Renew(ctx, unfinishedItems(leased, unfinished)); err != nil {
+					r.logger.Error("failed to renew item leases", zap.Int("items", len(unfinished)), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return tracked
+}
@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// fakeLeaser holds the items of another replica until they are released
+type fakeLeaser struct {
+	mu       sync.Mutex
+	held     map[uuid.UUID]bool
+	finished []uuid.UUID
+}
+
+func (l *fakeLeaser) Lease(ctx context.Context, itemIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var leased, held []uuid.UUID
+	for _, itemID := range itemIDs {
+		if l.held[itemID] {
+			held = append(held, itemID)
+		} else {
+			leased = append(leased, itemID)
+		}
+	}
+	return leased, held, nil
+}
+
+func (l *fakeLeaser) Renew(ctx context.Context, itemIDs []uuid.UUID) error   { return nil }
+func (l *fakeLeaser) Release(ctx context.Context, itemIDs []uuid.UUID) error { return nil }
+func (l *fakeLeaser) Interval() time.Duration                                { return time.Millisecond }
+
+func (l *fakeLeaser) Finish(ctx context.Context, itemIDs []uuid.UUID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finished = append(l.finished, itemIDs...)
+	return nil
+}
+
+func (l *fakeLeaser) expire(itemID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.held, itemID)
+}
+
+func succeedBatch(ctx context.Context, batch []uuid.UUID) <-chan itemCompletion {
+	completions := make(chan itemCompletion, len(batch))
+	for index, itemID := range batch {
+		completions <- itemCompletion{index: index, outcome: newItemOutcome(itemID, false, 1, nil, 0)}
+	}
+	close(completions)
+	return completions
+}
+
+func TestLeasedRunTakesOverHeldItems(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	cursor := batch[len(batch)-1]
+
+	// The second item is held by another replica
+	leaser := &fakeLeaser{held: map[uuid.UUID]bool{batch[1]: true}}
+	updates := make(chan models.JobUpdate, 10)
+	run := newLeasedRun(leaser, updates, succeedBatch, log)
+
+	if !run.runBatch(context.Background(), &cursor, batch) {
+		t.Fatal("expected every update to be sent")
+	}
+
+	// The held item stays pending past the cursor, so that a resumed job leases it again
+	var last models.JobUpdate
+	for i := 0; i < 2; i++ {
+		last = <-updates
+	}
+	if !reflect.DeepEqual(last.NewCheckpoint.Pending, []uuid.UUID{batch[1]}) {
+		t.Fatalf("expected the held item to be pending, got %v", last.NewCheckpoint.Pending)
+	}
+
+	// The lease of the other replica expires, the item is taken over
+	leaser.expire(batch[1])
+	if !run.drain(context.Background(), &cursor) {
+		t.Fatal("expected the held item to be drained")
+	}
+
+	last = <-updates
+	if last.Completed != 1 || len(last.NewCheckpoint.Pending) != 0 {
+		t.Fatalf("unexpected update for the taken over item: %+v", last)
+	}
+
+	if len(leaser.finished) != len(batch) {
+		t.Fatalf("expected every item to be finished, got %d", len(leaser.finished))
+	}
+}
+
+func TestLeasedRunStopsTakingBatchesOncePaused(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobContext, ctx := models.NewJobContextForJob(models.NewJob(models.JobScope{}))
+	leaser := &fakeLeaser{}
+	updates := make(chan models.JobUpdate, 10)
+	run := newLeasedRun(leaser, updates, succeedBatch, log)
+
+	first := []uuid.UUID{uuid.New()}
+	if !run.runBatch(ctx, &first[0], first) {
+		t.Fatal("expected the batch to run before the pause")
+	}
+	<-updates
+
+	// The pause leaves the execution running, only the next batch is turned down
+	jobContext.RequestPause()
+	second := []uuid.UUID{uuid.New()}
+	if run.runBatch(ctx, &second[0], second) {
+		t.Fatal("expected the batch to be turned down once paused")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected the pause to leave the execution context running")
+	}
+	if len(updates) != 0 || len(leaser.finished) != len(first) {
+		t.Fatalf("expected only the batch before the pause to be processed, got %d finished", len(leaser.finished))
+	}
+}
+
+func TestLeasedRunMarksJobDrainedOnceItemsFinished(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobContext, ctx := models.NewJobContextForJob(models.NewJob(models.JobScope{}))
+	leaser := &fakeLeaser{}
+	updates := make(chan models.JobUpdate, 10)
+	run := newLeasedRun(leaser, updates, succeedBatch, log)
+
+	last := []uuid.UUID{uuid.New()}
+	if !run.runBatch(ctx, &last[0], last) {
+		t.Fatal("expected the last batch to run")
+	}
+	<-updates
+
+	// The pause comes in while the last batch is in flight, after which the listing ends
+	jobContext.RequestPause()
+	if jobContext.Drained() {
+		t.Fatal("expected the job not to be drained before the listing ended")
+	}
+	if !run.drain(ctx, &last[0]) {
+		t.Fatal("expected the drain to succeed without deferred items")
+	}
+	if !jobContext.Drained() {
+		t.Fatal("expected the job to be drained, so that it completes rather than pauses")
+	}
+}
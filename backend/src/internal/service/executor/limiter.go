@@ -0,0 +1,129 @@
+// ./src/internal/service/executor/limiter.go
+package executor
+
+import (
+	"context"
+	"sync"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// LimitReporter is implemented by the executors which adapt their limits as they run
+// The effective limits are reported in the state of the jobs they run
+type LimitReporter interface {
+	// Limits returns the limits the executor currently processes items within
+	Limits() models.JobLimits
+}
+
+// adaptiveLimiter limits the number of items processed at once, overall and per host
+// The overall limit is halved as upstream services rate limit or time out, and grows back by one after a full window of successes
+type adaptiveLimiter struct {
+	mutex sync.Mutex
+
+	// limit is the number of items currently processed at once
+	limit int
+	// minLimit and maxLimit bound the limit as it adapts
+	minLimit int
+	maxLimit int
+	// perHost is the number of items of the same host processed at once, zero when unlimited
+	perHost int
+
+	// inFlight is the number of items being processed
+	inFlight int
+	// hosts is the number of items being processed per host
+	hosts map[string]int
+	// successes is the number of successes since the limit last changed
+	successes int
+
+	// released is closed and replaced whenever a slot is released, waking up the waiting items
+	released chan struct{}
+}
+
+func newAdaptiveLimiter(maxLimit, minLimit, perHost int) *adaptiveLimiter {
+	maxLimit = max(1, maxLimit)
+	minLimit = min(max(1, minLimit), maxLimit)
+
+	return &adaptiveLimiter{
+		limit:    maxLimit,
+		minLimit: minLimit,
+		maxLimit: maxLimit,
+		perHost:  max(0, perHost),
+		hosts:    make(map[string]int),
+		released: make(chan struct{}),
+	}
+}
+
+// Acquire waits for a slot to process an item of the host, items without a host are only bound by the overall limit
+func (l *adaptiveLimiter) Acquire(ctx context.Context, host string) error {
+	for {
+		l.mutex.Lock()
+		if l.inFlight < l.limit && (host == "" || l.perHost == 0 || l.hosts[host] < l.perHost) {
+			l.inFlight++
+			if host != "" {
+				l.hosts[host]++
+			}
+			l.mutex.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release releases the slot of an item of the host, adapting the limit to the error the item ended with
+// Items whose context is done, as their batch ran out of time or their job was cancelled, leave the limit as is
+// Their errors stem from the executor rather than from an upstream service pushing back
+func (l *adaptiveLimiter) Release(ctx context.Context, host string, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.inFlight--
+	if host != "" {
+		if l.hosts[host]--; l.hosts[host] <= 0 {
+			delete(l.hosts, host)
+		}
+	}
+
+	switch {
+	case err == nil:
+		l.successes++
+		if l.successes >= l.limit && l.limit < l.maxLimit {
+			l.limit++
+			l.successes = 0
+		}
+	case ctx.Err() != nil:
+	case isBackoffError(err):
+		l.limit = max(l.minLimit, l.limit/2)
+		l.successes = 0
+	}
+
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+func (l *adaptiveLimiter) Limits() models.JobLimits {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return models.JobLimits{
+		Concurrency:    l.limit,
+		MaxConcurrency: l.maxLimit,
+		PerHost:        l.perHost,
+	}
+}
+
+// isBackoffError returns true if the error shows an upstream service pushing back
+func isBackoffError(err error) bool {
+	switch classifyError(err) {
+	case models.JobErrorClassRateLimited, models.JobErrorClassTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+func TestAdaptiveLimiterBacksOffAndRecovers(t *testing.T) {
+	limiter := newAdaptiveLimiter(8, 2, 0)
+	ctx := context.Background()
+
+	// Rate limits halve the limit, down to its floor
+	for _, want := range []int{4, 2, 2} {
+		if err := limiter.Acquire(ctx, ""); err != nil {
+			t.Fatal(err)
+		}
+		limiter.Release(ctx, "", &models.UpstreamError{Service: "openai api", StatusCode: 429, Err: errors.New("too many requests")})
+		if got := limiter.Limits().Concurrency; got != want {
+			t.Fatalf("expected a limit of %d, got %d", want, got)
+		}
+	}
+
+	// Permanent errors leave the limit as is, a full window of successes grows it by one
+	limiter.Acquire(ctx, "")
+	limiter.Release(ctx, "", errors.New("page not found"))
+	for i := 0; i < 2; i++ {
+		limiter.Acquire(ctx, "")
+		limiter.Release(ctx, "", nil)
+	}
+	if got := limiter.Limits().Concurrency; got != 3 {
+		t.Fatalf("expected a limit of 3, got %d", got)
+	}
+
+	// Items of a batch which ran out of time leave the limit as is
+	expired, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	limiter.Acquire(ctx, "")
+	limiter.Release(expired, "", expired.Err())
+	if got := limiter.Limits().Concurrency; got != 3 {
+		t.Fatalf("expected the expired batch to leave the limit at 3, got %d", got)
+	}
+}
+
+func TestAdaptiveLimiterCapsHosts(t *testing.T) {
+	limiter := newAdaptiveLimiter(4, 1, 1)
+	ctx := context.Background()
+
+	if err := limiter.Acquire(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other hosts are processed alongside, the same host waits for its slot
+	if err := limiter.Acquire(ctx, "example.org"); err != nil {
+		t.Fatal(err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(waitCtx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the host to be capped, got %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- limiter.Acquire(ctx, "example.com") }()
+	limiter.Release(ctx, "example.com", nil)
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the host slot to be handed over once released")
+	}
+}
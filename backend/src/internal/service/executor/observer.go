@@ -4,12 +4,16 @@ package executor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/recorder"
 	"github.com/wizenheimer/byrd/src/internal/repository/workflow"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -30,26 +34,62 @@ type workflowObserver struct {
 	// this would be used to execute the jobs in the workflow in a background
 	jobExecutor JobExecutor
 
+	// webhookService notifies the webhooks of the workspaces of the items which failed in the jobs
+	webhookService webhook.WebhookService
+
 	// logger represents the logger for the workflow
 	logger *logger.Logger
 
 	// activeJobs represents the active jobs in the workflow
+	// These are the jobs whose lease is held by this replica
 	activeJobs sync.Map // map[uuid.UUID]*jobContext
+
+	// assistedJobs represents the jobs of other replicas this replica helps with
+	// Finished jobs are kept until they stop running, so that they aren't joined again
+	assistedJobs sync.Map // map[uuid.UUID]*jobContext
+
+	// preemptedJobs represents the active jobs handed over to other replicas, rather than cancelled
+	preemptedJobs sync.Map // map[uuid.UUID]bool
+
+	// pausedJobs represents the active jobs being paused, rather than cancelled
+	pausedJobs sync.Map // map[uuid.UUID]bool
+
+	// cluster represents the configuration of this replica among the replicas sharing the workflow
+	cluster models.ClusterConfig
+
+	// stopping is set once the observer is shutting down, its jobs are handed over rather than cancelled
+	stopping atomic.Bool
+
+	// watchOnce ensures the watch loop is started once
+	watchOnce sync.Once
+
+	// stopWatch stops the watch loop
+	stopWatch context.CancelFunc
+
+	// watchContext is the context of the watch loop
+	watchContext context.Context
 }
 
 func NewWorkflowObserver(
 	workflowType models.WorkflowType,
 	repository workflow.WorkflowRepository,
 	jobExecutor JobExecutor,
+	webhookService webhook.WebhookService,
+	cluster models.ClusterConfig,
 	logger *logger.Logger,
 	errorRecord *recorder.ErrorRecorder,
 ) (WorkflowObserver, error) {
+	watchContext, stopWatch := context.WithCancel(context.Background())
 
 	workflowObserver := &workflowObserver{
-		workflowType: workflowType,
-		repository:   repository,
-		jobExecutor:  jobExecutor,
-		errorRecord:  errorRecord,
+		workflowType:   workflowType,
+		repository:     repository,
+		jobExecutor:    jobExecutor,
+		webhookService: webhookService,
+		errorRecord:    errorRecord,
+		cluster:        cluster.Normalize(),
+		watchContext:   watchContext,
+		stopWatch:      stopWatch,
 		logger: logger.WithFields(
 			map[string]interface{}{
 				"module": "workflow_executor",
@@ -59,79 +99,414 @@ func NewWorkflowObserver(
 	return workflowObserver, nil
 }
 
+// Recover takes over the running jobs whose replica is gone, and joins the running jobs of other replicas
+// Paused jobs are left paused, along with their checkpoint, until they are resumed
+// It then keeps watching the running jobs of the workflow in the background
 func (e *workflowObserver) Recover(ctx context.Context) error {
-	// List the workflows from the repository
+	if err := e.reconcile(ctx); err != nil {
+		e.errorRecord.RecordError(ctx, err, zap.Any("workflow_type", e.workflowType))
+		return err
+	}
+
+	e.watchOnce.Do(func() {
+		go e.watch(e.watchContext)
+	})
+
+	return nil
+}
+
+// jobLeaseName returns the name of the lease held by the replica in charge of the job
+func (e *workflowObserver) jobLeaseName(jobID uuid.UUID) string {
+	return fmt.Sprintf("job:%s:%s", e.workflowType, jobID)
+}
+
+// watch reconciles the running jobs of the workflow at every poll interval
+func (e *workflowObserver) watch(ctx context.Context) {
+	ticker := time.NewTicker(e.cluster.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.reconcile(ctx); err != nil {
+				e.logger.Error("failed to reconcile running jobs", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reconcile goes through the running jobs of the workflow
+// Leases of the jobs run by this replica are renewed, jobs without a replica are taken over, and the remaining ones are joined
+func (e *workflowObserver) reconcile(ctx context.Context) error {
 	jobs, err := e.repository.ListActiveJobs(ctx, e.workflowType)
 	if err != nil {
-		e.errorRecord.RecordError(ctx, err, zap.Any("workflow_type", e.workflowType))
 		return err
 	}
 
-	// Recover the workflows
+	running := make(map[uuid.UUID]bool, len(jobs))
 	for _, job := range jobs {
-		jobContext, executionContext := models.NewJobContextForJob(&job)
-		e.activeJobs.Store(job.JobID, jobContext)
-		go e.executeJob(executionContext, jobContext)
+		running[job.JobID] = true
+		e.reconcileJob(ctx, job)
 	}
 
+	// Stop helping with the jobs which are no longer running
+	e.assistedJobs.Range(func(key, value interface{}) bool {
+		jobID, ok := key.(uuid.UUID)
+		if !ok || running[jobID] {
+			return true
+		}
+		if jc, ok := value.(*models.JobContext); ok {
+			jc.HandleCancellation()
+		}
+		e.assistedJobs.Delete(jobID)
+		return true
+	})
+
 	return nil
 }
 
-func (e *workflowObserver) Submit(ctx context.Context) (uuid.UUID, error) {
-	// Create a new job
-	job := models.NewJob()
+func (e *workflowObserver) reconcileJob(ctx context.Context, job models.Job) {
+	cancelRequested, err := e.repository.CancelRequested(ctx, job.JobID, e.workflowType)
+	if err != nil {
+		e.logger.Error("failed to check job cancellation", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+
+	pauseRequested, err := e.repository.PauseRequested(ctx, job.JobID, e.workflowType)
+	if err != nil {
+		e.logger.Error("failed to check job pause", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+
+	// The lease of a job run by this replica is renewed, a job whose lease was taken over is handed over
+	if value, ok := e.activeJobs.Load(job.JobID); ok {
+		jc, ok := value.(*models.JobContext)
+		if !ok {
+			e.logger.Error("cannot parse job context")
+			return
+		}
+
+		held, err := e.repository.AcquireLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID, e.cluster.LeaseTTL)
+		switch {
+		case err != nil:
+			e.logger.Error("failed to renew job lease", zap.Any("jobID", job.JobID), zap.Error(err))
+		case !held:
+			e.logger.Warn("job lease was taken over by another replica", zap.Any("jobID", job.JobID))
+			e.preemptedJobs.Store(job.JobID, true)
+			jc.HandleCancellation()
+		case cancelRequested:
+			jc.HandleCancellation()
+		case pauseRequested:
+			e.pausedJobs.Store(job.JobID, true)
+			jc.RequestPause()
+		}
+		return
+	}
+
+	// A job whose lease is free has no replica in charge of it, it is taken over
+	acquired, err := e.repository.AcquireLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID, e.cluster.LeaseTTL)
+	if err != nil {
+		e.logger.Error("failed to acquire job lease", zap.Any("jobID", job.JobID), zap.Error(err))
+		return
+	}
+
+	value, assisting := e.assistedJobs.Load(job.JobID)
+	if acquired {
+		// Items leased by the helper are released as it stops, and picked up again by the job
+		if assisting {
+			if jc, ok := value.(*models.JobContext); ok {
+				jc.HandleCancellation()
+			}
+			e.assistedJobs.Delete(job.JobID)
+		}
+
+		if cancelRequested {
+			e.cancelOrphanedJob(ctx, job)
+			return
+		}
+
+		if pauseRequested {
+			e.pauseOrphanedJob(ctx, job)
+			return
+		}
+
+		e.takeOverJob(ctx, job)
+		return
+	}
+
+	if cancelRequested || pauseRequested {
+		if assisting {
+			if jc, ok := value.(*models.JobContext); ok {
+				// The items being processed by the helper of a paused job finish, it only stops taking new batches
+				if cancelRequested {
+					jc.HandleCancellation()
+				} else {
+					jc.RequestPause()
+				}
+			}
+		}
+		return
+	}
+
+	// Help the replica in charge of the job with its items
+	if !assisting && !e.stopping.Load() {
+		jobContext, executionContext := models.NewJobContextForJob(&job)
+		if _, loaded := e.assistedJobs.LoadOrStore(job.JobID, jobContext); !loaded {
+			e.logger.Debug("joining job of another replica", zap.Any("jobID", job.JobID))
+			go e.assistJob(executionContext, jobContext)
+		}
+	}
+}
+
+// takeOverJob resumes a job left behind by another replica, from its last checkpoint
+func (e *workflowObserver) takeOverJob(ctx context.Context, job models.Job) {
+	if e.stopping.Load() {
+		if err := e.repository.ReleaseLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID); err != nil {
+			e.logger.Error("failed to release job lease", zap.Any("jobID", job.JobID), zap.Error(err))
+		}
+		return
+	}
+
+	if err := e.repository.IncrementPreemptions(ctx, job.JobID); err != nil {
+		e.logger.Warn("failed to record job preemption", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+
+	e.logger.Info("taking over job", zap.Any("jobID", job.JobID), zap.Any("checkpoint", job.Checkpoint))
+
+	jobContext, executionContext := models.NewJobContextForJob(&job)
+	e.activeJobs.Store(job.JobID, jobContext)
+	go e.executeJob(executionContext, jobContext)
+}
+
+// cancelOrphanedJob cancels a job whose cancellation was requested after its replica was gone
+func (e *workflowObserver) cancelOrphanedJob(ctx context.Context, job models.Job) {
+	job.Status = models.JobStatusAborted
+	if err := e.repository.CancelJob(ctx, job.JobID, &job.JobState, e.workflowType); err != nil {
+		e.logger.Error("failed to persist job cancellation", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+
+	if err := e.repository.ReleaseLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID); err != nil {
+		e.logger.Error("failed to release job lease", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+}
+
+// pauseOrphanedJob pauses a job whose pause was requested after its replica was gone
+func (e *workflowObserver) pauseOrphanedJob(ctx context.Context, job models.Job) {
+	if err := e.repository.PauseJob(ctx, job.JobID, &job.JobState, e.workflowType); err != nil {
+		e.logger.Error("failed to persist job pause", zap.Any("jobID", job.JobID), zap.Error(err))
+	} else {
+		e.publishStateEvent(ctx, job.JobID, models.JobEventPaused)
+	}
+
+	if err := e.repository.ReleaseLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID); err != nil {
+		e.logger.Error("failed to release job lease", zap.Any("jobID", job.JobID), zap.Error(err))
+	}
+}
+
+func (e *workflowObserver) Submit(ctx context.Context, scope models.JobScope) (uuid.UUID, error) {
+	// Create a new job, limited to the items within the scope
+	job := models.NewJob(scope)
+	if err := e.submit(ctx, job); err != nil {
+		return uuid.Nil, err
+	}
+
+	return job.JobID, nil
+}
+
+func (e *workflowObserver) SubmitJob(ctx context.Context, jobID uuid.UUID, scope models.JobScope) error {
+	// The job was submitted already if it was recorded
+	_, err := e.repository.GetRecord(ctx, jobID, e.workflowType)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, workflow.ErrJobNotFound) {
+		return err
+	}
+
+	job := models.NewJob(scope)
+	job.JobID = jobID
+	return e.submit(ctx, job)
+}
+
+// submit starts the job, and executes it in the background
+func (e *workflowObserver) submit(ctx context.Context, job *models.Job) error {
+	scope := job.Scope
 
 	// Create a new job context
 	jobContext, executionContext := models.NewJobContextForJob(job)
 
-	// Start the job in the repository
-	if err := e.repository.StartJob(ctx, job.JobID, e.workflowType); err != nil {
+	// Lease the job before starting it, so that other replicas join it rather than take it over
+	if _, err := e.repository.AcquireLease(ctx, e.jobLeaseName(job.JobID), e.cluster.ReplicaID, e.cluster.LeaseTTL); err != nil {
 		e.errorRecord.RecordError(ctx, err, zap.Any("workflowType", e.workflowType))
-		return uuid.Nil, err
+		return err
 	}
 
-	// Store the job context in the active jobs
+	// Store the job context in the active jobs, before the job becomes visible to the watch loop
 	e.activeJobs.Store(job.JobID, jobContext)
 
+	// Start the job in the repository
+	if err := e.repository.StartJob(ctx, job.JobID, scope, e.workflowType); err != nil {
+		e.activeJobs.Delete(job.JobID)
+		e.errorRecord.RecordError(ctx, err, zap.Any("workflowType", e.workflowType))
+		return err
+	}
+
 	// Start the job execution
 	go e.executeJob(executionContext, jobContext)
 
-	return job.JobID, nil
+	return nil
 }
 
 func (e *workflowObserver) executeJob(executionContext context.Context, jobContext *models.JobContext) {
+	// Count the items of the job, so that its progress can be followed against them
+	e.countItems(executionContext, jobContext)
+
 	// Execute the job
-	jobUpdateCh, jobErrorCh := e.jobExecutor.Execute(executionContext, jobContext.JobState)
+	leaser := NewJobLeaser(e.repository, jobContext.JobID, e.workflowType, e.cluster)
+	jobUpdateCh, jobErrorCh := e.jobExecutor.Execute(executionContext, jobContext.GetJobState(), leaser)
 
-	// Wait for the job to complete
-	for {
+	// Wait for the job to complete, the executor closes the errors before the last update is received
+	// The job ends once both are drained, so that neither its last update nor its last error is lost
+	for jobUpdateCh != nil || jobErrorCh != nil {
 		select {
 		case <-executionContext.Done():
 			e.handleJobCancellation(jobContext)
 			return
 		case jobUpdate, ok := <-jobUpdateCh:
 			if !ok {
-				e.handleJobCompletion(executionContext, jobContext)
-				return
+				jobUpdateCh = nil
+				continue
 			}
 			e.handleJobUpdate(executionContext, jobContext, jobUpdate)
 		case jobError, ok := <-jobErrorCh:
 			if !ok {
-				e.handleJobCompletion(executionContext, jobContext)
-				return
+				jobErrorCh = nil
+				continue
+			}
+			e.handleJobError(jobContext, &jobError)
+		}
+	}
+	e.handleJobEnd(executionContext, jobContext)
+}
+
+// handleJobEnd handles the job once the executor stopped
+// The executor also stops as the job is cancelled or paused, in which case the job didn't complete
+// A paused job stops once the items being processed finished, its checkpoint is then persisted
+// A job which ran into errors of its own, such as failing to list its items, fails rather than completes
+func (e *workflowObserver) handleJobEnd(executionContext context.Context, jobContext *models.JobContext) {
+	if executionContext.Err() != nil {
+		e.handleJobCancellation(jobContext)
+		return
+	}
+	// A job drained before the pause took effect has nothing left to resume, it completes instead
+	if models.PauseRequested(executionContext) && !jobContext.Drained() {
+		if _, paused := e.pausedJobs.LoadAndDelete(jobContext.JobID); paused {
+			e.handleJobPause(jobContext)
+			return
+		}
+	}
+	if len(jobContext.Errors()) > 0 {
+		e.handleJobFailure(executionContext, jobContext)
+		return
+	}
+	e.handleJobCompletion(executionContext, jobContext)
+}
+
+// assistJob processes the items of a job run by another replica
+// The replica in charge of the job keeps its state, the helper only contributes to its progress and outcomes
+func (e *workflowObserver) assistJob(executionContext context.Context, jobContext *models.JobContext) {
+	leaser := NewJobLeaser(e.repository, jobContext.JobID, e.workflowType, e.cluster)
+	jobUpdateCh, jobErrorCh := e.jobExecutor.Execute(executionContext, jobContext.GetJobState(), leaser)
+
+	for jobUpdateCh != nil || jobErrorCh != nil {
+		select {
+		case <-executionContext.Done():
+			return
+		case jobUpdate, ok := <-jobUpdateCh:
+			if !ok {
+				jobUpdateCh = nil
+				continue
+			}
+			e.recordOutcomes(executionContext, jobContext.JobID, jobUpdate.Outcomes)
+			jobContext.HandleUpdate(&jobUpdate)
+
+			// The checkpoint of the job is kept by the replica in charge of it, the helper only sends its progress
+			state := jobContext.GetJobState()
+			e.addProgress(executionContext, jobContext.JobID, jobUpdate, &state)
+			event := models.NewJobEvent(jobContext.JobID, e.workflowType, models.JobEventProgress, state)
+			event.Errors = outcomeErrors(jobUpdate.Outcomes)
+			e.publishEvent(executionContext, event)
+		case jobError, ok := <-jobErrorCh:
+			if !ok {
+				jobErrorCh = nil
+				continue
 			}
 			e.handleJobError(jobContext, &jobError)
 		}
 	}
+	jobContext.HandleCompletion()
+}
+
+// countItems sets the number of items of the job, unless it was counted by the replica which started it
+// Failing to count the items doesn't interrupt the job, its progress is then sent without an ETA
+func (e *workflowObserver) countItems(ctx context.Context, jobContext *models.JobContext) {
+	state := jobContext.GetJobState()
+	if state.Total > 0 {
+		return
+	}
+
+	total, err := e.jobExecutor.CountItems(ctx, state.Scope)
+	if err != nil {
+		e.logger.Error("failed to count job items", zap.Any("jobID", jobContext.JobID), zap.Error(err))
+		return
+	}
+
+	jobContext.SetTotal(total)
+	if err := e.repository.SetState(ctx, jobContext.JobID, e.workflowType, jobContext.GetJobState()); err != nil {
+		e.logger.Error("failed to persist job state", zap.Error(err))
+	}
 }
 
 func (e *workflowObserver) handleJobCancellation(jobContext *models.JobContext) {
+	// A job handed over to another replica keeps running, only its lease is released
+	_, preempted := e.preemptedJobs.LoadAndDelete(jobContext.JobID)
+	_, paused := e.pausedJobs.LoadAndDelete(jobContext.JobID)
+	if paused && !preempted {
+		e.handleJobPause(jobContext)
+		return
+	}
+
 	jobContext.HandleCancellation()
+	if !preempted && !e.stopping.Load() {
+		// Refresh remote state
+		if err := e.repository.CancelJob(context.Background(), jobContext.JobID, &jobContext.JobState, e.workflowType); err != nil {
+			e.logger.Error("failed to persist job cancellation", zap.Error(err))
+		}
+		e.publishStateEvent(context.Background(), jobContext.JobID, models.JobEventAborted)
+	}
 
-	// Refresh remote state
-	if err := e.repository.CancelJob(context.Background(), jobContext.JobID, &jobContext.JobState, e.workflowType); err != nil {
-		e.logger.Error("failed to persist job cancellation", zap.Error(err))
-		return
+	if err := e.repository.ReleaseLease(context.Background(), e.jobLeaseName(jobContext.JobID), e.cluster.ReplicaID); err != nil {
+		e.logger.Error("failed to release job lease", zap.Any("jobID", jobContext.JobID), zap.Error(err))
+	}
+
+	// Refresh local state
+	e.activeJobs.Delete(jobContext.JobID)
+}
+
+// handleJobPause persists the checkpoint of the paused job, and releases it until it's resumed
+// The execution of the job is stopped, along with the listing of its items
+func (e *workflowObserver) handleJobPause(jobContext *models.JobContext) {
+	jobContext.HandlePause()
+
+	state := jobContext.GetJobState()
+	if err := e.repository.PauseJob(context.Background(), jobContext.JobID, &state, e.workflowType); err != nil {
+		e.logger.Error("failed to persist job pause", zap.Any("jobID", jobContext.JobID), zap.Error(err))
+	} else {
+		e.publishStateEvent(context.Background(), jobContext.JobID, models.JobEventPaused)
+	}
+
+	if err := e.repository.ReleaseLease(context.Background(), e.jobLeaseName(jobContext.JobID), e.cluster.ReplicaID); err != nil {
+		e.logger.Error("failed to release job lease", zap.Any("jobID", jobContext.JobID), zap.Error(err))
 	}
 
 	// Refresh local state
@@ -139,74 +514,165 @@ func (e *workflowObserver) handleJobCancellation(jobContext *models.JobContext)
 }
 
 func (e *workflowObserver) handleJobCompletion(ctx context.Context, jobContext *models.JobContext) {
-	// Handle job completion
 	jobContext.HandleCompletion()
+	e.finishJob(ctx, jobContext, models.JobEventCompleted)
+}
+
+// handleJobFailure ends the job as failed, as it ran into errors of its own
+func (e *workflowObserver) handleJobFailure(ctx context.Context, jobContext *models.JobContext) {
+	jobContext.HandleFailure()
+	e.finishJob(ctx, jobContext, models.JobEventFailed)
+}
+
+// finishJob persists the final state of the job which ended, and notifies the webhooks of its failures at once
+func (e *workflowObserver) finishJob(ctx context.Context, jobContext *models.JobContext, eventType models.JobEventType) {
+	// A pause requested meanwhile has nothing left to pause
+	e.pausedJobs.Delete(jobContext.JobID)
 
 	// Refresh remote state
-	if err := e.repository.CompleteJob(ctx, jobContext.JobID, &jobContext.JobState, e.workflowType); err != nil {
+	state := jobContext.GetJobState()
+	if err := e.repository.CompleteJob(ctx, jobContext.JobID, &state, e.workflowType); err != nil {
 		e.errorRecord.RecordError(ctx, err, zap.Any("JobID", jobContext.JobID))
 		return
 	}
+	e.publishStateEvent(ctx, jobContext.JobID, eventType)
+	go e.publishJobFailures(context.Background(), jobContext.JobID, state.Scope, jobContext.Errors())
+
+	if err := e.repository.ReleaseLease(ctx, e.jobLeaseName(jobContext.JobID), e.cluster.ReplicaID); err != nil {
+		e.logger.Error("failed to release job lease", zap.Any("jobID", jobContext.JobID), zap.Error(err))
+	}
 
 	// Refresh local state
 	e.activeJobs.Delete(jobContext.JobID)
 }
 
 func (e *workflowObserver) handleJobError(jobContext *models.JobContext, jobError *models.JobError) {
-	jobContext.IncrementFailed(1)
+	jobContext.HandleError(jobError)
 	e.logger.Error("encountered error during job execution", zap.Any("jobID", jobContext.JobID), zap.Error(jobError.Error), zap.Any("jobCheckpoint", jobContext.Checkpoint))
+
+	event := models.NewJobEvent(jobContext.JobID, e.workflowType, models.JobEventError, jobContext.GetJobState())
+	event.Errors = []models.JobItemError{{Error: jobError.Error.Error()}}
+	e.publishEvent(context.Background(), event)
 }
 
 func (e *workflowObserver) handleJobUpdate(ctx context.Context, jobContext *models.JobContext, jobUpdate models.JobUpdate) {
-	if err := e.repository.SetState(ctx, jobContext.JobID, e.workflowType, jobContext.JobState); err != nil {
+	// Outcomes are recorded before the checkpoint moves past the items
+	e.recordOutcomes(ctx, jobContext.JobID, jobUpdate.Outcomes)
+
+	// Persist the state including the update, so that a resumed job doesn't process the items again
+	jobContext.HandleUpdate(&jobUpdate)
+	if reporter, ok := e.jobExecutor.(LimitReporter); ok {
+		jobContext.SetLimits(reporter.Limits())
+	}
+	if err := e.repository.SetState(ctx, jobContext.JobID, e.workflowType, jobContext.GetJobState()); err != nil {
 		e.logger.Error("failed to persist job state", zap.Error(err))
+	}
+
+	state := jobContext.GetJobState()
+	e.addProgress(ctx, jobContext.JobID, jobUpdate, &state)
+	event := models.NewJobEvent(jobContext.JobID, e.workflowType, models.JobEventProgress, state)
+	event.Checkpoint = &state.Checkpoint
+	event.Errors = outcomeErrors(jobUpdate.Outcomes)
+	e.publishEvent(ctx, event)
+}
+
+// addProgress adds the items of the update to the progress shared by the replicas running the job
+// The state is updated with the progress of the job across the replicas
+func (e *workflowObserver) addProgress(ctx context.Context, jobID uuid.UUID, jobUpdate models.JobUpdate, state *models.JobState) {
+	if jobUpdate.Completed == 0 && jobUpdate.Failed == 0 && jobUpdate.Skipped == 0 {
 		return
 	}
 
-	jobContext.HandleUpdate(&jobUpdate)
+	progress, err := e.repository.AddProgress(ctx, jobID, e.workflowType, jobUpdate.Completed, jobUpdate.Failed, jobUpdate.Skipped)
+	if err != nil {
+		e.logger.Error("failed to add job progress", zap.Any("jobID", jobID), zap.Error(err))
+		return
+	}
+
+	state.Completed = progress.Completed
+	state.Failed = progress.Failed
+	state.Skipped = progress.Skipped
 }
 
-func (e *workflowObserver) Status(ctx context.Context, jobID uuid.UUID) (*models.JobStatus, error) {
-	// Get the job from the active jobs
-	jobContext, ok := e.activeJobs.Load(jobID)
-	if !ok {
-		return nil, errors.New("job not found")
+// publishEvent publishes the event to the clients following the job
+// Failing to publish an event doesn't interrupt the job
+func (e *workflowObserver) publishEvent(ctx context.Context, event models.JobEvent) {
+	if err := e.repository.PublishEvent(ctx, event); err != nil {
+		e.logger.Error("failed to publish job event", zap.Any("jobID", event.JobID), zap.Any("type", event.Type), zap.Error(err))
 	}
+}
 
-	jc, ok := jobContext.(*models.JobContext)
-	if !ok {
-		return nil, errors.New("cannot parse job context")
+// publishStateEvent publishes an event with the stored state of the job, including the progress of every replica running it
+func (e *workflowObserver) publishStateEvent(ctx context.Context, jobID uuid.UUID, eventType models.JobEventType) {
+	state, err := e.repository.GetState(ctx, jobID, e.workflowType)
+	if err != nil {
+		e.logger.Error("failed to get job state", zap.Any("jobID", jobID), zap.Error(err))
+		return
+	}
+
+	event := models.NewJobEvent(jobID, e.workflowType, eventType, state)
+	event.Checkpoint = &state.Checkpoint
+	e.publishEvent(ctx, event)
+}
+
+// outcomeErrors returns the errors of the failed outcomes
+func outcomeErrors(outcomes []models.JobItemOutcome) []models.JobItemError {
+	var itemErrors []models.JobItemError
+	for _, outcome := range outcomes {
+		if outcome.Status != models.JobItemStatusFailed {
+			continue
+		}
+		itemID := outcome.ItemID
+		itemErrors = append(itemErrors, models.JobItemError{ItemID: &itemID, Error: outcome.Error})
 	}
+	return itemErrors
+}
+
+func (e *workflowObserver) Status(ctx context.Context, jobID uuid.UUID) (*models.JobStatus, error) {
+	// Get the job across the replicas
+	job, err := e.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the job status
-	status := jc.GetStatus()
+	status := job.Status
 	return &status, nil
 }
 
 func (e *workflowObserver) State(ctx context.Context, jobID uuid.UUID) (*models.JobState, error) {
-	// Get the job from the active jobs
-	jobContext, ok := e.activeJobs.Load(jobID)
-	if !ok {
-		return nil, errors.New("job not found")
-	}
-
-	jc, ok := jobContext.(*models.JobContext)
-	if !ok {
-		return nil, errors.New("cannot parse job context")
+	// Get the job across the replicas
+	job, err := e.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get the job state
-	status, checkpoint := jc.GetState()
-	return &models.JobState{
-		Status:     status,
-		Checkpoint: checkpoint,
-	}, nil
+	return &job.JobState, nil
 }
 
 func (e *workflowObserver) Get(ctx context.Context, jobID uuid.UUID) (*models.Job, error) {
-	// Get the job from the active jobs
+	// Get the job from the repository, as it may be run by another replica
+	state, err := e.repository.GetState(ctx, jobID, e.workflowType)
+	if err == nil {
+		return &models.Job{
+			JobID:    jobID,
+			JobState: state,
+		}, nil
+	}
+
+	// Fall back to the active jobs
 	jobContext, ok := e.activeJobs.Load(jobID)
 	if !ok {
-		return nil, errors.New("job not found")
+		// The state of jobs which ended long ago has expired, their record holds their final state
+		record, err := e.repository.GetRecord(ctx, jobID, e.workflowType)
+		if err != nil {
+			return nil, errors.New("job not found")
+		}
+		return &models.Job{
+			JobID:    jobID,
+			JobState: record.JobState(),
+		}, nil
 	}
 
 	jc, ok := jobContext.(*models.JobContext)
@@ -214,7 +680,10 @@ func (e *workflowObserver) Get(ctx context.Context, jobID uuid.UUID) (*models.Jo
 		return nil, errors.New("cannot parse job context")
 	}
 	// Get the job
-	job := jc.Job
+	job := models.Job{
+		JobID:    jc.JobID,
+		JobState: jc.GetJobState(),
+	}
 	return &job, nil
 }
 
@@ -222,29 +691,120 @@ func (e *workflowObserver) Cancel(ctx context.Context, jobID uuid.UUID) error {
 	// Get the job from the active jobs
 	jobContext, ok := e.activeJobs.Load(jobID)
 	if !ok {
-		return errors.New("job not found")
+		// The job may be run by another replica, which picks up the request as it renews the lease of the job
+		state, err := e.repository.GetState(ctx, jobID, e.workflowType)
+		if err != nil {
+			return errors.New("job not found")
+		}
+		switch state.Status {
+		case models.JobStatusRunning:
+			return e.repository.RequestCancel(ctx, jobID, e.workflowType)
+		case models.JobStatusPaused:
+			// No replica runs a paused job, it's cancelled right away
+			state.Status = models.JobStatusAborted
+			if err := e.repository.CancelJob(ctx, jobID, &state, e.workflowType); err != nil {
+				return err
+			}
+			e.publishStateEvent(ctx, jobID, models.JobEventAborted)
+			return nil
+		default:
+			return fmt.Errorf("job is not running, its status is %s", state.Status)
+		}
 	}
 
 	jc, ok := jobContext.(*models.JobContext)
 	if !ok {
 		return errors.New("cannot parse job context")
 	}
-	// Cancel the job
+	// Cancel the job, even if its pause was requested
+	e.pausedJobs.Delete(jobID)
 	jc.HandleCancellation()
 	e.activeJobs.Delete(jobID)
 	return nil
 }
 
+func (e *workflowObserver) Pause(ctx context.Context, jobID uuid.UUID) error {
+	// Get the job from the active jobs
+	jobContext, ok := e.activeJobs.Load(jobID)
+	if !ok {
+		// The job may be run by another replica, which picks up the request as it renews the lease of the job
+		state, err := e.repository.GetState(ctx, jobID, e.workflowType)
+		if err != nil {
+			return errors.New("job not found")
+		}
+		if state.Status != models.JobStatusRunning {
+			return fmt.Errorf("job is not running, its status is %s", state.Status)
+		}
+		return e.repository.RequestPause(ctx, jobID, e.workflowType)
+	}
+
+	jc, ok := jobContext.(*models.JobContext)
+	if !ok {
+		return errors.New("cannot parse job context")
+	}
+	// Pause the job, its checkpoint is persisted once the items being processed finished and the executor stopped
+	e.pausedJobs.Store(jobID, true)
+	jc.RequestPause()
+	return nil
+}
+
+func (e *workflowObserver) Resume(ctx context.Context, jobID uuid.UUID) error {
+	if e.stopping.Load() {
+		return errors.New("workflow is shutting down")
+	}
+
+	// Lease the job before resuming it, so that other replicas join it rather than take it over
+	acquired, err := e.repository.AcquireLease(ctx, e.jobLeaseName(jobID), e.cluster.ReplicaID, e.cluster.LeaseTTL)
+	if err != nil {
+		e.errorRecord.RecordError(ctx, err, zap.Any("workflowType", e.workflowType))
+		return err
+	}
+	if !acquired {
+		return errors.New("job is held by another replica")
+	}
+
+	state, err := e.repository.ResumeJob(ctx, jobID, e.workflowType)
+	if err != nil {
+		if err := e.repository.ReleaseLease(ctx, e.jobLeaseName(jobID), e.cluster.ReplicaID); err != nil {
+			e.logger.Error("failed to release job lease", zap.Any("jobID", jobID), zap.Error(err))
+		}
+		return err
+	}
+
+	// Run the job again from its checkpoint
+	job := models.Job{
+		JobID:    jobID,
+		JobState: state,
+	}
+	jobContext, executionContext := models.NewJobContextForJob(&job)
+	e.activeJobs.Store(jobID, jobContext)
+	e.publishEvent(ctx, models.NewJobEvent(jobID, e.workflowType, models.JobEventResumed, state))
+
+	go e.executeJob(executionContext, jobContext)
+
+	return nil
+}
+
 func (e *workflowObserver) List(ctx context.Context, status models.JobStatus) ([]models.Job, error) {
+	// List the jobs from the repository, as they may be run by other replicas
+	jobs, err := e.repository.ListJobs(ctx, e.workflowType, status)
+	if err == nil {
+		return jobs, nil
+	}
+	e.logger.Error("failed to list jobs, falling back to the active jobs", zap.Error(err))
+
 	// List the jobs from the active jobs
-	var jobs []models.Job
+	jobs = nil
 	e.activeJobs.Range(func(key, value interface{}) bool {
 		jc, ok := value.(*models.JobContext)
 		if !ok {
 			e.logger.Error("cannot parse job context")
 			return false
 		}
-		job := jc.Job
+		job := models.Job{
+			JobID:    jc.JobID,
+			JobState: jc.GetJobState(),
+		}
 		if job.Status == status {
 			jobs = append(jobs, job)
 		}
@@ -254,7 +814,13 @@ func (e *workflowObserver) List(ctx context.Context, status models.JobStatus) ([
 	return jobs, nil
 }
 
+// Shutdown hands the active jobs over to the other replicas, and stops helping with theirs
+// Jobs are resumed from their checkpoint by another replica, or by this one once it restarts
 func (e *workflowObserver) Shutdown(ctx context.Context) error {
+	// Stop watching the running jobs
+	e.stopping.Store(true)
+	e.stopWatch()
+
 	// Iterate over the active jobs and cancel them
 	e.activeJobs.Range(func(key, value interface{}) bool {
 		jobContext, ok := value.(*models.JobContext)
@@ -266,6 +832,13 @@ func (e *workflowObserver) Shutdown(ctx context.Context) error {
 		return true
 	})
 
+	e.assistedJobs.Range(func(key, value interface{}) bool {
+		if jobContext, ok := value.(*models.JobContext); ok {
+			jobContext.HandleCancellation()
+		}
+		return true
+	})
+
 	// Shutdown the job executor
 	if err := e.jobExecutor.Terminate(ctx); err != nil {
 		e.logger.Error("failed to shutdown job executor", zap.Error(err))
@@ -275,9 +848,10 @@ func (e *workflowObserver) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-func (e *workflowObserver) History(ctx context.Context, limit, offset *int) ([]models.JobRecord, error) {
+func (e *workflowObserver) History(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error) {
 	// Get the history from the repository
-	jobRecords, err := e.repository.ListRecords(ctx, &e.workflowType, limit, offset)
+	filter.WorkflowType = &e.workflowType
+	jobRecords, err := e.repository.ListRecords(ctx, filter, limit, offset)
 	if err != nil {
 		e.logger.Error("failed to get history", zap.Error(err))
 		return nil, err
@@ -285,3 +859,193 @@ func (e *workflowObserver) History(ctx context.Context, limit, offset *int) ([]m
 
 	return jobRecords, nil
 }
+
+func (e *workflowObserver) Events(ctx context.Context, jobID *uuid.UUID) (<-chan models.JobEvent, error) {
+	// Subscribe to the events of the workflow, as its jobs may be run by other replicas
+	events, err := e.repository.SubscribeEvents(ctx, e.workflowType)
+	if err != nil {
+		e.logger.Error("failed to subscribe to job events", zap.Error(err))
+		return nil, err
+	}
+
+	if jobID == nil {
+		return events, nil
+	}
+
+	// Filter the events of the job
+	jobEvents := make(chan models.JobEvent)
+	go func() {
+		defer close(jobEvents)
+		for event := range events {
+			if event.JobID != *jobID {
+				continue
+			}
+			select {
+			case jobEvents <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return jobEvents, nil
+}
+
+func (e *workflowObserver) Record(ctx context.Context, jobID uuid.UUID) (*models.JobRecord, error) {
+	// Get the record from the repository
+	record, err := e.repository.GetRecord(ctx, jobID, e.workflowType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (e *workflowObserver) Failures(ctx context.Context, jobID uuid.UUID, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error) {
+	// Get the dead-letter list from the repository
+	failures, err := e.repository.ListFailures(ctx, jobID, e.workflowType, status, limit, offset)
+	if err != nil {
+		e.logger.Error("failed to list failures", zap.Any("jobID", jobID), zap.Error(err))
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+func (e *workflowObserver) RetryFailures(ctx context.Context, jobID uuid.UUID, itemIDs []uuid.UUID) ([]models.JobFailure, error) {
+	// The items are retried within the scope of the job, which its record outlives
+	record, err := e.repository.GetRecord(ctx, jobID, e.workflowType)
+	if err != nil {
+		e.logger.Error("failed to get job record", zap.Any("jobID", jobID), zap.Error(err))
+		return nil, err
+	}
+	scope := record.JobState().Scope
+
+	// Claim the failures, so that concurrent retries don't process the same items
+	failures, err := e.repository.ClaimFailures(ctx, jobID, e.workflowType, itemIDs)
+	if err != nil {
+		e.errorRecord.RecordError(ctx, err, zap.Any("jobID", jobID), zap.Any("workflowType", e.workflowType))
+		return nil, err
+	}
+
+	if len(failures) == 0 {
+		return nil, errors.New("no pending failures found")
+	}
+
+	// Retry the failures in the background, as it outlives the request
+	go e.retryFailures(context.Background(), jobID, scope, failures)
+
+	return failures, nil
+}
+
+// retryFailures processes the failed items again and records the outcome of the new attempt
+func (e *workflowObserver) retryFailures(ctx context.Context, jobID uuid.UUID, scope models.JobScope, failures []models.JobFailure) {
+	attempts := make(map[uuid.UUID]int, len(failures))
+	itemIDs := make([]uuid.UUID, 0, len(failures))
+	for _, failure := range failures {
+		attempts[failure.ItemID] = failure.Attempts
+		itemIDs = append(itemIDs, failure.ItemID)
+	}
+
+	var itemErrors []models.JobItemError
+	for outcome := range e.jobExecutor.Retry(ctx, scope, itemIDs) {
+		// The outcome counts the attempts made on this retry, on top of the previous ones
+		outcome.Attempt += attempts[outcome.ItemID]
+		e.recordOutcomes(ctx, jobID, []models.JobItemOutcome{outcome})
+		itemErrors = append(itemErrors, outcomeErrors([]models.JobItemOutcome{outcome})...)
+	}
+
+	// The items failing again are notified at once, as the failures of a job are
+	e.publishFailures(ctx, jobID, itemErrors, nil, nil)
+}
+
+// recordOutcomes persists the outcomes of the items processed by the job
+// Failing to record outcomes doesn't interrupt the job
+func (e *workflowObserver) recordOutcomes(ctx context.Context, jobID uuid.UUID, outcomes []models.JobItemOutcome) {
+	if len(outcomes) == 0 {
+		return
+	}
+
+	for i := range outcomes {
+		outcomes[i].JobID = jobID
+		outcomes[i].WorkflowType = e.workflowType
+	}
+
+	if err := e.repository.RecordOutcomes(ctx, outcomes); err != nil {
+		e.logger.Error("failed to record job outcomes", zap.Any("jobID", jobID), zap.Int("outcomes", len(outcomes)), zap.Error(err))
+	}
+}
+
+// publishJobFailures notifies the webhooks of the failures of the job once it ended
+// The failed items are listed from the dead-letter list, so that the items of every replica running the job are included
+func (e *workflowObserver) publishJobFailures(ctx context.Context, jobID uuid.UUID, scope models.JobScope, jobErrors []models.JobItemError) {
+	if e.webhookService == nil {
+		return
+	}
+
+	pending := models.JobFailureStatusPending
+	failures, err := e.repository.ListFailures(ctx, jobID, e.workflowType, &pending, nil, nil)
+	if err != nil {
+		e.logger.Error("failed to list job failures", zap.Any("jobID", jobID), zap.Error(err))
+		return
+	}
+
+	itemErrors := make([]models.JobItemError, 0, len(failures))
+	for _, failure := range failures {
+		itemID := failure.ItemID
+		itemErrors = append(itemErrors, models.JobItemError{ItemID: &itemID, Error: failure.LastError})
+	}
+
+	e.publishFailures(ctx, jobID, itemErrors, jobErrors, scope.WorkspaceIDs)
+}
+
+// publishFailures notifies the webhooks of the workspaces of the items which failed in the job, with a single event each
+// Each workspace is only sent the failures of its own items, the errors of the job itself are sent to the workspaces it's scoped to
+func (e *workflowObserver) publishFailures(ctx context.Context, jobID uuid.UUID, itemErrors, jobErrors []models.JobItemError, workspaceIDs []uuid.UUID) {
+	if e.webhookService == nil || (len(itemErrors) == 0 && len(jobErrors) == 0) {
+		return
+	}
+
+	itemIDs := make([]uuid.UUID, 0, len(itemErrors))
+	for _, itemError := range itemErrors {
+		itemIDs = append(itemIDs, *itemError.ItemID)
+	}
+
+	// Items are the workspaces themselves, unless the executor resolves their workspace
+	itemWorkspaceIDs := make(map[uuid.UUID]uuid.UUID, len(itemIDs))
+	if resolver, ok := e.jobExecutor.(WorkspaceResolver); ok && len(itemIDs) > 0 {
+		resolved, err := resolver.ItemWorkspaces(ctx, itemIDs)
+		if err != nil {
+			e.logger.Error("failed to resolve workspaces of failed items", zap.Any("jobID", jobID), zap.Error(err))
+			return
+		}
+		itemWorkspaceIDs = resolved
+	} else {
+		for _, itemID := range itemIDs {
+			itemWorkspaceIDs[itemID] = itemID
+		}
+	}
+
+	failures := make(map[uuid.UUID][]models.JobItemError)
+	for _, itemError := range itemErrors {
+		if workspaceID, ok := itemWorkspaceIDs[*itemError.ItemID]; ok {
+			failures[workspaceID] = append(failures[workspaceID], itemError)
+		}
+	}
+	if len(jobErrors) > 0 {
+		for _, workspaceID := range workspaceIDs {
+			failures[workspaceID] = append(failures[workspaceID], jobErrors...)
+		}
+	}
+
+	for workspaceID, workspaceFailures := range failures {
+		event := models.JobFailedEvent{
+			JobID:        jobID,
+			WorkflowType: e.workflowType,
+			Failures:     workspaceFailures,
+		}
+		if err := e.webhookService.Publish(ctx, workspaceID, models.WebhookEventJobFailed, event); err != nil {
+			e.logger.Error("failed to publish job failures", zap.Any("jobID", jobID), zap.Any("workspaceID", workspaceID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// recordingWebhookService records the events published to each workspace
+type recordingWebhookService struct {
+	webhook.WebhookService
+	mu     sync.Mutex
+	events map[uuid.UUID][]models.JobFailedEvent
+}
+
+func (s *recordingWebhookService) Publish(ctx context.Context, workspaceID uuid.UUID, eventType models.WebhookEventType, data any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[workspaceID] = append(s.events[workspaceID], data.(models.JobFailedEvent))
+	return nil
+}
+
+func TestPublishFailuresSendsOneEventPerWorkspace(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	webhookService := &recordingWebhookService{events: make(map[uuid.UUID][]models.JobFailedEvent)}
+	e := &workflowObserver{webhookService: webhookService, logger: log}
+
+	// The items of the executor are the workspaces themselves
+	failing, scoped := uuid.New(), uuid.New()
+	itemErrors := []models.JobItemError{
+		{ItemID: &failing, Error: "first attempt failed"},
+		{ItemID: &failing, Error: "second attempt failed"},
+	}
+	jobErrors := []models.JobItemError{{Error: "failed to list items"}}
+
+	e.publishFailures(context.Background(), uuid.New(), itemErrors, jobErrors, []uuid.UUID{failing, scoped})
+
+	if len(webhookService.events) != 2 {
+		t.Fatalf("expected events for 2 workspaces, got %d", len(webhookService.events))
+	}
+	if events := webhookService.events[failing]; len(events) != 1 || len(events[0].Failures) != 3 {
+		t.Errorf("expected a single event with the item and job errors, got %+v", events)
+	}
+	if events := webhookService.events[scoped]; len(events) != 1 || len(events[0].Failures) != 1 {
+		t.Errorf("expected a single event with the job errors, got %+v", events)
+	}
+}
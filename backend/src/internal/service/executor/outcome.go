@@ -0,0 +1,130 @@
+// ./src/internal/service/executor/outcome.go
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// itemCompletion is the outcome of processing a single item of a batch
+type itemCompletion struct {
+	// index is the index of the item in the batch
+	index int
+
+	// outcome is the outcome of processing the item
+	outcome models.JobItemOutcome
+}
+
+// batchProcessor processes a batch of items, emitting a completion for every item of the batch
+type batchProcessor func(ctx context.Context, batch []uuid.UUID) <-chan itemCompletion
+
+// scopedBatchProcessor processes a batch of items within the scope of the job
+type scopedBatchProcessor func(ctx context.Context, batch []uuid.UUID, scope models.JobScope) <-chan itemCompletion
+
+// withScope binds the batches processed to the scope of the job
+func withScope(processBatch scopedBatchProcessor, scope models.JobScope) batchProcessor {
+	return func(ctx context.Context, batch []uuid.UUID) <-chan itemCompletion {
+		return processBatch(ctx, batch, scope)
+	}
+}
+
+// newItemOutcome creates the outcome of processing an item after the given number of attempts
+// The job is filled in by the workflow observer, which is the one aware of it
+func newItemOutcome(itemID uuid.UUID, skipped bool, attempts int, err error, duration time.Duration) models.JobItemOutcome {
+	outcome := models.JobItemOutcome{
+		ItemID:   itemID,
+		Attempt:  attempts,
+		Status:   models.JobItemStatusSucceeded,
+		Duration: duration,
+		Time:     time.Now(),
+	}
+
+	switch {
+	case err != nil:
+		outcome.Status = models.JobItemStatusFailed
+		outcome.Error = err.Error()
+	case skipped:
+		outcome.Status = models.JobItemStatusSkipped
+	}
+
+	return outcome
+}
+
+// sendBatchUpdates sends an update as each item of the batch finishes
+// The checkpoint holds the cursor past the batch, the items of the batch which are yet to finish, and the pending items carried along
+// This way a resumed job processes the unfinished items of the batch before moving past the cursor
+// Returns false if the job was cancelled before every update was sent
+func sendBatchUpdates(ctx context.Context, updates chan<- models.JobUpdate, checkpoint models.JobCheckpoint, batch []uuid.UUID, completions <-chan itemCompletion) bool {
+	pending := make(map[uuid.UUID]bool, len(batch))
+	for _, itemID := range batch {
+		pending[itemID] = true
+	}
+
+	for completion := range completions {
+		outcome := completion.outcome
+		delete(pending, outcome.ItemID)
+
+		update := models.JobUpdate{
+			Time: time.Now(),
+			NewCheckpoint: models.JobCheckpoint{
+				BatchID: checkpoint.BatchID,
+				Pending: append(unfinishedItems(batch, pending), checkpoint.Pending...),
+			},
+			Outcomes: []models.JobItemOutcome{outcome},
+		}
+
+		switch outcome.Status {
+		case models.JobItemStatusFailed:
+			update.Failed = 1
+		case models.JobItemStatusSkipped:
+			update.Skipped = 1
+		default:
+			update.Completed = 1
+		}
+
+		select {
+		case updates <- update:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
+// unfinishedItems returns the items of the batch which are yet to finish, in the order of the batch
+func unfinishedItems(batch []uuid.UUID, pending map[uuid.UUID]bool) []uuid.UUID {
+	unfinished := make([]uuid.UUID, 0, len(pending))
+	for _, itemID := range batch {
+		if pending[itemID] {
+			unfinished = append(unfinished, itemID)
+		}
+	}
+	return unfinished
+}
+
+// retryItems processes the items again in batches of the given size, emitting the outcome of every item
+func retryItems(ctx context.Context, itemIDs []uuid.UUID, batchSize int, processBatch batchProcessor) <-chan models.JobItemOutcome {
+	outcomes := make(chan models.JobItemOutcome, len(itemIDs))
+
+	go func() {
+		defer close(outcomes)
+
+		batchSize = max(1, batchSize)
+		for start := 0; start < len(itemIDs); start += batchSize {
+			batch := itemIDs[start:min(start+batchSize, len(itemIDs))]
+			for completion := range processBatch(ctx, batch) {
+				outcomes <- completion.outcome
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	return outcomes
+}
@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+func TestSendBatchUpdatesTracksUnfinishedItems(t *testing.T) {
+	batch := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	cursor := batch[len(batch)-1]
+
+	// The last item finishes first, while the first one fails
+	completions := make(chan itemCompletion, len(batch))
+	completions <- itemCompletion{index: 2, outcome: newItemOutcome(batch[2], false, 1, nil, 0)}
+	completions <- itemCompletion{index: 0, outcome: newItemOutcome(batch[0], false, 3, errors.New("status code: 503"), 0)}
+	close(completions)
+
+	updates := make(chan models.JobUpdate, len(batch))
+	if !sendBatchUpdates(context.Background(), updates, models.JobCheckpoint{BatchID: &cursor}, batch, completions) {
+		t.Fatal("expected every update to be sent")
+	}
+	close(updates)
+
+	var received []models.JobUpdate
+	for update := range updates {
+		received = append(received, update)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("expected an update per finished item, got %d", len(received))
+	}
+
+	if received[0].Completed != 1 || !reflect.DeepEqual(received[0].NewCheckpoint.Pending, []uuid.UUID{batch[0], batch[1]}) {
+		t.Fatalf("unexpected first update: %+v", received[0])
+	}
+
+	// The item which never finished stays pending, so that a resumed job processes it
+	last := received[1]
+	if last.Failed != 1 || *last.NewCheckpoint.BatchID != cursor || !reflect.DeepEqual(last.NewCheckpoint.Pending, []uuid.UUID{batch[1]}) {
+		t.Fatalf("unexpected last update: %+v", last)
+	}
+}
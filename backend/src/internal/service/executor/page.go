@@ -4,6 +4,8 @@ package executor
 import (
 	"context"
 	"errors"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,8 +25,15 @@ type pageExecutor struct {
 
 	// runtimeConfig represents the runtime configuration for the workflow
 	runtimeConfig models.JobExecutorConfig
+
+	// limiter limits the pages processed at once, overall and per host, shared by the jobs of the executor
+	limiter *adaptiveLimiter
 }
 
+// compile time check if the interface is implemented
+var _ LimitReporter = (*pageExecutor)(nil)
+var _ WorkspaceResolver = (*pageExecutor)(nil)
+
 func NewPageExecutor(pageService page.PageService, runtimeConfig models.JobExecutorConfig, logger *logger.Logger) (JobExecutor, error) {
 	if logger == nil {
 		return nil, errors.New("logger is required")
@@ -33,13 +42,14 @@ func NewPageExecutor(pageService page.PageService, runtimeConfig models.JobExecu
 	pe := pageExecutor{
 		pageService:   pageService,
 		runtimeConfig: runtimeConfig,
+		limiter:       newAdaptiveLimiter(runtimeConfig.Parallelism, runtimeConfig.MinParallelism, runtimeConfig.MaxPerHost),
 		logger:        logger.WithFields(map[string]interface{}{"module": "page_executor"}),
 	}
 
 	return &pe, nil
 }
 
-func (pe *pageExecutor) Execute(executionContext context.Context, jobState models.JobState) (<-chan models.JobUpdate, <-chan models.JobError) {
+func (pe *pageExecutor) Execute(executionContext context.Context, jobState models.JobState, leaser ItemLeaser) (<-chan models.JobUpdate, <-chan models.JobError) {
 	updates := make(chan models.JobUpdate, 1)
 	errors := make(chan models.JobError, 1)
 
@@ -47,8 +57,17 @@ func (pe *pageExecutor) Execute(executionContext context.Context, jobState model
 		defer close(updates)
 		defer close(errors)
 
-		checkpoint := jobState.Checkpoint.BatchID
-		pageBatchChan, errBatchChan := pe.pageService.ListActivePages(executionContext, pe.runtimeConfig.Parallelism, checkpoint)
+		// Items are leased before being processed, as other replicas may be sharing the job
+		run := newLeasedRun(leaser, updates, pe.processBatch, pe.logger)
+
+		// Resume the unfinished items of the checkpoint before moving past its cursor
+		checkpoint := jobState.Checkpoint
+		cursor := checkpoint.BatchID
+		if !run.runBatch(executionContext, cursor, checkpoint.Pending) {
+			return
+		}
+
+		pageBatchChan, errBatchChan := pe.pageService.ListActivePages(executionContext, pe.runtimeConfig.Parallelism, checkpoint.BatchID, jobState.Scope)
 
 		batchStartTime := time.Now()
 
@@ -56,34 +75,15 @@ func (pe *pageExecutor) Execute(executionContext context.Context, jobState model
 			select {
 			case pageBatch, ok := <-pageBatchChan:
 				if !ok {
+					// Wait for the items held by other replicas to finish, or take them over
+					run.drain(executionContext, cursor)
 					return
 				}
 
-				// Get the completion channel for this batch
-				completionChan := pe.processBatch(executionContext, pageBatch, errors)
-
-				// Track max completion for this batch
-				maxIndex := -1
-				for index := range completionChan {
-					if index > maxIndex {
-						maxIndex = index
-					}
-				}
-
-				// Send update if we processed anything
-				if maxIndex >= 0 && maxIndex < len(pageBatch) {
-					select {
-					case updates <- models.JobUpdate{
-						Time:      time.Now(),
-						Completed: int64(maxIndex + 1),
-						Failed:    int64(len(pageBatch) - (maxIndex + 1)),
-						NewCheckpoint: models.JobCheckpoint{
-							BatchID: &pageBatch[maxIndex],
-						},
-					}:
-					case <-executionContext.Done():
-						return
-					}
+				// Process the leased items of this batch, sending an update as each of them finishes
+				cursor = &pageBatch[len(pageBatch)-1]
+				if !run.runBatch(executionContext, cursor, pageBatch) {
+					return
 				}
 
 				// Handle rate limiting
@@ -116,9 +116,51 @@ func (pe *pageExecutor) Execute(executionContext context.Context, jobState model
 	return updates, errors
 }
 
-func (pe *pageExecutor) processBatch(ctx context.Context, pageBatch []uuid.UUID, errors chan models.JobError) <-chan int {
+func (pe *pageExecutor) Retry(ctx context.Context, _ models.JobScope, itemIDs []uuid.UUID) <-chan models.JobItemOutcome {
+	// The items are the pages themselves, so they are within the scope of the job
+	return retryItems(ctx, itemIDs, pe.runtimeConfig.Parallelism, pe.processBatch)
+}
+
+func (pe *pageExecutor) CountItems(ctx context.Context, scope models.JobScope) (int64, error) {
+	return pe.pageService.CountActivePages(ctx, scope)
+}
+
+func (pe *pageExecutor) Limits() models.JobLimits {
+	return pe.limiter.Limits()
+}
+
+func (pe *pageExecutor) ItemWorkspaces(ctx context.Context, itemIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	return pe.pageService.ListPageWorkspaceIDs(ctx, itemIDs)
+}
+
+// pageHosts returns the host of each page of the batch
+// Pages whose host isn't known are only bound by the overall limit
+func (pe *pageExecutor) pageHosts(ctx context.Context, pageBatch []uuid.UUID) map[uuid.UUID]string {
+	hosts := make(map[uuid.UUID]string, len(pageBatch))
+	if pe.runtimeConfig.MaxPerHost <= 0 {
+		return hosts
+	}
+
+	urls, err := pe.pageService.ListPageURLs(ctx, pageBatch)
+	if err != nil {
+		pe.logger.Error("failed to list page urls, pages aren't limited per host", zap.Error(err))
+		return hosts
+	}
+
+	for pageID, pageURL := range urls {
+		parsed, err := url.Parse(pageURL)
+		if err != nil {
+			continue
+		}
+		hosts[pageID] = strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	}
+
+	return hosts
+}
 
-	completions := make(chan int, len(pageBatch))
+func (pe *pageExecutor) processBatch(ctx context.Context, pageBatch []uuid.UUID) <-chan itemCompletion {
+
+	completions := make(chan itemCompletion, len(pageBatch))
 
 	// Validate timeout
 	if pe.runtimeConfig.UpperBound <= 0 {
@@ -129,6 +171,7 @@ func (pe *pageExecutor) processBatch(ctx context.Context, pageBatch []uuid.UUID,
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, pe.runtimeConfig.UpperBound)
+	hosts := pe.pageHosts(timeoutCtx, pageBatch)
 
 	var wg sync.WaitGroup
 
@@ -138,26 +181,26 @@ func (pe *pageExecutor) processBatch(ctx context.Context, pageBatch []uuid.UUID,
 			defer wg.Done()
 
 			start := time.Now()
-			err := pe.processPage(timeoutCtx, pageID)
+			skipped, attempts, err := withRetry(timeoutCtx, pe.runtimeConfig.RetryPolicy, pe.logger, func(ctx context.Context) (bool, error) {
+				// Every attempt waits for a slot, so that retries back off along with the limit
+				host := hosts[pageID]
+				if err := pe.limiter.Acquire(ctx, host); err != nil {
+					return false, err
+				}
+				skipped, err := pe.processPage(ctx, pageID)
+				pe.limiter.Release(ctx, host, err)
+				return skipped, err
+			})
 			duration := time.Since(start)
 
+			// Completions are buffered to the size of the batch, so that every outcome is reported
+			completions <- itemCompletion{index: pageIndex, outcome: newItemOutcome(pageID, skipped, attempts, err, duration)}
+
 			if err != nil {
 				pe.logger.Error("page processing failed",
 					zap.Any("pageID", pageID),
 					zap.Duration("duration", duration),
 					zap.Error(err))
-
-				select {
-				case errors <- models.JobError{Error: err, Time: time.Now()}:
-				case <-timeoutCtx.Done():
-				}
-				return
-			}
-			select {
-			case completions <- pageIndex:
-			case <-timeoutCtx.Done():
-				pe.logger.Error("completion send timed out",
-					zap.Any("pageID", pageID))
 			}
 		}(index, pageID)
 	}
@@ -172,10 +215,10 @@ func (pe *pageExecutor) processBatch(ctx context.Context, pageBatch []uuid.UUID,
 	return completions
 }
 
-func (pe *pageExecutor) processPage(ctx context.Context, pageID uuid.UUID) error {
+func (pe *pageExecutor) processPage(ctx context.Context, pageID uuid.UUID) (bool, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return false, ctx.Err()
 	default:
 		return pe.pageService.RefreshPage(ctx, pageID)
 	}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/service/page"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// failingPageService fails to refresh every page with the same error
+type failingPageService struct {
+	page.PageService
+	err error
+}
+
+func (s *failingPageService) RefreshPage(ctx context.Context, pageID uuid.UUID) (bool, error) {
+	return false, s.err
+}
+
+func TestProcessBatchBacksOffOnUpstreamErrors(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		limit int
+	}{
+		{"rate limited", &models.UpstreamError{Service: "api.screenshotone.com", StatusCode: 429, Err: errors.New("max retries reached")}, 4},
+		{"timed out", context.DeadlineExceeded, 4},
+		{"permanent", errors.New("page not found"), 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageService := &failingPageService{err: fmt.Errorf("failed to refresh screenshot: %w", tt.err)}
+			executor, err := NewPageExecutor(pageService, models.JobExecutorConfig{
+				Parallelism:    8,
+				MinParallelism: 1,
+				UpperBound:     time.Second,
+				RetryPolicy:    models.JobRetryPolicy{MaxAttempts: 1},
+			}, log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pe := executor.(*pageExecutor)
+
+			for completion := range pe.processBatch(context.Background(), []uuid.UUID{uuid.New()}) {
+				if completion.outcome.Error == "" {
+					t.Errorf("expected the page to fail, got %+v", completion.outcome)
+				}
+			}
+
+			if got := pe.Limits().Concurrency; got != tt.limit {
+				t.Errorf("expected a limit of %d, got %d", tt.limit, got)
+			}
+		})
+	}
+}
@@ -46,7 +46,7 @@ func NewReportExecutor(
 	return &re, nil
 }
 
-func (re *reportExecutor) Execute(executionContext context.Context, jobState models.JobState) (<-chan models.JobUpdate, <-chan models.JobError) {
+func (re *reportExecutor) Execute(executionContext context.Context, jobState models.JobState, leaser ItemLeaser) (<-chan models.JobUpdate, <-chan models.JobError) {
 	re.logger.Info("Executing report job", zap.Any("job_state", jobState))
 
 	updates := make(chan models.JobUpdate, 1)
@@ -56,9 +56,17 @@ func (re *reportExecutor) Execute(executionContext context.Context, jobState mod
 		defer close(updates)
 		defer close(errors)
 
-		checkpoint := jobState.Checkpoint.BatchID
+		// Items are leased before being processed, as other replicas may be sharing the job
+		run := newLeasedRun(leaser, updates, withScope(re.processBatch, jobState.Scope), re.logger)
 
-		workspaceBatchChan, errBatchChan := re.workspaceService.ListActiveWorkspaces(executionContext, re.runtimeConfig.Parallelism, checkpoint)
+		// Resume the unfinished items of the checkpoint before moving past its cursor
+		checkpoint := jobState.Checkpoint
+		cursor := checkpoint.BatchID
+		if !run.runBatch(executionContext, cursor, checkpoint.Pending) {
+			return
+		}
+
+		workspaceBatchChan, errBatchChan := re.workspaceService.ListActiveWorkspaces(executionContext, re.runtimeConfig.Parallelism, checkpoint.BatchID, jobState.Scope)
 
 		batchStartTime := time.Now()
 
@@ -66,34 +74,15 @@ func (re *reportExecutor) Execute(executionContext context.Context, jobState mod
 			select {
 			case workspaceBatch, ok := <-workspaceBatchChan:
 				if !ok {
+					// Wait for the items held by other replicas to finish, or take them over
+					run.drain(executionContext, cursor)
 					return
 				}
 
-				// Get completion channel for the batch
-				completionChan := re.processBatch(executionContext, workspaceBatch, errors)
-
-				// Find the max index of the batch
-				maxIndex := -1
-				for index := range completionChan {
-					if index > maxIndex {
-						maxIndex = index
-					}
-				}
-
-				// Send the update
-				if maxIndex >= 0 && maxIndex < len(workspaceBatch) {
-					select {
-					case updates <- models.JobUpdate{
-						Time:      time.Now(),
-						Completed: int64(maxIndex + 1),
-						Failed:    int64(len(workspaceBatch) - (maxIndex + 1)),
-						NewCheckpoint: models.JobCheckpoint{
-							BatchID: &workspaceBatch[maxIndex],
-						},
-					}:
-					case <-executionContext.Done():
-						return
-					}
+				// Process the leased items of this batch, sending an update as each of them finishes
+				cursor = &workspaceBatch[len(workspaceBatch)-1]
+				if !run.runBatch(executionContext, cursor, workspaceBatch) {
+					return
 				}
 
 				// Handle rate limiting
@@ -131,8 +120,16 @@ func (re *reportExecutor) Execute(executionContext context.Context, jobState mod
 	return updates, errors
 }
 
-func (re *reportExecutor) processBatch(ctx context.Context, workspaceBatch []uuid.UUID, errors chan models.JobError) <-chan int {
-	completions := make(chan int, len(workspaceBatch))
+func (re *reportExecutor) Retry(ctx context.Context, scope models.JobScope, itemIDs []uuid.UUID) <-chan models.JobItemOutcome {
+	return retryItems(ctx, itemIDs, re.runtimeConfig.Parallelism, withScope(re.processBatch, scope))
+}
+
+func (re *reportExecutor) CountItems(ctx context.Context, scope models.JobScope) (int64, error) {
+	return re.workspaceService.CountActiveWorkspaces(ctx, scope)
+}
+
+func (re *reportExecutor) processBatch(ctx context.Context, workspaceBatch []uuid.UUID, scope models.JobScope) <-chan itemCompletion {
+	completions := make(chan itemCompletion, len(workspaceBatch))
 
 	// Validate timeout
 	if re.runtimeConfig.UpperBound <= 0 {
@@ -152,30 +149,17 @@ func (re *reportExecutor) processBatch(ctx context.Context, workspaceBatch []uui
 			defer wg.Done()
 
 			start := time.Now()
-			err := re.processWorkspace(timeoutCtx, workspaceID)
+			attempts, err := re.processWorkspace(timeoutCtx, workspaceID, scope)
 			duration := time.Since(start)
 
+			// Completions are buffered to the size of the batch, so that every outcome is reported
+			completions <- itemCompletion{index: workspaceIndex, outcome: newItemOutcome(workspaceID, false, attempts, err, duration)}
+
 			if err != nil {
 				re.logger.Error("workspace processing failed",
 					zap.Any("workspaceID", workspaceID),
 					zap.Duration("duration", duration),
 					zap.Error(err))
-
-				select {
-				case errors <- models.JobError{Error: err, Time: time.Now()}:
-				case <-timeoutCtx.Done():
-				}
-				return
-			}
-
-			select {
-			case completions <- workspaceIndex:
-			case <-timeoutCtx.Done():
-				re.logger.Error("timeout exceeded for report executor",
-					zap.Duration("upperBound", re.runtimeConfig.UpperBound),
-					zap.Duration("duration", time.Since(start)),
-					zap.Any("workspaceID", workspaceID),
-					zap.Error(err))
 			}
 		}(index, workspaceID)
 	}
@@ -190,27 +174,39 @@ func (re *reportExecutor) processBatch(ctx context.Context, workspaceBatch []uui
 	return completions
 }
 
-func (re *reportExecutor) processWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+// processWorkspace creates the reports of the competitors of the workspace within the scope of the job
+// Competitors are retried one at a time, so that the reports already created aren't created again
+// Returns the highest number of attempts made for a step of the workspace
+func (re *reportExecutor) processWorkspace(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) (int, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return 1, ctx.Err()
 	default:
 		// Process the workspace
-		competitors, _, err := re.workspaceService.ListCompetitorsForWorkspace(ctx, workspaceID, nil, nil)
+		var competitors []models.Competitor
+		_, attempts, err := withRetry(ctx, re.runtimeConfig.RetryPolicy, re.logger, func(ctx context.Context) (bool, error) {
+			var err error
+			competitors, err = re.workspaceService.ListCompetitorsInScope(ctx, workspaceID, scope)
+			return false, err
+		})
 		if err != nil {
-			return err
+			return attempts, err
 		}
 		errs := make([]error, 0)
 		for _, competitor := range competitors {
-			if err := re.processCompetitor(ctx, workspaceID, competitor.ID); err != nil {
-				re.logger.Error("failed to process competitor", zap.Any("workspaceID", workspaceID), zap.Any("competitorID", competitor.ID), zap.Error(err))
+			_, competitorAttempts, err := withRetry(ctx, re.runtimeConfig.RetryPolicy, re.logger, func(ctx context.Context) (bool, error) {
+				return false, re.processCompetitor(ctx, workspaceID, competitor.ID)
+			})
+			attempts = max(attempts, competitorAttempts)
+			if err != nil {
+				re.logger.Error("failed to process competitor", zap.Any("workspaceID", workspaceID), zap.Any("competitorID", competitor.ID), zap.Int("attempts", competitorAttempts), zap.Error(err))
 				errs = append(errs, err)
 			}
 		}
 		if len(errs) > 0 {
 			err = fmt.Errorf("failed to process some competitors, %v", errs)
 		}
-		return err
+		return attempts, err
 	}
 }
 
@@ -0,0 +1,90 @@
+// ./src/internal/service/executor/retry.go
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// classifyError returns the class of an error encountered while processing an item
+// Errors of the upstream services are classified by the status code they responded with
+func classifyError(err error) models.JobErrorClass {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return models.JobErrorClassPermanent
+	}
+
+	var upstreamErr *models.UpstreamError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &upstreamErr) && upstreamErr.StatusCode != 0:
+		return classifyStatusCode(upstreamErr.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return models.JobErrorClassTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return models.JobErrorClassTimeout
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE), errors.Is(err, io.ErrUnexpectedEOF):
+		return models.JobErrorClassNetwork
+	default:
+		return models.JobErrorClassPermanent
+	}
+}
+
+// classifyStatusCode returns the class of an error for the status code an upstream service responded with
+func classifyStatusCode(statusCode int) models.JobErrorClass {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return models.JobErrorClassRateLimited
+	case statusCode == http.StatusRequestTimeout:
+		return models.JobErrorClassTimeout
+	case statusCode >= http.StatusInternalServerError:
+		return models.JobErrorClassServer
+	default:
+		return models.JobErrorClassPermanent
+	}
+}
+
+// withRetry processes an item until it succeeds, fails with an error which isn't retryable, or runs out of attempts
+// Returns whether the item was skipped, the number of attempts made and the error of the last attempt
+func withRetry(ctx context.Context, policy models.JobRetryPolicy, logger *logger.Logger, process func(ctx context.Context) (bool, error)) (bool, int, error) {
+	maxAttempts := max(1, policy.MaxAttempts)
+
+	for attempt := 1; ; attempt++ {
+		skipped, err := process(ctx)
+		if err == nil {
+			return skipped, attempt, nil
+		}
+
+		// The batch ran out of time, or the job was cancelled
+		if ctx.Err() != nil {
+			return false, attempt, err
+		}
+
+		class := classifyError(err)
+		if attempt >= maxAttempts || !policy.Retryable(class) {
+			return false, attempt, err
+		}
+
+		backoff := policy.Backoff(attempt)
+		logger.Debug("retrying item",
+			zap.Int("attempt", attempt),
+			zap.String("errorClass", string(class)),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return false, attempt, err
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want models.JobErrorClass
+	}{
+		{fmt.Errorf("failed to refresh page: %w", context.DeadlineExceeded), models.JobErrorClassTimeout},
+		{fmt.Errorf("failed to refresh screenshot: %w", &models.UpstreamError{Service: "api.screenshotone.com", StatusCode: 503, Err: errors.New("max retries reached")}), models.JobErrorClassServer},
+		{fmt.Errorf("failed to compare contents: %w", &models.UpstreamError{Service: "openai api", StatusCode: 429, Err: errors.New("too many requests")}), models.JobErrorClassRateLimited},
+		{&models.UpstreamError{Service: "anthropic api", StatusCode: 400, Err: errors.New("invalid request")}, models.JobErrorClassPermanent},
+		{&models.UpstreamError{Service: "acme.test", Err: fmt.Errorf("max retries reached: %w", syscall.ECONNRESET)}, models.JobErrorClassNetwork},
+		{errors.New("read tcp: connection reset by peer"), models.JobErrorClassPermanent},
+		{context.Canceled, models.JobErrorClassPermanent},
+		{errors.New("page not found"), models.JobErrorClassPermanent},
+	}
+
+	for _, test := range tests {
+		if got := classifyError(test.err); got != test.want {
+			t.Errorf("classifyError(%q) = %s, want %s", test.err, got, test.want)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	log, err := logger.NewLogger(logger.LoggerConfig{Level: logger.ErrorLevel, OutputPaths: []string{"stdout"}, ErrorPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policy := models.JobRetryPolicy{
+		MaxAttempts:     3,
+		BaseBackoff:     time.Millisecond,
+		MaxBackoff:      2 * time.Millisecond,
+		Jitter:          0.5,
+		RetryableErrors: []models.JobErrorClass{models.JobErrorClassServer},
+	}
+
+	calls := 0
+	_, attempts, err := withRetry(context.Background(), policy, log, func(ctx context.Context) (bool, error) {
+		calls++
+		if calls < 3 {
+			return false, &models.UpstreamError{Service: "api.screenshotone.com", StatusCode: 502, Err: errors.New("max retries reached")}
+		}
+		return false, nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success on the third attempt, got attempts=%d err=%v", attempts, err)
+	}
+
+	calls = 0
+	_, attempts, err = withRetry(context.Background(), policy, log, func(ctx context.Context) (bool, error) {
+		calls++
+		return false, errors.New("page not found")
+	})
+	if err == nil || attempts != 1 || calls != 1 {
+		t.Fatalf("expected permanent errors not to be retried, got attempts=%d calls=%d err=%v", attempts, calls, err)
+	}
+
+	if backoff := policy.Backoff(5); backoff > policy.MaxBackoff || backoff < policy.MaxBackoff/2 {
+		t.Fatalf("expected backoff to be capped with jitter, got %s", backoff)
+	}
+}
@@ -20,12 +20,17 @@ type PageHistoryService interface {
 	// This is trigger during page creation by the page service and by workflow service.
 	// It returns true if the new page history was created or it returns false if the page history already exists.
 	// Error is returned if there was an issue creating the page history.
-	CreatePageHistory(ctx context.Context, pageID uuid.UUID, diff *models.DynamicChanges, prevURL, currURL string) error
+	// diff holds the changes along with the unified diff of the content and the visual diff they were derived from.
+	CreatePageHistory(ctx context.Context, pageID uuid.UUID, diff *models.DiffResult, prevURL, currURL string) error
 
 	// ListPageHistory lists the history of a page, paginated by pageHistoryPaginationParam
 	// This is triggered when a user wants to list all page histories of a page
 	ListPageHistory(ctx context.Context, pageID uuid.UUID, limit, offset *int) ([]models.PageHistory, bool, error)
 
+	// GetPageHistory returns a single page history of a page
+	// This is triggered when a user wants to audit the raw diff of a page history
+	GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error)
+
 	// ClearPageHistory clears the history of a page.
 	ClearPageHistory(ctx context.Context, pageIDs []uuid.UUID) error
 
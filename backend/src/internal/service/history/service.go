@@ -29,7 +29,8 @@ func NewPageHistoryService(pageHistoryRepo history.PageHistoryRepository, logger
 // This is trigger during page creation by the page service and by workflow service.
 // It returns true if the new page history was created or it returns false if the page history already exists.
 // Error is returned if there was an issue creating the page history.
-func (ph *pageHistoryService) CreatePageHistory(ctx context.Context, pageID uuid.UUID, diff *models.DynamicChanges, prevURL, currURL string) error {
+// diff holds the changes along with the unified diff of the content and the visual diff they were derived from.
+func (ph *pageHistoryService) CreatePageHistory(ctx context.Context, pageID uuid.UUID, diff *models.DiffResult, prevURL, currURL string) error {
 	return ph.pageHistoryRepo.CreateHistoryForPage(ctx, pageID, diff, prevURL, currURL)
 }
 
@@ -39,6 +40,12 @@ func (ph *pageHistoryService) ListPageHistory(ctx context.Context, pageID uuid.U
 	return ph.pageHistoryRepo.BatchGetPageHistory(ctx, pageID, limit, offset)
 }
 
+// GetPageHistory returns a single page history of a page
+// This is triggered when a user wants to audit the raw diff of a page history
+func (ph *pageHistoryService) GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error) {
+	return ph.pageHistoryRepo.GetPageHistory(ctx, pageID, historyID)
+}
+
 // ClearPageHistory clears the history of a page.
 func (ph *pageHistoryService) ClearPageHistory(ctx context.Context, pageIDs []uuid.UUID) error {
 	return ph.pageHistoryRepo.BatchRemovePageHistory(ctx, pageIDs)
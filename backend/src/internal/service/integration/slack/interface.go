@@ -51,5 +51,18 @@ type SlackWorkspaceService interface {
 	HandleSlackInteractionPayload(ctx context.Context, payload slack.InteractionCallback) error
 
 	// ----- Slack Report Management ----- //
+
+	// DispatchReportToWorkspaceMembers posts the latest report of a competitor to the channel of the Slack workspace
 	DispatchReportToWorkspaceMembers(ctx context.Context, workspaceID, competitorID uuid.UUID) error
+
+	// DispatchReportToSubscribers sends the reports of a competitor created since the subscribers were last notified as a direct message to the subscribers who want them on Slack
+	// Returns the users the report was sent to
+	DispatchReportToSubscribers(ctx context.Context, workspaceID, competitorID uuid.UUID, subscribers []core_models.NotificationSubscriber) ([]uuid.UUID, error)
+
+	// DispatchDigestToWorkspaceMembers posts a digest to the channel of the Slack workspace
+	DispatchDigestToWorkspaceMembers(ctx context.Context, digest core_models.Digest) error
+
+	// DispatchDigestToSubscribers sends a digest as a direct message to the subscribers who want it on Slack
+	// Returns the users the digest was sent to
+	DispatchDigestToSubscribers(ctx context.Context, digest core_models.Digest, subscribers []core_models.NotificationSubscriber) ([]uuid.UUID, error)
 }
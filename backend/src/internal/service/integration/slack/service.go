@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/slack-go/slack"
@@ -339,6 +340,7 @@ func (svc *slackWorkspaceService) HandleSlackInteractionPayload(ctx context.Cont
 	return errors.New("unsupported interaction type")
 }
 
+// DispatchReportToWorkspaceMembers posts the latest report of the competitor to the channel of the Slack workspace
 func (svc *slackWorkspaceService) DispatchReportToWorkspaceMembers(ctx context.Context, workspaceID, competitorID uuid.UUID) error {
 	report, err := svc.rs.GetLatest(ctx, workspaceID, competitorID)
 	if err != nil {
@@ -350,3 +352,109 @@ func (svc *slackWorkspaceService) DispatchReportToWorkspaceMembers(ctx context.C
 
 	return svc.refreshReport(ctx, report)
 }
+
+// DispatchReportToSubscribers sends the reports of the competitor created since the subscribers were last notified as a direct message to the subscribers who want them on Slack
+// Each subscriber gets the reports new to them aggregated into one
+// Subscribers are matched to the members of the Slack workspace by their email address
+func (svc *slackWorkspaceService) DispatchReportToSubscribers(ctx context.Context, workspaceID, competitorID uuid.UUID, subscribers []core_models.NotificationSubscriber) ([]uuid.UUID, error) {
+	recipients := make([]core_models.NotificationSubscriber, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if subscriber.Wants(competitorID, core_models.NotificationChannelSlack) {
+			recipients = append(recipients, subscriber)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	// The reports new to any of the subscribers date from within the notification window
+	now := time.Now()
+	reports, err := svc.rs.ListSince(ctx, workspaceID, competitorID, now.Add(-core_models.NotificationWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.sendDirectMessages(ctx, workspaceID, recipients, func(recipient core_models.NotificationSubscriber) (string, bool) {
+		report, ok := core_models.AggregateReports(recipient.FilterReports(reports, now))
+		if !ok {
+			return "", false
+		}
+		changes := recipient.FilterChanges(report.Changes)
+		if len(changes) == 0 {
+			return "", false
+		}
+		report.Changes = changes
+		return formatSlackReportMarkdown(report), true
+	})
+}
+
+// DispatchDigestToWorkspaceMembers posts the digest to the channel of the Slack workspace
+func (svc *slackWorkspaceService) DispatchDigestToWorkspaceMembers(ctx context.Context, digest core_models.Digest) error {
+	if digest.IsEmpty() {
+		return nil
+	}
+
+	return svc.postChannelMessage(ctx, digest.WorkspaceID, formatSlackDigestMarkdown(digest, core_models.NotificationFrequencyInstant.DigestTitle()))
+}
+
+// DispatchDigestToSubscribers sends the digest as a direct message to the subscribers who want it on Slack
+// Each subscriber gets the competitors and categories they follow
+func (svc *slackWorkspaceService) DispatchDigestToSubscribers(ctx context.Context, digest core_models.Digest, subscribers []core_models.NotificationSubscriber) ([]uuid.UUID, error) {
+	recipients := make([]core_models.NotificationSubscriber, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if subscriber.Notifies(core_models.NotificationChannelSlack) {
+			recipients = append(recipients, subscriber)
+		}
+	}
+	if len(recipients) == 0 || digest.IsEmpty() {
+		return nil, nil
+	}
+
+	return svc.sendDirectMessages(ctx, digest.WorkspaceID, recipients, func(recipient core_models.NotificationSubscriber) (string, bool) {
+		filteredDigest := recipient.FilterDigest(digest)
+		if filteredDigest.IsEmpty() {
+			return "", false
+		}
+		return formatSlackDigestMarkdown(filteredDigest, recipient.Frequency.DigestTitle()), true
+	})
+}
+
+// sendDirectMessages sends each recipient the message formatted for them, recipients without a message are skipped
+// Recipients are matched to the members of the Slack workspace by their email address
+// Returns the users the message was sent to, along with the errors of the others
+func (svc *slackWorkspaceService) sendDirectMessages(ctx context.Context, workspaceID uuid.UUID, recipients []core_models.NotificationSubscriber, format func(core_models.NotificationSubscriber) (string, bool)) ([]uuid.UUID, error) {
+	ws, err := svc.repo.GetSlackWorkspaceByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if ws.AccessToken == "" {
+		return nil, errors.New("no access token found for Slack workspace")
+	}
+
+	client := slack.New(ws.AccessToken)
+
+	sent := make([]uuid.UUID, 0, len(recipients))
+	errs := make([]error, 0)
+	for _, recipient := range recipients {
+		message, ok := format(recipient)
+		if !ok {
+			continue
+		}
+
+		user, err := client.GetUserByEmailContext(ctx, recipient.Email)
+		if err != nil {
+			svc.logger.Error("failed to find slack user", zap.Any("workspaceID", workspaceID), zap.Any("userID", recipient.UserID), zap.Error(err))
+			errs = append(errs, upstreamError(err))
+			continue
+		}
+
+		if _, _, err := client.PostMessageContext(ctx, user.ID, slack.MsgOptionText(message, false)); err != nil {
+			svc.logger.Error("failed to send slack message", zap.Any("workspaceID", workspaceID), zap.Any("userID", recipient.UserID), zap.Error(err))
+			errs = append(errs, upstreamError(err))
+			continue
+		}
+		sent = append(sent, recipient.UserID)
+	}
+
+	return sent, errors.Join(errs...)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/slack-go/slack"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"go.uber.org/zap"
 )
@@ -38,20 +40,42 @@ func formatSlackReportMarkdown(report models.Report) string {
 	return reportMarkdown
 }
 
+func formatSlackDigestMarkdown(digest models.Digest, title string) string {
+	digestMarkdown := fmt.Sprintf(
+		"# %s\n   %s - %s\n\n",
+		title, digest.Since.Format("Jan 2, 2006"), digest.GeneratedAt.Format("Jan 2, 2006"),
+	)
+
+	for _, entry := range digest.Entries {
+		digestMarkdown += formatSlackReportMarkdown(models.Report{
+			CompetitorName: entry.CompetitorName,
+			Changes:        entry.Changes,
+			Time:           entry.Time,
+		})
+	}
+
+	return digestMarkdown
+}
+
 func (svc *slackWorkspaceService) refreshReport(ctx context.Context, report *models.Report) error {
+	// Format the report into Markdown
+	reportMarkdown := formatSlackReportMarkdown(*report)
+
+	return svc.postChannelMessage(ctx, report.WorkspaceID, reportMarkdown)
+}
+
+// postChannelMessage posts the markdown to the channel of the Slack workspace through its webhook
+func (svc *slackWorkspaceService) postChannelMessage(ctx context.Context, workspaceID uuid.UUID, markdown string) error {
 	// Get the Slack workspace
-	slackWorkspace, err := svc.repo.GetSlackWorkspaceByWorkspaceID(ctx, report.WorkspaceID)
+	slackWorkspace, err := svc.repo.GetSlackWorkspaceByWorkspaceID(ctx, workspaceID)
 	if err != nil {
 		svc.logger.Error("Failed to get Slack workspace", zap.Error(err))
 		return err
 	}
 
-	// Format the report into Markdown
-	reportMarkdown := formatSlackReportMarkdown(*report)
-
 	// Create the webhook message payload
 	payload := map[string]interface{}{
-		"text":   reportMarkdown,
+		"text":   markdown,
 		"mrkdwn": true,
 	}
 
@@ -87,7 +111,7 @@ func (svc *slackWorkspaceService) refreshReport(ctx context.Context, report *mod
 	}
 
 	svc.logger.Info("Successfully sent report via webhook",
-		zap.Any("reportMarkdown", reportMarkdown),
+		zap.Any("reportMarkdown", markdown),
 		zap.String("webhookURL", slackWorkspace.ChannelWebhookURL),
 	)
 	return nil
@@ -97,3 +121,17 @@ type competitorDTO struct {
 	ChannelID string   `json:"channel_id"`
 	URLs      []string `json:"urls"`
 }
+
+// upstreamError carries the status code of the errors of the Slack API, so that they're classified by it
+func upstreamError(err error) error {
+	var rateLimitedErr *slack.RateLimitedError
+	var statusCodeErr slack.StatusCodeError
+	switch {
+	case errors.As(err, &rateLimitedErr):
+		return &models.UpstreamError{Service: "slack api", StatusCode: http.StatusTooManyRequests, Err: err}
+	case errors.As(err, &statusCodeErr):
+		return &models.UpstreamError{Service: "slack api", StatusCode: statusCodeErr.Code, Err: err}
+	default:
+		return err
+	}
+}
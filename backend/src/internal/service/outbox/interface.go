@@ -0,0 +1,25 @@
+// ./src/internal/service/outbox/interface.go
+package outbox
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// OutboxService queues the emails in the outbox, and drains it with retries in the background
+type OutboxService interface {
+	// Enqueue queues the email for delivery, along with the workspace and the reports it carries
+	// The email is written in the transaction of the context, if any, and sent once it's committed
+	Enqueue(ctx context.Context, workspaceID *uuid.UUID, reportIDs []uuid.UUID, email models.Email) (*models.OutboxEmail, error)
+
+	// ListReportEmails lists the emails of the workspace carrying the report, along with their delivery status
+	ListReportEmails(ctx context.Context, workspaceID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error)
+
+	// Start drains the outbox in the background until the context is done, or the service is shut down
+	Start(ctx context.Context) error
+
+	// Shutdown stops draining the outbox, and waits for the emails being sent to be recorded or the context to be done
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,134 @@
+// ./src/internal/service/outbox/service.go
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wizenheimer/byrd/src/internal/email"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/recorder"
+	"github.com/wizenheimer/byrd/src/internal/repository/outbox"
+	"github.com/wizenheimer/byrd/src/internal/transaction"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// compile time check if the interface is implemented
+var _ OutboxService = (*outboxService)(nil)
+
+// MaxOutboxQueryLimit is the maximum number of emails listed at once
+const MaxOutboxQueryLimit = 50
+
+// WorkerConfig configures the draining of the outbox
+type WorkerConfig struct {
+	// PollInterval is how often the outbox is checked for due emails
+	PollInterval time.Duration
+
+	// BatchSize is the number of emails claimed at once
+	BatchSize int
+
+	// SendTimeout bounds each attempt at sending an email
+	SendTimeout time.Duration
+}
+
+type outboxService struct {
+	repo outbox.OutboxRepository
+
+	// emailClient sends the emails of the outbox
+	emailClient email.EmailClient
+
+	// retryPolicy spaces out the attempts at sending an email
+	retryPolicy models.JobRetryPolicy
+
+	config WorkerConfig
+
+	// tm records an attempt along with the email queued again for its rejected recipients
+	tm *transaction.TxManager
+
+	// stop stops the worker, which closes stopped once the emails being sent were recorded
+	stop    context.CancelFunc
+	stopped chan struct{}
+
+	logger *logger.Logger
+
+	errorRecorder *recorder.ErrorRecorder
+}
+
+// NewOutboxService creates a new outbox service
+// The emails are sent with the email client, and retried according to the retry policy
+func NewOutboxService(repo outbox.OutboxRepository, emailClient email.EmailClient, retryPolicy models.JobRetryPolicy, config WorkerConfig, tm *transaction.TxManager, logger *logger.Logger, errorRecorder *recorder.ErrorRecorder) (OutboxService, error) {
+	if repo == nil {
+		return nil, errors.New("outbox repository is required")
+	}
+	if emailClient == nil {
+		return nil, errors.New("email client is required")
+	}
+	if tm == nil {
+		return nil, errors.New("transaction manager is required")
+	}
+	if config.PollInterval <= 0 || config.BatchSize <= 0 || config.SendTimeout <= 0 {
+		return nil, errors.New("outbox poll interval, batch size and send timeout must be positive")
+	}
+
+	return &outboxService{
+		repo:          repo,
+		emailClient:   emailClient,
+		retryPolicy:   retryPolicy,
+		config:        config,
+		tm:            tm,
+		logger:        logger.WithFields(map[string]interface{}{"module": "outbox_service"}),
+		errorRecorder: errorRecorder,
+	}, nil
+}
+
+func (s *outboxService) Enqueue(ctx context.Context, workspaceID *uuid.UUID, reportIDs []uuid.UUID, email models.Email) (*models.OutboxEmail, error) {
+	if len(email.To) == 0 {
+		return nil, errors.New("email has no recipients")
+	}
+
+	return s.repo.Enqueue(ctx, workspaceID, reportIDs, email)
+}
+
+func (s *outboxService) ListReportEmails(ctx context.Context, workspaceID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error) {
+	if limit != nil {
+		if *limit < 0 {
+			return nil, false, errors.New("limit cannot be negative")
+		} else if *limit > MaxOutboxQueryLimit {
+			return nil, false, fmt.Errorf("limit cannot exceed %d", MaxOutboxQueryLimit)
+		}
+	}
+
+	if offset != nil && *offset < 0 {
+		return nil, false, errors.New("offset cannot be negative")
+	}
+
+	return s.repo.ListReportEmails(ctx, workspaceID, reportID, limit, offset)
+}
+
+func (s *outboxService) Start(ctx context.Context) error {
+	ctx, s.stop = context.WithCancel(ctx)
+	s.stopped = make(chan struct{})
+
+	go func() {
+		defer close(s.stopped)
+		s.run(ctx)
+	}()
+	return nil
+}
+
+func (s *outboxService) Shutdown(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+
+	s.stop()
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
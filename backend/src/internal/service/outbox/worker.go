@@ -0,0 +1,203 @@
+// ./src/internal/service/outbox/worker.go
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wizenheimer/byrd/src/internal/email"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryCheckDelay is how long after being sent the delivery of an email is checked, leaving time for it to bounce
+	deliveryCheckDelay = 15 * time.Minute
+
+	// deliveryCheckWindow is how long after being sent the delivery of an email may still be checked
+	deliveryCheckWindow = 24 * time.Hour
+)
+
+// run drains the outbox, and checks the delivery of the emails sent, on every poll until the context is done
+func (s *outboxService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.drain(ctx)
+		s.checkDeliveries(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drain sends the due emails batch by batch, until none is left
+func (s *outboxService) drain(ctx context.Context) {
+	// Claimed emails aren't due again before the lease expires, which outlasts an attempt at each of them
+	lease := 2 * s.config.SendTimeout
+
+	for ctx.Err() == nil {
+		messages, err := s.repo.ClaimDue(ctx, s.config.BatchSize, lease)
+		if err != nil {
+			s.logger.Error("failed to claim due emails", zap.Error(err))
+			return
+		}
+
+		// Claimed emails are sent even as the worker stops, so that their attempts are recorded rather than left to the lease
+		sendCtx := context.WithoutCancel(ctx)
+
+		var wg sync.WaitGroup
+		for _, message := range messages {
+			wg.Add(1)
+			go func(message models.OutboxEmail) {
+				defer wg.Done()
+				s.send(sendCtx, message)
+			}(message)
+		}
+		wg.Wait()
+
+		if len(messages) < s.config.BatchSize {
+			return
+		}
+	}
+}
+
+// send attempts to send the email, and records the outcome
+func (s *outboxService) send(ctx context.Context, message models.OutboxEmail) {
+	sendCtx, cancel := context.WithTimeout(ctx, s.config.SendTimeout)
+	messageID, err := s.sendEmail(sendCtx, message.Email())
+	cancel()
+
+	outcome := attemptOutcome(s.retryPolicy, message, messageID, err, time.Now())
+	var rejected *email.RejectedRecipientsError
+	hasRejected := errors.As(err, &rejected)
+
+	// The attempt is recorded along with the email queued again for the rejected recipients
+	// Recording either on its own would drop the rejected recipients, or send the email again to the others
+	recordErr := s.tm.RunInTx(ctx, nil, func(ctx context.Context) error {
+		if err := s.repo.RecordAttempt(ctx, message.ID, outcome); err != nil {
+			return err
+		}
+		if hasRejected {
+			return s.requeueRejected(ctx, message, rejected)
+		}
+		return nil
+	})
+	if recordErr != nil {
+		// The email is claimed again once the lease expires
+		s.logger.Error("failed to record email attempt", zap.Any("emailID", message.ID), zap.Error(recordErr))
+		return
+	}
+
+	if hasRejected {
+		s.logger.Warn("email sent with rejected recipients, queued them again",
+			zap.Any("emailID", message.ID), zap.Strings("recipients", rejected.Recipients), zap.Error(rejected.Err))
+		return
+	}
+
+	if err != nil {
+		fields := []zap.Field{zap.Any("emailID", message.ID), zap.Int("attempts", message.Attempts), zap.String("status", string(outcome.Status))}
+		if outcome.Status == models.OutboxEmailStatusQueued {
+			s.logger.Warn("failed to send email, retrying", append(fields, zap.Error(err))...)
+			return
+		}
+		s.errorRecorder.RecordError(ctx, err, append(fields, zap.Strings("subscriberEmails", message.To), zap.String("emailSubject", message.Subject))...)
+	}
+}
+
+// requeueRejected queues the email again for the recipients it wasn't sent to, so that they're attempted on their own
+// The queued email bounces once every one of its recipients is rejected
+func (s *outboxService) requeueRejected(ctx context.Context, message models.OutboxEmail, rejected *email.RejectedRecipientsError) error {
+	retry := message.Email()
+	retry.To = rejected.Recipients
+	if _, err := s.repo.Enqueue(ctx, message.WorkspaceID, message.ReportIDs, retry); err != nil {
+		return fmt.Errorf("failed to queue the email again for the rejected recipients: %w", err)
+	}
+	return nil
+}
+
+// sendEmail sends the email, and returns the identifier the provider assigned to it when the client tracks the emails it sends
+func (s *outboxService) sendEmail(ctx context.Context, message models.Email) (string, error) {
+	if tracker, ok := s.emailClient.(email.DeliveryTracker); ok {
+		return tracker.SendTracked(ctx, message)
+	}
+	return "", s.emailClient.Send(ctx, message)
+}
+
+// attemptOutcome decides the status of the email following an attempt at sending it
+// Bounced emails aren't retried, others are until the attempts of the retry policy are exhausted
+// Emails sent with rejected recipients are sent, the rejection is recorded as the error of the attempt
+func attemptOutcome(retryPolicy models.JobRetryPolicy, message models.OutboxEmail, messageID string, err error, attemptedAt time.Time) models.OutboxEmailAttempt {
+	outcome := models.OutboxEmailAttempt{
+		Status:            models.OutboxEmailStatusSent,
+		ProviderMessageID: messageID,
+		AttemptedAt:       attemptedAt,
+	}
+	if err == nil {
+		return outcome
+	}
+
+	outcome.Error = err.Error()
+	var rejected *email.RejectedRecipientsError
+	switch {
+	case errors.As(err, &rejected):
+		// The email was sent to the other recipients, the rejected ones are queued again on their own
+	case errors.Is(err, email.ErrBounced):
+		outcome.Status = models.OutboxEmailStatusBounced
+	case message.Attempts < retryPolicy.MaxAttempts:
+		nextAttemptAt := attemptedAt.Add(retryPolicy.Backoff(message.Attempts))
+		outcome.Status = models.OutboxEmailStatusQueued
+		outcome.NextAttemptAt = &nextAttemptAt
+	default:
+		outcome.Status = models.OutboxEmailStatusFailed
+	}
+
+	return outcome
+}
+
+// checkDeliveries asks the provider whether the emails sent lately bounced, when the client can tell
+func (s *outboxService) checkDeliveries(ctx context.Context) {
+	tracker, ok := s.emailClient.(email.DeliveryTracker)
+	if !ok {
+		return
+	}
+
+	// Claimed checks aren't claimed again before the lease expires, which outlasts a check of each of them
+	// A check which failed is retried once its lease expired, until the emails fall out of the window
+	now := time.Now()
+	lease := time.Duration(s.config.BatchSize+1) * s.config.SendTimeout
+	messages, err := s.repo.ClaimDeliveryChecks(ctx, now.Add(-deliveryCheckWindow), now.Add(-deliveryCheckDelay), s.config.BatchSize, lease)
+	if err != nil {
+		s.logger.Error("failed to claim email delivery checks", zap.Error(err))
+		return
+	}
+
+	for _, message := range messages {
+		checkCtx, cancel := context.WithTimeout(ctx, s.config.SendTimeout)
+		bounced, err := tracker.Bounced(checkCtx, message.ProviderMessageID)
+		cancel()
+		if err != nil {
+			s.logger.Warn("failed to check email delivery, retrying", zap.Any("emailID", message.ID), zap.Error(err))
+			continue
+		}
+		if !bounced {
+			if err := s.repo.MarkDeliveryChecked(ctx, message.ID); err != nil {
+				s.logger.Error("failed to mark email delivery checked", zap.Any("emailID", message.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		if err := s.repo.MarkBounced(ctx, message.ID, "email bounced after being sent"); err != nil {
+			s.logger.Error("failed to mark email bounced", zap.Any("emailID", message.ID), zap.Error(err))
+			continue
+		}
+		s.logger.Warn("email bounced", zap.Any("emailID", message.ID), zap.Strings("subscriberEmails", message.To))
+	}
+}
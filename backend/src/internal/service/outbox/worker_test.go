@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wizenheimer/byrd/src/internal/email"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+func TestAttemptOutcome(t *testing.T) {
+	retryPolicy := models.JobRetryPolicy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+	attemptedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		attempts      int
+		err           error
+		status        models.OutboxEmailStatus
+		nextAttemptAt *time.Time
+	}{
+		{"sent", 1, nil, models.OutboxEmailStatusSent, nil},
+		{"retried", 2, errors.New("provider unavailable"), models.OutboxEmailStatusQueued, ptr(attemptedAt.Add(2 * time.Minute))},
+		{"attempts exhausted", 3, errors.New("provider unavailable"), models.OutboxEmailStatusFailed, nil},
+		{"rejected recipients", 1, &email.RejectedRecipientsError{Recipients: []string{"a@example.com"}, Err: errors.New("550 no such user")}, models.OutboxEmailStatusSent, nil},
+		{"bounced", 1, fmt.Errorf("recipient rejected: %w", email.ErrBounced), models.OutboxEmailStatusBounced, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := attemptOutcome(retryPolicy, models.OutboxEmail{Attempts: tt.attempts}, "message-id", tt.err, attemptedAt)
+
+			if outcome.Status != tt.status {
+				t.Errorf("expected status %s, got %s", tt.status, outcome.Status)
+			}
+			if (outcome.NextAttemptAt == nil) != (tt.nextAttemptAt == nil) ||
+				(outcome.NextAttemptAt != nil && !outcome.NextAttemptAt.Equal(*tt.nextAttemptAt)) {
+				t.Errorf("expected next attempt at %v, got %v", tt.nextAttemptAt, outcome.NextAttemptAt)
+			}
+			if (tt.err == nil) != (outcome.Error == "") {
+				t.Errorf("expected the error of the attempt to be recorded, got %q", outcome.Error)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
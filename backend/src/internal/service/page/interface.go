@@ -3,6 +3,7 @@ package page
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -19,17 +20,45 @@ type PageService interface {
 
 	ListPageHistory(ctx context.Context, pageID uuid.UUID, limit, offset *int) ([]models.PageHistory, bool, error)
 
+	GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error)
+
+	// ListPageCaptures lists the captures stored for the page, most recent first
+	ListPageCaptures(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error)
+
+	// GetPageCapture retrieves the screenshot and content of the page captured at the given time
+	GetPageCapture(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+
+	// ComparePageCaptures diffs the content of the page captured at the given times
+	ComparePageCaptures(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error)
+
+	// ExtractPageContent extracts the content of the latest capture of the page, as it is compared
+	// The selectors of the page are used when none are specified, allowing selectors to be tried out before saving them
+	ExtractPageContent(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error)
+
 	UpdatePage(ctx context.Context, competitorID uuid.UUID, pageID uuid.UUID, page models.PageProps) (*models.Page, error)
 
 	ListCompetitorPages(ctx context.Context, competitorID uuid.UUID, limit, offset *int) ([]models.Page, bool, error)
 
-	ListActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID) (<-chan []uuid.UUID, <-chan error)
+	// ListActivePages streams batches of active pages which are due for a check
+	// Pages within a non-empty scope are streamed whether they are due or not
+	ListActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID, scope models.JobScope) (<-chan []uuid.UUID, <-chan error)
+
+	// CountActivePages returns the number of pages ListActivePages streams for the scope
+	CountActivePages(ctx context.Context, scope models.JobScope) (int64, error)
+
+	// ListPageURLs returns the URL of each of the pages, pages which aren't found are left out
+	ListPageURLs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]string, error)
+
+	// ListPageWorkspaceIDs returns the workspace each of the pages belongs to, pages which aren't found are left out
+	ListPageWorkspaceIDs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
 
 	RemovePage(ctx context.Context, competitorIDs []uuid.UUID, pageIDs []uuid.UUID) error
 
 	PageExists(ctx context.Context, competitorID, pageID uuid.UUID) (bool, error)
 
-	RefreshPage(ctx context.Context, pageID uuid.UUID) error
+	// RefreshPage refreshes the page and records its history
+	// It returns true if the page was skipped as its content is unchanged since the last check
+	RefreshPage(ctx context.Context, pageID uuid.UUID) (bool, error)
 
 	GetLatestPageHistory(ctx context.Context, pageID []uuid.UUID) ([]models.PageHistory, error)
 
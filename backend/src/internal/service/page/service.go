@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/service/diff"
 	"github.com/wizenheimer/byrd/src/internal/service/history"
 	"github.com/wizenheimer/byrd/src/internal/service/screenshot"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -25,15 +27,17 @@ type pageService struct {
 	pageHistoryService history.PageHistoryService
 	diffService        diff.DiffService
 	screenshotService  screenshot.ScreenshotService
+	webhookService     webhook.WebhookService
 	logger             *logger.Logger
 }
 
-func NewPageService(pageRepo page.PageRepository, pageHistoryService history.PageHistoryService, diffService diff.DiffService, screenshotService screenshot.ScreenshotService, logger *logger.Logger) PageService {
+func NewPageService(pageRepo page.PageRepository, pageHistoryService history.PageHistoryService, diffService diff.DiffService, screenshotService screenshot.ScreenshotService, webhookService webhook.WebhookService, logger *logger.Logger) PageService {
 	return &pageService{
 		pageRepo:           pageRepo,
 		pageHistoryService: pageHistoryService,
 		diffService:        diffService,
 		screenshotService:  screenshotService,
+		webhookService:     webhookService,
 		logger:             logger.WithFields(map[string]interface{}{"module": "page_service"}),
 	}
 }
@@ -84,6 +88,8 @@ func (ps *pageService) CreatePage(ctx context.Context, competitorID uuid.UUID, p
 	return createdPages, nil
 }
 
+// backdateRefresh captures the baseline for newly created pages
+// The first check of a page is compared against its baseline
 func (ps *pageService) backdateRefresh(pages []models.Page) {
 	for _, page := range pages {
 		go func(page models.Page) {
@@ -91,35 +97,35 @@ func (ps *pageService) backdateRefresh(pages []models.Page) {
 			defer cancel()
 
 			screenshotRequestOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
-			ir, _, err := ps.screenshotService.Refresh(ctx, screenshotRequestOptions, true)
+			ir, content, err := ps.screenshotService.Refresh(ctx, screenshotRequestOptions)
 
 			if err != nil {
 				ps.logger.Error("failed to refresh page", zap.Any("pageID", page.ID), zap.Error(err))
 			}
 
-			if ir == nil {
-				ps.logger.Error("failed to get image response for page", zap.Any("pageID", page.ID))
-				ir = &models.ScreenshotImage{
-					StoragePath: "",
+			// Record the capture time and the fingerprint of the baseline
+			// so that the next check is compared against it, and skipped when unchanged
+			if err == nil && content != nil && content.Metadata != nil {
+				fingerprint, _, err := ps.diffService.Fingerprint(content, ps.contentFilter(ctx, &page))
+				if err != nil {
+					ps.logger.Error("failed to fingerprint content", zap.Any("pageID", page.ID), zap.Error(err))
+				}
+				if err := ps.pageRepo.UpdatePageCheck(ctx, page.ID, fingerprint, content.Metadata.CapturedAt); err != nil {
+					ps.logger.Error("failed to update page check", zap.Any("pageID", page.ID), zap.Error(err))
 				}
 			}
 
-			diff, err := models.NewEmptyDynamicChanges(page.DiffProfile)
-			if err != nil {
-				ps.logger.Error("failed to create empty dynamic changes", zap.Any("pageID", page.ID), zap.Error(err))
-			}
-
-			if diff == nil {
-				ps.logger.Error("failed to get diff for page, defaulting to empty", zap.Any("pageID", page.ID))
-				diff = &models.DynamicChanges{}
+			baselinePath := capturePath(ir, content)
+			if baselinePath == "" {
+				ps.logger.Error("failed to get capture for page", zap.Any("pageID", page.ID))
 			}
 
 			if err := ps.pageHistoryService.CreatePageHistory(
 				context.Background(),
 				page.ID,
-				diff,
-				ir.StoragePath,
-				ir.StoragePath,
+				ps.emptyDiff(&page),
+				baselinePath,
+				baselinePath,
 			); err != nil {
 				ps.logger.Error("failed to create page history", zap.Any("pageID", page.ID), zap.Error(err))
 			}
@@ -135,14 +141,117 @@ func (ps *pageService) ListPageHistory(ctx context.Context, pageID uuid.UUID, li
 	return ps.pageHistoryService.ListPageHistory(ctx, pageID, limit, offset)
 }
 
+func (ps *pageService) GetPageHistory(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error) {
+	return ps.pageHistoryService.GetPageHistory(ctx, pageID, historyID)
+}
+
+func (ps *pageService) ListPageCaptures(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error) {
+	page, err := ps.pageRepo.GetCompetitorPageByID(ctx, competitorID, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	screenshotOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
+	return ps.screenshotService.ListCaptures(ctx, screenshotOptions)
+}
+
+func (ps *pageService) GetPageCapture(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
+	page, err := ps.pageRepo.GetCompetitorPageByID(ctx, competitorID, pageID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	screenshotOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
+	return ps.screenshotService.Retrieve(ctx, screenshotOptions, capturedAt)
+}
+
+func (ps *pageService) ComparePageCaptures(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error) {
+	page, err := ps.pageRepo.GetCompetitorPageByID(ctx, competitorID, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	screenshotOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
+
+	_, previousHTMLContent, err := ps.screenshotService.Retrieve(ctx, screenshotOptions, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve capture at %s: %w", from.Format(time.RFC3339), err)
+	}
+
+	_, currentHTMLContent, err := ps.screenshotService.Retrieve(ctx, screenshotOptions, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve capture at %s: %w", to.Format(time.RFC3339), err)
+	}
+
+	return ps.diffService.Compare(ctx, previousHTMLContent, currentHTMLContent, ps.contentFilter(ctx, page), page.DiffProfile)
+}
+
+func (ps *pageService) ExtractPageContent(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error) {
+	page, err := ps.pageRepo.GetCompetitorPageByID(ctx, competitorID, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !page.LastCheckedAt.Valid {
+		return nil, errors.New("page has not been captured yet")
+	}
+
+	// Fallback to the selectors of the page when none are specified
+	filter := ps.contentFilter(ctx, page)
+	if selectors != nil {
+		filter.Selectors = *selectors
+	}
+
+	screenshotOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
+	_, content, err := ps.screenshotService.Retrieve(ctx, screenshotOptions, page.LastCheckedAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve latest capture: %w", err)
+	}
+
+	extraction, err := ps.diffService.Extract(content, filter)
+	if err != nil {
+		return nil, err
+	}
+	extraction.CapturedAt = page.LastCheckedAt.Time
+
+	return extraction, nil
+}
+
 func (ps *pageService) UpdatePage(ctx context.Context, competitorID uuid.UUID, pageID uuid.UUID, page models.PageProps) (*models.Page, error) {
 	captureProfileRequiresUpdate := page.CaptureProfile != nil
 	diffProfileRequiresUpdate := len(page.DiffProfile) > 0
 	urlRequiresUpdate := page.URL != ""
+	checkIntervalRequiresUpdate := page.CheckInterval > 0
+	contentSelectorsRequireUpdate := page.ContentSelectors != nil
+	noiseRulesRequireUpdate := page.NoiseRules != nil
 
 	var updatedPage *models.Page
 	var err error
 
+	// The check interval is updated on its own, as it doesn't affect the remaining fields
+	if checkIntervalRequiresUpdate {
+		updatedPage, err = ps.pageRepo.UpdateCompetitorCheckInterval(ctx, competitorID, pageID, page.CheckInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The content selectors are updated on their own, an empty set of selectors resets them to the whole page
+	if contentSelectorsRequireUpdate {
+		updatedPage, err = ps.pageRepo.UpdateCompetitorContentSelectors(ctx, competitorID, pageID, *page.ContentSelectors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The noise rules are updated on their own, an empty set of rules clears them
+	if noiseRulesRequireUpdate {
+		updatedPage, err = ps.pageRepo.UpdateCompetitorNoiseRules(ctx, competitorID, pageID, page.NoiseRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If all three fields require an update, update the page
 	if captureProfileRequiresUpdate && diffProfileRequiresUpdate && urlRequiresUpdate {
 		updatedPage, err = ps.pageRepo.UpdateCompetitorPage(ctx, competitorID, pageID, page)
@@ -187,7 +296,19 @@ func (ps *pageService) ListCompetitorPages(ctx context.Context, competitorID uui
 	return ps.pageRepo.GetCompetitorPages(ctx, competitorID, limit, offset)
 }
 
-func (ps *pageService) ListActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID) (<-chan []uuid.UUID, <-chan error) {
+func (ps *pageService) CountActivePages(ctx context.Context, scope models.JobScope) (int64, error) {
+	return ps.pageRepo.CountActivePages(ctx, scope)
+}
+
+func (ps *pageService) ListPageURLs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	return ps.pageRepo.GetPageURLs(ctx, pageIDs)
+}
+
+func (ps *pageService) ListPageWorkspaceIDs(ctx context.Context, pageIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	return ps.pageRepo.GetPageWorkspaceIDs(ctx, pageIDs)
+}
+
+func (ps *pageService) ListActivePages(ctx context.Context, batchSize int, lastPageID *uuid.UUID, scope models.JobScope) (<-chan []uuid.UUID, <-chan error) {
 	pagesChan := make(chan []uuid.UUID)
 	errorsChan := make(chan error)
 
@@ -201,7 +322,7 @@ func (ps *pageService) ListActivePages(ctx context.Context, batchSize int, lastP
 			case <-ctx.Done():
 				return
 			default:
-				activePages, err := ps.pageRepo.GetActivePages(ctx, batchSize, currentPageID)
+				activePages, err := ps.pageRepo.GetActivePages(ctx, batchSize, currentPageID, scope)
 				if err != nil {
 					errorsChan <- err
 					return
@@ -234,69 +355,216 @@ func (ps *pageService) ListActivePages(ctx context.Context, batchSize int, lastP
 }
 
 // RefreshPage with the given pageID using best effort strategy
-func (ps *pageService) RefreshPage(ctx context.Context, pageID uuid.UUID) error {
+// The page is compared against its last capture, as recorded by the previous check
+// When the content fingerprint matches the one recorded during the previous check,
+// a no change history is recorded without diffing the contents, the screenshots are still compared
+// It returns true if the page was skipped for being unchanged, neither in its content nor visually
+// Failing to capture or to compare the page fails the check, the errors of the upstream services are returned as is
+func (ps *pageService) RefreshPage(ctx context.Context, pageID uuid.UUID) (bool, error) {
 	urlContext, cancel := context.WithTimeout(ctx, 180*time.Second)
 	defer cancel()
 
 	page, err := ps.pageRepo.GetPageByPageID(ctx, pageID)
 	if err != nil {
-		return fmt.Errorf("failed to get page: %w", err)
+		return false, fmt.Errorf("failed to get page: %w", err)
 	}
 
 	screenshotOptions := models.GetScreenshotRequestOptions(page.URL, page.CaptureProfile)
 
-	// Handle current screenshot refresh with fallback
-	var currentPath string
-	var currentHTMLContent *models.ScreenshotContent
-	currentImgResp, currentHTMLContentResp, err := ps.screenshotService.Refresh(urlContext, screenshotOptions, false)
+	// The capture failing fails the check, so that it's retried when the failure is transient
+	var capturedAt time.Time
+	currentImgResp, currentHTMLContent, err := ps.screenshotService.Refresh(urlContext, screenshotOptions)
 	if err != nil {
-		ps.logger.Error("failed to refresh screenshot", zap.Error(err), zap.Any("pageID", pageID))
-		currentPath = ""
-		currentHTMLContent = &models.ScreenshotContent{}
-	} else {
-		currentPath = currentImgResp.StoragePath
-		currentHTMLContent = currentHTMLContentResp
+		return false, fmt.Errorf("failed to refresh screenshot: %w", err)
+	}
+	currentPath := capturePath(currentImgResp, currentHTMLContent)
+	if currentHTMLContent.Metadata != nil {
+		capturedAt = currentHTMLContent.Metadata.CapturedAt
+	}
+
+	// Skip the diff when the content is unchanged since the last check
+	filter := ps.contentFilter(ctx, page)
+	var fingerprint string
+	var noiseRules []string
+	if currentPath != "" {
+		fingerprint, noiseRules, err = ps.diffService.Fingerprint(currentHTMLContent, filter)
+		if err != nil {
+			ps.logger.Error("failed to fingerprint content", zap.Error(err), zap.Any("pageID", pageID))
+		}
+	}
+	if fingerprint != "" && page.LastCheckedAt.Valid {
+		previousFingerprint, err := ps.pageRepo.GetContentFingerprint(ctx, pageID)
+		if err != nil {
+			ps.logger.Error("failed to get content fingerprint", zap.Error(err), zap.Any("pageID", pageID))
+		} else if previousFingerprint == fingerprint {
+			return ps.recordUnchangedPage(ctx, page, screenshotOptions, currentImgResp, currentPath, fingerprint, noiseRules, capturedAt)
+		}
 	}
 
 	// Handle previous screenshot retrieval with fallback
 	var previousPath string
+	var previousImage *models.ScreenshotImage
 	var previousHTMLContent *models.ScreenshotContent
-	prevImgResp, previousHtmlContentResp, err := ps.screenshotService.Retrieve(ctx, screenshotOptions, false)
-	if err != nil {
+	if !page.LastCheckedAt.Valid {
+		ps.logger.Warn("page has not been captured before", zap.Any("pageID", pageID))
+		previousHTMLContent = &models.ScreenshotContent{}
+	} else if prevImgResp, previousHtmlContentResp, err := ps.screenshotService.Retrieve(ctx, screenshotOptions, page.LastCheckedAt.Time); err != nil {
 		ps.logger.Error("failed to retrieve previous screenshot", zap.Error(err), zap.Any("pageID", pageID))
 		previousPath = ""
 		previousHTMLContent = &models.ScreenshotContent{}
 	} else {
-		previousPath = prevImgResp.StoragePath
+		previousPath = capturePath(prevImgResp, previousHtmlContentResp)
+		previousImage = prevImgResp
 		previousHTMLContent = previousHtmlContentResp
 	}
 
-	var diff *models.DynamicChanges
+	var diff *models.DiffResult
 	// Only perform diff if both paths are non-empty
 	if currentPath != "" && previousPath != "" {
-		diffResult, err := ps.diffService.Compare(ctx, previousHTMLContent, currentHTMLContent, page.DiffProfile)
+		// The comparison failing fails the check, the page is compared again when it's retried
+		diff, err = ps.diffService.Compare(ctx, previousHTMLContent, currentHTMLContent, filter, page.DiffProfile)
 		if err != nil {
-			ps.logger.Error("failed to compare contents", zap.Error(err), zap.Any("pageID", pageID))
-			diff, err = models.NewEmptyDynamicChanges(page.DiffProfile)
-			if err != nil {
-				diff = &models.DynamicChanges{}
-			}
-		} else {
-			diff = diffResult
+			return false, fmt.Errorf("failed to compare contents: %w", err)
 		}
 	} else {
 		ps.logger.Warn("skipping diff due to missing screenshots",
 			zap.Any("pageID", pageID),
 			zap.String("currentPath", currentPath),
 			zap.String("previousPath", previousPath))
-		diff, err = models.NewEmptyDynamicChanges(page.DiffProfile)
+		diff = ps.emptyDiff(page)
+	}
+
+	// Compare the screenshots, for providers which capture them
+	if previousImage != nil && currentImgResp != nil {
+		diff.Visual = ps.compareScreenshots(ctx, page, previousImage, currentImgResp)
+	}
+
+	// Create history with best effort approach
+	if err := ps.pageHistoryService.CreatePageHistory(ctx, pageID, diff, previousPath, currentPath); err != nil {
+		return false, err
+	}
+
+	// Notify the webhooks of the workspace of the changes
+	if diff.Changes.HasChanges() {
+		ps.publishPageChanged(ctx, page, diff)
+	}
+
+	// Record the capture, the next check is compared against it
+	if !capturedAt.IsZero() {
+		if err := ps.pageRepo.UpdatePageCheck(ctx, pageID, fingerprint, capturedAt); err != nil {
+			ps.logger.Error("failed to update page check", zap.Error(err), zap.Any("pageID", pageID))
+		}
+	}
+
+	return false, nil
+}
+
+// recordUnchangedPage records a no change history for a page whose content is identical to the previous check
+// The noise rules which fired on the content are recorded, as they may be what kept the content identical
+// The screenshots are still compared, as changes to the images of a page leave its content identical
+// It returns true if the page is unchanged visually as well
+func (ps *pageService) recordUnchangedPage(ctx context.Context, page *models.Page, opts models.ScreenshotRequestOptions, currentImage *models.ScreenshotImage, currentPath, fingerprint string, noiseRules []string, capturedAt time.Time) (bool, error) {
+	ps.logger.Debug("content unchanged since last check, skipping diff", zap.Any("pageID", page.ID))
+
+	// The previous capture is located from the time of the last capture, without retrieving it
+	contentType := screenshot.ContentTypeImage
+	if !opts.GetProvider().CapturesScreenshot() {
+		contentType = screenshot.ContentTypeContent
+	}
+	previousPath, err := screenshot.DeterminePath(opts, contentType, page.LastCheckedAt.Time)
+	if err != nil {
+		previousPath = currentPath
+	}
+
+	diff := ps.emptyDiff(page)
+	diff.NoiseRules = noiseRules
+
+	// Compare the screenshots, for providers which capture them
+	if currentImage != nil {
+		previousImage, _, err := ps.screenshotService.Retrieve(ctx, opts, page.LastCheckedAt.Time)
 		if err != nil {
-			diff = &models.DynamicChanges{}
+			ps.logger.Error("failed to retrieve previous screenshot", zap.Error(err), zap.Any("pageID", page.ID))
+		} else if previousImage != nil {
+			previousPath = previousImage.StoragePath
+			diff.Visual = ps.compareScreenshots(ctx, page, previousImage, currentImage)
 		}
 	}
 
-	// Create history with best effort approach
-	return ps.pageHistoryService.CreatePageHistory(ctx, pageID, diff, previousPath, currentPath)
+	if err := ps.pageHistoryService.CreatePageHistory(ctx, page.ID, diff, previousPath, currentPath); err != nil {
+		return false, err
+	}
+
+	if !capturedAt.IsZero() {
+		if err := ps.pageRepo.UpdatePageCheck(ctx, page.ID, fingerprint, capturedAt); err != nil {
+			ps.logger.Error("failed to update page check", zap.Error(err), zap.Any("pageID", page.ID))
+		}
+	}
+
+	return diff.Visual == nil, nil
+}
+
+// contentFilter returns the filter applied to the content of the page before it is compared
+// The noise rules of the workspace apply before the rules of the page
+// Failures to get the rules of the workspace are logged, falling back to the rules of the page
+func (ps *pageService) contentFilter(ctx context.Context, page *models.Page) models.ContentFilter {
+	workspaceNoiseRules, err := ps.pageRepo.GetWorkspaceNoiseRules(ctx, page.ID)
+	if err != nil {
+		ps.logger.Error("failed to get workspace noise rules", zap.Error(err), zap.Any("pageID", page.ID))
+	}
+
+	return models.ContentFilter{
+		Selectors:  page.ContentSelectors,
+		NoiseRules: slices.Concat(workspaceNoiseRules, page.NoiseRules),
+	}
+}
+
+// emptyDiff returns a diff without changes for the page
+func (ps *pageService) emptyDiff(page *models.Page) *models.DiffResult {
+	changes, err := models.NewEmptyDynamicChanges(page.DiffProfile)
+	if err != nil {
+		ps.logger.Error("failed to create empty dynamic changes, defaulting to empty", zap.Any("pageID", page.ID), zap.Error(err))
+		changes = &models.DynamicChanges{}
+	}
+
+	return &models.DiffResult{
+		Changes: changes,
+	}
+}
+
+// compareScreenshots computes the visual diff of the screenshots of a page
+// The visual diff is returned only when it is significant, along with its overlay stored next to the current screenshot
+// Failures are logged, leaving the visual diff out of the page history
+func (ps *pageService) compareScreenshots(ctx context.Context, page *models.Page, previous, current *models.ScreenshotImage) *models.VisualDiff {
+	threshold := page.CaptureProfile.GetVisualDiffThreshold()
+	visualDiff, err := ps.diffService.CompareVisual(ctx, previous, current, threshold, page.DiffProfile)
+	if err != nil {
+		ps.logger.Error("failed to compare screenshots", zap.Error(err), zap.Any("pageID", page.ID))
+		return nil
+	}
+
+	if !visualDiff.Significant {
+		return nil
+	}
+
+	overlayPath, err := ps.screenshotService.StoreOverlay(ctx, current, visualDiff.Overlay)
+	if err != nil {
+		ps.logger.Error("failed to store visual diff overlay", zap.Error(err), zap.Any("pageID", page.ID))
+	}
+	visualDiff.OverlayPath = overlayPath
+
+	return visualDiff
+}
+
+// capturePath returns the path recording a capture in the page history
+// Captures without a screenshot are recorded by the path of their content
+func capturePath(img *models.ScreenshotImage, content *models.ScreenshotContent) string {
+	if img != nil {
+		return img.StoragePath
+	}
+	if content != nil {
+		return content.StoragePath
+	}
+	return ""
 }
 
 func (ps *pageService) RemovePage(ctx context.Context, competitorIDs []uuid.UUID, pageIDs []uuid.UUID) error {
@@ -356,3 +624,28 @@ func (ps *pageService) CountActivePagesForCompetitors(ctx context.Context, compe
 
 	return count, nil
 }
+
+// publishPageChanged notifies the webhooks of the workspace of the page of its changes
+// Failing to notify them doesn't interrupt the refresh of the page
+func (ps *pageService) publishPageChanged(ctx context.Context, page *models.Page, diff *models.DiffResult) {
+	workspaceIDs, err := ps.pageRepo.GetPageWorkspaceIDs(ctx, []uuid.UUID{page.ID})
+	if err != nil {
+		ps.logger.Error("failed to get workspace of page", zap.Error(err), zap.Any("pageID", page.ID))
+		return
+	}
+	workspaceID, ok := workspaceIDs[page.ID]
+	if !ok {
+		return
+	}
+
+	event := models.PageChangedEvent{
+		PageID:       page.ID,
+		CompetitorID: page.CompetitorID,
+		URL:          page.URL,
+		Changes:      diff.Changes,
+		Visual:       diff.Visual,
+	}
+	if err := ps.webhookService.Publish(ctx, workspaceID, models.WebhookEventPageChanged, event); err != nil {
+		ps.logger.Error("failed to publish page changes", zap.Error(err), zap.Any("pageID", page.ID))
+	}
+}
@@ -2,6 +2,7 @@ package report
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -15,6 +16,9 @@ type ReportService interface {
 	// GetLatest returns the latest report for the given workspace and competitor
 	GetLatest(ctx context.Context, workspaceID, competitorID uuid.UUID) (*models.Report, error)
 
+	// ListSince returns the reports of the competitor created after the given time, newest first
+	ListSince(ctx context.Context, workspaceID, competitorID uuid.UUID, since time.Time) ([]models.Report, error)
+
 	// GetContent returns the content of the report using the given report model
 	GetContent(ctx context.Context, reportURI string) (string, error)
 
@@ -24,6 +28,20 @@ type ReportService interface {
 	// Create creates a new report for the given workspace and competitor
 	Create(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string, history []models.PageHistory) (*models.Report, error)
 
+	// GetDigest returns the digest of the reports of the competitors of the workspace created within the notification window
+	GetDigest(ctx context.Context, workspaceID uuid.UUID) (*models.Digest, error)
+
+	// DispatchDigest sends the digest to the subscribers, nothing is sent when the digest is empty
+	// The subject and title of the digest follow the frequency it is sent at
+	// Returns whether the digest was sent
+	DispatchDigest(ctx context.Context, digest models.Digest, workspaceName string, frequency models.NotificationFrequency, subscriberEmails []string) (bool, error)
+
+	// ListEmails lists the emails which carried the report of the competitor, along with their delivery status
+	ListEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error)
+
 	// Dispatch send the report to it's subscribers.
-	Dispatch(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string, subscriberEmails []string) error
+	// The reports created after the time are aggregated into one, only the latest report is sent when no time is given
+	// The report is narrowed down to the changes of the categories, all of them when no category is given
+	// Returns whether the report was sent, it isn't when there is no report or none of its changes are in the categories
+	Dispatch(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string, categories []string, since *time.Time, subscriberEmails []string) (bool, error)
 }
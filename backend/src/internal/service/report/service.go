@@ -7,12 +7,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/wizenheimer/byrd/src/internal/email"
 	"github.com/wizenheimer/byrd/src/internal/email/template"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/recorder"
 	"github.com/wizenheimer/byrd/src/internal/repository/report"
 	"github.com/wizenheimer/byrd/src/internal/service/ai"
+	"github.com/wizenheimer/byrd/src/internal/service/outbox"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -32,19 +33,23 @@ type reportService struct {
 	// library
 	library template.TemplateLibrary
 
-	// emailChannel
-	emailClient email.EmailClient
+	// outboxService queues the emails of the reports for delivery
+	outboxService outbox.OutboxService
 
 	// errorRecorder
 	errorRecorder *recorder.ErrorRecorder
+
+	// webhookService notifies the webhooks of the workspaces of the reports created
+	webhookService webhook.WebhookService
 }
 
 // NewReportService creates a new report service.
 func NewReportService(
 	aiService ai.AIService,
-	emailClient email.EmailClient,
+	outboxService outbox.OutboxService,
 	library template.TemplateLibrary,
 	repo report.ReportRepository,
+	webhookService webhook.WebhookService,
 	logger *logger.Logger,
 	errorRecorder *recorder.ErrorRecorder,
 ) (ReportService, error) {
@@ -52,11 +57,12 @@ func NewReportService(
 		logger: logger.WithFields(map[string]any{
 			"service": "report",
 		}),
-		aiService:     aiService,
-		emailClient:   emailClient,
-		errorRecorder: errorRecorder,
-		library:       library,
-		repo:          repo,
+		aiService:      aiService,
+		outboxService:  outboxService,
+		errorRecorder:  errorRecorder,
+		library:        library,
+		repo:           repo,
+		webhookService: webhookService,
 	}
 	return &rs, nil
 }
@@ -89,6 +95,11 @@ func (s *reportService) GetLatest(ctx context.Context, workspaceID, competitorID
 	return report, nil
 }
 
+// ListSince returns the reports of the competitor created after the given time, newest first
+func (s *reportService) ListSince(ctx context.Context, workspaceID, competitorID uuid.UUID, since time.Time) ([]models.Report, error) {
+	return s.repo.ListSince(ctx, workspaceID, []uuid.UUID{competitorID}, since)
+}
+
 // GetContent returns the content of the report with the given ID.
 func (s *reportService) GetContent(ctx context.Context, reportURI string) (string, error) {
 	reportContent, err := s.repo.GetReportContent(ctx, reportURI)
@@ -155,19 +166,49 @@ func (s *reportService) Create(ctx context.Context, workspaceID, competitorID uu
 		return nil, err
 	}
 
+	// Notify the webhooks of the workspace, failing to notify them doesn't fail the report
+	event := models.ReportCreatedEvent{
+		ReportID:       report.ID,
+		CompetitorID:   report.CompetitorID,
+		CompetitorName: report.CompetitorName,
+		Changes:        report.Changes,
+		Time:           report.Time,
+	}
+	if err := s.webhookService.Publish(ctx, workspaceID, models.WebhookEventReportCreated, event); err != nil {
+		s.logger.Error("failed to publish report creation", zap.Any("reportID", report.ID), zap.Error(err))
+	}
+
 	return report, nil
 }
 
 // Dispatch send the report to it's subscribers.
-func (s *reportService) Dispatch(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string, subscriberEmails []string) error {
-	report, err := s.GetLatest(ctx, workspaceID, competitorID)
+// The email is queued in the outbox, in the transaction of the context if any
+func (s *reportService) Dispatch(ctx context.Context, workspaceID, competitorID uuid.UUID, competitorName string, categories []string, since *time.Time, subscriberEmails []string) (bool, error) {
+	reports, err := s.listDispatched(ctx, workspaceID, competitorID, since)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	report, ok := models.AggregateReports(reports)
+	if !ok {
+		s.logger.Debug("no report since the last dispatch, skipping dispatch", zap.Any("competitorID", competitorID), zap.Timep("since", since))
+		return false, nil
 	}
 
-	reportContent, err := s.GetContent(ctx, report.URI)
+	var reportContent string
+	if len(categories) == 0 && len(reports) == 1 {
+		reportContent, err = s.GetContent(ctx, report.URI)
+	} else {
+		// Render the report again with the changes of every report, of the categories only
+		changes := models.FilterCategoryChanges(report.Changes, categories)
+		if len(changes) == 0 {
+			s.logger.Debug("no changes for the categories, skipping dispatch", zap.Any("competitorID", competitorID), zap.Strings("categories", categories))
+			return false, nil
+		}
+		reportContent, err = s.renderHTML(competitorName, changes)
+	}
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	email := models.Email{
@@ -177,19 +218,93 @@ func (s *reportService) Dispatch(ctx context.Context, workspaceID, competitorID
 		EmailFormat:  models.EmailFormatHTML,
 	}
 
-	go s.sendEmail(email)
+	return s.queueEmail(ctx, workspaceID, models.ReportIDs(reports), email)
+}
+
+// listDispatched returns the reports of the competitor created after the time, or its latest report when no time is given
+func (s *reportService) listDispatched(ctx context.Context, workspaceID, competitorID uuid.UUID, since *time.Time) ([]models.Report, error) {
+	if since == nil {
+		report, err := s.GetLatest(ctx, workspaceID, competitorID)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Report{*report}, nil
+	}
 
-	return nil
+	return s.ListSince(ctx, workspaceID, competitorID, *since)
 }
 
-func (s *reportService) sendEmail(email models.Email) {
-	// Create a context with 30 second timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel() // Important to avoid context leak
+// ListEmails lists the emails which carried the report of the competitor, along with their delivery status
+func (s *reportService) ListEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error) {
+	report, err := s.Get(ctx, reportID)
+	if err != nil {
+		return nil, false, err
+	}
 
-	if err := s.emailClient.Send(ctx, email); err != nil {
-		s.errorRecorder.RecordError(ctx, err, zap.Any("subscriberEmails", email.To), zap.Any("emailSubject", email.EmailSubject))
+	if report.WorkspaceID != workspaceID || report.CompetitorID != competitorID {
+		return nil, false, fmt.Errorf("report with ID %s not found", reportID)
 	}
+
+	return s.outboxService.ListReportEmails(ctx, workspaceID, reportID, limit, offset)
+}
+
+// GetDigest returns the digest of the reports of the competitors of the workspace created within the notification window
+func (s *reportService) GetDigest(ctx context.Context, workspaceID uuid.UUID) (*models.Digest, error) {
+	since := time.Now().UTC().Add(-models.NotificationWindow)
+
+	reports, err := s.repo.ListSince(ctx, workspaceID, nil, since)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := models.NewDigest(workspaceID, reports, since)
+	return &digest, nil
+}
+
+// DispatchDigest sends the digest to the subscribers.
+// The email is queued in the outbox, in the transaction of the context if any
+func (s *reportService) DispatchDigest(ctx context.Context, digest models.Digest, workspaceName string, frequency models.NotificationFrequency, subscriberEmails []string) (bool, error) {
+	if digest.IsEmpty() || len(subscriberEmails) == 0 {
+		s.logger.Debug("nothing to dispatch, skipping digest", zap.Any("workspaceID", digest.WorkspaceID), zap.Int("entries", len(digest.Entries)))
+		return false, nil
+	}
+
+	title := frequency.DigestTitle()
+	digestContent, err := s.renderDigestHTML(digest, title, workspaceName)
+	if err != nil {
+		return false, err
+	}
+
+	email := models.Email{
+		To:           subscriberEmails,
+		EmailSubject: title + " for " + workspaceName,
+		EmailContent: digestContent,
+		EmailFormat:  models.EmailFormatHTML,
+	}
+
+	reportIDs := make([]uuid.UUID, 0, len(digest.Entries))
+	for _, entry := range digest.Entries {
+		reportIDs = append(reportIDs, entry.ReportIDs...)
+	}
+
+	return s.queueEmail(ctx, digest.WorkspaceID, reportIDs, email)
+}
+
+// queueEmail queues the email of the reports of the workspace in the outbox
+// Returns whether the email was queued, it isn't when it has no recipients
+func (s *reportService) queueEmail(ctx context.Context, workspaceID uuid.UUID, reportIDs []uuid.UUID, email models.Email) (bool, error) {
+	if len(email.To) == 0 {
+		s.logger.Debug("no subscribers, skipping email", zap.Any("workspaceID", workspaceID))
+		return false, nil
+	}
+
+	queued, err := s.outboxService.Enqueue(ctx, &workspaceID, reportIDs, email)
+	if err != nil {
+		return false, err
+	}
+
+	s.logger.Debug("queued report email", zap.Any("emailID", queued.ID), zap.Any("workspaceID", workspaceID), zap.Int("reports", len(reportIDs)))
+	return true, nil
 }
 
 func (s *reportService) renderHTML(competitorName string, changes []models.CategoryChange) (string, error) {
@@ -240,3 +355,49 @@ func (s *reportService) renderHTML(competitorName string, changes []models.Categ
 
 	return htmlContent, nil
 }
+
+func (s *reportService) renderDigestHTML(digest models.Digest, title, workspaceName string) (string, error) {
+	tmp, err := s.library.GetTemplate(template.WeeklyDigestTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	digestTemplate, ok := tmp.(*template.DigestTemplate)
+	if !ok {
+		return "", errors.New("failed to assert template to DigestTemplate")
+	}
+
+	// Override template with digest data
+	digestTemplate.Title = title
+	digestTemplate.Workspace = workspaceName
+	digestTemplate.GeneratedAt = digest.GeneratedAt
+	digestTemplate.FromDate = digest.Since
+	digestTemplate.ToDate = digest.GeneratedAt
+
+	// Map each entry to a competitor, keeping the ranking of the digest
+	digestTemplate.Competitors = make([]template.CompetitorDigest, 0, len(digest.Entries))
+	for _, entry := range digest.Entries {
+		sections := make([]template.Section, 0, len(entry.Changes))
+		for _, change := range entry.Changes {
+			bullets := make([]template.BulletPoint, len(change.Changes))
+			for i, changeText := range change.Changes {
+				bullets[i] = template.BulletPoint{
+					Text: changeText,
+				}
+			}
+
+			sections = append(sections, template.Section{
+				Title:   change.Category,
+				Summary: change.Summary,
+				Bullets: bullets,
+			})
+		}
+
+		digestTemplate.Competitors = append(digestTemplate.Competitors, template.CompetitorDigest{
+			Competitor: entry.CompetitorName,
+			Sections:   sections,
+		})
+	}
+
+	return digestTemplate.RenderHTML()
+}
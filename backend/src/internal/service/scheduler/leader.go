@@ -0,0 +1,77 @@
+// ./src/internal/service/scheduler/leader.go
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	workflow_repo "github.com/wizenheimer/byrd/src/internal/repository/workflow"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// schedulerLeaseName is the name of the lease held by the leader among the replicas
+const schedulerLeaseName = "scheduler"
+
+// leaderElector elects the replica firing the schedules, so that replicas sharing the schedules don't fire them twice
+type leaderElector struct {
+	// repository is the repository holding the lease of the leader
+	repository workflow_repo.LeaseRepository
+
+	// cluster is the configuration of this replica among the replicas
+	cluster models.ClusterConfig
+
+	// logger is the logger for the leader elector
+	logger *logger.Logger
+
+	// leaderUntil is the time, in unix nanoseconds, until which this replica is sure to hold the lease
+	leaderUntil atomic.Int64
+}
+
+func newLeaderElector(repository workflow_repo.LeaseRepository, cluster models.ClusterConfig, logger *logger.Logger) *leaderElector {
+	return &leaderElector{
+		repository: repository,
+		cluster:    cluster,
+		logger:     logger,
+	}
+}
+
+// campaign acquires the lease of the leader, or renews it if this replica is already the leader
+func (l *leaderElector) campaign(ctx context.Context) {
+	wasLeader := l.isLeader()
+	start := time.Now()
+
+	acquired, err := l.repository.AcquireLease(ctx, schedulerLeaseName, l.cluster.ReplicaID, l.cluster.LeaseTTL)
+	if err != nil {
+		// Leadership lapses on its own if the lease can't be renewed in time
+		l.logger.Error("failed to campaign for scheduler leadership", zap.Error(err))
+		return
+	}
+
+	if !acquired {
+		l.leaderUntil.Store(0)
+		if wasLeader {
+			l.logger.Warn("lost scheduler leadership", zap.String("replicaID", l.cluster.ReplicaID))
+		}
+		return
+	}
+
+	// The lease is only trusted for two thirds of its ttl, leaving room for clock drift and latency
+	l.leaderUntil.Store(start.Add(l.cluster.LeaseTTL * 2 / 3).UnixNano())
+	if !wasLeader {
+		l.logger.Info("acquired scheduler leadership", zap.String("replicaID", l.cluster.ReplicaID))
+	}
+}
+
+// resign releases the lease of the leader, so that another replica takes over without waiting for it to expire
+func (l *leaderElector) resign(ctx context.Context) error {
+	l.leaderUntil.Store(0)
+	return l.repository.ReleaseLease(ctx, schedulerLeaseName, l.cluster.ReplicaID)
+}
+
+// isLeader returns true if this replica is the leader
+func (l *leaderElector) isLeader() bool {
+	return time.Now().UnixNano() < l.leaderUntil.Load()
+}
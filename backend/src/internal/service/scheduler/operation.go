@@ -75,7 +75,7 @@ func (op *CreateScheduleOperation) Execute(ctx context.Context) error {
 		Hooks:        []func(){op.svc.syncWorkflow(ctx, op.remoteID)},
 		ScheduleSpec: op.workflowProp.Spec,
 	}
-	cmd := op.svc.triggerWorkflow(ctx, op.workflowProp.WorkflowType)
+	cmd := op.svc.triggerWorkflow(ctx, op.workflowProp)
 	f, err := op.svc.scheduler.Schedule(cmd, opts)
 	if err != nil {
 		return err
@@ -113,6 +113,7 @@ type UpdateScheduleOperation struct {
 	workflowProp    models.WorkflowScheduleProps
 	oldWorkflowProp models.WorkflowScheduleProps
 	oldFunc         *models.ScheduledFunc
+	newFunc         *models.ScheduledFunc
 }
 
 func (op *UpdateScheduleOperation) Execute(ctx context.Context) error {
@@ -121,18 +122,14 @@ func (op *UpdateScheduleOperation) Execute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	op.oldWorkflowProp = models.WorkflowScheduleProps{
-		WorkflowType: schedule.WorkflowType,
-		Spec:         schedule.Spec,
-	}
+	op.oldWorkflowProp = schedule.Props()
 
-	v, ok := op.svc.scheduledFuncs.Load(op.remoteID)
-	if !ok {
-		return fmt.Errorf("scheduled function not found")
-	}
-	op.oldFunc, ok = v.(*models.ScheduledFunc)
-	if !ok {
-		return fmt.Errorf("failed to cast scheduled function")
+	// Schedules created on other replicas may not be synced yet, they are scheduled afresh
+	if v, ok := op.svc.scheduledFuncs.Load(op.remoteID); ok {
+		op.oldFunc, ok = v.(*models.ScheduledFunc)
+		if !ok {
+			return fmt.Errorf("failed to cast scheduled function")
+		}
 	}
 
 	// Update repository
@@ -145,11 +142,19 @@ func (op *UpdateScheduleOperation) Execute(ctx context.Context) error {
 		Hooks:        []func(){op.svc.syncWorkflow(ctx, op.remoteID)},
 		ScheduleSpec: op.workflowProp.Spec,
 	}
-	f, err := op.svc.scheduler.Update(op.oldFunc.ID, op.svc.triggerWorkflow(ctx, op.workflowProp.WorkflowType), opts)
+	cmd := op.svc.triggerWorkflow(ctx, op.workflowProp)
+
+	var f *models.ScheduledFunc
+	if op.oldFunc != nil {
+		f, err = op.svc.scheduler.Update(op.oldFunc.ID, cmd, opts)
+	} else {
+		f, err = op.svc.scheduler.Schedule(cmd, opts)
+	}
 	if err != nil {
 		return err
 	}
 
+	op.newFunc = f
 	op.svc.scheduledFuncs.Store(op.remoteID, f)
 	return nil
 }
@@ -162,12 +167,24 @@ func (op *UpdateScheduleOperation) Rollback(ctx context.Context) error {
 		return err
 	}
 
+	// The schedule wasn't synced before the update, the function scheduled by it is removed
+	if op.oldFunc == nil {
+		if op.newFunc != nil {
+			if err := op.svc.scheduler.Delete(op.newFunc.ID); err != nil {
+				logger.Fatal("failed to rollback update operation", zap.Error(err))
+				return err
+			}
+			op.svc.scheduledFuncs.Delete(op.remoteID)
+		}
+		return nil
+	}
+
 	// Restore old state in scheduler
 	opts := scheduler.ScheduleOptions{
 		Hooks:        []func(){op.svc.syncWorkflow(ctx, op.remoteID)},
 		ScheduleSpec: op.oldWorkflowProp.Spec,
 	}
-	cmd := op.svc.triggerWorkflow(ctx, op.oldWorkflowProp.WorkflowType)
+	cmd := op.svc.triggerWorkflow(ctx, op.oldWorkflowProp)
 	f, err := op.svc.scheduler.Update(op.oldFunc.ID, cmd, opts)
 	if err != nil {
 		logger.Fatal("failed to rollback update operation", zap.Error(err))
@@ -195,10 +212,7 @@ func (op *DeleteScheduleOperation) Execute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	op.oldWorkflowProp = models.WorkflowScheduleProps{
-		WorkflowType: schedule.WorkflowType,
-		Spec:         schedule.Spec,
-	}
+	op.oldWorkflowProp = schedule.Props()
 
 	// Delete from repository first
 	if err := op.svc.repository.DeleteSchedule(ctx, op.remoteID); err != nil {
@@ -207,7 +221,9 @@ func (op *DeleteScheduleOperation) Execute(ctx context.Context) error {
 
 	value, ok := op.svc.scheduledFuncs.Load(op.remoteID)
 	if !ok {
-		return errors.New("scheduled function not found")
+		// The schedule wasn't synced to this replica, the replicas which synced it remove it as they sync again
+		op.wasDeleted = true
+		return nil
 	}
 	op.oldFunc, ok = value.(*models.ScheduledFunc)
 	if !ok {
@@ -252,7 +268,7 @@ func (op *DeleteScheduleOperation) Rollback(ctx context.Context) error {
 		Hooks:        []func(){op.svc.syncWorkflow(ctx, op.remoteID)},
 		ScheduleSpec: op.oldWorkflowProp.Spec,
 	}
-	f, err := op.svc.scheduler.Schedule(op.svc.triggerWorkflow(ctx, op.oldWorkflowProp.WorkflowType), opts)
+	f, err := op.svc.scheduler.Schedule(op.svc.triggerWorkflow(ctx, op.oldWorkflowProp), opts)
 	if err != nil {
 		logger.Fatal("failed to restore scheduler state", zap.Error(err))
 		return fmt.Errorf("failed to restore scheduler state: %v", err)
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/recorder"
 	"github.com/wizenheimer/byrd/src/internal/repository/schedule"
+	workflow_repo "github.com/wizenheimer/byrd/src/internal/repository/workflow"
 	"github.com/wizenheimer/byrd/src/internal/scheduler"
 	"github.com/wizenheimer/byrd/src/internal/service/workflow"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
@@ -39,6 +41,22 @@ type schedulerService struct {
 
 	// parser is the cron parser
 	parser cron.Parser
+
+	// leader elects the replica firing the schedules
+	// Every replica keeps the schedules in its scheduler, so that another one takes over as soon as it is elected
+	leader *leaderElector
+
+	// cluster is the configuration of this replica among the replicas sharing the schedules
+	cluster models.ClusterConfig
+
+	// mu serializes the changes to the schedules of the scheduler
+	mu sync.Mutex
+
+	// syncedProps are the props of the schedules as last synced from the repository
+	syncedProps map[models.ScheduleID]models.WorkflowScheduleProps
+
+	// stopWatch stops the watch loop
+	stopWatch context.CancelFunc
 }
 
 // NewSchedulerService creates a new scheduler service
@@ -46,10 +64,13 @@ func NewSchedulerService(
 	repository schedule.ScheduleRepository,
 	scheduler scheduler.Scheduler,
 	workflowService workflow.WorkflowService,
+	leases workflow_repo.LeaseRepository,
+	cluster models.ClusterConfig,
 	logger *logger.Logger,
 	errorRecord *recorder.ErrorRecorder,
 ) (SchedulerService, error) {
 	parser := utils.NewScheduleParser()
+	cluster = cluster.Normalize()
 
 	s := schedulerService{
 		repository: repository,
@@ -60,26 +81,45 @@ func NewSchedulerService(
 		scheduler:       scheduler,
 		workflowService: workflowService,
 		parser:          parser,
+		cluster:         cluster,
+		syncedProps:     make(map[models.ScheduleID]models.WorkflowScheduleProps),
 	}
+	s.leader = newLeaderElector(leases, cluster, s.logger)
 	return &s, nil
 }
 
 // Start starts the scheduler service
+// Schedules only fire on the leader among the replicas, which is elected and kept in the background
 func (s *schedulerService) Start(ctx context.Context, recovery bool) error {
 	err := s.scheduler.Start()
 	if err != nil {
 		return err
 	}
 
+	s.leader.campaign(ctx)
+
 	if recovery {
 		s.Recover(ctx)
 	}
 
+	watchContext, stopWatch := context.WithCancel(context.Background())
+	s.stopWatch = stopWatch
+	go s.watch(watchContext, recovery)
+
 	return nil
 }
 
 // Gracefully stops the scheduler service gracefully
 func (s *schedulerService) Stop(ctx context.Context) error {
+	if s.stopWatch != nil {
+		s.stopWatch()
+	}
+
+	// Hand the leadership over to another replica
+	if err := s.leader.resign(ctx); err != nil {
+		s.logger.Error("failed to resign scheduler leadership", zap.Error(err))
+	}
+
 	err := s.scheduler.Stop()
 	if err != nil {
 		s.errorRecord.RecordError(ctx, fmt.Errorf("failed to stop scheduler %v", err.Error()))
@@ -87,18 +127,76 @@ func (s *schedulerService) Stop(ctx context.Context) error {
 	return err
 }
 
-func (s *schedulerService) triggerWorkflow(ctx context.Context, workflowType models.WorkflowType) func() {
+// watch campaigns for leadership at every poll interval
+// When recovering, the schedules are synced from the repository as well, as they may be changed by other replicas
+func (s *schedulerService) watch(ctx context.Context, recovery bool) {
+	ticker := time.NewTicker(s.cluster.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.leader.campaign(ctx)
+			if recovery {
+				s.Recover(ctx)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *schedulerService) triggerWorkflow(ctx context.Context, workflowProp models.WorkflowScheduleProps) func() {
+	workflowType := workflowProp.WorkflowType
+	pipeline := workflowProp.Pipeline
+
 	return func() {
-		// Execute the workflow
-		_, err := s.workflowService.Submit(ctx, workflowType)
+		// Only the leader submits the workflow, so that it runs once across the replicas
+		if !s.leader.isLeader() {
+			s.logger.Debug("skipping scheduled workflow on follower", zap.Any("workflowType", workflowType))
+			return
+		}
+
+		// Run the pipeline as one unit, each stage starting once the previous one completed
+		if len(pipeline) > 0 {
+			if _, err := s.workflowService.RunPipeline(ctx, pipeline, models.JobScope{}); err != nil {
+				s.errorRecord.RecordError(ctx, fmt.Errorf("failed to run pipeline %v", err.Error()), zap.Any("pipeline", pipeline))
+			}
+			return
+		}
+
+		// Execute the workflow across every item
+		_, err := s.workflowService.Submit(ctx, workflowType, models.JobScope{})
 		if err != nil {
 			s.errorRecord.RecordError(ctx, fmt.Errorf("failed to submit workflow %v", err.Error()), zap.Any("workflowType", workflowType))
 		}
 	}
 }
 
+// validateProps validates the props of a schedule
+// The workflow type of a pipeline schedule is set to its first stage
+func (s *schedulerService) validateProps(workflowProp models.WorkflowScheduleProps) (models.WorkflowScheduleProps, error) {
+	if _, err := s.parser.Parse(workflowProp.Spec); err != nil {
+		return workflowProp, err
+	}
+
+	if workflowProp.IsPipeline() {
+		if err := models.ValidatePipeline(workflowProp.Pipeline); err != nil {
+			return workflowProp, err
+		}
+		workflowProp.WorkflowType = workflowProp.Pipeline[0]
+	}
+
+	return workflowProp, nil
+}
+
 func (s *schedulerService) syncWorkflow(ctx context.Context, remoteScheduleID models.ScheduleID) func() {
 	return func() {
+		// Only the leader syncs the workflow times, as it is the one running the workflow
+		if !s.leader.isLeader() {
+			return
+		}
+
 		// Get the scheduled function
 		v, ok := s.scheduledFuncs.Load(remoteScheduleID)
 		if !ok {
@@ -121,7 +219,7 @@ func (s *schedulerService) syncWorkflow(ctx context.Context, remoteScheduleID mo
 // Schedule schedules a new workflow
 func (s *schedulerService) Schedule(ctx context.Context, workflowProp models.WorkflowScheduleProps) (models.ScheduleID, error) {
 	// Add validation to the workflowProp
-	_, err := s.parser.Parse(workflowProp.Spec)
+	workflowProp, err := s.validateProps(workflowProp)
 	if err != nil {
 		return models.NilScheduleID(), err
 	}
@@ -134,10 +232,14 @@ func (s *schedulerService) Schedule(ctx context.Context, workflowProp models.Wor
 		workflowProp: workflowProp,
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if err := op.Execute(ctx); err != nil {
 		s.errorRecord.RecordError(ctx, fmt.Errorf("failed to schedule workflow, %s", err.Error()), zap.Any("remoteScheduleID", remoteScheduleID))
 		return models.NilScheduleID(), err
 	}
+	s.syncedProps[remoteScheduleID] = workflowProp
 
 	return remoteScheduleID, nil
 }
@@ -149,27 +251,40 @@ func (s *schedulerService) Unschedule(ctx context.Context, remoteScheduleID mode
 		remoteID: remoteScheduleID,
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	err := op.Execute(ctx)
 	if err != nil {
 		s.errorRecord.RecordError(ctx, fmt.Errorf("failed to unschedule workflow, %s", err.Error()), zap.Any("remoteScheduleID", remoteScheduleID))
 		return err
 	}
+	delete(s.syncedProps, remoteScheduleID)
 
 	return nil
 }
 
 // Reschedule reschedules a workflow
 func (s *schedulerService) Reschedule(ctx context.Context, remoteScheduleID models.ScheduleID, workflowProp models.WorkflowScheduleProps) (models.ScheduleID, error) {
+	workflowProp, err := s.validateProps(workflowProp)
+	if err != nil {
+		return models.NilScheduleID(), err
+	}
+
 	op := &UpdateScheduleOperation{
 		svc:          s,
 		remoteID:     remoteScheduleID,
 		workflowProp: workflowProp,
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if err := op.Execute(ctx); err != nil {
 		s.errorRecord.RecordError(ctx, fmt.Errorf("failed to reschedule workflow, %s", err.Error()), zap.Any("remoteScheduleID", remoteScheduleID))
 		return models.NilScheduleID(), err
 	}
+	s.syncedProps[remoteScheduleID] = workflowProp
 
 	return remoteScheduleID, nil
 }
@@ -177,18 +292,17 @@ func (s *schedulerService) Reschedule(ctx context.Context, remoteScheduleID mode
 // Get returns the schedule of a workflow
 func (s *schedulerService) Get(ctx context.Context, remoteScheduleID models.ScheduleID) (*models.WorkflowSchedule, error) {
 	// Get schedule of a workflow from local state
-	v, ok := s.scheduledFuncs.Load(remoteScheduleID)
-	if !ok {
-		return nil, errors.New("scheduled function not found")
-	}
-	f, ok := v.(*models.ScheduledFunc)
-	if !ok {
-		return nil, errors.New("failed to cast scheduled function")
-	}
+	// Schedules created on other replicas are synced in the background, the repository is authoritative until then
+	if v, ok := s.scheduledFuncs.Load(remoteScheduleID); ok {
+		f, ok := v.(*models.ScheduledFunc)
+		if !ok {
+			return nil, errors.New("failed to cast scheduled function")
+		}
 
-	_, err := s.scheduler.Get(f.ID)
-	if err != nil {
-		return nil, err
+		_, err := s.scheduler.Get(f.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Get the workflow schedule
@@ -211,26 +325,52 @@ func (s *schedulerService) List(ctx context.Context, limit, offset *int, workflo
 	return workflowSchedules, nil
 }
 
+// Recover syncs the scheduled functions with the schedules of the repository
+// Schedules missing from the scheduler are scheduled, changed ones are rescheduled, and deleted ones are removed
 func (s *schedulerService) Recover(ctx context.Context) {
-	// Recover scheduled functions
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	workflows, err := s.List(ctx, nil, nil, nil)
 	if err != nil {
 		s.errorRecord.RecordError(ctx, fmt.Errorf("failed to list scheduled workflows, %s", err.Error()))
 		return
 	}
 
+	remote := make(map[models.ScheduleID]bool, len(workflows))
 	for _, workflow := range workflows {
 		scheduleID := workflow.ID
+		remote[scheduleID] = true
+		props := workflow.Props()
+
 		// Schedule options
 		opts := scheduler.ScheduleOptions{
 			Hooks:        []func(){s.syncWorkflow(ctx, scheduleID)},
 			ScheduleSpec: workflow.Spec,
 		}
 		// Scheduled command
-		cmd := s.triggerWorkflow(ctx, workflow.WorkflowType)
-
-		// Trigger the scheduler to schedule the workflow
-		f, err := s.scheduler.Schedule(cmd, opts)
+		cmd := s.triggerWorkflow(ctx, props)
+
+		var f *models.ScheduledFunc
+		if v, ok := s.scheduledFuncs.Load(scheduleID); ok {
+			existing, ok := v.(*models.ScheduledFunc)
+			if !ok {
+				s.logger.Error("failed to cast scheduled function", zap.Any("scheduleID", scheduleID))
+				continue
+			}
+
+			synced, known := s.syncedProps[scheduleID]
+			if existing.Spec == workflow.Spec && (!known || synced.Equal(props)) {
+				s.syncedProps[scheduleID] = props
+				continue
+			}
+
+			// The schedule was changed by another replica
+			f, err = s.scheduler.Update(existing.ID, cmd, opts)
+		} else {
+			// Trigger the scheduler to schedule the workflow
+			f, err = s.scheduler.Schedule(cmd, opts)
+		}
 		if err != nil {
 			s.errorRecord.RecordError(ctx, fmt.Errorf("failed to schedule workflow, %s", err.Error()), zap.Any("scheduleID", scheduleID), zap.Any("workflowID", workflow.ID))
 			continue
@@ -238,5 +378,23 @@ func (s *schedulerService) Recover(ctx context.Context) {
 
 		// Associate the remote schedule ID with scheduled function
 		s.scheduledFuncs.Store(scheduleID, f)
+		s.syncedProps[scheduleID] = props
 	}
+
+	// Remove the schedules deleted by other replicas
+	s.scheduledFuncs.Range(func(key, value interface{}) bool {
+		scheduleID, ok := key.(models.ScheduleID)
+		if !ok || remote[scheduleID] {
+			return true
+		}
+		if f, ok := value.(*models.ScheduledFunc); ok {
+			if err := s.scheduler.Delete(f.ID); err != nil {
+				s.logger.Error("failed to remove deleted schedule", zap.Any("scheduleID", scheduleID), zap.Error(err))
+				return true
+			}
+		}
+		s.scheduledFuncs.Delete(scheduleID)
+		delete(s.syncedProps, scheduleID)
+		return true
+	})
 }
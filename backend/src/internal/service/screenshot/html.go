@@ -0,0 +1,94 @@
+// ./src/internal/service/screenshot/html.go
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/wizenheimer/byrd/src/internal/client"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+)
+
+// maxHTMLContentSize caps the size of the html fetched for a page
+const maxHTMLContentSize int64 = 10 << 20
+
+const (
+	// htmlFetchTimeout bounds each attempt at fetching the html of a page
+	htmlFetchTimeout = 30 * time.Second
+
+	// htmlMaxRedirects caps the redirects followed when fetching the html of a page
+	htmlMaxRedirects = 5
+)
+
+// htmlProvider fetches the raw html content of a page over plain http
+// It doesn't render the page, nor capture a screenshot
+// This suits text-only pages such as changelogs and docs
+type htmlProvider struct {
+	httpClient *client.HTTPClient
+}
+
+var _ CaptureProvider = (*htmlProvider)(nil)
+
+// NewPublicHTMLClient creates the client fetching the html of the pages
+// The URLs of the pages are user supplied, so the client only connects to public addresses, following a few redirects
+func NewPublicHTMLClient(logger *logger.Logger) (*client.HTTPClient, error) {
+	return client.NewClient(logger, client.WithHTTPClient(client.NewPublicHTTPClient(htmlFetchTimeout, htmlMaxRedirects)))
+}
+
+// NewHTMLProvider creates a capture provider which fetches the raw html of a page
+// The client should only connect to public addresses, see NewPublicHTMLClient
+func NewHTMLProvider(httpClient *client.HTTPClient) (CaptureProvider, error) {
+	if httpClient == nil {
+		return nil, errors.New("HTTP client is required")
+	}
+
+	return &htmlProvider{
+		httpClient: httpClient,
+	}, nil
+}
+
+// Capture fetches the html content for the given URL, the returned image is always nil
+func (p *htmlProvider) Capture(ctx context.Context, opts models.ScreenshotRequestOptions) (image.Image, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.URL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+
+	// Apply the request options which are meaningful without a browser
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if opts.UserAgent != nil {
+		req.Header.Set("User-Agent", *opts.UserAgent)
+	}
+	if opts.Authorization != nil {
+		req.Header.Set("Authorization", *opts.Authorization)
+	}
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+	for _, cookie := range opts.Cookies {
+		req.Header.Add("Cookie", cookie)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &models.UpstreamError{Service: req.URL.Host, StatusCode: resp.StatusCode, Err: errors.New("failed to retrieve content")}
+	}
+
+	htmlBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxHTMLContentSize))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return nil, string(htmlBytes), nil
+}
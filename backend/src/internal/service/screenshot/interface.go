@@ -3,20 +3,38 @@ package screenshot
 
 import (
 	"context"
+	"image"
+	"time"
 
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 )
 
 type ScreenshotService interface {
-	// Refresh refreshes the screenshot and html content for the given URL
-	// opts are the options to use for the screenshot request
-	// backDate is a flag to indicate if the responses should be backdated
-	// Prior to refresh it attempts to retrieve
-	Refresh(ctx context.Context, opts models.ScreenshotRequestOptions, backDate bool) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+	// Refresh captures the screenshot and html content for the given URL
+	// opts are the options to use for the screenshot request, their provider selects how the page is captured
+	// The responses are stored under the time of capture, which is recorded in their metadata
+	// The screenshot is nil when the provider doesn't capture screenshots
+	Refresh(ctx context.Context, opts models.ScreenshotRequestOptions) (*models.ScreenshotImage, *models.ScreenshotContent, error)
 
 	// Retrieve retrieves the screenshot and html content for the given URL
 	// opts are the options to use for the screenshot request
-	// when backDate is true, it will attempt to retrieve the last available screenshot
-	// when backDate is false, it will attempt to retrieve the latest screenshot
-	Retrieve(ctx context.Context, opts models.ScreenshotRequestOptions, backDate bool) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+	// capturedAt is the time the screenshot was captured at, as recorded in its metadata
+	// The screenshot is nil when the provider doesn't capture screenshots
+	Retrieve(ctx context.Context, opts models.ScreenshotRequestOptions, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+
+	// StoreOverlay stores the overlay of a visual diff next to the screenshot it highlights
+	// It returns the storage path of the overlay
+	StoreOverlay(ctx context.Context, screenshot *models.ScreenshotImage, overlay image.Image) (string, error)
+
+	// ListCaptures lists the captures stored for the given URL, most recent first
+	// opts are the options to use for the screenshot request
+	ListCaptures(ctx context.Context, opts models.ScreenshotRequestOptions) ([]models.ScreenshotCapture, error)
+}
+
+// CaptureProvider captures the content of a page, along with its screenshot where supported
+type CaptureProvider interface {
+	// Capture captures the page for the given options
+	// It returns the screenshot and the html content of the page
+	// The screenshot is nil for providers which only fetch the content
+	Capture(ctx context.Context, opts models.ScreenshotRequestOptions) (image.Image, string, error)
 }
@@ -3,6 +3,7 @@ package screenshot
 
 import (
 	"github.com/wizenheimer/byrd/src/internal/client"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/repository/screenshot"
 )
 
@@ -23,7 +24,7 @@ func WithHTTPClient(client *client.HTTPClient) ScreenshotServiceOption {
 	}
 }
 
-// WithQPS sets the QPS limit
+// WithQPS sets the QPS limit of the capture API, a token bucket enforces it when positive
 func WithQPS(qps float64) ScreenshotServiceOption {
 	return func(s *screenshotService) {
 		s.qps = qps
@@ -50,3 +51,11 @@ func WithSignature(signature string) ScreenshotServiceOption {
 		s.signature = signature
 	}
 }
+
+// WithCaptureProvider registers the provider used to capture pages of the given provider type
+// This overrides the default provider for the type
+func WithCaptureProvider(providerType models.CaptureProviderType, provider CaptureProvider) ScreenshotServiceOption {
+	return func(s *screenshotService) {
+		s.providers[providerType] = provider
+	}
+}
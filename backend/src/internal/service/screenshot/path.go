@@ -2,46 +2,43 @@
 package screenshot
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/wizenheimer/byrd/src/internal/constants"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 )
 
-// GeneratePath generates a path for a given hash, year, week number and run id
-func generatePath(hash string, year, weekNumber int, runID string) (string, error) {
-	if weekNumber < 1 || weekNumber > 52 {
-		return "", fmt.Errorf("invalid week number: %d", weekNumber)
-	}
+// overlaySuffix is the suffix of the overlays stored next to the screenshots
+const overlaySuffix = ".overlay"
+
+// maxTimestamp is the largest unix timestamp that can be encoded in a path
+// Timestamps are subtracted from it so that paths sort in reverse chronological order
+const maxTimestamp int64 = 9_999_999_999
 
-	if year < 2000 || year > 2100 {
-		return "", fmt.Errorf("invalid year: %d", year)
+// generatePath generates a path for a given hash and capture time
+func generatePath(hash string, capturedAt time.Time) (string, error) {
+	if capturedAt.IsZero() {
+		return "", errors.New("capture time is required")
 	}
 
-	if runID != constants.FirstRunID && runID != constants.LastRunID {
-		return "", fmt.Errorf("invalid run ID: %s", runID)
+	timestamp := capturedAt.Unix()
+	if timestamp < 0 || timestamp > maxTimestamp {
+		return "", fmt.Errorf("invalid capture time: %v", capturedAt)
 	}
 
 	// Generates a path that sorts in reverse chronological order
 	// This is useful for listing the most recent content first
-	reverseYear := 9999 - year
-	reverseWeek := 53 - weekNumber // 53 since ISO weeks go from 1-53
-	reverseRun := constants.LastRunID
-	if runID == constants.LastRunID {
-		reverseRun = constants.FirstRunID // Reverse the run IDs too
-	}
+	reverseTimestamp := maxTimestamp - timestamp
 
-	return fmt.Sprintf("%s/%04d-%02d-%s", hash, reverseYear, reverseWeek, reverseRun), nil
+	return fmt.Sprintf("%s/%010d", hash, reverseTimestamp), nil
 }
 
-// GetCurrentScreenshotPath returns the path to the current screenshot for a given url
-func getCurrentScreenshotPath(opts models.ScreenshotRequestOptions) (string, error) {
-	hash := opts.Hash()
-
-	year, weekNumber, runID := getCurrentTimeComponents(true)
-	path, err := generatePath(hash, year, weekNumber, runID)
+// getScreenshotPath returns the path to the screenshot captured at the given time for a given url
+func getScreenshotPath(opts models.ScreenshotRequestOptions, capturedAt time.Time) (string, error) {
+	path, err := generatePath(opts.Hash(), capturedAt)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate path: %w", err)
 	}
@@ -49,12 +46,9 @@ func getCurrentScreenshotPath(opts models.ScreenshotRequestOptions) (string, err
 	return fmt.Sprintf("images/%s", path), nil
 }
 
-// GetCurrentContentPath returns the path to the current content for a given url
-func getCurrentContentPath(opts models.ScreenshotRequestOptions) (string, error) {
-	hash := opts.Hash()
-
-	year, weekNumber, runID := getCurrentTimeComponents(true)
-	path, err := generatePath(hash, year, weekNumber, runID)
+// getContentPath returns the path to the content captured at the given time for a given url
+func getContentPath(opts models.ScreenshotRequestOptions, capturedAt time.Time) (string, error) {
+	path, err := generatePath(opts.Hash(), capturedAt)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate path: %w", err)
 	}
@@ -62,83 +56,38 @@ func getCurrentContentPath(opts models.ScreenshotRequestOptions) (string, error)
 	return fmt.Sprintf("text/%s", path), nil
 }
 
-// GetPreviousScreenshotPath returns the path to the previous screenshot for a given url
-func getPreviousScreenshotPath(opts models.ScreenshotRequestOptions) (string, error) {
-	hash := opts.Hash()
-
-	year, weekNumber, runID := getPreviousTimeComponents(true)
-	path, err := generatePath(hash, year, weekNumber, runID)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate path: %w", err)
-	}
-
-	return fmt.Sprintf("images/%s", path), nil
+// getOverlayPath returns the path to the overlay of a visual diff, stored next to the screenshot it highlights
+func getOverlayPath(screenshotPath string) string {
+	return screenshotPath + overlaySuffix
 }
 
-// GetPreviousContentPath returns the path to the previous content for a given url
-func getPreviousContentPath(opts models.ScreenshotRequestOptions) (string, error) {
-	hash := opts.Hash()
-
-	year, weekNumber, runID := getPreviousTimeComponents(true)
-	path, err := generatePath(hash, year, weekNumber, runID)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate path: %w", err)
-	}
-
-	return fmt.Sprintf("text/%s", path), nil
+// isOverlayPath returns true if the path is the path to an overlay
+func isOverlayPath(path string) bool {
+	return strings.HasSuffix(path, overlaySuffix)
 }
 
-// getCurrentTimeComponents returns the current year, week number, and run ID
-func getCurrentTimeComponents(enableBucketing bool) (year, weekNumber int, runID string) {
-	now := time.Now()
-	year, weekNumber = now.ISOWeek()
-
-	currentWeekDay := now.Weekday()
-	if currentWeekDay == 0 { // Convert Sunday (0) to 7
-		currentWeekDay = 7
-	}
-
-	if enableBucketing {
-		if int(currentWeekDay) <= 3 { // Monday, Tuesday, Wednesday
-			runID = constants.FirstRunID
-		} else { // Thursday, Friday, Saturday, Sunday
-			runID = constants.LastRunID
-		}
-	} else {
-		runID = strconv.Itoa(int(currentWeekDay))
-	}
+// getScreenshotPrefix returns the prefix under which all screenshots for a given url are stored
+func getScreenshotPrefix(opts models.ScreenshotRequestOptions) string {
+	return fmt.Sprintf("images/%s/", opts.Hash())
+}
 
-	return year, weekNumber, runID
+// getContentPrefix returns the prefix under which all content for a given url is stored
+func getContentPrefix(opts models.ScreenshotRequestOptions) string {
+	return fmt.Sprintf("text/%s/", opts.Hash())
 }
 
-// getPreviousTimeComponents returns the previous year, week number, and run ID
-func getPreviousTimeComponents(enableBucketing bool) (year, weekNumber int, runID string) {
-	now := time.Now()
-	currentYear, currentWeek := now.ISOWeek()
+// parseCaptureTime recovers the capture time from a path generated by generatePath
+func parseCaptureTime(path string) (time.Time, error) {
+	segment := path[strings.LastIndex(path, "/")+1:]
 
-	currentWeekDay := now.Weekday()
-	if currentWeekDay == 0 { // Convert Sunday (0) to 7
-		currentWeekDay = 7
+	reverseTimestamp, err := strconv.ParseInt(segment, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid path %s: %w", path, err)
 	}
 
-	if enableBucketing {
-		if int(currentWeekDay) <= 3 { // Monday, Tuesday, Wednesday
-			runID = constants.LastRunID
-			if currentWeek > 1 {
-				year = currentYear
-				weekNumber = currentWeek - 1
-			} else {
-				year = currentYear - 1
-				weekNumber = 52
-			}
-		} else { // Thursday, Friday, Saturday, Sunday
-			runID = constants.FirstRunID
-			year = currentYear
-			weekNumber = currentWeek
-		}
-	} else {
-		runID = currentWeekDay.String()
+	if reverseTimestamp < 0 || reverseTimestamp > maxTimestamp {
+		return time.Time{}, fmt.Errorf("invalid path %s: timestamp out of range", path)
 	}
 
-	return year, weekNumber, runID
+	return time.Unix(maxTimestamp-reverseTimestamp, 0).UTC(), nil
 }
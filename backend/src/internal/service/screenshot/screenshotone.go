@@ -0,0 +1,159 @@
+// ./src/internal/service/screenshot/screenshotone.go
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	_ "image/jpeg" // Register JPEG format
+	_ "image/png"  // Register PNG format
+
+	"github.com/wizenheimer/byrd/src/internal/client"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+// screenshotOneProvider captures the screenshot and the rendered html content using ScreenshotOne
+type screenshotOneProvider struct {
+	httpClient *client.HTTPClient
+	origin     string
+	key        string
+}
+
+var _ CaptureProvider = (*screenshotOneProvider)(nil)
+
+// NewScreenshotOneProvider creates a capture provider backed by ScreenshotOne
+func NewScreenshotOneProvider(httpClient *client.HTTPClient, origin, key string) (CaptureProvider, error) {
+	if httpClient == nil {
+		return nil, errors.New("HTTP client is required")
+	}
+	if key == "" {
+		return nil, errors.New("screenshot key is required")
+	}
+	if origin == "" {
+		return nil, errors.New("screenshot origin is required")
+	}
+
+	return &screenshotOneProvider{
+		httpClient: httpClient,
+		origin:     origin,
+		key:        key,
+	}, nil
+}
+
+// Capture captures the screenshot and html content for the given URL
+// it ensures that the screenshot and content are fetched and aren't null before returning
+func (p *screenshotOneProvider) Capture(ctx context.Context, opts models.ScreenshotRequestOptions) (image.Image, string, error) {
+	defaultOpt := models.GetDefaultScreenshotRequestOptions(opts.URL)
+	mergedOpt := models.MergeScreenshotRequestOptions(defaultOpt, opts)
+
+	// The provider selects this implementation, it isn't an option understood by ScreenshotOne
+	mergedOpt.Provider = nil
+
+	req, err := p.createScreenshotRequest(ctx, http.MethodGet, "take", mergedOpt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	img, err := p.getScreenshot(resp)
+	if err != nil {
+		return nil, "", err
+	} else if img == nil {
+		return nil, "", errors.New("failed to retrieve screenshot")
+	}
+
+	content, err := p.getContent(resp)
+	if err != nil {
+		return nil, "", err
+	} else if content == nil {
+		return nil, "", errors.New("failed to retrieve content")
+	}
+
+	return *img, *content, nil
+}
+
+func (p *screenshotOneProvider) getScreenshot(resp *http.Response) (*image.Image, error) {
+	if resp == nil {
+		return nil, errors.New("received nil response")
+	}
+
+	imageContentTypes := []string{
+		"image/png",
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !utils.Contains(imageContentTypes, contentType) {
+		return nil, fmt.Errorf("received unexpected content type: %v", contentType)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &img, nil
+}
+
+func (p *screenshotOneProvider) getContent(resp *http.Response) (*string, error) {
+	if resp == nil {
+		return nil, errors.New("received nil response")
+	}
+	renderedURL := resp.Header.Get("X-ScreenshotOne-Content-URL")
+	if renderedURL == "" {
+		return nil, errors.New("no content URL found in headers, cannot proceed with rendering")
+	}
+
+	htmlResp, err := http.Get(renderedURL)
+	if err != nil {
+		return nil, err
+	}
+	if htmlResp == nil {
+		return nil, errors.New("received nil response from content URL")
+	}
+	defer htmlResp.Body.Close()
+	if htmlResp.StatusCode != http.StatusOK {
+		return nil, &models.UpstreamError{Service: htmlResp.Request.URL.Host, StatusCode: htmlResp.StatusCode, Err: errors.New("failed to retrieve content")}
+	}
+
+	htmlBytes, err := io.ReadAll(htmlResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(htmlBytes)
+	return &content, nil
+}
+
+// CreateScreenshotRequest creates a new HTTP request with query parameters from a struct
+func (p *screenshotOneProvider) createScreenshotRequest(ctx context.Context, requestMethod, requestPath string, opts interface{}) (*http.Request, error) {
+	requestURL := fmt.Sprintf("%s/%s", strings.TrimRight(p.origin, "/"), strings.TrimLeft(requestPath, "/"))
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL: %w", err)
+	}
+
+	// Convert struct to query parameters
+	q := u.Query()
+	q.Set("access_key", p.key)
+	addStructToQuery(q, opts)
+	u.RawQuery = q.Encode()
+
+	// Create request with context
+	req, err := http.NewRequestWithContext(ctx, requestMethod, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	return req, nil
+}
@@ -4,6 +4,10 @@ package screenshot
 import (
 	"context"
 	"errors"
+	"fmt"
+	"image"
+	"sort"
+	"time"
 
 	_ "image/jpeg" // Register JPEG format
 	_ "image/png"  // Register PNG format
@@ -12,6 +16,8 @@ import (
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/repository/screenshot"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 type screenshotService struct {
@@ -21,12 +27,17 @@ type screenshotService struct {
 	origin     string
 	key        string
 	signature  string
-	logger     *logger.Logger
+	providers  map[models.CaptureProviderType]CaptureProvider
+	// limiters are the token buckets of the providers backed by a rate limited capture API
+	limiters map[models.CaptureProviderType]*rate.Limiter
+	logger   *logger.Logger
 }
 
 // NewScreenshotService creates a new screenshot service with the given options
 func NewScreenshotService(logger *logger.Logger, opts ...ScreenshotServiceOption) (ScreenshotService, error) {
 	s := &screenshotService{
+		providers: make(map[models.CaptureProviderType]CaptureProvider),
+		limiters:  make(map[models.CaptureProviderType]*rate.Limiter),
 		logger: logger.WithFields(
 			map[string]interface{}{
 				"module": "screenshot_service",
@@ -53,58 +64,90 @@ func NewScreenshotService(logger *logger.Logger, opts ...ScreenshotServiceOption
 		return nil, errors.New("screenshot origin is required")
 	}
 
+	// Register the default providers, unless overridden
+	if _, ok := s.providers[models.CaptureProviderScreenshotOne]; !ok {
+		provider, err := NewScreenshotOneProvider(s.httpClient, s.origin, s.key)
+		if err != nil {
+			return nil, err
+		}
+		s.providers[models.CaptureProviderScreenshotOne] = provider
+	}
+	// Captures through the screenshot API are bound by its rate limit, across the jobs and requests of the service
+	if s.qps > 0 {
+		s.limiters[models.CaptureProviderScreenshotOne] = rate.NewLimiter(rate.Limit(s.qps), 1)
+	}
+	if _, ok := s.providers[models.CaptureProviderHTML]; !ok {
+		// Pages are fetched directly, so they aren't bound by the rate limits of the screenshot client
+		htmlClient, err := NewPublicHTMLClient(logger)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := NewHTMLProvider(htmlClient)
+		if err != nil {
+			return nil, err
+		}
+		s.providers[models.CaptureProviderHTML] = provider
+	}
+
 	return s, nil
 }
 
-func (s *screenshotService) Refresh(ctx context.Context, opts models.ScreenshotRequestOptions, backDate bool) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
+func (s *screenshotService) Refresh(ctx context.Context, opts models.ScreenshotRequestOptions) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
 	if opts.URL == "" {
 		return nil, nil, errors.New("URL is required for generating screenshot")
 	}
 
-	// Step 1: Check if the screenshot exists in storage
-	existingScreenshot, existingContent, err := s.Retrieve(ctx, opts, backDate)
-	if err == nil {
-		return existingScreenshot, existingContent, nil
+	provider, ok := s.providers[opts.GetProvider()]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported capture provider: %s", opts.GetProvider())
 	}
 
-	// Step 2: If the screenshot does not exist in storage, create a new screenshot
-	// Step 2.1: Determine the screenshot path
-	screenshotPath, err := DeterminePath(opts, ContentTypeImage, backDate)
-	if err != nil {
-		return nil, nil, err
-	}
-	contentPath, err := DeterminePath(opts, ContentTypeContent, backDate)
-	if err != nil {
-		return nil, nil, err
+	// Step 1: Capture the screenshot image and content, once the provider has a token to spare
+	if limiter, ok := s.limiters[opts.GetProvider()]; ok {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, nil, fmt.Errorf("capture provider rate limit wait: %w", err)
+		}
 	}
 
-	// Step 2.2: Refresh the screenshot image and content
-	img, content, err := s.refreshScreenshot(ctx, opts)
+	img, content, err := provider.Capture(ctx, opts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Step 2.3: Get the screenshot metadata
-	metadata, err := getScreenshotMetadata(backDate)
+	// Step 2: Determine the screenshot paths from the time of capture
+	// Step 2.1: Get the screenshot metadata
+	metadata := models.NewScreenshotMetadata(time.Now())
+
+	// Step 2.2: Get the screenshot content path
+	contentPath, err := DeterminePath(opts, ContentTypeContent, metadata.CapturedAt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Step 2.4: Create the screenshot image and content
-	screenshotImage := &models.ScreenshotImage{
-		StoragePath: screenshotPath,
-		Image:       *img,
-		Metadata:    metadata,
-	}
+	// Step 2.3: Create the screenshot content
 	screenshotContent := &models.ScreenshotContent{
 		StoragePath: contentPath,
-		Content:     *content,
+		Content:     content,
 		Metadata:    metadata,
 	}
 
-	// Step 3: Store the screenshot in storage
-	if err := s.storage.StoreScreenshotImage(ctx, screenshotImage); err != nil {
-		return nil, nil, err
+	// Step 3: Store the screenshot in storage, for providers which capture one
+	var screenshotImage *models.ScreenshotImage
+	if img != nil {
+		screenshotPath, err := DeterminePath(opts, ContentTypeImage, metadata.CapturedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		screenshotImage = &models.ScreenshotImage{
+			StoragePath: screenshotPath,
+			Image:       img,
+			Metadata:    metadata,
+		}
+
+		if err := s.storage.StoreScreenshotImage(ctx, screenshotImage); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Step 4: Store the screenshot content in storage
@@ -115,30 +158,108 @@ func (s *screenshotService) Refresh(ctx context.Context, opts models.ScreenshotR
 	return screenshotImage, screenshotContent, nil
 }
 
-func (s *screenshotService) Retrieve(ctx context.Context, opts models.ScreenshotRequestOptions, backDate bool) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
-	// Step 1: Determine the screenshot path
-	screenshotPath, err := DeterminePath(opts, ContentTypeImage, backDate)
+func (s *screenshotService) Retrieve(ctx context.Context, opts models.ScreenshotRequestOptions, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
+	// Step 1: Retrieve the screenshot from storage, for providers which capture one
+	var screenshotImage *models.ScreenshotImage
+	if opts.GetProvider().CapturesScreenshot() {
+		screenshotPath, err := DeterminePath(opts, ContentTypeImage, capturedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		screenshotImage, err = s.storage.RetrieveScreenshotImage(ctx, screenshotPath)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Step 2: Determine the screenshot content path
+	contentPath, err := DeterminePath(opts, ContentTypeContent, capturedAt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Step 2: Retrieve the screenshot from storage
-	screenshotImage, err := s.storage.RetrieveScreenshotImage(ctx, screenshotPath)
+	// Step 3: Retrieve the screenshot content from storage
+	screenshotContent, err := s.storage.RetrieveScreenshotContent(ctx, contentPath)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Step 3: Determine the screenshot content path
-	contentPath, err := DeterminePath(opts, ContentTypeContent, backDate)
+	return screenshotImage, screenshotContent, nil
+}
+
+func (s *screenshotService) StoreOverlay(ctx context.Context, screenshot *models.ScreenshotImage, overlay image.Image) (string, error) {
+	if screenshot == nil || screenshot.StoragePath == "" {
+		return "", errors.New("screenshot path is required for storing its overlay")
+	}
+	if overlay == nil {
+		return "", errors.New("overlay is required")
+	}
+
+	overlayImage := &models.ScreenshotImage{
+		StoragePath: getOverlayPath(screenshot.StoragePath),
+		Image:       overlay,
+		Metadata:    screenshot.Metadata,
+	}
+
+	if err := s.storage.StoreScreenshotImage(ctx, overlayImage); err != nil {
+		return "", err
+	}
+
+	return overlayImage.StoragePath, nil
+}
+
+func (s *screenshotService) ListCaptures(ctx context.Context, opts models.ScreenshotRequestOptions) ([]models.ScreenshotCapture, error) {
+	// Step 1: List the screenshots and content stored for the url
+	screenshotPaths, err := s.storage.ListScreenshots(ctx, getScreenshotPrefix(opts))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Step 4: Retrieve the screenshot content from storage
-	screenshotContent, err := s.storage.RetrieveScreenshotContent(ctx, contentPath)
+	contentPaths, err := s.storage.ListScreenshots(ctx, getContentPrefix(opts))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return screenshotImage, screenshotContent, nil
+	// Step 2: Group the paths by their time of capture
+	captures := make(map[time.Time]*models.ScreenshotCapture)
+	capture := func(path string) (*models.ScreenshotCapture, bool) {
+		capturedAt, err := parseCaptureTime(path)
+		if err != nil {
+			s.logger.Debug("skipping unrecognized path", zap.String("path", path), zap.Error(err))
+			return nil, false
+		}
+
+		if _, ok := captures[capturedAt]; !ok {
+			captures[capturedAt] = &models.ScreenshotCapture{CapturedAt: capturedAt}
+		}
+		return captures[capturedAt], true
+	}
+
+	for _, path := range screenshotPaths {
+		if isOverlayPath(path) {
+			continue
+		}
+		if c, ok := capture(path); ok {
+			c.ImagePath = path
+		}
+	}
+
+	for _, path := range contentPaths {
+		if c, ok := capture(path); ok {
+			c.ContentPath = path
+		}
+	}
+
+	// Step 3: Order the captures from the most recent to the oldest
+	timeline := make([]models.ScreenshotCapture, 0, len(captures))
+	for _, c := range captures {
+		timeline = append(timeline, *c)
+	}
+
+	sort.Slice(timeline, func(i, j int) bool {
+		return timeline[i].CapturedAt.After(timeline[j].CapturedAt)
+	})
+
+	return timeline, nil
 }
@@ -3,6 +3,7 @@ package screenshot
 
 import (
 	"fmt"
+	"time"
 
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 )
@@ -14,22 +15,13 @@ const (
 	ContentTypeContent ContentType = "content"
 )
 
-func DeterminePath(opts models.ScreenshotRequestOptions, contentType ContentType, backDate bool) (string, error) {
+// DeterminePath returns the storage path of the screenshot or content captured at the given time
+func DeterminePath(opts models.ScreenshotRequestOptions, contentType ContentType, capturedAt time.Time) (string, error) {
 	switch contentType {
 	case ContentTypeImage:
-		// Get the current screenshot path
-		if backDate {
-			return getPreviousScreenshotPath(opts)
-		} else {
-			return getCurrentScreenshotPath(opts)
-		}
+		return getScreenshotPath(opts, capturedAt)
 	case ContentTypeContent:
-		// Get the current content path
-		if backDate {
-			return getPreviousContentPath(opts)
-		} else {
-			return getCurrentContentPath(opts)
-		}
+		return getContentPath(opts, capturedAt)
 	default:
 		return "", fmt.Errorf("unsupported content type: %s", contentType)
 	}
@@ -2,153 +2,13 @@
 package screenshot
 
 import (
-	"context"
-	"errors"
 	"fmt"
-	"image"
-	"io"
-	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
-
-	_ "image/jpeg" // Register JPEG format
-	_ "image/png"  // Register PNG format
-
-	models "github.com/wizenheimer/byrd/src/internal/models/core"
-	"github.com/wizenheimer/byrd/src/pkg/utils"
 )
 
-// refreshScreenshot refreshes the screenshot and html content for the given URL
-// it ensures that the screenshot and content are fetched and aren't null before returning
-func (s *screenshotService) refreshScreenshot(ctx context.Context, opts models.ScreenshotRequestOptions) (*image.Image, *string, error) {
-	defaultOpt := models.GetDefaultScreenshotRequestOptions(opts.URL)
-	mergedOpt := models.MergeScreenshotRequestOptions(defaultOpt, opts)
-
-	req, err := s.createScreenshotRequest(ctx, http.MethodGet, "take", mergedOpt)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	img, err := s.getScreenshot(resp)
-	if err != nil {
-		return nil, nil, err
-	} else if img == nil {
-		return nil, nil, errors.New("failed to retrieve screenshot")
-	}
-
-	content, err := s.getContent(resp)
-	if err != nil {
-		return nil, nil, err
-	} else if content == nil {
-		return nil, nil, errors.New("failed to retrieve content")
-	}
-
-	return img, content, nil
-}
-
-func getScreenshotMetadata(backDate bool) (*models.ScreenshotMetadata, error) {
-	var currentDayString string
-	var currentWeek int
-	var currentYear int
-	if backDate {
-		currentYear, currentWeek, currentDayString = getPreviousTimeComponents(true)
-	} else {
-		currentYear, currentWeek, currentDayString = getCurrentTimeComponents(true)
-	}
-	currentDay, err := strconv.Atoi(currentDayString)
-	if err != nil {
-		return nil, err
-	}
-
-	return &models.ScreenshotMetadata{
-		Year:       currentYear,
-		WeekNumber: currentWeek,
-		WeekDay:    currentDay,
-	}, nil
-}
-
-func (s *screenshotService) getScreenshot(resp *http.Response) (*image.Image, error) {
-	if resp == nil {
-		return nil, errors.New("received nil response")
-	}
-
-	imageContentTypes := []string{
-		"image/png",
-	}
-
-	contentType := resp.Header.Get("Content-Type")
-	if !utils.Contains(imageContentTypes, contentType) {
-		return nil, fmt.Errorf("received unexpected content type: %v", contentType)
-	}
-
-	img, _, err := image.Decode(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return &img, nil
-}
-
-func (s *screenshotService) getContent(resp *http.Response) (*string, error) {
-	if resp == nil {
-		return nil, errors.New("received nil response")
-	}
-	renderedURL := resp.Header.Get("X-ScreenshotOne-Content-URL")
-	if renderedURL == "" {
-		return nil, errors.New("no content URL found in headers, cannot proceed with rendering")
-	}
-
-	htmlResp, err := http.Get(renderedURL)
-	if err != nil {
-		return nil, err
-	}
-	if htmlResp == nil {
-		return nil, errors.New("received nil response from content URL")
-	}
-	defer htmlResp.Body.Close()
-	if htmlResp.StatusCode != http.StatusOK {
-		return nil, errors.New("failed to retrieve content")
-	}
-
-	htmlBytes, err := io.ReadAll(htmlResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	content := string(htmlBytes)
-	return &content, nil
-}
-
-// CreateScreenshotRequest creates a new HTTP request with query parameters from a struct
-func (s *screenshotService) createScreenshotRequest(ctx context.Context, requestMethod, requestPath string, opts interface{}) (*http.Request, error) {
-	requestURL := fmt.Sprintf("%s/%s", strings.TrimRight(s.origin, "/"), strings.TrimLeft(requestPath, "/"))
-	u, err := url.Parse(requestURL)
-	if err != nil {
-		return nil, fmt.Errorf("parsing URL: %w", err)
-	}
-
-	// Convert struct to query parameters
-	q := u.Query()
-	q.Set("access_key", s.key)
-	addStructToQuery(q, opts)
-	u.RawQuery = q.Encode()
-
-	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, requestMethod, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	return req, nil
-}
-
 // addStructToQuery converts a struct to query parameters
 func addStructToQuery(q url.Values, data interface{}) {
 	val := reflect.ValueOf(data)
@@ -0,0 +1,111 @@
+// ./src/internal/service/webhook/delivery.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+const (
+	// SignatureHeader holds the signature of the timestamp and the payload, computed with the secret of the webhook
+	SignatureHeader = "X-Byrd-Signature"
+
+	// TimestampHeader holds the unix time the payload was signed at
+	TimestampHeader = "X-Byrd-Timestamp"
+
+	// EventHeader holds the type of the event
+	EventHeader = "X-Byrd-Event"
+
+	// DeliveryHeader holds the unique identifier of the delivery, shared by its attempts
+	DeliveryHeader = "X-Byrd-Delivery"
+)
+
+// maxDrainedResponseLength bounds the response body read, and discarded, to reuse the connection
+const maxDrainedResponseLength = 64 << 10
+
+// attempt posts the payload of the delivery to the webhook, and records the outcome
+// The delivery is left pending when it's retried, and failed once it isn't
+func (s *webhookService) attempt(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery, attempt int, retry bool) (*models.WebhookDelivery, error) {
+	responseStatus, err := s.post(ctx, webhook, delivery)
+
+	outcome := models.WebhookDeliveryAttempt{
+		Status:         models.WebhookDeliveryStatusSucceeded,
+		Attempt:        attempt,
+		ResponseStatus: responseStatus,
+		AttemptedAt:    time.Now(),
+	}
+	if err != nil {
+		outcome.Error = err.Error()
+		outcome.Status = models.WebhookDeliveryStatusFailed
+		if retry && attempt < s.retryPolicy.MaxAttempts {
+			nextAttemptAt := outcome.AttemptedAt.Add(s.retryPolicy.Backoff(attempt))
+			outcome.Status = models.WebhookDeliveryStatusPending
+			outcome.NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	recorded, recordErr := s.repo.RecordDeliveryAttempt(ctx, delivery.ID, outcome)
+	if recordErr != nil {
+		s.logDeliveryError(delivery, "failed to record webhook delivery attempt", recordErr)
+		return nil, recordErr
+	}
+
+	if err != nil {
+		s.logDeliveryError(delivery, "failed to deliver webhook event", err)
+	}
+
+	return recorded, nil
+}
+
+// post sends the signed payload of the delivery to the webhook
+// It returns the status code of the response, nil when the endpoint didn't respond
+// Responses outside of the 2xx range, redirects among them, are errors
+// The body of the response is never recorded, so that the delivery log doesn't echo what the endpoint returned
+func (s *webhookService) post(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) (*int, error) {
+	req, err := newSignedRequest(ctx, webhook, delivery, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post webhook event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedResponseLength))
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		return &status, fmt.Errorf("webhook responded with status %d", status)
+	}
+
+	return &status, nil
+}
+
+// newSignedRequest prepares the request posting the payload of the delivery, signed at the time
+func newSignedRequest(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery, signedAt time.Time) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := signedAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Byrd-Webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, utils.SignPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	return req, nil
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+)
+
+func TestPostSignsPayload(t *testing.T) {
+	secret, err := utils.GenerateSigningSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+
+	responseStatus := http.StatusNoContent
+	var verified bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		verified = utils.ValidatePayloadSignature(secret, timestamp, body, r.Header.Get(SignatureHeader))
+		w.WriteHeader(responseStatus)
+	}))
+	defer server.Close()
+
+	s := &webhookService{client: server.Client()}
+	webhook := models.Webhook{ID: uuid.New(), URL: server.URL, Secret: secret}
+	delivery := models.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: webhook.ID,
+		EventType: models.WebhookEventTest,
+		Payload:   []byte(`{"type":"webhook.test"}`),
+	}
+
+	status, err := s.post(context.Background(), webhook, delivery)
+	if err != nil || status == nil || *status != http.StatusNoContent {
+		t.Fatalf("expected the delivery to succeed, got status %v and error %v", status, err)
+	}
+	if !verified {
+		t.Fatal("expected the signature to match the payload")
+	}
+
+	// Signatures don't carry over to another secret
+	if utils.ValidatePayloadSignature("another secret", 0, delivery.Payload, utils.SignPayload(secret, 0, delivery.Payload)) {
+		t.Fatal("expected the signature to be rejected with another secret")
+	}
+
+	responseStatus = http.StatusBadGateway
+	status, err = s.post(context.Background(), webhook, delivery)
+	if err == nil || status == nil || *status != http.StatusBadGateway {
+		t.Fatalf("expected the delivery to fail with the response status, got status %v and error %v", status, err)
+	}
+}
@@ -0,0 +1,47 @@
+// ./src/internal/service/webhook/interface.go
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// WebhookService manages the webhooks of the workspaces and delivers their events
+// Payloads are signed with the secret of the webhook, and retried with backoff until acknowledged
+type WebhookService interface {
+	// CreateWebhook creates a webhook for the workspace
+	// The secret signing its payloads is only returned here
+	CreateWebhook(ctx context.Context, workspaceID uuid.UUID, props models.WebhookProps) (*models.Webhook, error)
+
+	// GetWebhook returns a webhook of the workspace, without its secret
+	GetWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.Webhook, error)
+
+	// ListWebhooks lists the webhooks of the workspace, without their secret
+	ListWebhooks(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Webhook, bool, error)
+
+	// UpdateWebhook replaces the settings of a webhook of the workspace
+	UpdateWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID, props models.WebhookProps) (*models.Webhook, error)
+
+	// DeleteWebhook deletes a webhook of the workspace
+	DeleteWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) error
+
+	// ListDeliveries lists the deliveries of a webhook of the workspace, latest first
+	ListDeliveries(ctx context.Context, workspaceID, webhookID uuid.UUID, limit, offset *int) ([]models.WebhookDelivery, bool, error)
+
+	// SendTestEvent delivers a test event to a webhook of the workspace, even when it's paused
+	// The event is attempted once, and the delivery returned along with its outcome
+	SendTestEvent(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.WebhookDelivery, error)
+
+	// Publish delivers an event to the active webhooks of the workspace subscribed to it
+	// The deliveries are logged before returning, and attempted by the worker
+	Publish(ctx context.Context, workspaceID uuid.UUID, eventType models.WebhookEventType, data any) error
+
+	// Start attempts the due deliveries in the background until the context is done, or the service is shut down
+	// Deliveries are claimed from the delivery log, so that they are retried across restarts
+	Start(ctx context.Context) error
+
+	// Shutdown stops attempting the due deliveries, and waits for the attempts under way to be recorded or the context to be done
+	Shutdown(ctx context.Context) error
+}
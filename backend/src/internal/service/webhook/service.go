@@ -0,0 +1,273 @@
+// ./src/internal/service/webhook/service.go
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wizenheimer/byrd/src/internal/client"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/repository/webhook"
+	"github.com/wizenheimer/byrd/src/pkg/logger"
+	"github.com/wizenheimer/byrd/src/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// compile time check if the interface is implemented
+var _ WebhookService = (*webhookService)(nil)
+
+// MaxWebhookQueryLimit is the maximum number of webhooks or deliveries listed at once
+const MaxWebhookQueryLimit = 50
+
+// WorkerConfig configures the attempts at the deliveries
+type WorkerConfig struct {
+	// PollInterval is how often the delivery log is checked for due deliveries
+	PollInterval time.Duration
+
+	// BatchSize is the number of deliveries claimed at once
+	BatchSize int
+
+	// SendTimeout bounds each attempt at a delivery
+	SendTimeout time.Duration
+}
+
+type webhookService struct {
+	repo webhook.WebhookRepository
+
+	// retryPolicy spaces out the attempts at a delivery
+	retryPolicy models.JobRetryPolicy
+
+	// client posts the events to the webhooks
+	client *http.Client
+
+	config WorkerConfig
+
+	// wake signals the worker that deliveries were published, so that they don't wait for the next poll
+	wake chan struct{}
+
+	// stop stops the worker, which closes stopped once the attempts under way were recorded
+	stop    context.CancelFunc
+	stopped chan struct{}
+
+	logger *logger.Logger
+}
+
+// NewWebhookService creates a new webhook service
+// Each attempt at a delivery times out after the send timeout, and is retried according to the retry policy
+// Webhook URLs are user supplied, so the events are only posted to public addresses, and redirects aren't followed
+func NewWebhookService(repo webhook.WebhookRepository, retryPolicy models.JobRetryPolicy, config WorkerConfig, logger *logger.Logger) (WebhookService, error) {
+	if repo == nil {
+		return nil, errors.New("webhook repository is required")
+	}
+	if config.PollInterval <= 0 || config.BatchSize <= 0 || config.SendTimeout <= 0 {
+		return nil, errors.New("webhook poll interval, batch size and send timeout must be positive")
+	}
+
+	return &webhookService{
+		repo:        repo,
+		retryPolicy: retryPolicy,
+		client:      client.NewPublicHTTPClient(config.SendTimeout, 0),
+		config:      config,
+		wake:        make(chan struct{}, 1),
+		logger:      logger.WithFields(map[string]interface{}{"module": "webhook_service"}),
+	}, nil
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, workspaceID uuid.UUID, props models.WebhookProps) (*models.Webhook, error) {
+	if err := validateWebhookURL(props.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateSigningSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateWebhook(ctx, workspaceID, secret, props)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.repo.GetWebhook(ctx, workspaceID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := webhook.Redacted()
+	return &redacted, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Webhook, bool, error) {
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, false, err
+	}
+
+	webhooks, hasMore, err := s.repo.ListWebhooks(ctx, workspaceID, limit, offset)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for i := range webhooks {
+		webhooks[i] = webhooks[i].Redacted()
+	}
+
+	return webhooks, hasMore, nil
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID, props models.WebhookProps) (*models.Webhook, error) {
+	if err := validateWebhookURL(props.URL); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.UpdateWebhook(ctx, workspaceID, webhookID, props)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := webhook.Redacted()
+	return &redacted, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, workspaceID, webhookID uuid.UUID) error {
+	return s.repo.DeleteWebhook(ctx, workspaceID, webhookID)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, workspaceID, webhookID uuid.UUID, limit, offset *int) ([]models.WebhookDelivery, bool, error) {
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, false, err
+	}
+
+	return s.repo.ListDeliveries(ctx, workspaceID, webhookID, limit, offset)
+}
+
+func (s *webhookService) SendTestEvent(ctx context.Context, workspaceID, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	webhook, err := s.repo.GetWebhook(ctx, workspaceID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := models.NewWebhookEvent(workspaceID, models.WebhookEventTest, models.WebhookTestEvent{
+		WebhookID: webhook.ID,
+		Message:   "This is a test event sent from Byrd",
+	})
+
+	// The delivery is never due, so that the worker doesn't attempt it as well
+	delivery, err := s.createDelivery(ctx, *webhook, event, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The outcome is reported right away, rather than retried in the background
+	return s.attempt(ctx, *webhook, *delivery, 1, false)
+}
+
+func (s *webhookService) Publish(ctx context.Context, workspaceID uuid.UUID, eventType models.WebhookEventType, data any) error {
+	webhooks, err := s.repo.ListSubscribedWebhooks(ctx, workspaceID, eventType)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	event := models.NewWebhookEvent(workspaceID, eventType, data)
+
+	now := time.Now()
+	var errs []error
+	for _, webhook := range webhooks {
+		if _, err := s.createDelivery(ctx, webhook, event, &now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Wake the worker up, unless it's already due to wake up
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *webhookService) Start(ctx context.Context) error {
+	ctx, s.stop = context.WithCancel(ctx)
+	s.stopped = make(chan struct{})
+
+	go func() {
+		defer close(s.stopped)
+		s.run(ctx)
+	}()
+	return nil
+}
+
+func (s *webhookService) Shutdown(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+
+	s.stop()
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// createDelivery logs the pending delivery of the event to the webhook, due at the next attempt time
+func (s *webhookService) createDelivery(ctx context.Context, webhook models.Webhook, event models.WebhookEvent, nextAttemptAt *time.Time) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	return s.repo.CreateDelivery(ctx, webhook.ID, event, payload, nextAttemptAt)
+}
+
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+
+	// Hostnames are checked once resolved, when the events are posted
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return errors.New("webhook URL must point to a public address")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !client.IsPublicAddress(addr) {
+		return errors.New("webhook URL must point to a public address")
+	}
+
+	return nil
+}
+
+func validatePagination(limit, offset *int) error {
+	if limit != nil {
+		if *limit < 0 {
+			return errors.New("limit cannot be negative")
+		} else if *limit > MaxWebhookQueryLimit {
+			return fmt.Errorf("limit cannot exceed %d", MaxWebhookQueryLimit)
+		}
+	}
+
+	if offset != nil && *offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+
+	return nil
+}
+
+func (s *webhookService) logDeliveryError(delivery models.WebhookDelivery, msg string, err error) {
+	s.logger.Error(msg, zap.Any("webhookID", delivery.WebhookID), zap.Any("deliveryID", delivery.ID), zap.Error(err))
+}
@@ -0,0 +1,72 @@
+// ./src/internal/service/webhook/worker.go
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"go.uber.org/zap"
+)
+
+// run attempts the due deliveries on every poll, and as soon as events are published, until the context is done
+func (s *webhookService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.drain(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drain attempts the due deliveries batch by batch, until none is left
+func (s *webhookService) drain(ctx context.Context) {
+	// Claimed deliveries aren't due again before the lease expires, which outlasts an attempt at each of them
+	lease := 2 * s.config.SendTimeout
+
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.config.BatchSize, lease)
+		if err != nil {
+			s.logger.Error("failed to claim due webhook deliveries", zap.Error(err))
+			return
+		}
+
+		// Claimed deliveries are attempted even as the worker stops, so that their attempts are recorded rather than left to the lease
+		deliverCtx := context.WithoutCancel(ctx)
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(delivery models.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(deliverCtx, delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < s.config.BatchSize {
+			return
+		}
+	}
+}
+
+// deliver attempts the delivery to its webhook, and records the outcome
+// A failed attempt leaves the delivery pending until its backoff elapsed, or fails it once the attempts are exhausted
+func (s *webhookService) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	webhook, err := s.repo.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		// The delivery is claimed again once the lease expires
+		s.logDeliveryError(delivery, "failed to get webhook of the delivery", err)
+		return
+	}
+
+	_, _ = s.attempt(ctx, *webhook, delivery, delivery.Attempts+1, true)
+}
@@ -24,19 +24,38 @@ type WorkflowService interface {
 	// This would be called during the initialization of the service
 	Recover(ctx context.Context) error
 
+	// RunPipeline starts a run of the pipeline, limited to the items within the scope
+	// Each stage submits a job once the job of the previous stage completed, the run ends as soon as a stage doesn't
+	RunPipeline(ctx context.Context, stages []models.WorkflowType, scope models.JobScope) (*models.PipelineRun, error)
+
+	// Pipeline returns a pipeline run, along with the status of each of its stages
+	// This would be called by the client to follow a pipeline run
+	Pipeline(ctx context.Context, runID uuid.UUID) (*models.PipelineRun, error)
+
+	// ListPipelines returns the pipeline runs, optionally filtered by status
+	ListPipelines(ctx context.Context, status *models.PipelineStatus, limit, offset *int) ([]models.PipelineRun, error)
+
 	// Register registers a new executor to the workflow service
 	// This would be called during the initialization of the service
 	// Raises an error if the executor already exists
 	Register(workflowType models.WorkflowType, observer executor.WorkflowObserver) error
 
-	// Submits a new job to the workflow
-	// This would be called by the client to submit a new job
-	Submit(ctx context.Context, workflowType models.WorkflowType) (uuid.UUID, error)
+	// Submits a new job to the workflow, limited to the items within the scope
+	// This would be called by the client to submit a new job, an empty scope covers every item
+	Submit(ctx context.Context, workflowType models.WorkflowType, scope models.JobScope) (uuid.UUID, error)
 
 	// Stops a running job in the workflow
 	// This would be called by the client to stop a running job
 	Stop(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) error
 
+	// Pauses a running job in the workflow, keeping its checkpoint
+	// This would be called by the client to hold a job back during an outage of its dependencies
+	Pause(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) error
+
+	// Resumes a paused job in the workflow from its checkpoint
+	// This would be called by the client once the job can make progress again
+	Resume(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) error
+
 	// Gets a running job in the workflow
 	// This would be called by the client to get the status of a running job
 	State(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) (*models.Job, error)
@@ -45,7 +64,23 @@ type WorkflowService interface {
 	// This would be called by the client to get the list of running jobs
 	List(ctx context.Context, workflowType models.WorkflowType, jobStatus models.JobStatus) ([]models.Job, error)
 
-	// History returns the history of job runs
+	// History returns the history of job runs matching the filter
 	// This would be called by the client to get the history of job runs
-	History(ctx context.Context, limit, offset *int, workflowType *models.WorkflowType) ([]models.JobRecord, error)
+	History(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error)
+
+	// Events streams the progress of the jobs of a workflow until the context is done
+	// This would be called by the client to follow a job as it runs, when jobID is set only its events are streamed
+	Events(ctx context.Context, workflowType models.WorkflowType, jobID *uuid.UUID) (<-chan models.JobEvent, error)
+
+	// Record returns the record of a job, running or not
+	// This would be called by the client to get the duration and final state of a job
+	Record(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) (*models.JobRecord, error)
+
+	// ListFailures returns the dead-letter list of a job, optionally filtered by status
+	// This would be called by the client to find out which items failed in a job and why
+	ListFailures(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error)
+
+	// RetryFailures retries the pending failures of a job in the background
+	// When itemIDs is empty, every pending failure of the job is retried
+	RetryFailures(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID, itemIDs []uuid.UUID) ([]models.JobFailure, error)
 }
@@ -0,0 +1,256 @@
+// ./src/internal/service/workflow/pipeline.go
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+	"github.com/wizenheimer/byrd/src/internal/service/executor"
+	"go.uber.org/zap"
+)
+
+// maxPipelineErrors is the number of consecutive errors after which the current stage of a pipeline run fails
+const maxPipelineErrors = 3
+
+func pipelineLeaseName(runID uuid.UUID) string {
+	return fmt.Sprintf("pipeline:%s", runID.String())
+}
+
+// RunPipeline starts a run of the pipeline, limited to the items within the scope
+// Each stage submits a job once the job of the previous stage completed, the run ends as soon as a stage doesn't
+func (ws *workflowService) RunPipeline(ctx context.Context, stages []models.WorkflowType, scope models.JobScope) (*models.PipelineRun, error) {
+	if !ws.live.Load() {
+		return nil, errors.New("service is not live")
+	}
+
+	if err := models.ValidatePipeline(stages); err != nil {
+		return nil, err
+	}
+
+	for _, stage := range stages {
+		if _, ok := ws.executors.Load(stage); !ok {
+			return nil, fmt.Errorf("executor not found for %s", stage)
+		}
+	}
+
+	run := models.NewPipelineRun(stages, scope)
+
+	// Lease the run before persisting it, so that other replicas don't take it over
+	if _, err := ws.repository.AcquireLease(ctx, pipelineLeaseName(run.ID), ws.cluster.ReplicaID, ws.cluster.LeaseTTL); err != nil {
+		return nil, err
+	}
+
+	if err := ws.repository.CreatePipelineRun(ctx, *run); err != nil {
+		ws.releasePipeline(run.ID)
+		return nil, err
+	}
+
+	// The run is driven in the background, the caller gets a copy of it as it started
+	started := *run
+	started.Stages = slices.Clone(run.Stages)
+	ws.drivePipeline(run)
+
+	return &started, nil
+}
+
+// Pipeline returns a pipeline run, along with the status of each of its stages
+func (ws *workflowService) Pipeline(ctx context.Context, runID uuid.UUID) (*models.PipelineRun, error) {
+	run, err := ws.repository.GetPipelineRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// ListPipelines returns the pipeline runs, optionally filtered by status
+func (ws *workflowService) ListPipelines(ctx context.Context, status *models.PipelineStatus, limit, offset *int) ([]models.PipelineRun, error) {
+	return ws.repository.ListPipelineRuns(ctx, status, limit, offset)
+}
+
+// watchPipelines takes over the pipeline runs whose replica stopped renewing their lease
+func (ws *workflowService) watchPipelines(ctx context.Context) {
+	ws.recoverPipelines(ctx)
+
+	ticker := time.NewTicker(ws.cluster.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ws.recoverPipelines(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recoverPipelines drives the running pipeline runs which aren't leased by any replica
+func (ws *workflowService) recoverPipelines(ctx context.Context) {
+	status := models.PipelineStatusRunning
+	runs, err := ws.repository.ListPipelineRuns(ctx, &status, nil, nil)
+	if err != nil {
+		ws.logger.Error("failed to list running pipelines", zap.Error(err))
+		return
+	}
+
+	for index := range runs {
+		run := runs[index]
+		if _, ok := ws.pipelines.Load(run.ID); ok {
+			continue
+		}
+
+		acquired, err := ws.repository.AcquireLease(ctx, pipelineLeaseName(run.ID), ws.cluster.ReplicaID, ws.cluster.LeaseTTL)
+		if err != nil {
+			ws.logger.Error("failed to lease pipeline run", zap.Any("runID", run.ID), zap.Error(err))
+			continue
+		}
+		if !acquired {
+			continue
+		}
+
+		ws.logger.Info("taking over pipeline run", zap.Any("runID", run.ID))
+		ws.drivePipeline(&run)
+	}
+}
+
+// drivePipeline drives the run in the background, unless this replica drives it already
+func (ws *workflowService) drivePipeline(run *models.PipelineRun) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, loaded := ws.pipelines.LoadOrStore(run.ID, cancel); loaded {
+		cancel()
+		return
+	}
+
+	go func() {
+		defer ws.pipelines.Delete(run.ID)
+		defer ws.releasePipeline(run.ID)
+		ws.runPipeline(ctx, run)
+	}()
+}
+
+// runPipeline advances the run stage by stage until it ends, renewing its lease in between
+// It returns early if the run was stopped, or leased by another replica
+func (ws *workflowService) runPipeline(ctx context.Context, run *models.PipelineRun) {
+	ticker := time.NewTicker(ws.cluster.PollInterval)
+	defer ticker.Stop()
+
+	errs := 0
+	for {
+		index, ok := run.CurrentStage()
+		if !ok {
+			return
+		}
+
+		if err := ws.advancePipeline(ctx, run, index); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			errs++
+			ws.logger.Error("failed to advance pipeline run", zap.Any("runID", run.ID), zap.Any("stage", run.Stages[index].WorkflowType), zap.Int("errors", errs), zap.Error(err))
+			if errs >= maxPipelineErrors {
+				run.Fail(index)
+			}
+		} else {
+			errs = 0
+		}
+
+		if err := ws.repository.UpdatePipelineRun(ctx, *run); err != nil {
+			ws.logger.Error("failed to persist pipeline run", zap.Any("runID", run.ID), zap.Error(err))
+		}
+
+		// The next stage starts as soon as the previous one completed
+		if next, ok := run.CurrentStage(); ok && next != index && errs == 0 {
+			continue
+		}
+		if run.Status != models.PipelineStatusRunning {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		acquired, err := ws.repository.AcquireLease(ctx, pipelineLeaseName(run.ID), ws.cluster.ReplicaID, ws.cluster.LeaseTTL)
+		if err != nil {
+			// The lease lapses on its own if it can't be renewed in time
+			ws.logger.Error("failed to renew pipeline lease", zap.Any("runID", run.ID), zap.Error(err))
+		} else if !acquired {
+			ws.logger.Warn("pipeline run was taken over by another replica", zap.Any("runID", run.ID))
+			return
+		}
+	}
+}
+
+// advancePipeline submits the job of a pending stage, or updates a running stage from the state of its job
+// The job ID of a stage is persisted before its job is submitted, so that a stage isn't submitted twice once the run is taken over
+func (ws *workflowService) advancePipeline(ctx context.Context, run *models.PipelineRun, index int) error {
+	stage := run.Stages[index]
+
+	if stage.Status == models.PipelineStagePending || stage.JobID == nil {
+		run.PrepareStage(index, uuid.New())
+		if err := ws.repository.UpdatePipelineRun(ctx, *run); err != nil {
+			run.Stages[index] = stage
+			return err
+		}
+		stage = run.Stages[index]
+	}
+
+	if stage.Status == models.PipelineStageStarting {
+		if err := ws.submitJob(ctx, stage.WorkflowType, *stage.JobID, run.Scope); err != nil {
+			return err
+		}
+		run.StartStage(index, *stage.JobID)
+		return nil
+	}
+
+	job, err := ws.State(ctx, stage.WorkflowType, *stage.JobID)
+	if err != nil {
+		return err
+	}
+	run.UpdateStage(index, job.JobState)
+	return nil
+}
+
+// submitJob submits the job of a stage under its job ID, unless it was submitted already
+func (ws *workflowService) submitJob(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID, scope models.JobScope) error {
+	if !ws.live.Load() {
+		return errors.New("service is not live")
+	}
+
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return errors.New("executor not found")
+	}
+
+	return exc.(executor.WorkflowObserver).SubmitJob(ctx, jobID, scope)
+}
+
+// releasePipeline releases the lease of the run, so that another replica takes it over right away
+func (ws *workflowService) releasePipeline(runID uuid.UUID) {
+	if err := ws.repository.ReleaseLease(context.Background(), pipelineLeaseName(runID), ws.cluster.ReplicaID); err != nil {
+		ws.logger.Error("failed to release pipeline lease", zap.Any("runID", runID), zap.Error(err))
+	}
+}
+
+// stopPipelines stops driving the pipeline runs, their jobs keep running
+func (ws *workflowService) stopPipelines() {
+	if ws.stopWatch != nil {
+		ws.stopWatch()
+	}
+
+	ws.pipelines.Range(func(_, value interface{}) bool {
+		if cancel, ok := value.(context.CancelFunc); ok {
+			cancel()
+		}
+		return true
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
 	"github.com/wizenheimer/byrd/src/internal/recorder"
+	workflow_repo "github.com/wizenheimer/byrd/src/internal/repository/workflow"
 	"github.com/wizenheimer/byrd/src/internal/service/executor"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
@@ -20,9 +21,21 @@ type workflowService struct {
 	logger      *logger.Logger
 	errorRecord *recorder.ErrorRecorder
 	live        atomic.Bool
+
+	// repository persists the pipeline runs and holds the leases of the replicas driving them
+	repository workflow_repo.WorkflowRepository
+
+	// cluster is the configuration of this replica among the replicas sharing the pipeline runs
+	cluster models.ClusterConfig
+
+	// pipelines are the pipeline runs driven by this replica
+	pipelines sync.Map //map[uuid.UUID]context.CancelFunc
+
+	// stopWatch stops the loop taking over the pipeline runs of other replicas
+	stopWatch context.CancelFunc
 }
 
-func NewWorkflowService(logger *logger.Logger, errorRecord *recorder.ErrorRecorder) (WorkflowService, error) {
+func NewWorkflowService(repository workflow_repo.WorkflowRepository, cluster models.ClusterConfig, logger *logger.Logger, errorRecord *recorder.ErrorRecorder) (WorkflowService, error) {
 	if logger == nil {
 		return nil, errors.New("logger is required")
 	}
@@ -32,6 +45,8 @@ func NewWorkflowService(logger *logger.Logger, errorRecord *recorder.ErrorRecord
 			"module": "workflow_service",
 		}),
 		errorRecord: errorRecord,
+		repository:  repository,
+		cluster:     cluster.Normalize(),
 	}
 
 	return &ws, nil
@@ -59,6 +74,12 @@ func (ws *workflowService) Initialize(ctx context.Context) error {
 
 	// Start accepting new jobs
 	ws.live.Store(true)
+
+	// Resume the pipeline runs left behind, their stages submit jobs once the service is live
+	watchContext, stopWatch := context.WithCancel(context.Background())
+	ws.stopWatch = stopWatch
+	go ws.watchPipelines(watchContext)
+
 	return nil
 }
 
@@ -68,6 +89,9 @@ func (ws *workflowService) Shutdown(ctx context.Context) error {
 	// Stop accepting new jobs
 	ws.live.Store(false)
 
+	// Hand the pipeline runs over to the other replicas
+	ws.stopPipelines()
+
 	// Stop all running jobs
 	var errs []error
 	ws.executors.Range(func(key, value interface{}) bool {
@@ -127,9 +151,9 @@ func (ws *workflowService) Register(workflowType models.WorkflowType, executor e
 	return nil
 }
 
-// Submits a new job to the workflow
-// This would be called by the client to submit a new job
-func (ws *workflowService) Submit(ctx context.Context, workflowType models.WorkflowType) (uuid.UUID, error) {
+// Submits a new job to the workflow, limited to the items within the scope
+// This would be called by the client to submit a new job, an empty scope covers every item
+func (ws *workflowService) Submit(ctx context.Context, workflowType models.WorkflowType, scope models.JobScope) (uuid.UUID, error) {
 	if !ws.live.Load() {
 		return uuid.Nil, errors.New("service is not live")
 	}
@@ -139,7 +163,7 @@ func (ws *workflowService) Submit(ctx context.Context, workflowType models.Workf
 		return uuid.Nil, errors.New("executor not found")
 	}
 
-	jobID, err := exc.(executor.WorkflowObserver).Submit(ctx)
+	jobID, err := exc.(executor.WorkflowObserver).Submit(ctx, scope)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -167,6 +191,46 @@ func (ws *workflowService) Stop(ctx context.Context, workflowType models.Workflo
 	return executor.Cancel(ctx, jobID)
 }
 
+// Pauses a running job in the workflow, keeping its checkpoint
+// This would be called by the client to hold a job back during an outage of its dependencies
+func (ws *workflowService) Pause(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) error {
+	if !ws.live.Load() {
+		return errors.New("service is not live")
+	}
+
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return errors.New("executor not found")
+	}
+
+	executor, ok := exc.(executor.WorkflowObserver)
+	if !ok {
+		return errors.New("failed to cast executor to WorkflowObserver")
+	}
+
+	return executor.Pause(ctx, jobID)
+}
+
+// Resumes a paused job in the workflow from its checkpoint
+// This would be called by the client once the job can make progress again
+func (ws *workflowService) Resume(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) error {
+	if !ws.live.Load() {
+		return errors.New("service is not live")
+	}
+
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return errors.New("executor not found")
+	}
+
+	executor, ok := exc.(executor.WorkflowObserver)
+	if !ok {
+		return errors.New("failed to cast executor to WorkflowObserver")
+	}
+
+	return executor.Resume(ctx, jobID)
+}
+
 // Gets a running job in the workflow
 // This would be called by the client to get the status of a running job
 func (ws *workflowService) State(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) (*models.Job, error) {
@@ -199,9 +263,9 @@ func (ws *workflowService) List(ctx context.Context, workflowType models.Workflo
 	return jobs, nil
 }
 
-func (ws *workflowService) History(ctx context.Context, limit, offset *int, workflowType *models.WorkflowType) ([]models.JobRecord, error) {
+func (ws *workflowService) History(ctx context.Context, filter models.JobRecordFilter, limit, offset *int) ([]models.JobRecord, error) {
 	observers := make([]executor.WorkflowObserver, 0)
-	if workflowType == nil {
+	if filter.WorkflowType == nil {
 		// Get all executors history
 		ws.executors.Range(func(_, value interface{}) bool {
 			obs, ok := value.(executor.WorkflowObserver)
@@ -214,7 +278,7 @@ func (ws *workflowService) History(ctx context.Context, limit, offset *int, work
 		})
 	} else {
 		// Get specific executor history
-		exc, ok := ws.executors.Load(*workflowType)
+		exc, ok := ws.executors.Load(*filter.WorkflowType)
 		if !ok {
 			return nil, errors.New("executor not found")
 		}
@@ -228,7 +292,7 @@ func (ws *workflowService) History(ctx context.Context, limit, offset *int, work
 
 	var observerHistory []models.JobRecord
 	for _, observer := range observers {
-		history, err := observer.History(ctx, limit, offset)
+		history, err := observer.History(ctx, filter, limit, offset)
 		if err != nil {
 			return nil, err
 		}
@@ -241,3 +305,51 @@ func (ws *workflowService) History(ctx context.Context, limit, offset *int, work
 
 	return observerHistory, nil
 }
+
+// Events streams the progress of the jobs of a workflow until the context is done
+// This would be called by the client to follow a job as it runs, when jobID is set only its events are streamed
+func (ws *workflowService) Events(ctx context.Context, workflowType models.WorkflowType, jobID *uuid.UUID) (<-chan models.JobEvent, error) {
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return nil, errors.New("executor not found")
+	}
+
+	return exc.(executor.WorkflowObserver).Events(ctx, jobID)
+}
+
+// Record returns the record of a job, running or not
+// This would be called by the client to get the duration and final state of a job
+func (ws *workflowService) Record(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID) (*models.JobRecord, error) {
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return nil, errors.New("executor not found")
+	}
+
+	return exc.(executor.WorkflowObserver).Record(ctx, jobID)
+}
+
+// ListFailures returns the dead-letter list of a job, optionally filtered by status
+// This would be called by the client to find out which items failed in a job and why
+func (ws *workflowService) ListFailures(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID, status *models.JobFailureStatus, limit, offset *int) ([]models.JobFailure, error) {
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return nil, errors.New("executor not found")
+	}
+
+	return exc.(executor.WorkflowObserver).Failures(ctx, jobID, status, limit, offset)
+}
+
+// RetryFailures retries the pending failures of a job in the background
+// When itemIDs is empty, every pending failure of the job is retried
+func (ws *workflowService) RetryFailures(ctx context.Context, workflowType models.WorkflowType, jobID uuid.UUID, itemIDs []uuid.UUID) ([]models.JobFailure, error) {
+	if !ws.live.Load() {
+		return nil, errors.New("service is not live")
+	}
+
+	exc, ok := ws.executors.Load(workflowType)
+	if !ok {
+		return nil, errors.New("executor not found")
+	}
+
+	return exc.(executor.WorkflowObserver).RetryFailures(ctx, jobID, itemIDs)
+}
@@ -3,6 +3,7 @@ package workspace
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -94,6 +95,11 @@ func (ws *workspaceService) ListCompetitorsForWorkspace(ctx context.Context, wor
 	return competitors, hasMore, nil
 }
 
+// ListCompetitorsInScope lists the competitors of the workspace within the scope of a job
+func (ws *workspaceService) ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error) {
+	return ws.competitorService.ListCompetitorsInScope(ctx, workspaceID, scope)
+}
+
 // ListPagesForCompetitor lists the pages for a competitor
 func (ws *workspaceService) ListPagesForCompetitor(ctx context.Context, workspaceID, competitorID uuid.UUID, limit, offset *int) ([]models.Page, bool, error) {
 	pages, hasMore, err := ws.competitorService.ListCompetitorPages(ctx, competitorID, limit, offset)
@@ -114,6 +120,31 @@ func (ws *workspaceService) ListHistoryForPage(ctx context.Context, pageID uuid.
 	return pageHistory, hasMore, nil
 }
 
+// GetHistoryForPage gets a single history entry of a page
+func (ws *workspaceService) GetHistoryForPage(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error) {
+	return ws.competitorService.GetPageHistory(ctx, pageID, historyID)
+}
+
+// ListCapturesForPage lists the timeline of captures of a page, most recent first
+func (ws *workspaceService) ListCapturesForPage(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error) {
+	return ws.competitorService.ListPageCaptures(ctx, competitorID, pageID)
+}
+
+// GetCaptureForPage gets the screenshot and content of a page captured at the given time
+func (ws *workspaceService) GetCaptureForPage(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error) {
+	return ws.competitorService.GetPageCapture(ctx, competitorID, pageID, capturedAt)
+}
+
+// CompareCapturesForPage diffs the content of a page captured at the given times
+func (ws *workspaceService) CompareCapturesForPage(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error) {
+	return ws.competitorService.ComparePageCaptures(ctx, competitorID, pageID, from, to)
+}
+
+// DryRunContentSelectorsForPage extracts the content of the latest capture of a page using the given selectors
+func (ws *workspaceService) DryRunContentSelectorsForPage(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error) {
+	return ws.competitorService.ExtractPageContent(ctx, competitorID, pageID, selectors)
+}
+
 func (ws *workspaceService) RemovePageFromWorkspace(ctx context.Context, competitorID, pageID uuid.UUID) error {
 	return ws.competitorService.RemovePagesFromCompetitor(ctx, competitorID, []uuid.UUID{pageID})
 }
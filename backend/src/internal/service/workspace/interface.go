@@ -3,6 +3,7 @@ package workspace
 
 import (
 	"context"
+	"time"
 
 	// "github.com/clerk/clerk-sdk-go/v2"
 	"github.com/google/uuid"
@@ -39,6 +40,12 @@ type WorkspaceService interface {
 
 	UpdateWorkspacePlan(ctx context.Context, workspaceID uuid.UUID, plan models.WorkspacePlan) error
 
+	// GetWorkspaceNoiseRules gets the noise rules applied to the pages of a workspace
+	GetWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID) ([]models.NoiseRule, error)
+
+	// UpdateWorkspaceNoiseRules replaces the noise rules applied to the pages of a workspace
+	UpdateWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID, noiseRules []models.NoiseRule) error
+
 	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) (models.WorkspaceStatus, error)
 
 	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID, limit, offset *int, roleFilter *models.WorkspaceRole) ([]models.WorkspaceUser, bool, error)
@@ -77,10 +84,24 @@ type WorkspaceService interface {
 
 	ListCompetitorsForWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset *int) ([]models.Competitor, bool, error)
 
+	// ListCompetitorsInScope lists the competitors of the workspace within the scope of a job
+	// Every competitor of the workspace is within an empty scope
+	ListCompetitorsInScope(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) ([]models.Competitor, error)
+
 	ListPagesForCompetitor(ctx context.Context, workspaceID, competitorID uuid.UUID, limit, offset *int) ([]models.Page, bool, error)
 
 	ListHistoryForPage(ctx context.Context, pageID uuid.UUID, limit, offset *int) ([]models.PageHistory, bool, error)
 
+	GetHistoryForPage(ctx context.Context, pageID, historyID uuid.UUID) (*models.PageHistory, error)
+
+	ListCapturesForPage(ctx context.Context, competitorID, pageID uuid.UUID) ([]models.ScreenshotCapture, error)
+
+	GetCaptureForPage(ctx context.Context, competitorID, pageID uuid.UUID, capturedAt time.Time) (*models.ScreenshotImage, *models.ScreenshotContent, error)
+
+	CompareCapturesForPage(ctx context.Context, competitorID, pageID uuid.UUID, from, to time.Time) (*models.DiffResult, error)
+
+	DryRunContentSelectorsForPage(ctx context.Context, competitorID, pageID uuid.UUID, selectors *models.ContentSelectors) (*models.ContentExtraction, error)
+
 	RemovePageFromWorkspace(ctx context.Context, competitorID, pageID uuid.UUID) error
 
 	RemoveCompetitorFromWorkspace(ctx context.Context, workspaceID, competitorID uuid.UUID) error
@@ -92,16 +113,53 @@ type WorkspaceService interface {
 	// ListReports lists the reports for a competitor.
 	ListReports(ctx context.Context, workspaceID, competitorID uuid.UUID, limit, offset *int) ([]models.Report, bool, error)
 
+	// ListReportEmails lists the emails which carried a report of a competitor, along with their delivery status.
+	ListReportEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error)
+
 	// CreateReport creates a report for a competitor.
 	CreateReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) (*models.Report, error)
 
-	// DispatchReport dispatches a report for a competitor.
-	DispatchReportToWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) error
+	// DispatchReportToSubscribers emails the reports of a competitor created since the subscribers were last notified to the subscribers who want them by email.
+	// Returns the users the report was emailed to.
+	DispatchReportToSubscribers(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error)
 
 	// DispatchReport dispatches a report for a competitor to an email list.
 	DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscriberEmails []string) error
 
-	ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID) (<-chan []uuid.UUID, <-chan error)
+	// GetDigest gets the digest of the latest reports of the competitors of a workspace within the scope of a job.
+	// Every competitor of the workspace is within an empty scope.
+	GetDigest(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) (*models.Digest, error)
+
+	// DispatchDigest dispatches the digest of a workspace to an email list.
+	DispatchDigest(ctx context.Context, workspaceID uuid.UUID, subscriberEmails []string) error
+
+	// DispatchDigestToSubscribers emails the digest of a workspace to the subscribers who want it by email.
+	// Returns the users the digest was emailed to.
+	DispatchDigestToSubscribers(ctx context.Context, workspaceID uuid.UUID, digest models.Digest, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error)
+
+	// GetWorkspaceReportMode gets how the reports of a workspace are dispatched to its members
+	GetWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID) (models.ReportMode, error)
+
+	// UpdateWorkspaceReportMode updates how the reports of a workspace are dispatched to its members
+	UpdateWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID, reportMode models.ReportMode) error
+
+	// GetNotificationSubscription gets the notification settings of a member, the defaults when the member hasn't edited them
+	GetNotificationSubscription(ctx context.Context, workspaceID uuid.UUID, workspaceMemberEmail string) (*models.NotificationSubscription, error)
+
+	// UpdateNotificationSubscription replaces the notification settings of a member
+	UpdateNotificationSubscription(ctx context.Context, workspaceID uuid.UUID, workspaceMemberEmail string, props models.NotificationSubscriptionProps) (*models.NotificationSubscription, error)
+
+	// ListNotificationSubscribers lists every member of a workspace due to be notified at the time
+	ListNotificationSubscribers(ctx context.Context, workspaceID uuid.UUID, now time.Time) ([]models.NotificationSubscriber, error)
+
+	// MarkSubscribersNotified records the time the users were notified
+	MarkSubscribersNotified(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID, notifiedAt time.Time) error
+
+	// ListActiveWorkspaces streams batches of active workspaces within the scope
+	ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID, scope models.JobScope) (<-chan []uuid.UUID, <-chan error)
+
+	// CountActiveWorkspaces returns the number of workspaces ListActiveWorkspaces streams for the scope
+	CountActiveWorkspaces(ctx context.Context, scope models.JobScope) (int64, error)
 
 	CanCreateWorkspace(ctx context.Context, userID uuid.UUID) (bool, error)
 
@@ -0,0 +1,131 @@
+// ./src/internal/service/workspace/notification_mgt.go
+package workspace
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/wizenheimer/byrd/src/internal/models/core"
+)
+
+// notificationMemberPageSize is the number of members loaded at once when listing the subscribers of a workspace
+const notificationMemberPageSize = 100
+
+// GetNotificationSubscription returns the notification settings of a member, the defaults when the member hasn't edited them
+func (ws *workspaceService) GetNotificationSubscription(ctx context.Context, workspaceID uuid.UUID, workspaceMemberEmail string) (*models.NotificationSubscription, error) {
+	workspaceUser, err := ws.userService.GetUserByEmail(ctx, workspaceMemberEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := ws.workspaceRepo.GetNotificationSubscription(ctx, workspaceID, workspaceUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		defaultSubscription := models.DefaultNotificationSubscription(workspaceID, workspaceUser.ID)
+		return &defaultSubscription, nil
+	}
+
+	return subscription, nil
+}
+
+// UpdateNotificationSubscription replaces the notification settings of a member
+func (ws *workspaceService) UpdateNotificationSubscription(ctx context.Context, workspaceID uuid.UUID, workspaceMemberEmail string, props models.NotificationSubscriptionProps) (*models.NotificationSubscription, error) {
+	workspaceUser, err := ws.userService.GetUserByEmail(ctx, workspaceMemberEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the competitors belong to the workspace
+	props.CompetitorIDs = uniqueUUIDs(props.CompetitorIDs)
+	if len(props.CompetitorIDs) > 0 {
+		competitors, err := ws.competitorService.GetCompetitorForWorkspace(ctx, workspaceID, props.CompetitorIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(competitors) != len(props.CompetitorIDs) {
+			return nil, errors.New("some competitors don't belong to the workspace")
+		}
+	}
+
+	return ws.workspaceRepo.UpsertNotificationSubscription(ctx, workspaceID, workspaceUser.ID, props)
+}
+
+// ListNotificationSubscribers returns the members of the workspace due to be notified at the time
+// Every member is loaded, a page at a time, along with their notification settings
+func (ws *workspaceService) ListNotificationSubscribers(ctx context.Context, workspaceID uuid.UUID, now time.Time) ([]models.NotificationSubscriber, error) {
+	subscribers := make([]models.NotificationSubscriber, 0)
+
+	limit := notificationMemberPageSize
+	for offset := 0; ; offset += limit {
+		members, hasMore, err := ws.workspaceRepo.ListWorkspaceMembers(ctx, workspaceID, &limit, &offset, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			break
+		}
+
+		userIDs := make([]uuid.UUID, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.ID)
+		}
+
+		users, err := ws.userService.ListUsersByUserIDs(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions, err := ws.workspaceRepo.ListNotificationSubscriptions(ctx, workspaceID, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		subscriptionsByUser := make(map[uuid.UUID]models.NotificationSubscription, len(subscriptions))
+		for _, subscription := range subscriptions {
+			subscriptionsByUser[subscription.UserID] = subscription
+		}
+
+		for _, user := range users {
+			if user.Email == nil {
+				continue
+			}
+			subscription, ok := subscriptionsByUser[user.ID]
+			if !ok {
+				subscription = models.DefaultNotificationSubscription(workspaceID, user.ID)
+			}
+			if !subscription.IsDue(now) {
+				continue
+			}
+			subscribers = append(subscribers, models.NotificationSubscriber{
+				NotificationSubscription: subscription,
+				Email:                    *user.Email,
+			})
+		}
+
+		if !hasMore {
+			break
+		}
+	}
+
+	return subscribers, nil
+}
+
+// MarkSubscribersNotified records the time the users were notified, so that their frequency is honored
+// Only the users who were sent a message should be marked, the others stay due
+func (ws *workspaceService) MarkSubscribersNotified(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID, notifiedAt time.Time) error {
+	return ws.workspaceRepo.MarkMembersNotified(ctx, workspaceID, uniqueUUIDs(userIDs), notifiedAt)
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
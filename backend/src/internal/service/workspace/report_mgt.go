@@ -3,6 +3,9 @@ package workspace
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	models "github.com/wizenheimer/byrd/src/internal/models/core"
@@ -14,40 +17,193 @@ func (ws *workspaceService) ListReports(ctx context.Context, workspaceID, compet
 	return ws.competitorService.ListReports(ctx, workspaceID, competitorID, limit, offset)
 }
 
+func (ws *workspaceService) ListReportEmails(ctx context.Context, workspaceID, competitorID, reportID uuid.UUID, limit, offset *int) ([]models.OutboxEmail, bool, error) {
+	return ws.competitorService.ListReportEmails(ctx, workspaceID, competitorID, reportID, limit, offset)
+}
+
 func (ws *workspaceService) CreateReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) (*models.Report, error) {
 	return ws.competitorService.CreateReport(ctx, workspaceID, competitorID)
 }
 
-func (ws *workspaceService) DispatchReportToWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID) error {
-	members, hasMore, err := ws.workspaceRepo.ListWorkspaceMembers(ctx, workspaceID, nil, nil, nil)
+// DispatchReportToSubscribers emails the reports of the competitor created since the subscribers were last notified, aggregated into one
+// Subscribers following the same categories and last notified at the same time share an email
+// The emails are queued in a single transaction, so that a retry of the dispatch doesn't send any of them twice
+// Returns the users the report was emailed to, subscribers without new changes in their categories aren't
+func (ws *workspaceService) DispatchReportToSubscribers(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error) {
+	now := time.Now()
+
+	// Group the subscribers by the categories they follow, and the time since which the reports are new to them
+	groups := make(map[string][]models.NotificationSubscriber)
+	categories := make(map[string][]string)
+	since := make(map[string]time.Time)
+	for _, subscriber := range subscribers {
+		if !subscriber.Wants(competitorID, models.NotificationChannelEmail) {
+			continue
+		}
+		subscriberCategories := slices.Clone(subscriber.Categories)
+		slices.Sort(subscriberCategories)
+		subscriberSince := subscriber.NotifiedSince(now)
+		key := strings.Join(subscriberCategories, ",") + "|" + subscriberSince.UTC().Format(time.RFC3339Nano)
+		groups[key] = append(groups[key], subscriber)
+		categories[key] = subscriberCategories
+		since[key] = subscriberSince
+	}
+
+	var notified []uuid.UUID
+	err := ws.tm.RunInTx(ctx, nil, func(ctx context.Context) error {
+		errs := make([]error, 0)
+		for key, group := range groups {
+			groupSince := since[key]
+			sent, err := ws.competitorService.DispatchReport(ctx, workspaceID, competitorID, categories[key], &groupSince, subscriberEmails(group))
+			if err != nil {
+				ws.logger.Error("failed to dispatch report", zap.Any("workspaceID", workspaceID), zap.Any("competitorID", competitorID), zap.Strings("categories", categories[key]), zap.Error(err))
+				errs = append(errs, err)
+				continue
+			}
+			if sent {
+				notified = append(notified, subscriberUserIDs(group)...)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
 	if err != nil {
-		return err
+		// None of the emails were queued
+		return nil, err
+	}
+
+	return notified, nil
+}
+
+// GetDigest gets the digest of the latest reports of the competitors of the workspace within the scope
+func (ws *workspaceService) GetDigest(ctx context.Context, workspaceID uuid.UUID, scope models.JobScope) (*models.Digest, error) {
+	digest, err := ws.competitorService.GetDigest(ctx, workspaceID)
+	if err != nil || scope.IsEmpty() {
+		return digest, err
+	}
+
+	competitors, err := ws.competitorService.ListCompetitorsInScope(ctx, workspaceID, scope)
+	if err != nil {
+		return nil, err
 	}
-	if len(members) == 0 {
-		return errors.New("no members found")
+	if len(competitors) == 0 {
+		// An empty filter would keep every competitor
+		empty := models.NewDigest(workspaceID, nil, digest.Since)
+		return &empty, nil
 	}
-	if hasMore {
-		ws.logger.Warn("more members found", zap.Any("workspaceID", workspaceID))
+
+	competitorIDs := make([]uuid.UUID, 0, len(competitors))
+	for _, competitor := range competitors {
+		competitorIDs = append(competitorIDs, competitor.ID)
 	}
 
-	userIDs := make([]uuid.UUID, 0)
-	for _, member := range members {
-		userIDs = append(userIDs, member.ID)
+	scoped := digest.Filter(competitorIDs, nil)
+	return &scoped, nil
+}
+
+// DispatchDigest emails the digest of the workspace to an email list
+func (ws *workspaceService) DispatchDigest(ctx context.Context, workspaceID uuid.UUID, subscriberEmails []string) error {
+	// Clean up the email list for duplicates and nil values
+	subscriberEmails = utils.CleanEmailList(subscriberEmails, nil)
+
+	workspace, err := ws.workspaceRepo.GetWorkspaceByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return err
 	}
 
-	users, err := ws.userService.ListUsersByUserIDs(ctx, userIDs)
+	digest, err := ws.competitorService.GetDigest(ctx, workspaceID)
 	if err != nil {
 		return err
 	}
 
-	subscriberEmails := make([]string, 0)
-	for _, user := range users {
-		if user.Email != nil {
-			subscriberEmails = append(subscriberEmails, *user.Email)
+	_, err = ws.competitorService.DispatchDigest(ctx, *digest, workspace.Name, models.NotificationFrequencyInstant, subscriberEmails)
+	return err
+}
+
+// DispatchDigestToSubscribers emails the digest to the subscribers who want it by email
+// Each subscriber gets the competitors and categories they follow, subscribers following the same ones share an email
+// The emails are queued in a single transaction, so that a retry of the dispatch doesn't send any of them twice
+// Returns the users the digest was emailed to, subscribers whose digest is empty once filtered aren't
+func (ws *workspaceService) DispatchDigestToSubscribers(ctx context.Context, workspaceID uuid.UUID, digest models.Digest, subscribers []models.NotificationSubscriber) ([]uuid.UUID, error) {
+	workspace, err := ws.workspaceRepo.GetWorkspaceByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the subscribers by the competitors and categories they follow, the time they were last notified, and the frequency naming their digest
+	groups := make(map[string][]models.NotificationSubscriber)
+	digests := make(map[string]models.Digest)
+	for _, subscriber := range subscribers {
+		if !subscriber.Notifies(models.NotificationChannelEmail) {
+			continue
+		}
+		key := string(subscriber.Frequency) + "|" + subscriptionFilterKey(subscriber.NotificationSubscription)
+		if _, ok := digests[key]; !ok {
+			digests[key] = subscriber.FilterDigest(digest)
 		}
+		groups[key] = append(groups[key], subscriber)
+	}
+
+	var notified []uuid.UUID
+	err = ws.tm.RunInTx(ctx, nil, func(ctx context.Context) error {
+		errs := make([]error, 0)
+		for key, group := range groups {
+			sent, err := ws.competitorService.DispatchDigest(ctx, digests[key], workspace.Name, group[0].Frequency, subscriberEmails(group))
+			if err != nil {
+				ws.logger.Error("failed to dispatch digest", zap.Any("workspaceID", workspaceID), zap.Error(err))
+				errs = append(errs, err)
+				continue
+			}
+			if sent {
+				notified = append(notified, subscriberUserIDs(group)...)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		// None of the emails were queued
+		return nil, err
+	}
+
+	return notified, nil
+}
+
+// subscriberEmails returns the email addresses of the subscribers, without duplicates
+func subscriberEmails(subscribers []models.NotificationSubscriber) []string {
+	emails := make([]string, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		emails = append(emails, subscriber.Email)
+	}
+	return utils.CleanEmailList(emails, nil)
+}
+
+// subscriberUserIDs returns the users of the subscribers
+func subscriberUserIDs(subscribers []models.NotificationSubscriber) []uuid.UUID {
+	userIDs := make([]uuid.UUID, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		userIDs = append(userIDs, subscriber.UserID)
+	}
+	return userIDs
+}
+
+// subscriptionFilterKey identifies the competitors and categories a subscription follows, and the reports it was already notified about
+func subscriptionFilterKey(subscription models.NotificationSubscription) string {
+	competitorIDs := make([]string, 0, len(subscription.CompetitorIDs))
+	for _, competitorID := range subscription.CompetitorIDs {
+		competitorIDs = append(competitorIDs, competitorID.String())
+	}
+	slices.Sort(competitorIDs)
+
+	categories := slices.Clone(subscription.Categories)
+	slices.Sort(categories)
+
+	lastNotifiedAt := ""
+	if subscription.LastNotifiedAt != nil {
+		lastNotifiedAt = subscription.LastNotifiedAt.UTC().Format(time.RFC3339Nano)
 	}
 
-	return ws.competitorService.DispatchReport(ctx, workspaceID, competitorID, subscriberEmails)
+	return strings.Join(competitorIDs, ",") + "|" + strings.Join(categories, ",") + "|" + lastNotifiedAt
 }
 
 func (ws *workspaceService) DispatchReport(ctx context.Context, workspaceID uuid.UUID, competitorID uuid.UUID, subscriberEmails []string) error {
@@ -55,5 +211,6 @@ func (ws *workspaceService) DispatchReport(ctx context.Context, workspaceID uuid
 	subscriberEmails = utils.CleanEmailList(subscriberEmails, nil)
 
 	// Dispatch the report
-	return ws.competitorService.DispatchReport(ctx, workspaceID, competitorID, subscriberEmails)
+	_, err := ws.competitorService.DispatchReport(ctx, workspaceID, competitorID, nil, nil, subscriberEmails)
+	return err
 }
@@ -116,6 +116,26 @@ func (ws *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID uui
 	return nil
 }
 
+// GetWorkspaceNoiseRules gets the noise rules applied to the pages of a workspace
+func (ws *workspaceService) GetWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID) ([]models.NoiseRule, error) {
+	return ws.workspaceRepo.GetWorkspaceNoiseRules(ctx, workspaceID)
+}
+
+// UpdateWorkspaceNoiseRules replaces the noise rules applied to the pages of a workspace
+func (ws *workspaceService) UpdateWorkspaceNoiseRules(ctx context.Context, workspaceID uuid.UUID, noiseRules []models.NoiseRule) error {
+	return ws.workspaceRepo.UpdateWorkspaceNoiseRules(ctx, workspaceID, noiseRules)
+}
+
+// GetWorkspaceReportMode gets how the reports of a workspace are dispatched to its members
+func (ws *workspaceService) GetWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID) (models.ReportMode, error) {
+	return ws.workspaceRepo.GetWorkspaceReportMode(ctx, workspaceID)
+}
+
+// UpdateWorkspaceReportMode updates how the reports of a workspace are dispatched to its members
+func (ws *workspaceService) UpdateWorkspaceReportMode(ctx context.Context, workspaceID uuid.UUID, reportMode models.ReportMode) error {
+	return ws.workspaceRepo.UpdateWorkspaceReportMode(ctx, workspaceID, reportMode)
+}
+
 func (ws *workspaceService) UpdateWorkspacePlan(ctx context.Context, workspaceID uuid.UUID, plan models.WorkspacePlan) error {
 	return ws.workspaceRepo.UpdateWorkspacePlan(ctx, workspaceID, plan)
 }
@@ -143,7 +163,11 @@ func (ws *workspaceService) DeleteWorkspace(ctx context.Context, workspaceID uui
 	return models.WorkspaceInactive, nil
 }
 
-func (ws *workspaceService) ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID) (<-chan []uuid.UUID, <-chan error) {
+func (ws *workspaceService) CountActiveWorkspaces(ctx context.Context, scope models.JobScope) (int64, error) {
+	return ws.workspaceRepo.CountActiveWorkspaces(ctx, scope)
+}
+
+func (ws *workspaceService) ListActiveWorkspaces(ctx context.Context, batchSize int, lastWorkspaceID *uuid.UUID, scope models.JobScope) (<-chan []uuid.UUID, <-chan error) {
 	workspaceChan := make(chan []uuid.UUID)
 	errorChan := make(chan error)
 
@@ -155,7 +179,7 @@ func (ws *workspaceService) ListActiveWorkspaces(ctx context.Context, batchSize
 		hasMore := true
 
 		for hasMore {
-			activeWorkspaces, err := ws.workspaceRepo.ListActiveWorkspaces(ctx, batchSize, currentLastID)
+			activeWorkspaces, err := ws.workspaceRepo.ListActiveWorkspaces(ctx, batchSize, currentLastID, scope)
 			if err != nil {
 				errorChan <- err
 				return
@@ -0,0 +1,193 @@
+// ./src/pkg/utils/diff.go
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineOperation is the operation applied to a line when going from one version to another
+type LineOperation int
+
+const (
+	// LineEqual is a line that is present in both versions
+	LineEqual LineOperation = iota
+	// LineInsert is a line that is only present in the newer version
+	LineInsert
+	// LineDelete is a line that is only present in the older version
+	LineDelete
+)
+
+// maxDiffCells caps the size of the LCS table built for a single diff
+// Inputs beyond this size are treated as a full replacement
+const maxDiffCells = 4_000_000
+
+// LineEdit is a single line in the edit script between two versions
+type LineEdit struct {
+	Operation LineOperation
+	Text      string
+}
+
+// DiffLines computes a line level edit script which transforms a into b
+// It is deterministic, the same inputs always produce the same edit script
+func DiffLines(a, b []string) []LineEdit {
+	// Trim the common prefix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	// Trim the common suffix
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]LineEdit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, LineEdit{Operation: LineEqual, Text: line})
+	}
+
+	edits = append(edits, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, LineEdit{Operation: LineEqual, Text: line})
+	}
+
+	return edits
+}
+
+// diffMiddle computes the edit script for the part of the inputs
+// which isn't shared as a common prefix or suffix
+func diffMiddle(a, b []string) []LineEdit {
+	edits := make([]LineEdit, 0, len(a)+len(b))
+
+	// Fallback to a full replacement when the table would be too large
+	if len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			edits = append(edits, LineEdit{Operation: LineDelete, Text: line})
+		}
+		for _, line := range b {
+			edits = append(edits, LineEdit{Operation: LineInsert, Text: line})
+		}
+		return edits
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk the table to build the edit script, preferring deletions over insertions
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, LineEdit{Operation: LineEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, LineEdit{Operation: LineDelete, Text: a[i]})
+			i++
+		default:
+			edits = append(edits, LineEdit{Operation: LineInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, LineEdit{Operation: LineDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, LineEdit{Operation: LineInsert, Text: b[j]})
+	}
+
+	return edits
+}
+
+// UnifiedDiff renders the edit script between a and b in the unified diff format
+// contextLines controls the number of unchanged lines surrounding each hunk
+// It returns an empty string when both versions are identical
+func UnifiedDiff(fromFile, toFile string, a, b []string, contextLines int) string {
+	edits := DiffLines(a, b)
+
+	// oldLines[i] and newLines[i] hold the number of lines consumed from a and b before edits[i]
+	oldLines := make([]int, len(edits)+1)
+	newLines := make([]int, len(edits)+1)
+	changes := make([]int, 0)
+	for i, edit := range edits {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		switch edit.Operation {
+		case LineEqual:
+			oldLines[i+1]++
+			newLines[i+1]++
+		case LineDelete:
+			oldLines[i+1]++
+			changes = append(changes, i)
+		case LineInsert:
+			newLines[i+1]++
+			changes = append(changes, i)
+		}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromFile, toFile)
+
+	for first := 0; first < len(changes); {
+		// Merge changes whose surrounding context would overlap into a single hunk
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last]-1 <= 2*contextLines {
+			last++
+		}
+
+		start := max(0, changes[first]-contextLines)
+		end := min(len(edits), changes[last]+contextLines+1)
+
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[end]-oldLines[start]),
+			hunkRange(newLines[start], newLines[end]-newLines[start]),
+		)
+		for _, edit := range edits[start:end] {
+			switch edit.Operation {
+			case LineEqual:
+				builder.WriteString(" ")
+			case LineDelete:
+				builder.WriteString("-")
+			case LineInsert:
+				builder.WriteString("+")
+			}
+			builder.WriteString(edit.Text)
+			builder.WriteString("\n")
+		}
+
+		first = last + 1
+	}
+
+	return builder.String()
+}
+
+// hunkRange formats the range of a hunk header
+// start is the number of lines preceding the hunk and count is the number of lines in it
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
@@ -262,8 +262,9 @@ func (m *MarkdownMinifier) cleanLinks(content string) string {
 	}
 
 	// Clean up any leftover backslashes and extra whitespace
+	// Whitespace is collapsed within lines only, line breaks delimit the headings, list items and paragraphs
 	content = strings.ReplaceAll(content, "\\", " ")
-	content = regexp.MustCompile(`\s+`).ReplaceAllString(content, " ")
+	content = regexp.MustCompile(`[^\S\n]+`).ReplaceAllString(content, " ")
 
 	// Final cleanup for any remaining URL patterns
 	content = m.linkPatterns[len(m.linkPatterns)-2].ReplaceAllString(content, "") // LinkedIn titled images
@@ -41,6 +41,8 @@ var BoolParser = func(s string) (bool, error) { return strconv.ParseBool(s) }
 
 var Float64Parser = func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
 
+var StrSliceParser = SliceParser(",", StrParser)
+
 var IntSliceParser = SliceParser(",", IntParser)
 
 var BoolSliceParser = SliceParser(",", BoolParser)
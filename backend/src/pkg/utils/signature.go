@@ -0,0 +1,34 @@
+// ./src/pkg/utils/signature.go
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateSigningSecret returns a random secret to sign payloads with
+func GenerateSigningSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(secret), nil
+}
+
+// SignPayload signs the payload sent at the timestamp, the same way TokenManager signs its tokens
+// The timestamp is signed along with the payload so that a captured payload can't be replayed later on
+func SignPayload(secret string, timestamp int64, payload []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	h.Write(payload)
+	return base64.URLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// ValidatePayloadSignature checks if the signature was computed over the payload sent at the timestamp
+func ValidatePayloadSignature(secret string, timestamp int64, payload []byte, signature string) bool {
+	expected := SignPayload(secret, timestamp, payload)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
+	"github.com/andybalholm/cascadia"
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 )
@@ -15,6 +17,18 @@ var validate *validator.Validate
 
 func InitializeValidator() {
 	validate = validator.New()
+
+	// css_selector validates that a field is a valid CSS selector
+	_ = validate.RegisterValidation("css_selector", func(fl validator.FieldLevel) bool {
+		_, err := cascadia.Compile(fl.Field().String())
+		return err == nil
+	})
+
+	// regexp validates that a field is a valid regular expression
+	_ = validate.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+		_, err := regexp.Compile(fl.Field().String())
+		return err == nil
+	})
 }
 
 func GetValidator() *validator.Validate {
@@ -43,6 +57,10 @@ func formatValidationError(err error) error {
 				errMsgs = append(errMsgs, fmt.Sprintf("%s must be at least %s", fieldName, fe.Param()))
 			case "max":
 				errMsgs = append(errMsgs, fmt.Sprintf("%s must not exceed %s", fieldName, fe.Param()))
+			case "css_selector":
+				errMsgs = append(errMsgs, fmt.Sprintf("%s must be a valid CSS selector", fieldName))
+			case "regexp":
+				errMsgs = append(errMsgs, fmt.Sprintf("%s must be a valid regular expression", fieldName))
 			default:
 				errMsgs = append(errMsgs, fmt.Sprintf("%s failed on validation tag '%s'", fieldName, fe.Tag()))
 			}
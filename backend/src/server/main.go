@@ -74,8 +74,19 @@ func main() {
 		cfg.ServiceName,
 	)
 
+	// The background workers run for the lifetime of the server
+	lifecycleCtx, stopLifecycle := context.WithCancel(context.Background())
+	defer stopLifecycle()
+
+	// Set up Redis, shared by the workflows and the rate limiters
+	redisClient, err := startup.SetupRedis(cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize redis", zap.Error(err))
+		return
+	}
+
 	// Initialize handlers using the new modular initializer
-	handlers, rm, am, err := startup.Initialize(context.Background(), cfg, logger, errorRecorder)
+	handlers, rm, am, workers, err := startup.Initialize(lifecycleCtx, cfg, redisClient, logger, errorRecorder)
 	if err != nil {
 		logger.Fatal("Failed to initialize handlers", zap.Error(err))
 		return
@@ -90,7 +101,7 @@ func main() {
 	})
 
 	// Initialize rate limiters
-	ratelimiters := middleware.NewRateLimiters(cfg)
+	ratelimiters := middleware.NewRateLimiters(cfg, redisClient)
 
 	// Initialize logging middleware
 	logSkipPaths := []string{"/health", "/metrics"}
@@ -125,6 +136,6 @@ func main() {
 	}()
 
 	// Setup shutdown handler
-	shutdownHandler := shutdown.NewShutdownHandler(app, cfg.Server.ShutdownTimeout, cfg.Server.ShutdownMaxAttempts, logger)
+	shutdownHandler := shutdown.NewShutdownHandler(app, workers, cfg.Server.ShutdownTimeout, cfg.Server.ShutdownMaxAttempts, logger)
 	shutdownHandler.HandleGracefulShutdown()
 }
@@ -23,16 +23,24 @@ type ShutdownConfig struct {
 	MaxAttempts int
 }
 
+// Worker is a background worker of the server, stopped once the server no longer accepts requests
+type Worker interface {
+	// Shutdown stops the worker, and waits for the work under way to finish or the context to be done
+	Shutdown(ctx context.Context) error
+}
+
 // ShutdownHandler encapsulates shutdown logic
 type ShutdownHandler struct {
 	app      *fiber.App
+	workers  []Worker
 	logger   *logger.Logger
 	config   ShutdownConfig
 	shutdown chan os.Signal
 }
 
 // NewShutdownHandler creates a new shutdown handler
-func NewShutdownHandler(app *fiber.App, timeout time.Duration, maxAttempts int, logger *logger.Logger) *ShutdownHandler {
+// The workers are stopped after the server, so that requests under way may still hand them work
+func NewShutdownHandler(app *fiber.App, workers []Worker, timeout time.Duration, maxAttempts int, logger *logger.Logger) *ShutdownHandler {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
@@ -43,6 +51,7 @@ func NewShutdownHandler(app *fiber.App, timeout time.Duration, maxAttempts int,
 
 	return &ShutdownHandler{
 		app:      app,
+		workers:  workers,
 		logger:   logger,
 		config:   config,
 		shutdown: shutdown,
@@ -113,6 +122,14 @@ func (h *ShutdownHandler) performCleanup(ctx context.Context) error {
 			return
 		}
 
+		// Stop the background workers, letting the work under way finish
+		for _, worker := range h.workers {
+			if err := worker.Shutdown(ctx); err != nil {
+				cleanup <- fmt.Errorf("worker shutdown error: %w", err)
+				return
+			}
+		}
+
 		// Close database connection pool
 		// TODO: Fix this
 		// h.pool.Close()
@@ -8,8 +8,8 @@ import (
 )
 
 func SetupScreenshotClient(cfg *config.Config, logger *logger.Logger) (*client.HTTPClient, error) {
+	// Captures are rate limited by the screenshot service, which waits for a token before each of them
 	screenshotClientOpts := []client.ClientOption{
-		client.WithRateLimit(cfg.Services.ScreenshotServiceQPS, 1),
 		client.WithRetry(3, []int{408, 429, 500, 502, 503, 504}),
 	}
 
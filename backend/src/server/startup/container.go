@@ -10,6 +10,7 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/service/scheduler"
 	"github.com/wizenheimer/byrd/src/internal/service/screenshot"
 	"github.com/wizenheimer/byrd/src/internal/service/user"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/internal/service/workflow"
 	"github.com/wizenheimer/byrd/src/internal/service/workspace"
 	"github.com/wizenheimer/byrd/src/internal/transaction"
@@ -24,6 +25,7 @@ func SetupHandlerContainer(
 	workflowService workflow.WorkflowService,
 	schedulerService scheduler.SchedulerService,
 	slackWorkspaceService slackworkspace.SlackWorkspaceService,
+	webhookService webhook.WebhookService,
 	library template.TemplateLibrary,
 	emailClient email.EmailClient,
 	tm *transaction.TxManager,
@@ -37,6 +39,7 @@ func SetupHandlerContainer(
 		workflowService,
 		schedulerService,
 		slackWorkspaceService,
+		webhookService,
 		library,
 		emailClient,
 		tm,
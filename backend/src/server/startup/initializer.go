@@ -4,6 +4,7 @@ package startup
 import (
 	"context"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/wizenheimer/byrd/src/internal/api/middleware"
 	"github.com/wizenheimer/byrd/src/internal/api/routes"
 	"github.com/wizenheimer/byrd/src/internal/config"
@@ -13,22 +14,26 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/transaction"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"github.com/wizenheimer/byrd/src/pkg/utils"
+	"github.com/wizenheimer/byrd/src/server/shutdown"
 	"github.com/wizenheimer/byrd/src/server/startup/services"
 )
 
+// Initialize sets up the handlers of the server, along with the background workers it runs
+// The workers run until the context is done, or they are shut down along with the server
 func Initialize(
 	ctx context.Context,
 	cfg *config.Config,
+	redisClient *redis.Client,
 	logger *logger.Logger,
 	errorRecorder *recorder.ErrorRecorder,
-) (*routes.HandlerContainer, *middleware.ResourceMiddleware, *middleware.AccessMiddleware, error) {
+) (*routes.HandlerContainer, *middleware.ResourceMiddleware, *middleware.AccessMiddleware, []shutdown.Worker, error) {
 	// Initialize utilities
 	utils.InitializeValidator()
 
 	// Initialize database
 	sqlDb, err := SetupDB(cfg)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Initialize transaction manager
@@ -37,53 +42,47 @@ func Initialize(
 	// Set up screenshot client
 	screenshotClient, err := SetupScreenshotClient(cfg, logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Set up services
 	screenshotService, err := services.SetupScreenshotService(cfg, screenshotClient, logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	aiService, err := services.SetupAIService(cfg, logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	diffService, err := diff.NewDiffService(aiService, logger)
 	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	// Set up Redis
-	redisClient, err := SetupRedis(cfg, logger)
-	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Setup email client
 	emailClient, err := setupEmailClient(cfg, logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Set up repositories
 	repos, err := SetupRepositories(ctx, cfg, tm, redisClient, logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Set up template library
 	templateLibrary, err := template.NewTemplateLibrary(logger)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	// Set up all services
-	services, err := SetupServices(cfg, repos, aiService, diffService, screenshotService, templateLibrary, emailClient, tm, logger, errorRecorder)
+	services, err := SetupServices(ctx, cfg, repos, aiService, diffService, screenshotService, templateLibrary, emailClient, tm, logger, errorRecorder)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	resourceMiddleware := middleware.NewResourceMiddleware(services.Workspace, logger)
@@ -98,14 +97,17 @@ func Initialize(
 		services.Workflow,
 		services.Scheduler,
 		services.SlackWorkspace,
+		services.Webhook,
 		templateLibrary,
 		emailClient,
 		tm,
 		logger,
 	)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	return handlers, resourceMiddleware, accessMiddleware, nil
+	workers := []shutdown.Worker{services.Webhook, services.Outbox}
+
+	return handlers, resourceMiddleware, accessMiddleware, workers, nil
 }
@@ -9,10 +9,12 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/repository/competitor"
 	"github.com/wizenheimer/byrd/src/internal/repository/history"
 	slack "github.com/wizenheimer/byrd/src/internal/repository/integration/slack"
+	"github.com/wizenheimer/byrd/src/internal/repository/outbox"
 	"github.com/wizenheimer/byrd/src/internal/repository/page"
 	"github.com/wizenheimer/byrd/src/internal/repository/report"
 	"github.com/wizenheimer/byrd/src/internal/repository/schedule"
 	"github.com/wizenheimer/byrd/src/internal/repository/user"
+	"github.com/wizenheimer/byrd/src/internal/repository/webhook"
 	"github.com/wizenheimer/byrd/src/internal/repository/workflow"
 	"github.com/wizenheimer/byrd/src/internal/repository/workspace"
 	"github.com/wizenheimer/byrd/src/internal/transaction"
@@ -29,6 +31,8 @@ type Repositories struct {
 	Workflow       workflow.WorkflowRepository
 	Report         report.ReportRepository
 	SlackWorkspace slack.SlackWorkspaceRepository
+	Webhook        webhook.WebhookRepository
+	Outbox         outbox.OutboxRepository
 }
 
 func SetupRepositories(ctx context.Context, cfg *config.Config, tm *transaction.TxManager, redisClient *redis.Client, logger *logger.Logger) (*Repositories, error) {
@@ -72,5 +76,7 @@ func SetupRepositories(ctx context.Context, cfg *config.Config, tm *transaction.
 		Report:         reportRepo,
 		Workflow:       workflowRepo,
 		SlackWorkspace: slackWorkspaceRepo,
+		Webhook:        webhook.NewWebhookRepository(tm, logger),
+		Outbox:         outbox.NewOutboxRepository(tm, logger),
 	}, nil
 }
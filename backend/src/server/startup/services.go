@@ -3,6 +3,8 @@ package startup
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wizenheimer/byrd/src/internal/config"
@@ -19,11 +21,13 @@ import (
 	"github.com/wizenheimer/byrd/src/internal/service/executor"
 	"github.com/wizenheimer/byrd/src/internal/service/history"
 	slackworkspace "github.com/wizenheimer/byrd/src/internal/service/integration/slack"
+	"github.com/wizenheimer/byrd/src/internal/service/outbox"
 	"github.com/wizenheimer/byrd/src/internal/service/page"
 	"github.com/wizenheimer/byrd/src/internal/service/report"
 	scheduler_svc "github.com/wizenheimer/byrd/src/internal/service/scheduler"
 	"github.com/wizenheimer/byrd/src/internal/service/screenshot"
 	"github.com/wizenheimer/byrd/src/internal/service/user"
+	"github.com/wizenheimer/byrd/src/internal/service/webhook"
 	"github.com/wizenheimer/byrd/src/internal/service/workflow"
 	"github.com/wizenheimer/byrd/src/internal/service/workspace"
 	"github.com/wizenheimer/byrd/src/internal/transaction"
@@ -40,10 +44,13 @@ type Services struct {
 	Workflow       workflow.WorkflowService
 	Scheduler      scheduler_svc.SchedulerService
 	SlackWorkspace slackworkspace.SlackWorkspaceService
+	Webhook        webhook.WebhookService
+	Outbox         outbox.OutboxService
 	TokenManager   *utils.TokenManager
 }
 
 func SetupServices(
+	ctx context.Context,
 	cfg *config.Config,
 	repos *Repositories,
 	aiService ai.AIService,
@@ -55,10 +62,28 @@ func SetupServices(
 	logger *logger.Logger,
 	errorRecorder *recorder.ErrorRecorder,
 ) (*Services, error) {
+	webhookService, err := webhook.NewWebhookService(repos.Webhook, setupWebhookRetryPolicy(cfg), webhook.WorkerConfig{
+		PollInterval: cfg.Services.WebhookPollInterval,
+		BatchSize:    cfg.Services.WebhookBatchSize,
+		SendTimeout:  cfg.Services.WebhookTimeout,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxService, err := outbox.NewOutboxService(repos.Outbox, emailClient, setupEmailRetryPolicy(cfg), outbox.WorkerConfig{
+		PollInterval: cfg.Services.EmailOutboxPollInterval,
+		BatchSize:    cfg.Services.EmailOutboxBatchSize,
+		SendTimeout:  cfg.Services.EmailSendTimeout,
+	}, tm, logger, errorRecorder)
+	if err != nil {
+		return nil, err
+	}
+
 	historyService := history.NewPageHistoryService(repos.History, logger)
-	pageService := page.NewPageService(repos.Page, historyService, diffService, screenshotService, logger)
+	pageService := page.NewPageService(repos.Page, historyService, diffService, screenshotService, webhookService, logger)
 
-	reportService, err := report.NewReportService(aiService, emailClient, templateLibrary, repos.Report, logger, errorRecorder)
+	reportService, err := report.NewReportService(aiService, outboxService, templateLibrary, repos.Report, webhookService, logger, errorRecorder)
 	if err != nil {
 		return nil, err
 	}
@@ -87,12 +112,17 @@ func SetupServices(
 		return nil, err
 	}
 
+	// The workflows and the schedules are shared with the other replicas under the same identity
+	cluster := setupClusterConfig(cfg)
+
 	workflowService, err := setupWorkflowService(
 		cfg,
+		cluster,
 		repos.Workflow,
 		pageService,
 		workspaceService,
 		slackWorkspaceService,
+		webhookService,
 		logger,
 		errorRecorder,
 	)
@@ -101,7 +131,9 @@ func SetupServices(
 	}
 
 	schedulerSvc, err := setupSchedulerService(
+		cluster,
 		repos.Schedule,
+		repos.Workflow,
 		workflowService,
 		logger,
 		errorRecorder,
@@ -114,6 +146,15 @@ func SetupServices(
 		return nil, err
 	}
 
+	// The workers run along with the server, and are shut down with it
+	if err := webhookService.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := outboxService.Start(ctx); err != nil {
+		return nil, err
+	}
+
 	return &Services{
 		History:        historyService,
 		Page:           pageService,
@@ -124,30 +165,109 @@ func SetupServices(
 		Scheduler:      schedulerSvc,
 		TokenManager:   tokenManager,
 		SlackWorkspace: slackWorkspaceService,
+		Webhook:        webhookService,
+		Outbox:         outboxService,
 	}, nil
 }
 
+// setupEmailClient prepares the email client of the configured provider
+// Emails are written to disk in the development profile and sent with resend otherwise, unless a provider is set
 func setupEmailClient(cfg *config.Config, logger *logger.Logger) (email.EmailClient, error) {
-	if cfg.Environment.EnvProfile == "development" {
-		return email.NewLocalEmailClient(context.Background(), logger)
+	provider := strings.ToLower(strings.TrimSpace(cfg.Services.EmailProvider))
+	if provider == "" {
+		provider = "resend"
+		if cfg.Environment.EnvProfile == "development" {
+			provider = "local"
+		}
 	}
 
-	return email.NewResendClient(context.Background(), cfg.Services.ResendAPIKey, cfg.Services.ResendNotificationEmail, logger)
+	switch provider {
+	case "local":
+		return email.NewLocalEmailClient(context.Background(), cfg.Services.LocalEmailDir, logger)
+	case "resend":
+		return email.NewResendClient(context.Background(), cfg.Services.ResendAPIKey, cfg.Services.ResendNotificationEmail, logger)
+	case "smtp":
+		return email.NewSMTPClient(context.Background(), email.SMTPConfig{
+			Host:     cfg.Services.SMTPHost,
+			Port:     cfg.Services.SMTPPort,
+			Username: cfg.Services.SMTPUsername,
+			Password: cfg.Services.SMTPPassword,
+			Security: email.SMTPSecurity(strings.ToLower(strings.TrimSpace(cfg.Services.SMTPSecurity))),
+			From:     cfg.Services.SMTPFrom,
+			ReplyTo:  cfg.Services.SMTPReplyTo,
+		}, logger)
+	default:
+		return nil, fmt.Errorf("unknown email provider %q, expected one of local, resend or smtp", cfg.Services.EmailProvider)
+	}
+}
+
+// setupClusterConfig prepares the configuration of this replica among the replicas sharing the workflows
+func setupClusterConfig(cfg *config.Config) models.ClusterConfig {
+	return models.ClusterConfig{
+		ReplicaID:    cfg.Workflow.ReplicaID,
+		LeaseTTL:     cfg.Workflow.LeaseTTL,
+		PollInterval: cfg.Workflow.PollInterval,
+	}.Normalize()
+}
+
+// setupRetryPolicy prepares the retry policy shared by the job executors
+func setupRetryPolicy(cfg *config.Config) models.JobRetryPolicy {
+	retryableErrors := make([]models.JobErrorClass, 0, len(cfg.Workflow.ExecutorRetryableErrors))
+	for _, class := range cfg.Workflow.ExecutorRetryableErrors {
+		retryableErrors = append(retryableErrors, models.JobErrorClass(class))
+	}
+
+	return models.JobRetryPolicy{
+		MaxAttempts:     cfg.Workflow.ExecutorRetryMaxAttempts,
+		BaseBackoff:     cfg.Workflow.ExecutorRetryBaseBackoff,
+		MaxBackoff:      cfg.Workflow.ExecutorRetryMaxBackoff,
+		Jitter:          cfg.Workflow.ExecutorRetryJitter,
+		RetryableErrors: retryableErrors,
+	}
+}
+
+// setupWebhookRetryPolicy prepares the retry policy of the webhook deliveries
+// Every failed attempt is retried, until the endpoint acknowledges the event
+func setupWebhookRetryPolicy(cfg *config.Config) models.JobRetryPolicy {
+	return models.JobRetryPolicy{
+		MaxAttempts: cfg.Services.WebhookRetryMaxAttempts,
+		BaseBackoff: cfg.Services.WebhookRetryBaseBackoff,
+		MaxBackoff:  cfg.Services.WebhookRetryMaxBackoff,
+		Jitter:      0.2,
+	}
+}
+
+// setupEmailRetryPolicy prepares the retry policy of the emails of the outbox
+// Every failed attempt is retried, unless the email bounced
+func setupEmailRetryPolicy(cfg *config.Config) models.JobRetryPolicy {
+	return models.JobRetryPolicy{
+		MaxAttempts: cfg.Services.EmailRetryMaxAttempts,
+		BaseBackoff: cfg.Services.EmailRetryBaseBackoff,
+		MaxBackoff:  cfg.Services.EmailRetryMaxBackoff,
+		Jitter:      0.2,
+	}
 }
 
 func setupWorkflowService(
 	cfg *config.Config,
+	cluster models.ClusterConfig,
 	workflowRepo workflow_repo.WorkflowRepository,
 	pageService page.PageService,
 	workspaceService workspace.WorkspaceService,
 	slackworkspaceService slackworkspace.SlackWorkspaceService,
+	webhookService webhook.WebhookService,
 	logger *logger.Logger,
 	errorRecorder *recorder.ErrorRecorder,
 ) (workflow.WorkflowService, error) {
+	retryPolicy := setupRetryPolicy(cfg)
+
 	screenshotTaskRuntimeConfig := models.JobExecutorConfig{
-		Parallelism: cfg.Workflow.ScreenshotExecutorParallelism,
-		LowerBound:  time.Duration(cfg.Workflow.ScreenshotExecutorLowerBound) * time.Second,
-		UpperBound:  time.Duration(cfg.Workflow.ScreenshotExecutorUpperBound) * time.Second,
+		Parallelism:    cfg.Workflow.ScreenshotExecutorParallelism,
+		MinParallelism: cfg.Workflow.ScreenshotExecutorMinParallelism,
+		MaxPerHost:     cfg.Workflow.ScreenshotExecutorMaxPerHost,
+		LowerBound:     time.Duration(cfg.Workflow.ScreenshotExecutorLowerBound) * time.Second,
+		UpperBound:     time.Duration(cfg.Workflow.ScreenshotExecutorUpperBound) * time.Second,
+		RetryPolicy:    retryPolicy,
 	}
 
 	screenshotTaskExecutor, err := executor.NewPageExecutor(pageService, screenshotTaskRuntimeConfig, logger)
@@ -159,6 +279,8 @@ func setupWorkflowService(
 		models.ScreenshotWorkflowType,
 		workflowRepo,
 		screenshotTaskExecutor,
+		webhookService,
+		cluster,
 		logger,
 		errorRecorder,
 	)
@@ -170,6 +292,7 @@ func setupWorkflowService(
 		Parallelism: cfg.Workflow.ReportExecutorParallelism,
 		LowerBound:  time.Duration(cfg.Workflow.ReportExecutorLowerBound) * time.Second,
 		UpperBound:  time.Duration(cfg.Workflow.ReportExecutorUpperBound) * time.Second,
+		RetryPolicy: retryPolicy,
 	}
 
 	reportTaskExecutor, err := executor.NewReportExecutor(workspaceService, logger, reportTaskRuntimeConfig)
@@ -181,6 +304,8 @@ func setupWorkflowService(
 		models.ReportWorkflowType,
 		workflowRepo,
 		reportTaskExecutor,
+		webhookService,
+		cluster,
 		logger,
 		errorRecorder,
 	)
@@ -202,6 +327,8 @@ func setupWorkflowService(
 		models.DispatchWorkflowType,
 		workflowRepo,
 		dispatchTaskExecutor,
+		webhookService,
+		cluster,
 		logger,
 		errorRecorder,
 	)
@@ -209,7 +336,7 @@ func setupWorkflowService(
 		return nil, err
 	}
 
-	workflowService, err := workflow.NewWorkflowService(logger, errorRecorder)
+	workflowService, err := workflow.NewWorkflowService(workflowRepo, cluster, logger, errorRecorder)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +361,9 @@ func setupWorkflowService(
 }
 
 func setupSchedulerService(
+	cluster models.ClusterConfig,
 	scheduleRepo schedule.ScheduleRepository,
+	workflowRepo workflow_repo.WorkflowRepository,
 	workflowService workflow.WorkflowService,
 	logger *logger.Logger,
 	errorRecorder *recorder.ErrorRecorder,
@@ -243,6 +372,8 @@ func setupSchedulerService(
 		scheduleRepo,
 		scheduler.NewScheduler(logger),
 		workflowService,
+		workflowRepo,
+		cluster,
 		logger,
 		errorRecorder,
 	)
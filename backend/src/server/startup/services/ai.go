@@ -2,18 +2,51 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/wizenheimer/byrd/src/internal/config"
 	"github.com/wizenheimer/byrd/src/internal/service/ai"
 	"github.com/wizenheimer/byrd/src/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// aiValidationTimeout is the time allowed for validating the AI service on startup
+const aiValidationTimeout = 30 * time.Second
+
 func SetupAIService(cfg *config.Config, logger *logger.Logger) (ai.AIService, error) {
-	aiService, err := ai.NewOpenAIService(cfg.Services.OpenAIKey, logger)
+	aiService, err := createAIService(cfg, logger)
 	if err != nil {
-		logger.Fatal("Failed to initialize AI service", zap.Error(err))
+		logger.Fatal("Failed to initialize AI service", zap.String("provider", cfg.Services.AIProvider), zap.Error(err))
 		return nil, err
 	}
 
-	return aiService, nil
+	if validator, ok := aiService.(ai.Validator); ok && cfg.Services.AIValidateOnStartup {
+		ctx, cancel := context.WithTimeout(context.Background(), aiValidationTimeout)
+		defer cancel()
+
+		if err := validator.Validate(ctx); err != nil {
+			logger.Fatal("Failed to validate AI service", zap.String("provider", cfg.Services.AIProvider), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Requests are rate limited once the service was validated, the validation isn't part of the workload
+	return ai.NewRateLimitedAIService(aiService, cfg.Services.AIServiceQPS, cfg.Services.AIServiceBurst), nil
+}
+
+func createAIService(cfg *config.Config, logger *logger.Logger) (ai.AIService, error) {
+	switch cfg.Services.AIProvider {
+	case "openai":
+		return ai.NewOpenAIService(cfg.Services.OpenAIKey, logger)
+	case "anthropic":
+		return ai.NewAnthropicService(cfg.Services.AnthropicAPIKey, cfg.Services.AIModel, logger)
+	case "local":
+		return ai.NewLocalAIService(cfg.Services.LocalAIBaseURL, cfg.Services.LocalAIKey, cfg.Services.AIModel, logger)
+	case "fake":
+		return ai.NewFakeAIService(logger)
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q, expected one of openai, anthropic, local or fake", cfg.Services.AIProvider)
+	}
 }
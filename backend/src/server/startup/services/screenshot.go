@@ -32,6 +32,7 @@ func SetupScreenshotService(cfg *config.Config, screenshotHTTPClient *client.HTT
 		screenshot_svc.WithHTTPClient(screenshotHTTPClient),
 		screenshot_svc.WithKey(cfg.Services.ScreenshotServiceAPIKey),
 		screenshot_svc.WithOrigin(cfg.Services.ScreenshotServiceOrigin),
+		screenshot_svc.WithQPS(cfg.Services.ScreenshotServiceQPS),
 	}
 
 	screenshotService, err := screenshot_svc.NewScreenshotService(logger, screenshotServiceOptions...)